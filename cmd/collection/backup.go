@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rodrwan/collection/pkg/backup"
+	"github.com/rodrwan/collection/services"
+)
+
+func runBackup(cs *services.CollectionService, args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	output := fs.String("o", "", "file to write, standard output by default")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: collection backup [flags]")
+		fmt.Fprintln(fs.Output(), "Writes the whole collection as a compressed archive for collection restore.")
+		fmt.Fprintln(fs.Output(), "The collection of a running server is downloaded from /api/backupCollection.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	manifest, err := cs.Backup(w)
+	if err != nil {
+		return err
+	}
+
+	// The archive may be going to standard output, report on standard error.
+	printManifest(os.Stderr, "backed up", manifest)
+	return nil
+}
+
+func runRestore(cs *services.CollectionService, args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: collection restore [file]")
+		fmt.Fprintln(fs.Output(), "Restores an archive written by collection backup into an empty collection,")
+		fmt.Fprintln(fs.Output(), "reading standard input when no file is given.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var r io.Reader = os.Stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	manifest, err := cs.Restore(r)
+	if err != nil {
+		return err
+	}
+
+	printManifest(os.Stdout, "restored", manifest)
+	return nil
+}
+
+func printManifest(w io.Writer, verb string, manifest backup.Manifest) {
+	fmt.Fprintf(w, "%s, format version %d, created %s:\n", verb, manifest.Version, manifest.CreatedAt.Format("2006-01-02 15:04:05"))
+	for _, s := range manifest.Sections {
+		fmt.Fprintf(w, "  %-10s %d\n", s.Name, s.Count)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/rodrwan/collection/pkg/catalog"
+	"github.com/rodrwan/collection/services"
+)
+
+func runCatalog(cs *services.CollectionService, args []string) error {
+	fs := flag.NewFlagSet("catalog", flag.ExitOnError)
+	output := fs.String("o", "catalog", "directory to write the site to")
+	title := fs.String("title", "Record Collection", "title of the site")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: collection catalog [flags]")
+		fmt.Fprintln(fs.Output(), "Writes the collection as a static HTML site that can be browsed without a server.")
+		fmt.Fprintln(fs.Output(), "Cover art is included when COLLECTION_BLOB_DIR is set.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	site, err := cs.Catalog(*title)
+	if err != nil {
+		return err
+	}
+
+	summary, err := catalog.Write(*output, site)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %d records in %d pages and %d images to %s\n", summary.Records, summary.Pages, summary.Images, *output)
+	return nil
+}
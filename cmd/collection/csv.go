@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rodrwan/collection/services"
+)
+
+func runExport(cs *services.CollectionService, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	layout := fs.String("layout", "songs", "one row per song (songs) or per record (records)")
+	columns := fs.String("columns", "", "comma separated columns to write, all of the layout by default")
+	output := fs.String("o", "", "file to write, standard output by default")
+	fs.Parse(args)
+
+	l, err := services.ParseCSVLayout(*layout)
+	if err != nil {
+		return err
+	}
+
+	var cols []string
+	for _, c := range strings.Split(*columns, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			cols = append(cols, c)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return cs.ExportCSV(w, l, cols)
+}
+
+func runImport(cs *services.CollectionService, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "validate every line without storing anything")
+	mapping := fs.String("map", "", `column mapping, e.g. "Album:name,Track:song_name"`)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: collection import [flags] [file]")
+		fmt.Fprintln(fs.Output(), "Reads standard input when no file is given.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	columns, err := services.ParseCSVColumnMap(*mapping)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	report, err := cs.ImportCSV(r, services.CSVImportOptions{Columns: columns, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+
+	return printReport(report)
+}
+
+// printReport prints an import summary and its failed lines, failing when
+// any line could not be imported.
+func printReport(report services.ImportReport) error {
+	verb := "imported"
+	if report.DryRun {
+		verb = "would import"
+	}
+	fmt.Printf("%s %d records and %d songs\n", verb, report.Records, report.Songs)
+
+	for _, le := range report.Errors {
+		fmt.Fprintf(os.Stderr, "line %d: %s\n", le.Line, le.Error)
+	}
+
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("%d lines failed", len(report.Errors))
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rodrwan/collection/services"
+)
+
+func runImportCUE(cs *services.CollectionService, args []string) error {
+	fs := flag.NewFlagSet("import-cue", flag.ExitOnError)
+	kind := fs.String("kind", "", "record kind, told from the audio files of the sheet by default")
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without storing anything")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: collection import-cue [flags] sheet.cue...")
+		fmt.Fprintln(fs.Output(), "The audio files of each sheet are looked up next to it for the length of their last track.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	for _, path := range fs.Args() {
+		if err := importCUE(cs, path, services.CUEImportOptions{
+			Kind:   *kind,
+			Dir:    filepath.Dir(path),
+			DryRun: *dryRun,
+		}); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func importCUE(cs *services.CollectionService, path string, opts services.CUEImportOptions) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	imported, err := cs.ImportCUE(f, opts)
+	if err != nil {
+		return err
+	}
+
+	verb := "added"
+	switch {
+	case imported.DryRun && imported.Created:
+		verb = "would create"
+	case imported.DryRun:
+		verb = "would add to"
+	case imported.Created:
+		verb = "created"
+	}
+
+	added := 0
+	for _, t := range imported.Tracks {
+		if t.Added {
+			added++
+		}
+	}
+	fmt.Printf("%s %s - %s (%s): %d of %d tracks\n", verb, imported.Record.Artist, imported.Record.Name, imported.Record.ID, added, len(imported.Tracks))
+
+	for _, t := range imported.Tracks {
+		note := ""
+		if !t.Added {
+			note = " (already there)"
+		}
+		fmt.Printf("  %02d %s - %s %s%s\n", t.Number, t.Performer, t.Title, time.Duration(t.Length)*time.Second, note)
+	}
+	for _, w := range imported.Warnings {
+		fmt.Fprintln(os.Stderr, w)
+	}
+
+	return nil
+}
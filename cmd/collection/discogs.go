@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rodrwan/collection/services"
+)
+
+func runImportDiscogs(cs *services.CollectionService, args []string) error {
+	fs := flag.NewFlagSet("import-discogs", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without storing anything")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: collection import-discogs [flags] [file]")
+		fmt.Fprintln(fs.Output(), "Reads standard input when no file is given.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var r io.Reader = os.Stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	summary, err := cs.ImportDiscogs(r, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	return printSummary(summary)
+}
+
+// printSummary prints what an import created and skipped, and its failed
+// rows, failing when any row could not be imported.
+func printSummary(summary services.ImportSummary) error {
+	verb := "created"
+	if summary.DryRun {
+		verb = "would create"
+	}
+	fmt.Printf("%s %d records, skipped %d already in the collection\n", verb, len(summary.Created), len(summary.Skipped))
+
+	for _, row := range summary.Skipped {
+		fmt.Printf("line %d: skipped %s (%s)\n", row.Line, row.Name, row.RecordID)
+	}
+	for _, row := range summary.Failed {
+		fmt.Fprintf(os.Stderr, "line %d: %s\n", row.Line, row.Reason)
+	}
+
+	if len(summary.Failed) > 0 {
+		return fmt.Errorf("%d rows failed", len(summary.Failed))
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rodrwan/collection/services"
+)
+
+func runImportITunes(cs *services.CollectionService, args []string) error {
+	fs := flag.NewFlagSet("import-itunes", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without storing anything")
+	verbose := fs.Bool("v", false, "list the tracks skipped")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: collection import-itunes [flags] [Library.xml]")
+		fmt.Fprintln(fs.Output(), "Imports the albums of an iTunes or Apple Music library export, reading")
+		fmt.Fprintln(fs.Output(), "standard input when no file is given. Tracks already in the collection are skipped.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var r io.Reader = os.Stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	result, err := cs.ImportITunes(r, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	created, updated := "created", "updated"
+	if result.DryRun {
+		created, updated = "would create", "update"
+	}
+	songs := 0
+	for _, rec := range append(result.Created, result.Updated...) {
+		songs += rec.Songs
+	}
+	fmt.Printf("%s %d records and %s %d with %d songs and %d plays, skipped %d of %d tracks\n",
+		created, len(result.Created), updated, len(result.Updated), songs, result.Plays, len(result.Skipped), result.Tracks)
+
+	if *verbose {
+		for _, t := range result.Skipped {
+			fmt.Printf("track %d: skipped %s (%s)\n", t.TrackID, t.Name, t.Reason)
+		}
+	}
+	for _, t := range result.Failed {
+		fmt.Fprintf(os.Stderr, "track %d: %s: %s\n", t.TrackID, t.Name, t.Reason)
+	}
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d tracks failed", len(result.Failed))
+	}
+
+	return nil
+}
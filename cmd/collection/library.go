@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rodrwan/collection/services"
+)
+
+func runScanLibrary(cs *services.CollectionService, args []string) error {
+	fs := flag.NewFlagSet("scan-library", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "report what would be added without storing anything")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: collection scan-library [flags] dir")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	scan, err := cs.ScanLibrary(fs.Arg(0), *dryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "added"
+	if scan.DryRun {
+		verb = "would add"
+	}
+	fmt.Printf("read %d files: %s %d records and songs to %d others, %d unchanged\n",
+		scan.Files, verb, len(scan.Created), len(scan.Updated), scan.Unchanged)
+
+	for _, r := range scan.Created {
+		fmt.Printf("new %s - %s (%d songs%s)\n", r.Artist, r.Name, r.Songs, coverNote(r))
+	}
+	for _, r := range scan.Updated {
+		fmt.Printf("updated %s - %s (%d new songs%s)\n", r.Artist, r.Name, r.Songs, coverNote(r))
+	}
+	for _, e := range scan.Failed {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", e.Path, e.Error)
+	}
+
+	return nil
+}
+
+func coverNote(r services.ScannedRecord) string {
+	if r.Cover {
+		return ", embedded cover"
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+// Command collection manages the collection from the command line.
+//
+// The backend is configured through the environment: with
+// COLLECTION_DATABASE_URL set the collection lives in postgres, otherwise in
+// memory, which is only useful to validate files. Cover art is read from the
+// blob directory set in COLLECTION_BLOB_DIR, if any.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rodrwan/collection/services"
+
+	_ "github.com/lib/pq"
+)
+
+// command is a subcommand, run with the arguments following its name.
+type command struct {
+	name  string
+	usage string
+	run   func(cs *services.CollectionService, args []string) error
+}
+
+var commands = []command{
+	{name: "backup", usage: "write the whole collection as a backup archive", run: runBackup},
+	{name: "catalog", usage: "write the collection as a static HTML site", run: runCatalog},
+	{name: "export", usage: "export the collection as CSV", run: runExport},
+	{name: "export-xlsx", usage: "export the collection as an XLSX spreadsheet", run: runExportXLSX},
+	{name: "import", usage: "import records and songs from CSV", run: runImport},
+	{name: "import-cue", usage: "add the record of a CUE sheet with its tracks", run: runImportCUE},
+	{name: "import-discogs", usage: "import a Discogs collection export", run: runImportDiscogs},
+	{name: "import-itunes", usage: "import the albums of an iTunes Library.xml", run: runImportITunes},
+	{name: "musicbrainz", usage: "link records to a MusicBrainz release dump", run: runMusicBrainz},
+	{name: "playlist", usage: "write a record or playlist as M3U, PLS or XSPF", run: runPlaylist},
+	{name: "restore", usage: "restore a backup archive into an empty collection", run: runRestore},
+	{name: "scan-library", usage: "add the records and songs of a music directory", run: runScanLibrary},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: collection <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-15s %s\n", c.name, c.usage)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run collection <command> -h for the flags of a command.")
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.name != flag.Arg(0) {
+			continue
+		}
+
+		cs, err := newCollectionService()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "collection:", err)
+			os.Exit(1)
+		}
+
+		if err := c.run(cs, flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "collection:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "collection: unknown command %q\n", flag.Arg(0))
+	usage()
+	os.Exit(2)
+}
+
+// newCollectionService builds the service on the backend set in the
+// environment.
+func newCollectionService() (*services.CollectionService, error) {
+	var cfgs []services.CollectionConfiguration
+	if dir := os.Getenv("COLLECTION_BLOB_DIR"); dir != "" {
+		cfgs = append(cfgs, services.WithLocalBlobStore(dir, "/api/blobs"))
+	}
+
+	url := os.Getenv("COLLECTION_DATABASE_URL")
+	if url == "" {
+		return services.NewCollectionService(append(cfgs,
+			services.WithRecordMemoryRepository(),
+			services.WithSongMemoryRepository(),
+			services.WithGenreMemoryRepository(),
+			services.WithLoanMemoryRepository(),
+			services.WithLocationMemoryRepository(),
+			services.WithPlaylistMemoryRepository(),
+			services.WithPlayMemoryRepository(),
+			services.WithReviewMemoryRepository(),
+			services.WithReleaseMemoryRepository(),
+			services.WithMatchReviewMemoryRepository(),
+		)...)
+	}
+
+	return services.NewCollectionService(append(cfgs,
+		services.WithRecordPostgresRepository(url, os.Getenv("COLLECTION_DATABASE_NAME"), sqlx.Open),
+		services.WithSongPostgresRepository(url, sqlx.Open),
+		services.WithGenrePostgresRepository(url, sqlx.Open),
+		services.WithLoanPostgresRepository(url, sqlx.Open),
+		services.WithLocationPostgresRepository(url, sqlx.Open),
+		services.WithPlaylistPostgresRepository(url, sqlx.Open),
+		services.WithPlayPostgresRepository(url, sqlx.Open),
+		services.WithReviewPostgresRepository(url, sqlx.Open),
+		services.WithReleasePostgresRepository(url, sqlx.Open),
+		services.WithMatchReviewPostgresRepository(url, sqlx.Open),
+	)...)
+}
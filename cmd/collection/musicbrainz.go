@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rodrwan/collection/services"
+)
+
+func runMusicBrainz(cs *services.CollectionService, args []string) error {
+	fs := flag.NewFlagSet("musicbrainz", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "report the matches without storing anything")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: collection musicbrainz [flags] [dump]")
+		fmt.Fprintln(fs.Output(), "Reads the mbdump/release file of a MusicBrainz JSON dump, gzip compressed or not,")
+		fmt.Fprintln(fs.Output(), "from standard input when no file is given. Ambiguous matches are listed for review.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var r io.Reader = os.Stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	enrichment, err := cs.EnrichFromMusicBrainz(r, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "linked"
+	if enrichment.DryRun {
+		verb = "would link"
+	}
+	fmt.Printf("read %d releases: %s %d records, %d to review, %d unmatched, %d already linked\n",
+		enrichment.Releases, verb, len(enrichment.Matched), len(enrichment.Review), enrichment.Unmatched, enrichment.Linked)
+
+	for _, m := range enrichment.Matched {
+		fmt.Printf("%s: release %s (score %.2f, %d songs)\n", m.Name, m.ReleaseID, m.Score, m.Songs)
+	}
+	for _, review := range enrichment.Review {
+		fmt.Printf("review %s:\n", review.Name)
+		for _, c := range review.Candidates {
+			fmt.Printf("  release %s %s - %s %s (score %.2f, %d tracks)\n",
+				c.ReleaseID, c.ArtistCredit, c.Title, c.ReleaseDate, c.Score, c.Tracks)
+		}
+	}
+
+	return nil
+}
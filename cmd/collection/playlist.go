@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/pkg/tracklist"
+	"github.com/rodrwan/collection/services"
+)
+
+func runPlaylist(cs *services.CollectionService, args []string) error {
+	fs := flag.NewFlagSet("playlist", flag.ExitOnError)
+	format := fs.String("format", "m3u", "playlist format: m3u, pls or xspf")
+	base := fs.String("base", "", "directory or URL of the music library, locations are relative by default")
+	isPlaylist := fs.Bool("playlist", false, "the id is of a saved playlist instead of a record")
+	output := fs.String("o", "", "file to write, standard output by default")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: collection playlist [flags] id")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	f, err := tracklist.ParseFormat(*format)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var list tracklist.Tracklist
+	if *isPlaylist {
+		list, err = cs.PlaylistTracklist(id, *base)
+	} else {
+		list, err = cs.RecordTracklist(id, *base)
+	}
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		out, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		w = out
+	}
+
+	return tracklist.Write(w, f, list)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rodrwan/collection/services"
+)
+
+func runExportXLSX(cs *services.CollectionService, args []string) error {
+	fs := flag.NewFlagSet("export-xlsx", flag.ExitOnError)
+	output := fs.String("o", "", "file to write, standard output by default")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: collection export-xlsx [flags]")
+		fmt.Fprintln(fs.Output(), "Writes the collection as a spreadsheet with Records, Songs and Summary sheets.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return cs.ExportXLSX(w)
+}
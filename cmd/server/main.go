@@ -34,10 +34,31 @@ func main() {
 		}),
 	)
 
-	collectionService, err := services.NewCollectionService(
+	cfgs := []services.CollectionConfiguration{
 		services.WithRecordMemoryRepository(),
 		services.WithSongMemoryRepository(),
-	)
+		services.WithGenreMemoryRepository(),
+		services.WithLoanMemoryRepository(),
+		services.WithLocationMemoryRepository(),
+		services.WithPlaylistMemoryRepository(),
+		services.WithPlayMemoryRepository(),
+		services.WithReviewMemoryRepository(),
+		services.WithReleaseMemoryRepository(),
+		services.WithMatchReviewMemoryRepository(),
+	}
+	blobDir := os.Getenv("COLLECTION_BLOB_DIR")
+	if blobDir == "" {
+		blobDir = "data/blobs"
+	}
+	cfgs = append(cfgs, services.WithLocalBlobStore(blobDir, "/api/blobs"))
+	if dir := os.Getenv("COLLECTION_LIBRARY_DIR"); dir != "" {
+		cfgs = append(cfgs, services.WithMusicLibrary(dir))
+	}
+	if path := os.Getenv("COLLECTION_RATES_FILE"); path != "" {
+		cfgs = append(cfgs, services.WithExchangeRatesFile(path))
+	}
+
+	collectionService, err := services.NewCollectionService(cfgs...)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -49,10 +70,90 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Imports stream their body, the routes after LimitBody read it whole.
+	api.Post("/bulkCreateRecords", handlers.BulkCreateRecords)
+	api.Post("/importITunesLibrary", handlers.ImportITunesLibrary)
+	api.Post("/enrichFromMusicBrainz", handlers.EnrichFromMusicBrainz)
+	api.Post("/restoreCollection", handlers.RestoreCollection)
+	api.Use(server.LimitBody(config.BodyLimit))
+
 	api.Get("/getRecords", handlers.GetRecords)
 	api.Post("/createRecord", handlers.CreateRecord)
 	api.Get("/getRecordById/:id", handlers.GetRecordById)
 	api.Post("/addSongToRecordById/:id", handlers.AddSongToRecordById)
+	api.Get("/getSongsByRecordId/:id", handlers.GetSongsByRecordId)
+
+	api.Post("/createGenre", handlers.CreateGenre)
+	api.Get("/getGenres", handlers.GetGenres)
+	api.Post("/tagRecordById/:id", handlers.TagRecordById)
+	api.Post("/tagSongById/:id", handlers.TagSongById)
+	api.Get("/getTagCounts", handlers.GetTagCounts)
+
+	api.Post("/gradeRecordById/:id", handlers.GradeRecordById)
+	api.Post("/changeRecordStatusById/:id", handlers.ChangeRecordStatusById)
+
+	api.Post("/addRecordValuationById/:id", handlers.AddRecordValuationById)
+	api.Get("/getValuation", handlers.GetValuation)
+
+	api.Post("/lendRecordById/:id", handlers.LendRecordById)
+	api.Post("/returnRecordById/:id", handlers.ReturnRecordById)
+	api.Get("/getCurrentLoans", handlers.GetCurrentLoans)
+	api.Get("/getOverdueLoans", handlers.GetOverdueLoans)
+
+	api.Post("/createLocation", handlers.CreateLocation)
+	api.Get("/getLocations", handlers.GetLocations)
+	api.Post("/assignRecordLocationById/:id", handlers.AssignRecordLocationById)
+	api.Get("/getRecordLocationById/:id", handlers.GetRecordLocationById)
+	api.Get("/getReshelvePlanById/:id", handlers.GetReshelvePlanById)
+
+	api.Post("/createPlaylist", handlers.CreatePlaylist)
+	api.Get("/getPlaylists", handlers.GetPlaylists)
+	api.Get("/getPlaylistById/:id", handlers.GetPlaylistById)
+	api.Post("/updatePlaylistById/:id", handlers.UpdatePlaylistById)
+	api.Delete("/deletePlaylistById/:id", handlers.DeletePlaylistById)
+	api.Post("/addSongsToPlaylistById/:id", handlers.AddSongsToPlaylistById)
+	api.Post("/removeSongFromPlaylistById/:id", handlers.RemoveSongFromPlaylistById)
+	api.Post("/movePlaylistSongById/:id", handlers.MovePlaylistSongById)
+	api.Post("/reorderPlaylistById/:id", handlers.ReorderPlaylistById)
+	api.Get("/exportPlaylistById/:id", handlers.ExportPlaylistById)
+	api.Get("/exportRecordPlaylistById/:id", handlers.ExportRecordPlaylistById)
+
+	api.Post("/logPlay", handlers.LogPlay)
+	api.Post("/logPlays", handlers.LogPlays)
+	api.Get("/getMostPlayedRecords", handlers.GetMostPlayedRecords)
+	api.Get("/getRecentlyPlayedRecords", handlers.GetRecentlyPlayedRecords)
+	api.Get("/getNeverPlayedRecords", handlers.GetNeverPlayedRecords)
+
+	api.Post("/rateRecordById/:id", handlers.RateRecordById)
+	api.Post("/rateSongById/:id", handlers.RateSongById)
+	api.Get("/getRecordReviewsById/:id", handlers.GetRecordReviewsById)
+	api.Get("/getSongReviewsById/:id", handlers.GetSongReviewsById)
+
+	api.Post("/uploadRecordCoverById/:id", handlers.UploadRecordCoverById)
+	api.Get("/blobs/*", handlers.GetBlob)
+
+	api.Post("/createRelease", handlers.CreateRelease)
+	api.Get("/getReleases", handlers.GetReleases)
+	api.Get("/getReleaseById/:id", handlers.GetReleaseById)
+	api.Post("/addVariantToReleaseById/:id", handlers.AddVariantToReleaseById)
+	api.Post("/removeVariantFromReleaseById/:id", handlers.RemoveVariantFromReleaseById)
+	api.Get("/getRecordVariantsById/:id", handlers.GetRecordVariantsById)
+
+	api.Get("/getDuplicateRecords", handlers.GetDuplicateRecords)
+	api.Post("/mergeRecords", handlers.MergeRecords)
+
+	api.Get("/exportRecordsCsv", handlers.ExportRecordsCsv)
+	api.Get("/exportRecordsXlsx", handlers.ExportRecordsXlsx)
+	api.Post("/importRecordsCsv", handlers.ImportRecordsCsv)
+	api.Post("/importDiscogsCollection", handlers.ImportDiscogsCollection)
+	api.Post("/importCueSheet", handlers.ImportCueSheet)
+	api.Get("/getMusicBrainzReviews", handlers.GetMusicBrainzReviews)
+	api.Post("/resolveMusicBrainzReviewById/:id", handlers.ResolveMusicBrainzReviewById)
+
+	api.Get("/backupCollection", handlers.BackupCollection)
+
+	api.Post("/scanLibrary", handlers.ScanLibrary)
+	api.Get("/getLibraryScanById/:id", handlers.GetLibraryScanById)
 
 	sig := make(chan os.Signal, 1)
 	serverCtx, serverStopCtx := context.WithCancel(context.Background())
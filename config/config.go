@@ -6,7 +6,14 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// BodyLimit is the largest request body read in memory. Larger bodies are
+// streamed to the import handlers that read them as a stream, and rejected
+// with 413 by the others, see server.LimitBody.
+const BodyLimit = 8 << 20
+
 var NewFiberConfig = fiber.Config{
+	BodyLimit:         BodyLimit,
+	StreamRequestBody: true,
 	// Override default error handler
 	ErrorHandler: func(ctx *fiber.Ctx, err error) error {
 		// Status code defaults to 500
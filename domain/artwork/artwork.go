@@ -0,0 +1,190 @@
+package artwork
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+var (
+	ErrUnsupportedType = errors.New("cover art must be a JPEG or PNG image")
+	ErrInvalidImage    = errors.New("cover art could not be decoded")
+	ErrImageTooLarge   = errors.New("cover art dimensions are too large")
+)
+
+// Content types accepted for cover art.
+const (
+	TypeJPEG = "image/jpeg"
+	TypePNG  = "image/png"
+)
+
+// MaxPixels bounds the decoded size of an upload, so a small file cannot
+// expand into a huge image in memory.
+const MaxPixels = 50 * 1000 * 1000
+
+// jpegQuality is used when re-encoding JPEG renditions.
+const jpegQuality = 85
+
+// Size is a rendition generated from every upload. Images are scaled down
+// so their longest side fits MaxDimension, they are never scaled up.
+type Size struct {
+	Name         string
+	MaxDimension int
+}
+
+// Original is the name of the rendition holding the uploaded image.
+const Original = "original"
+
+// Sizes lists the thumbnails generated for every upload.
+var Sizes = []Size{
+	{Name: "small", MaxDimension: 150},
+	{Name: "medium", MaxDimension: 600},
+}
+
+// Rendition is an encoded version of the cover art.
+type Rendition struct {
+	Name        string
+	ContentType string
+	Width       int
+	Height      int
+	Data        []byte
+}
+
+// DetectType sniffs the content type of an upload, ignoring whatever the
+// client claimed, and rejects anything but JPEG and PNG.
+func DetectType(data []byte) (string, error) {
+	switch contentType := http.DetectContentType(data); contentType {
+	case TypeJPEG, TypePNG:
+		return contentType, nil
+	}
+
+	return "", ErrUnsupportedType
+}
+
+// Extension returns the file extension of a supported content type.
+func Extension(contentType string) string {
+	if contentType == TypePNG {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// Process validates an upload and returns the original followed by one
+// rendition per size, all in the format of the upload.
+func Process(data []byte) ([]Rendition, error) {
+	contentType, err := DetectType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	bounds := img.Bounds()
+	renditions := []Rendition{{
+		Name:        Original,
+		ContentType: contentType,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+		Data:        data,
+	}}
+
+	for _, size := range Sizes {
+		thumb := Thumbnail(img, size.MaxDimension)
+
+		var buf bytes.Buffer
+		if err := encode(&buf, thumb, contentType); err != nil {
+			return nil, err
+		}
+
+		renditions = append(renditions, Rendition{
+			Name:        size.Name,
+			ContentType: contentType,
+			Width:       thumb.Bounds().Dx(),
+			Height:      thumb.Bounds().Dy(),
+			Data:        buf.Bytes(),
+		})
+	}
+
+	return renditions, nil
+}
+
+func encode(buf *bytes.Buffer, img image.Image, contentType string) error {
+	if contentType == TypePNG {
+		return png.Encode(buf, img)
+	}
+
+	return jpeg.Encode(buf, img, &jpeg.Options{Quality: jpegQuality})
+}
+
+// Thumbnail scales img down so its longest side is at most maxDimension,
+// keeping the aspect ratio. Each target pixel averages the source pixels it
+// covers, which keeps thumbnails smooth without external packages.
+func Thumbnail(img image.Image, maxDimension int) image.Image {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	if srcW <= maxDimension && srcH <= maxDimension {
+		return img
+	}
+
+	dstW, dstH := maxDimension, maxDimension
+	if srcW > srcH {
+		dstH = max(1, srcH*maxDimension/srcW)
+	} else {
+		dstW = max(1, srcW*maxDimension/srcH)
+	}
+
+	src := image.NewNRGBA(image.Rect(0, 0, srcW, srcH))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*srcH/dstH, max((y+1)*srcH/dstH, y*srcH/dstH+1)
+		for x := 0; x < dstW; x++ {
+			x0, x1 := x*srcW/dstW, max((x+1)*srcW/dstW, x*srcW/dstW+1)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(src.Pix[offset])
+					g += int(src.Pix[offset+1])
+					b += int(src.Pix[offset+2])
+					a += int(src.Pix[offset+3])
+					offset += 4
+					n++
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / n)
+			dst.Pix[offset+1] = uint8(g / n)
+			dst.Pix[offset+2] = uint8(b / n)
+			dst.Pix[offset+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,134 @@
+package artwork_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/rodrwan/collection/domain/artwork"
+)
+
+func newImage(w, h int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func encodePNG(img image.Image) []byte {
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+func encodeJPEG(img image.Image) []byte {
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, img, nil)
+	return buf.Bytes()
+}
+
+func encodeGIF(img image.Image) []byte {
+	var buf bytes.Buffer
+	gif.Encode(&buf, img, nil)
+	return buf.Bytes()
+}
+
+func TestArtwork_Process(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		expectedType string
+		expectedErr  error
+	}{
+		{
+			name:         "PNG",
+			data:         encodePNG(newImage(1200, 800)),
+			expectedType: artwork.TypePNG,
+		},
+		{
+			name:         "JPEG",
+			data:         encodeJPEG(newImage(800, 1200)),
+			expectedType: artwork.TypeJPEG,
+		},
+		{
+			name:        "GIF",
+			data:        encodeGIF(newImage(100, 100)),
+			expectedErr: artwork.ErrUnsupportedType,
+		},
+		{
+			name:        "Text",
+			data:        []byte("not an image"),
+			expectedErr: artwork.ErrUnsupportedType,
+		},
+		{
+			name:        "Truncated PNG",
+			data:        encodePNG(newImage(100, 100))[:40],
+			expectedErr: artwork.ErrInvalidImage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renditions, err := artwork.Process(tt.data)
+			if err != tt.expectedErr {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if len(renditions) != len(artwork.Sizes)+1 {
+				t.Fatalf("Expected %d renditions, got %d", len(artwork.Sizes)+1, len(renditions))
+			}
+			if renditions[0].Name != artwork.Original {
+				t.Errorf("Expected the original first, got %s", renditions[0].Name)
+			}
+
+			for i, size := range artwork.Sizes {
+				r := renditions[i+1]
+				if r.ContentType != tt.expectedType {
+					t.Errorf("Expected %s, got %s", tt.expectedType, r.ContentType)
+				}
+				if r.Width > size.MaxDimension || r.Height > size.MaxDimension {
+					t.Errorf("Expected %s to fit %d, got %dx%d", size.Name, size.MaxDimension, r.Width, r.Height)
+				}
+
+				cfg, _, err := image.DecodeConfig(bytes.NewReader(r.Data))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if cfg.Width != r.Width || cfg.Height != r.Height {
+					t.Errorf("Expected encoded %dx%d, got %dx%d", r.Width, r.Height, cfg.Width, cfg.Height)
+				}
+			}
+		})
+	}
+}
+
+func TestArtwork_Thumbnail(t *testing.T) {
+	thumb := artwork.Thumbnail(newImage(1200, 800), 150)
+	if b := thumb.Bounds(); b.Dx() != 150 || b.Dy() != 100 {
+		t.Errorf("Expected 150x100, got %dx%d", b.Dx(), b.Dy())
+	}
+
+	small := newImage(100, 40)
+	if thumb := artwork.Thumbnail(small, 150); thumb != small {
+		t.Errorf("Expected small images not to be scaled up")
+	}
+
+	solid := image.NewNRGBA(image.Rect(0, 0, 300, 300))
+	for i := range solid.Pix {
+		solid.Pix[i] = 200
+	}
+	thumb = artwork.Thumbnail(solid, 30)
+	if c := color.NRGBAModel.Convert(thumb.At(10, 10)).(color.NRGBA); c.R != 200 || c.A != 200 {
+		t.Errorf("Expected averaged pixels to keep a solid color, got %v", c)
+	}
+}
@@ -0,0 +1,172 @@
+package loan
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrMissingValues     = errors.New("missing value")
+	ErrInvalidDueDate    = errors.New("due date must be after the lent date")
+	ErrInvalidReturnDate = errors.New("return date must be after the lent date")
+	ErrAlreadyReturned   = errors.New("loan already returned")
+	ErrAlreadyLent       = errors.New("record is already lent")
+	ErrLoanNotFound      = errors.New("loan not found")
+)
+
+// Loan tracks a record lent to a borrower. A zero due date means the record
+// can be kept indefinitely, a zero returned date that it is still out.
+type Loan struct {
+	id         uuid.UUID
+	recordID   uuid.UUID
+	borrower   string
+	lentAt     time.Time
+	dueAt      time.Time
+	returnedAt time.Time
+}
+
+type PublicLoan struct {
+	ID         uuid.UUID  `json:"id,omitempty"`
+	RecordID   uuid.UUID  `json:"recordId,omitempty"`
+	Borrower   string     `json:"borrower,omitempty"`
+	LentAt     time.Time  `json:"lentAt"`
+	DueAt      *time.Time `json:"dueAt,omitempty"`
+	ReturnedAt *time.Time `json:"returnedAt,omitempty"`
+}
+
+func NewLoan(recordID uuid.UUID, borrower string, lentAt, dueAt time.Time) (Loan, error) {
+	return NewLoanWithID(uuid.New(), recordID, borrower, lentAt, dueAt)
+}
+
+func NewLoanWithID(id, recordID uuid.UUID, borrower string, lentAt, dueAt time.Time) (Loan, error) {
+	var errs validation.Errors
+
+	if recordID == uuid.Nil {
+		errs = errs.Add("recordId", validation.CodeRequired, ErrMissingValues)
+	}
+	if strings.TrimSpace(borrower) == "" {
+		errs = errs.Add("borrower", validation.CodeRequired, ErrMissingValues)
+	}
+	if lentAt.IsZero() {
+		errs = errs.Add("lentAt", validation.CodeRequired, ErrMissingValues)
+	}
+	if !dueAt.IsZero() && !dueAt.After(lentAt) {
+		errs = errs.Add("dueAt", validation.CodeOutOfRange, ErrInvalidDueDate)
+	}
+	if err := errs.Err(); err != nil {
+		return Loan{}, err
+	}
+
+	return Loan{
+		id:       id,
+		recordID: recordID,
+		borrower: strings.TrimSpace(borrower),
+		lentAt:   lentAt,
+		dueAt:    dueAt,
+	}, nil
+}
+
+func (l Loan) ToPublic() PublicLoan {
+	pl := PublicLoan{
+		ID:       l.GetID(),
+		RecordID: l.GetRecordID(),
+		Borrower: l.GetBorrower(),
+		LentAt:   l.GetLentAt(),
+	}
+	if !l.dueAt.IsZero() {
+		dueAt := l.dueAt
+		pl.DueAt = &dueAt
+	}
+	if !l.returnedAt.IsZero() {
+		returnedAt := l.returnedAt
+		pl.ReturnedAt = &returnedAt
+	}
+
+	return pl
+}
+
+func ToPublicArray(loans []Loan) []PublicLoan {
+	var ll []PublicLoan
+
+	for _, l := range loans {
+		ll = append(ll, l.ToPublic())
+	}
+	return ll
+}
+
+// Return closes the loan at the given date.
+func (l *Loan) Return(at time.Time) error {
+	if !l.IsActive() {
+		return ErrAlreadyReturned
+	}
+
+	if at.Before(l.lentAt) {
+		var errs validation.Errors
+		return errs.Add("returnedAt", validation.CodeOutOfRange, ErrInvalidReturnDate)
+	}
+
+	l.returnedAt = at
+	return nil
+}
+
+// IsActive reports whether the record has not been returned yet.
+func (l Loan) IsActive() bool {
+	return l.returnedAt.IsZero()
+}
+
+// IsOverdue reports whether the record is still out after its due date.
+func (l Loan) IsOverdue(now time.Time) bool {
+	return l.IsActive() && !l.dueAt.IsZero() && now.After(l.dueAt)
+}
+
+func (l *Loan) SetID(id uuid.UUID) {
+	l.id = id
+}
+
+func (l *Loan) SetRecordID(recordID uuid.UUID) {
+	l.recordID = recordID
+}
+
+func (l *Loan) SetBorrower(borrower string) {
+	l.borrower = borrower
+}
+
+func (l *Loan) SetLentAt(lentAt time.Time) {
+	l.lentAt = lentAt
+}
+
+func (l *Loan) SetDueAt(dueAt time.Time) {
+	l.dueAt = dueAt
+}
+
+func (l *Loan) SetReturnedAt(returnedAt time.Time) {
+	l.returnedAt = returnedAt
+}
+
+func (l Loan) GetID() uuid.UUID {
+	return l.id
+}
+
+func (l Loan) GetRecordID() uuid.UUID {
+	return l.recordID
+}
+
+func (l Loan) GetBorrower() string {
+	return l.borrower
+}
+
+func (l Loan) GetLentAt() time.Time {
+	return l.lentAt
+}
+
+func (l Loan) GetDueAt() time.Time {
+	return l.dueAt
+}
+
+func (l Loan) GetReturnedAt() time.Time {
+	return l.returnedAt
+}
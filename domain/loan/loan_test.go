@@ -0,0 +1,95 @@
+package loan_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/loan"
+)
+
+func TestLoan_NewLoan(t *testing.T) {
+	lentAt := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		recordID    uuid.UUID
+		borrower    string
+		dueAt       time.Time
+		expectedErr error
+	}{
+		{
+			name:     "Loan with due date",
+			recordID: uuid.New(),
+			borrower: "Percy",
+			dueAt:    lentAt.AddDate(0, 1, 0),
+		},
+		{
+			name:     "Loan without due date",
+			recordID: uuid.New(),
+			borrower: "Percy",
+		},
+		{
+			name:        "Missing borrower",
+			recordID:    uuid.New(),
+			borrower:    " ",
+			expectedErr: loan.ErrMissingValues,
+		},
+		{
+			name:        "Missing record",
+			borrower:    "Percy",
+			expectedErr: loan.ErrMissingValues,
+		},
+		{
+			name:        "Due before lent",
+			recordID:    uuid.New(),
+			borrower:    "Percy",
+			dueAt:       lentAt.AddDate(0, 0, -1),
+			expectedErr: loan.ErrInvalidDueDate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loan.NewLoan(tt.recordID, tt.borrower, lentAt, tt.dueAt)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestLoan_Return(t *testing.T) {
+	lentAt := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	dueAt := lentAt.AddDate(0, 0, 14)
+
+	l, _ := loan.NewLoan(uuid.New(), "Percy", lentAt, dueAt)
+	if !l.IsActive() {
+		t.Errorf("Expected new loan to be active")
+	}
+	if l.IsOverdue(dueAt) {
+		t.Errorf("Expected loan not to be overdue on its due date")
+	}
+	if !l.IsOverdue(dueAt.Add(time.Hour)) {
+		t.Errorf("Expected loan to be overdue after its due date")
+	}
+
+	if err := l.Return(lentAt.AddDate(0, 0, -1)); !errors.Is(err, loan.ErrInvalidReturnDate) {
+		t.Errorf("Expected error %v, got %v", loan.ErrInvalidReturnDate, err)
+	}
+
+	if err := l.Return(dueAt.AddDate(0, 0, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if l.IsActive() || l.IsOverdue(dueAt.AddDate(0, 1, 0)) {
+		t.Errorf("Expected returned loan to be closed")
+	}
+	if l.ToPublic().ReturnedAt == nil {
+		t.Errorf("Expected public loan to have a return date")
+	}
+
+	if err := l.Return(dueAt); err != loan.ErrAlreadyReturned {
+		t.Errorf("Expected error %v, got %v", loan.ErrAlreadyReturned, err)
+	}
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/loan"
+)
+
+type MemoryRepository struct {
+	loans []memoryLoan
+
+	sync.Mutex
+}
+
+type memoryLoan struct {
+	ID         uuid.UUID `db:"id"`
+	RecordID   uuid.UUID `db:"record_id"`
+	Borrower   string    `db:"borrower"`
+	LentAt     time.Time `db:"lent_at"`
+	DueAt      time.Time `db:"due_at"`
+	ReturnedAt time.Time `db:"returned_at"`
+}
+
+func NewFromLoan(l loan.Loan) memoryLoan {
+	return memoryLoan{
+		ID:         l.GetID(),
+		RecordID:   l.GetRecordID(),
+		Borrower:   l.GetBorrower(),
+		LentAt:     l.GetLentAt(),
+		DueAt:      l.GetDueAt(),
+		ReturnedAt: l.GetReturnedAt(),
+	}
+}
+
+func (ml memoryLoan) ToLoan() loan.Loan {
+	l := loan.Loan{}
+
+	l.SetID(ml.ID)
+	l.SetRecordID(ml.RecordID)
+	l.SetBorrower(ml.Borrower)
+	l.SetLentAt(ml.LentAt)
+	l.SetDueAt(ml.DueAt)
+	l.SetReturnedAt(ml.ReturnedAt)
+
+	return l
+}
+
+// Create a new memory repository
+func New(ctx context.Context) (*MemoryRepository, error) {
+	return &MemoryRepository{
+		loans: make([]memoryLoan, 0),
+	}, nil
+}
+
+func (mr *MemoryRepository) Get(id uuid.UUID) (loan.Loan, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for _, l := range mr.loans {
+		if l.ID == id {
+			return l.ToLoan(), nil
+		}
+	}
+
+	return loan.Loan{}, loan.ErrLoanNotFound
+}
+
+func (mr *MemoryRepository) Add(l loan.Loan) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for _, ml := range mr.loans {
+		if ml.RecordID == l.GetRecordID() && ml.ReturnedAt.IsZero() {
+			return loan.ErrAlreadyLent
+		}
+	}
+
+	mr.loans = append(mr.loans, NewFromLoan(l))
+
+	return nil
+}
+
+func (mr *MemoryRepository) Update(l *loan.Loan) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, ml := range mr.loans {
+		if ml.ID == l.GetID() {
+			mr.loans[i] = NewFromLoan(*l)
+			return nil
+		}
+	}
+
+	return loan.ErrLoanNotFound
+}
+
+func (mr *MemoryRepository) FindLoans() ([]loan.Loan, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	var ll []loan.Loan
+	for _, l := range mr.loans {
+		ll = append(ll, l.ToLoan())
+	}
+
+	return ll, nil
+}
+
+func (mr *MemoryRepository) FindActiveByRecord(recordID uuid.UUID) (loan.Loan, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for _, l := range mr.loans {
+		if l.RecordID == recordID && l.ReturnedAt.IsZero() {
+			return l.ToLoan(), nil
+		}
+	}
+
+	return loan.Loan{}, loan.ErrLoanNotFound
+}
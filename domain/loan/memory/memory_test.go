@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/loan"
+)
+
+func TestMemoryRepository_Add(t *testing.T) {
+	repo, _ := New(context.Background())
+
+	recordID := uuid.New()
+	lentAt := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	first, _ := loan.NewLoan(recordID, "Percy", lentAt, time.Time{})
+	second, _ := loan.NewLoan(recordID, "Rodrigo", lentAt, time.Time{})
+
+	if err := repo.Add(first); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Add(second); err != loan.ErrAlreadyLent {
+		t.Errorf("Expected error %v, got %v", loan.ErrAlreadyLent, err)
+	}
+
+	first.Return(lentAt.AddDate(0, 0, 7))
+	if err := repo.Update(&first); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Add(second); err != nil {
+		t.Errorf("Expected record to be lendable once returned, got %v", err)
+	}
+
+	active, err := repo.FindActiveByRecord(recordID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if active.GetID() != second.GetID() {
+		t.Errorf("Expected active loan %v, got %v", second.GetID(), active.GetID())
+	}
+
+	loans, _ := repo.FindLoans()
+	if len(loans) != 2 {
+		t.Errorf("Expected 2 loans, got %d", len(loans))
+	}
+}
+
+func TestMemoryRepository_NotFound(t *testing.T) {
+	repo, _ := New(context.Background())
+
+	if _, err := repo.Get(uuid.New()); err != loan.ErrLoanNotFound {
+		t.Errorf("Expected error %v, got %v", loan.ErrLoanNotFound, err)
+	}
+	if _, err := repo.FindActiveByRecord(uuid.New()); err != loan.ErrLoanNotFound {
+		t.Errorf("Expected error %v, got %v", loan.ErrLoanNotFound, err)
+	}
+
+	l, _ := loan.NewLoan(uuid.New(), "Percy", time.Now(), time.Time{})
+	if err := repo.Update(&l); err != loan.ErrLoanNotFound {
+		t.Errorf("Expected error %v, got %v", loan.ErrLoanNotFound, err)
+	}
+}
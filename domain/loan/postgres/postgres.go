@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rodrwan/collection/domain/loan"
+)
+
+// uniqueViolation is the postgres error code raised by the partial unique
+// index on loans (record_id) WHERE returned_at IS NULL.
+const uniqueViolation = "23505"
+
+type IPostgresSQl interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+type PostgresRepository struct {
+	db IPostgresSQl
+}
+
+type (
+	SqlOpener func(string, string) (*sqlx.DB, error)
+)
+
+type postgresLoan struct {
+	ID         uuid.UUID    `db:"id"`
+	RecordID   uuid.UUID    `db:"record_id"`
+	Borrower   string       `db:"borrower"`
+	LentAt     time.Time    `db:"lent_at"`
+	DueAt      sql.NullTime `db:"due_at"`
+	ReturnedAt sql.NullTime `db:"returned_at"`
+}
+
+func NewFromLoan(l loan.Loan) postgresLoan {
+	return postgresLoan{
+		ID:         l.GetID(),
+		RecordID:   l.GetRecordID(),
+		Borrower:   l.GetBorrower(),
+		LentAt:     l.GetLentAt(),
+		DueAt:      sql.NullTime{Time: l.GetDueAt(), Valid: !l.GetDueAt().IsZero()},
+		ReturnedAt: sql.NullTime{Time: l.GetReturnedAt(), Valid: !l.GetReturnedAt().IsZero()},
+	}
+}
+
+func (pl postgresLoan) ToLoan() loan.Loan {
+	l := loan.Loan{}
+
+	l.SetID(pl.ID)
+	l.SetRecordID(pl.RecordID)
+	l.SetBorrower(pl.Borrower)
+	l.SetLentAt(pl.LentAt)
+	l.SetDueAt(pl.DueAt.Time)
+	l.SetReturnedAt(pl.ReturnedAt.Time)
+
+	return l
+}
+
+// Create a new postgres repository
+func New(ctx context.Context, connectionString string, open SqlOpener) (*PostgresRepository, error) {
+	client, err := open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return &PostgresRepository{
+		db: client,
+	}, nil
+}
+
+// WithTx returns a repository that runs its statements in tx.
+func (pr *PostgresRepository) WithTx(tx *sqlx.Tx) *PostgresRepository {
+	return &PostgresRepository{
+		db: tx,
+	}
+}
+
+func (pr *PostgresRepository) Get(id uuid.UUID) (loan.Loan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var l postgresLoan
+	if err := pr.db.GetContext(ctx, &l, "SELECT * FROM loans WHERE id = $1", id); err != nil {
+		if err == sql.ErrNoRows {
+			return loan.Loan{}, loan.ErrLoanNotFound
+		}
+		return loan.Loan{}, err
+	}
+
+	return l.ToLoan(), nil
+}
+
+func (pr *PostgresRepository) Add(l loan.Loan) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.NamedExecContext(ctx, `INSERT INTO loans (id, record_id, borrower, lent_at, due_at, returned_at) VALUES (:id, :record_id, :borrower, :lent_at, :due_at, :returned_at)`, NewFromLoan(l))
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolation {
+		return loan.ErrAlreadyLent
+	}
+
+	return err
+}
+
+func (pr *PostgresRepository) Update(l *loan.Loan) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.NamedExecContext(ctx, `UPDATE loans SET borrower = :borrower, lent_at = :lent_at, due_at = :due_at, returned_at = :returned_at WHERE id = :id`, NewFromLoan(*l))
+	return err
+}
+
+func (pr *PostgresRepository) FindLoans() ([]loan.Loan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var pls []postgresLoan
+	if err := pr.db.SelectContext(ctx, &pls, "SELECT * FROM loans ORDER BY lent_at"); err != nil {
+		return []loan.Loan{}, err
+	}
+
+	var ll []loan.Loan
+	for _, l := range pls {
+		ll = append(ll, l.ToLoan())
+	}
+
+	return ll, nil
+}
+
+func (pr *PostgresRepository) FindActiveByRecord(recordID uuid.UUID) (loan.Loan, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var l postgresLoan
+	if err := pr.db.GetContext(ctx, &l, "SELECT * FROM loans WHERE record_id = $1 AND returned_at IS NULL", recordID); err != nil {
+		if err == sql.ErrNoRows {
+			return loan.Loan{}, loan.ErrLoanNotFound
+		}
+		return loan.Loan{}, err
+	}
+
+	return l.ToLoan(), nil
+}
@@ -0,0 +1,17 @@
+package loan
+
+import (
+	"github.com/google/uuid"
+)
+
+type LoanRepository interface {
+	Get(uuid.UUID) (Loan, error)
+	// Add stores a new loan, failing with ErrAlreadyLent when the record
+	// has another active loan.
+	Add(Loan) error
+	Update(*Loan) error
+	FindLoans() ([]Loan, error)
+	// FindActiveByRecord returns the loan of a record that is still out, or
+	// ErrLoanNotFound.
+	FindActiveByRecord(uuid.UUID) (Loan, error)
+}
@@ -0,0 +1,234 @@
+package location
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrMissingValues    = errors.New("missing value")
+	ErrInvalidKind      = errors.New("invalid location kind")
+	ErrInvalidParent    = errors.New("invalid parent location")
+	ErrInvalidPosition  = errors.New("position cannot be negative")
+	ErrNotAssignable    = errors.New("records can only be placed on a shelf or slot")
+	ErrNotAShelf        = errors.New("location is not a shelf")
+	ErrLocationNotFound = errors.New("location not found")
+)
+
+// Kind is the level of a location in the room > shelf > slot hierarchy.
+type Kind string
+
+const (
+	KindRoom  Kind = "room"
+	KindShelf Kind = "shelf"
+	KindSlot  Kind = "slot"
+)
+
+// parentKinds lists the kind each location must hang from. Rooms are the
+// top level and have no parent.
+var parentKinds = map[Kind]Kind{
+	KindRoom:  "",
+	KindShelf: KindRoom,
+	KindSlot:  KindShelf,
+}
+
+// ParseKind reads a kind ignoring case and surrounding spaces.
+func ParseKind(value string) (Kind, error) {
+	kind := Kind(strings.ToLower(strings.TrimSpace(value)))
+	if _, ok := parentKinds[kind]; !ok {
+		return "", ErrInvalidKind
+	}
+
+	return kind, nil
+}
+
+// ParentKind returns the kind the parent of a location of the given kind
+// must have, or "" for rooms.
+func ParentKind(kind Kind) Kind {
+	return parentKinds[kind]
+}
+
+// Location is a node of the physical storage tree, e.g. Living room >
+// Shelf A > Slot 3. Position orders shelves within a room and slots within
+// a shelf.
+type Location struct {
+	id       uuid.UUID
+	name     string
+	kind     Kind
+	parentID uuid.UUID
+	position int
+}
+
+type PublicLocation struct {
+	ID       uuid.UUID  `json:"id,omitempty"`
+	Name     string     `json:"name,omitempty"`
+	Kind     Kind       `json:"kind,omitempty"`
+	ParentID *uuid.UUID `json:"parentId,omitempty"`
+	Position int        `json:"position,omitempty"`
+}
+
+func NewLocation(name string, kind Kind, parentID uuid.UUID, position int) (Location, error) {
+	return NewLocationWithID(uuid.New(), name, kind, parentID, position)
+}
+
+func NewLocationWithID(id uuid.UUID, name string, kind Kind, parentID uuid.UUID, position int) (Location, error) {
+	var errs validation.Errors
+
+	if strings.TrimSpace(name) == "" {
+		errs = errs.Add("name", validation.CodeRequired, ErrMissingValues)
+	}
+
+	parentKind, ok := parentKinds[kind]
+	switch {
+	case kind == "":
+		errs = errs.Add("kind", validation.CodeRequired, ErrMissingValues)
+	case !ok:
+		errs = errs.Add("kind", validation.CodeInvalid, ErrInvalidKind)
+	case parentKind == "" && parentID != uuid.Nil:
+		errs = errs.Add("parentId", validation.CodeNotAllowed, ErrInvalidParent)
+	case parentKind != "" && parentID == uuid.Nil:
+		errs = errs.Add("parentId", validation.CodeRequired, ErrMissingValues)
+	}
+
+	if position < 0 {
+		errs = errs.Add("position", validation.CodeOutOfRange, ErrInvalidPosition)
+	}
+
+	if err := errs.Err(); err != nil {
+		return Location{}, err
+	}
+
+	return Location{
+		id:       id,
+		name:     strings.TrimSpace(name),
+		kind:     kind,
+		parentID: parentID,
+		position: position,
+	}, nil
+}
+
+func (l Location) ToPublic() PublicLocation {
+	pl := PublicLocation{
+		ID:       l.GetID(),
+		Name:     l.GetName(),
+		Kind:     l.GetKind(),
+		Position: l.GetPosition(),
+	}
+	if l.parentID != uuid.Nil {
+		parentID := l.parentID
+		pl.ParentID = &parentID
+	}
+
+	return pl
+}
+
+func ToPublicArray(locations []Location) []PublicLocation {
+	var ll []PublicLocation
+
+	for _, l := range locations {
+		ll = append(ll, l.ToPublic())
+	}
+	return ll
+}
+
+// CanHoldRecords reports whether records can be placed at the location.
+// Rooms are too coarse to find anything in.
+func (l Location) CanHoldRecords() bool {
+	return l.kind == KindShelf || l.kind == KindSlot
+}
+
+func (l *Location) SetID(id uuid.UUID) {
+	l.id = id
+}
+
+func (l *Location) SetName(name string) {
+	l.name = name
+}
+
+func (l *Location) SetKind(kind Kind) {
+	l.kind = kind
+}
+
+func (l *Location) SetParentID(parentID uuid.UUID) {
+	l.parentID = parentID
+}
+
+func (l *Location) SetPosition(position int) {
+	l.position = position
+}
+
+func (l Location) GetID() uuid.UUID {
+	return l.id
+}
+
+func (l Location) GetName() string {
+	return l.name
+}
+
+func (l Location) GetKind() Kind {
+	return l.kind
+}
+
+func (l Location) GetParentID() uuid.UUID {
+	return l.parentID
+}
+
+func (l Location) GetPosition() int {
+	return l.position
+}
+
+// Path returns the chain of locations from the room down to the given
+// location. It returns ErrLocationNotFound when the location or one of its
+// ancestors is unknown.
+func Path(locations []Location, id uuid.UUID) ([]Location, error) {
+	byID := make(map[uuid.UUID]Location, len(locations))
+	for _, l := range locations {
+		byID[l.id] = l
+	}
+
+	var path []Location
+	for current := id; current != uuid.Nil; {
+		l, ok := byID[current]
+		if !ok {
+			return nil, ErrLocationNotFound
+		}
+		// guard against cycles in badly stored data
+		if len(path) > len(locations) {
+			break
+		}
+		path = append([]Location{l}, path...)
+		current = l.parentID
+	}
+
+	return path, nil
+}
+
+// Label joins the names of a path, e.g. "Living room > Shelf A > Slot 3".
+func Label(path []Location) string {
+	names := make([]string, 0, len(path))
+	for _, l := range path {
+		names = append(names, l.name)
+	}
+
+	return strings.Join(names, " > ")
+}
+
+// Children returns the direct children of a location ordered by position.
+func Children(locations []Location, id uuid.UUID) []Location {
+	var children []Location
+	for _, l := range locations {
+		if l.parentID == id && l.id != id {
+			children = append(children, l)
+		}
+	}
+
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].position < children[j].position
+	})
+
+	return children
+}
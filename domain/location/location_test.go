@@ -0,0 +1,125 @@
+package location_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/location"
+)
+
+func TestLocation_NewLocation(t *testing.T) {
+	tests := []struct {
+		name        string
+		locName     string
+		kind        location.Kind
+		parentID    uuid.UUID
+		position    int
+		expectedErr error
+	}{
+		{
+			name:    "Room",
+			locName: "Living room",
+			kind:    location.KindRoom,
+		},
+		{
+			name:     "Shelf",
+			locName:  "Shelf A",
+			kind:     location.KindShelf,
+			parentID: uuid.New(),
+			position: 1,
+		},
+		{
+			name:        "Missing name",
+			kind:        location.KindRoom,
+			expectedErr: location.ErrMissingValues,
+		},
+		{
+			name:        "Invalid kind",
+			locName:     "Drawer",
+			kind:        location.Kind("drawer"),
+			expectedErr: location.ErrInvalidKind,
+		},
+		{
+			name:        "Room with parent",
+			locName:     "Living room",
+			kind:        location.KindRoom,
+			parentID:    uuid.New(),
+			expectedErr: location.ErrInvalidParent,
+		},
+		{
+			name:        "Slot without parent",
+			locName:     "Slot 1",
+			kind:        location.KindSlot,
+			expectedErr: location.ErrMissingValues,
+		},
+		{
+			name:        "Negative position",
+			locName:     "Shelf A",
+			kind:        location.KindShelf,
+			parentID:    uuid.New(),
+			position:    -1,
+			expectedErr: location.ErrInvalidPosition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := location.NewLocation(tt.locName, tt.kind, tt.parentID, tt.position)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestLocation_Path(t *testing.T) {
+	room, _ := location.NewLocation("Living room", location.KindRoom, uuid.Nil, 0)
+	shelf, _ := location.NewLocation("Shelf A", location.KindShelf, room.GetID(), 1)
+	slot, _ := location.NewLocation("Slot 3", location.KindSlot, shelf.GetID(), 3)
+	locations := []location.Location{slot, room, shelf}
+
+	path, err := location.Path(locations, slot.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label := location.Label(path); label != "Living room > Shelf A > Slot 3" {
+		t.Errorf("Expected label %q, got %q", "Living room > Shelf A > Slot 3", label)
+	}
+
+	if _, err := location.Path(locations, uuid.New()); err != location.ErrLocationNotFound {
+		t.Errorf("Expected error %v, got %v", location.ErrLocationNotFound, err)
+	}
+
+	if !slot.CanHoldRecords() || room.CanHoldRecords() {
+		t.Errorf("Expected only shelves and slots to hold records")
+	}
+}
+
+func TestLocation_PlanReshelve(t *testing.T) {
+	abbey := location.ShelfItem{RecordID: uuid.New(), Name: "Abbey Road", Slot: 2, Position: 1}
+	blue := location.ShelfItem{RecordID: uuid.New(), Name: "blue", Slot: 1, Position: 2}
+	kind := location.ShelfItem{RecordID: uuid.New(), Name: "Kind of Blue", Slot: 2, Position: 2}
+	animals := location.ShelfItem{RecordID: uuid.New(), Name: "Animals", Slot: 1, Position: 1}
+
+	// current order: Animals, blue, Abbey Road, Kind of Blue
+	moves := location.PlanReshelve([]location.ShelfItem{kind, abbey, blue, animals})
+
+	expected := []location.Move{
+		{RecordID: abbey.RecordID, Name: "Abbey Road", From: 3, To: 1},
+		{RecordID: animals.RecordID, Name: "Animals", From: 1, To: 2},
+		{RecordID: blue.RecordID, Name: "blue", From: 2, To: 3},
+	}
+	if len(moves) != len(expected) {
+		t.Fatalf("Expected %d moves, got %v", len(expected), moves)
+	}
+	for i := range expected {
+		if moves[i] != expected[i] {
+			t.Errorf("Expected move %v, got %v", expected[i], moves[i])
+		}
+	}
+
+	if moves := location.PlanReshelve([]location.ShelfItem{animals, blue}); len(moves) != 0 {
+		t.Errorf("Expected sorted shelf to need no moves, got %v", moves)
+	}
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/location"
+)
+
+type MemoryRepository struct {
+	locations []memoryLocation
+
+	sync.Mutex
+}
+
+type memoryLocation struct {
+	ID       uuid.UUID     `db:"id"`
+	Name     string        `db:"name"`
+	Kind     location.Kind `db:"kind"`
+	ParentID uuid.UUID     `db:"parent_id"`
+	Position int           `db:"position"`
+}
+
+func NewFromLocation(l location.Location) memoryLocation {
+	return memoryLocation{
+		ID:       l.GetID(),
+		Name:     l.GetName(),
+		Kind:     l.GetKind(),
+		ParentID: l.GetParentID(),
+		Position: l.GetPosition(),
+	}
+}
+
+func (ml memoryLocation) ToLocation() location.Location {
+	l := location.Location{}
+
+	l.SetID(ml.ID)
+	l.SetName(ml.Name)
+	l.SetKind(ml.Kind)
+	l.SetParentID(ml.ParentID)
+	l.SetPosition(ml.Position)
+
+	return l
+}
+
+// Create a new memory repository
+func New(ctx context.Context) (*MemoryRepository, error) {
+	return &MemoryRepository{
+		locations: make([]memoryLocation, 0),
+	}, nil
+}
+
+func (mr *MemoryRepository) Get(id uuid.UUID) (location.Location, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for _, l := range mr.locations {
+		if l.ID == id {
+			return l.ToLocation(), nil
+		}
+	}
+
+	return location.Location{}, location.ErrLocationNotFound
+}
+
+func (mr *MemoryRepository) Add(l location.Location) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	mr.locations = append(mr.locations, NewFromLocation(l))
+
+	return nil
+}
+
+func (mr *MemoryRepository) FindLocations() ([]location.Location, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	var ll []location.Location
+	for _, l := range mr.locations {
+		ll = append(ll, l.ToLocation())
+	}
+
+	return ll, nil
+}
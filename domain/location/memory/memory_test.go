@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/location"
+)
+
+func TestMemoryRepository_Get(t *testing.T) {
+	repo, _ := New(context.Background())
+
+	room, _ := location.NewLocation("Living room", location.KindRoom, uuid.Nil, 0)
+	shelf, _ := location.NewLocation("Shelf A", location.KindShelf, room.GetID(), 1)
+	repo.Add(room)
+	repo.Add(shelf)
+
+	got, err := repo.Get(shelf.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetParentID() != room.GetID() || got.GetKind() != location.KindShelf || got.GetPosition() != 1 {
+		t.Errorf("Expected %v, got %v", shelf, got)
+	}
+
+	if _, err := repo.Get(uuid.New()); err != location.ErrLocationNotFound {
+		t.Errorf("Expected error %v, got %v", location.ErrLocationNotFound, err)
+	}
+
+	locations, _ := repo.FindLocations()
+	if len(locations) != 2 {
+		t.Errorf("Expected 2 locations, got %d", len(locations))
+	}
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rodrwan/collection/domain/location"
+)
+
+type IPostgresSQl interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+type PostgresRepository struct {
+	db IPostgresSQl
+}
+
+type (
+	SqlOpener func(string, string) (*sqlx.DB, error)
+)
+
+type postgresLocation struct {
+	ID       uuid.UUID `db:"id"`
+	Name     string    `db:"name"`
+	Kind     string    `db:"kind"`
+	ParentID uuid.UUID `db:"parent_id"`
+	Position int       `db:"position"`
+}
+
+func NewFromLocation(l location.Location) postgresLocation {
+	return postgresLocation{
+		ID:       l.GetID(),
+		Name:     l.GetName(),
+		Kind:     string(l.GetKind()),
+		ParentID: l.GetParentID(),
+		Position: l.GetPosition(),
+	}
+}
+
+func (pl postgresLocation) ToLocation() location.Location {
+	l := location.Location{}
+
+	l.SetID(pl.ID)
+	l.SetName(pl.Name)
+	l.SetKind(location.Kind(pl.Kind))
+	l.SetParentID(pl.ParentID)
+	l.SetPosition(pl.Position)
+
+	return l
+}
+
+// Create a new postgres repository
+func New(ctx context.Context, connectionString string, open SqlOpener) (*PostgresRepository, error) {
+	client, err := open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return &PostgresRepository{
+		db: client,
+	}, nil
+}
+
+// WithTx returns a repository that runs its statements in tx.
+func (pr *PostgresRepository) WithTx(tx *sqlx.Tx) *PostgresRepository {
+	return &PostgresRepository{
+		db: tx,
+	}
+}
+
+func (pr *PostgresRepository) Get(id uuid.UUID) (location.Location, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var l postgresLocation
+	if err := pr.db.GetContext(ctx, &l, "SELECT * FROM locations WHERE id = $1", id); err != nil {
+		if err == sql.ErrNoRows {
+			return location.Location{}, location.ErrLocationNotFound
+		}
+		return location.Location{}, err
+	}
+
+	return l.ToLocation(), nil
+}
+
+func (pr *PostgresRepository) Add(l location.Location) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.NamedExecContext(ctx, `INSERT INTO locations (id, name, kind, parent_id, position) VALUES (:id, :name, :kind, :parent_id, :position)`, NewFromLocation(l))
+	return err
+}
+
+func (pr *PostgresRepository) FindLocations() ([]location.Location, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var pls []postgresLocation
+	if err := pr.db.SelectContext(ctx, &pls, "SELECT * FROM locations ORDER BY position, name"); err != nil {
+		return []location.Location{}, err
+	}
+
+	var ll []location.Location
+	for _, l := range pls {
+		ll = append(ll, l.ToLocation())
+	}
+
+	return ll, nil
+}
@@ -0,0 +1,11 @@
+package location
+
+import (
+	"github.com/google/uuid"
+)
+
+type LocationRepository interface {
+	Get(uuid.UUID) (Location, error)
+	Add(Location) error
+	FindLocations() ([]Location, error)
+}
@@ -0,0 +1,69 @@
+package location
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ShelfItem is a record as it currently sits on a shelf. Slot is the
+// position of the slot holding it, 0 when it sits on the shelf itself, and
+// Position its place within that slot or shelf.
+type ShelfItem struct {
+	RecordID uuid.UUID
+	Name     string
+	Slot     int
+	Position int
+}
+
+// Move is a record that changes place when a shelf is re-sorted. From and
+// To count from 1 across the whole shelf.
+type Move struct {
+	RecordID uuid.UUID `json:"recordId"`
+	Name     string    `json:"name"`
+	From     int       `json:"from"`
+	To       int       `json:"to"`
+}
+
+// PlanReshelve compares the current order of a shelf with its alphabetical
+// order and returns the records that would have to move, in their new
+// order. Names are compared ignoring case.
+func PlanReshelve(items []ShelfItem) []Move {
+	current := append([]ShelfItem(nil), items...)
+	sort.SliceStable(current, func(i, j int) bool {
+		if current[i].Slot != current[j].Slot {
+			return current[i].Slot < current[j].Slot
+		}
+		return current[i].Position < current[j].Position
+	})
+
+	from := make(map[uuid.UUID]int, len(current))
+	for i, item := range current {
+		from[item.RecordID] = i + 1
+	}
+
+	sorted := append([]ShelfItem(nil), current...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := strings.ToLower(sorted[i].Name), strings.ToLower(sorted[j].Name)
+		if a != b {
+			return a < b
+		}
+		return from[sorted[i].RecordID] < from[sorted[j].RecordID]
+	})
+
+	var moves []Move
+	for i, item := range sorted {
+		if from[item.RecordID] == i+1 {
+			continue
+		}
+		moves = append(moves, Move{
+			RecordID: item.RecordID,
+			Name:     item.Name,
+			From:     from[item.RecordID],
+			To:       i + 1,
+		})
+	}
+
+	return moves
+}
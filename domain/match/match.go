@@ -0,0 +1,55 @@
+// Package match keeps the MusicBrainz matches waiting for someone to pick
+// the release a record is.
+package match
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrReviewNotFound = errors.New("musicbrainz match review not found")
+)
+
+// Track is a track of a candidate release, what is needed to link a song
+// of the same name to its recording. Length is in seconds, 0 when unknown.
+type Track struct {
+	Title       string `json:"title"`
+	RecordingID string `json:"recordingId"`
+	Length      int64  `json:"length,omitempty"`
+}
+
+// Candidate is a MusicBrainz release a record may be, with everything
+// linking the record to it sets.
+type Candidate struct {
+	ReleaseID      string   `json:"releaseId"`
+	ReleaseGroupID string   `json:"releaseGroupId,omitempty"`
+	Title          string   `json:"title"`
+	ArtistCredit   string   `json:"artistCredit,omitempty"`
+	ArtistIDs      []string `json:"artistIds,omitempty"`
+	ReleaseDate    string   `json:"releaseDate,omitempty"`
+	Tracks         []Track  `json:"tracks"`
+	Score          float64  `json:"score"`
+}
+
+// Review is a record whose best candidates are too close to tell, or not
+// close enough to be trusted. A record has one review at most.
+type Review struct {
+	RecordID   uuid.UUID   `json:"recordId"`
+	Name       string      `json:"name"`
+	Artist     string      `json:"artist,omitempty"`
+	Candidates []Candidate `json:"candidates"`
+	CreatedAt  time.Time   `json:"createdAt"`
+}
+
+// Candidate returns the candidate of the review with the given release id.
+func (r Review) Candidate(releaseID string) (Candidate, bool) {
+	for _, c := range r.Candidates {
+		if c.ReleaseID == releaseID {
+			return c, true
+		}
+	}
+	return Candidate{}, false
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/match"
+)
+
+type MemoryRepository struct {
+	reviews []memoryReview
+
+	sync.Mutex
+}
+
+type memoryReview struct {
+	RecordID   uuid.UUID         `db:"record_id"`
+	Name       string            `db:"name"`
+	Artist     string            `db:"artist"`
+	Candidates []match.Candidate `db:"candidates"`
+	CreatedAt  time.Time         `db:"created_at"`
+}
+
+func NewFromReview(r match.Review) memoryReview {
+	return memoryReview{
+		RecordID:   r.RecordID,
+		Name:       r.Name,
+		Artist:     r.Artist,
+		Candidates: append([]match.Candidate(nil), r.Candidates...),
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+func (mr memoryReview) ToReview() match.Review {
+	return match.Review{
+		RecordID:   mr.RecordID,
+		Name:       mr.Name,
+		Artist:     mr.Artist,
+		Candidates: append([]match.Candidate(nil), mr.Candidates...),
+		CreatedAt:  mr.CreatedAt,
+	}
+}
+
+// Create a new memory repository
+func New(ctx context.Context) (*MemoryRepository, error) {
+	return &MemoryRepository{
+		reviews: make([]memoryReview, 0),
+	}, nil
+}
+
+func (mr *MemoryRepository) Get(recordID uuid.UUID) (match.Review, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for _, r := range mr.reviews {
+		if r.RecordID == recordID {
+			return r.ToReview(), nil
+		}
+	}
+
+	return match.Review{}, match.ErrReviewNotFound
+}
+
+func (mr *MemoryRepository) Save(r match.Review) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, m := range mr.reviews {
+		if m.RecordID == r.RecordID {
+			mr.reviews[i] = NewFromReview(r)
+			return nil
+		}
+	}
+
+	mr.reviews = append(mr.reviews, NewFromReview(r))
+
+	return nil
+}
+
+func (mr *MemoryRepository) Delete(recordID uuid.UUID) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, m := range mr.reviews {
+		if m.RecordID == recordID {
+			mr.reviews = append(mr.reviews[:i], mr.reviews[i+1:]...)
+			return nil
+		}
+	}
+
+	return match.ErrReviewNotFound
+}
+
+func (mr *MemoryRepository) FindReviews() ([]match.Review, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	rr := make([]match.Review, 0, len(mr.reviews))
+	for _, r := range mr.reviews {
+		rr = append(rr, r.ToReview())
+	}
+
+	sort.Slice(rr, func(i, j int) bool {
+		if rr[i].Name != rr[j].Name {
+			return rr[i].Name < rr[j].Name
+		}
+		return rr[i].RecordID.String() < rr[j].RecordID.String()
+	})
+
+	return rr, nil
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/match"
+)
+
+func TestMemoryRepository_Reviews(t *testing.T) {
+	repo, _ := New(context.Background())
+
+	hits := match.Review{RecordID: uuid.New(), Name: "Greatest Hits", Candidates: []match.Candidate{{ReleaseID: "abba-hits"}}}
+	blue := match.Review{RecordID: uuid.New(), Name: "Blue"}
+	for _, r := range []match.Review{hits, blue} {
+		if err := repo.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// saving again replaces the review of the record
+	hits.Candidates = append(hits.Candidates, match.Candidate{ReleaseID: "queen-hits"})
+	if err := repo.Save(hits); err != nil {
+		t.Fatal(err)
+	}
+
+	reviews, _ := repo.FindReviews()
+	if len(reviews) != 2 || reviews[0].Name != "Blue" {
+		t.Fatalf("Expected the reviews by name, got %v", reviews)
+	}
+
+	got, err := repo.Get(hits.RecordID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got.Candidate("queen-hits"); !ok || len(got.Candidates) != 2 {
+		t.Errorf("Expected the saved candidates, got %v", got.Candidates)
+	}
+
+	if err := repo.Delete(hits.RecordID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(hits.RecordID); err != match.ErrReviewNotFound {
+		t.Errorf("Expected error %v, got %v", match.ErrReviewNotFound, err)
+	}
+	if err := repo.Delete(hits.RecordID); err != match.ErrReviewNotFound {
+		t.Errorf("Expected error %v, got %v", match.ErrReviewNotFound, err)
+	}
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rodrwan/collection/domain/match"
+)
+
+type IPostgresSQl interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+type PostgresRepository struct {
+	db IPostgresSQl
+}
+
+type (
+	SqlOpener func(string, string) (*sqlx.DB, error)
+)
+
+type postgresReview struct {
+	RecordID   uuid.UUID  `db:"record_id"`
+	Name       string     `db:"name"`
+	Artist     string     `db:"artist"`
+	Candidates candidates `db:"candidates"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+// candidates is stored as a jsonb column.
+type candidates []match.Candidate
+
+func (c candidates) Value() (driver.Value, error) {
+	if len(c) == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(c)
+}
+
+func (c *candidates) Scan(src interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, c)
+	case string:
+		return json.Unmarshal([]byte(data), c)
+	}
+
+	return errors.New("unsupported jsonb value")
+}
+
+func NewFromReview(r match.Review) postgresReview {
+	return postgresReview{
+		RecordID:   r.RecordID,
+		Name:       r.Name,
+		Artist:     r.Artist,
+		Candidates: candidates(r.Candidates),
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+func (pr postgresReview) ToReview() match.Review {
+	return match.Review{
+		RecordID:   pr.RecordID,
+		Name:       pr.Name,
+		Artist:     pr.Artist,
+		Candidates: pr.Candidates,
+		CreatedAt:  pr.CreatedAt,
+	}
+}
+
+// Create a new postgres repository
+func New(ctx context.Context, connectionString string, open SqlOpener) (*PostgresRepository, error) {
+	client, err := open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return &PostgresRepository{
+		db: client,
+	}, nil
+}
+
+// WithTx returns a repository that runs its statements in tx.
+func (pr *PostgresRepository) WithTx(tx *sqlx.Tx) *PostgresRepository {
+	return &PostgresRepository{
+		db: tx,
+	}
+}
+
+func (pr *PostgresRepository) Get(recordID uuid.UUID) (match.Review, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var r postgresReview
+	if err := pr.db.GetContext(ctx, &r, "SELECT * FROM match_reviews WHERE record_id = $1", recordID); err != nil {
+		if err == sql.ErrNoRows {
+			return match.Review{}, match.ErrReviewNotFound
+		}
+		return match.Review{}, err
+	}
+
+	return r.ToReview(), nil
+}
+
+func (pr *PostgresRepository) Save(r match.Review) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.NamedExecContext(ctx, `INSERT INTO match_reviews (record_id, name, artist, candidates, created_at) VALUES (:record_id, :name, :artist, :candidates, :created_at)
+		ON CONFLICT (record_id) DO UPDATE SET name = EXCLUDED.name, artist = EXCLUDED.artist, candidates = EXCLUDED.candidates, created_at = EXCLUDED.created_at`, NewFromReview(r))
+	return err
+}
+
+func (pr *PostgresRepository) Delete(recordID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := pr.db.NamedExecContext(ctx, `DELETE FROM match_reviews WHERE record_id = :record_id`, map[string]interface{}{"record_id": recordID})
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return match.ErrReviewNotFound
+	}
+
+	return nil
+}
+
+func (pr *PostgresRepository) FindReviews() ([]match.Review, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var prs []postgresReview
+	if err := pr.db.SelectContext(ctx, &prs, "SELECT * FROM match_reviews ORDER BY name, record_id"); err != nil {
+		return []match.Review{}, err
+	}
+
+	rr := make([]match.Review, 0, len(prs))
+	for _, r := range prs {
+		rr = append(rr, r.ToReview())
+	}
+
+	return rr, nil
+}
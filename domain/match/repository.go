@@ -0,0 +1,14 @@
+package match
+
+import (
+	"github.com/google/uuid"
+)
+
+type ReviewRepository interface {
+	Get(recordID uuid.UUID) (Review, error)
+	// Save adds the review of a record, replacing the one it had.
+	Save(Review) error
+	Delete(recordID uuid.UUID) error
+	// FindReviews lists the reviews by record name.
+	FindReviews() ([]Review, error)
+}
@@ -0,0 +1,105 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	ErrInvalidCurrency  = errors.New("currency must be a three letter ISO 4217 code")
+	ErrCurrencyMismatch = errors.New("cannot add amounts in different currencies")
+)
+
+// Money is an amount in the minor unit of its currency, e.g. cents for EUR
+// or pesos for CLP, so sums never suffer from floating point rounding.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// exponents lists currencies whose minor unit is not a hundredth.
+var exponents = map[string]int{
+	"CLP": 0,
+	"ISK": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"VND": 0,
+	"BHD": 3,
+	"KWD": 3,
+	"OMR": 3,
+}
+
+// New returns an amount of minor units in the given currency.
+func New(amount int64, currency string) (Money, error) {
+	if !IsValidCurrency(currency) {
+		return Money{}, ErrInvalidCurrency
+	}
+
+	return Money{
+		Amount:   amount,
+		Currency: strings.ToUpper(currency),
+	}, nil
+}
+
+// Zero returns no money in the given currency.
+func Zero(currency string) Money {
+	return Money{Currency: strings.ToUpper(currency)}
+}
+
+// IsValidCurrency reports whether code looks like an ISO 4217 code.
+func IsValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+
+	for _, c := range strings.ToUpper(code) {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Exponent returns the number of decimals of the currency minor unit.
+func Exponent(currency string) int {
+	if exp, ok := exponents[strings.ToUpper(currency)]; ok {
+		return exp
+	}
+
+	return 2
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Add sums two amounts of the same currency.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{
+		Amount:   m.Amount + other.Amount,
+		Currency: m.Currency,
+	}, nil
+}
+
+// Major returns the amount in the currency major unit, e.g. euros.
+func (m Money) Major() float64 {
+	return float64(m.Amount) / math.Pow10(Exponent(m.Currency))
+}
+
+// FromMajor rounds an amount in major units to the currency minor unit.
+func FromMajor(amount float64, currency string) Money {
+	return Money{
+		Amount:   int64(math.Round(amount * math.Pow10(Exponent(currency)))),
+		Currency: strings.ToUpper(currency),
+	}
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%.*f %s", Exponent(m.Currency), m.Major(), m.Currency)
+}
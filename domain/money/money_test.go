@@ -0,0 +1,132 @@
+package money_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/domain/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoney_New(t *testing.T) {
+	m, err := money.New(2500, "eur")
+	assert.Nil(t, err)
+	assert.Equal(t, money.Money{Amount: 2500, Currency: "EUR"}, m)
+
+	_, err = money.New(2500, "euro")
+	assert.Equal(t, money.ErrInvalidCurrency, err)
+}
+
+func TestMoney_Add(t *testing.T) {
+	a := money.Money{Amount: 100, Currency: "EUR"}
+
+	sum, err := a.Add(money.Money{Amount: 250, Currency: "EUR"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(350), sum.Amount)
+
+	_, err = a.Add(money.Money{Amount: 250, Currency: "USD"})
+	assert.Equal(t, money.ErrCurrencyMismatch, err)
+}
+
+func TestMoney_String(t *testing.T) {
+	tests := []struct {
+		money money.Money
+		want  string
+	}{
+		{money: money.Money{Amount: 2599, Currency: "EUR"}, want: "25.99 EUR"},
+		{money: money.Money{Amount: 15000, Currency: "CLP"}, want: "15000 CLP"},
+		{money: money.Money{Amount: 1500, Currency: "KWD"}, want: "1.500 KWD"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.money.String())
+		})
+	}
+}
+
+func TestRates_Convert(t *testing.T) {
+	rates, err := money.NewRates("EUR", time.Time{}, map[string]float64{
+		"USD": 1.25,
+		"CLP": 900,
+	})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name    string
+		from    money.Money
+		to      string
+		want    money.Money
+		wantErr error
+	}{
+		{
+			name: "Base to currency",
+			from: money.Money{Amount: 1000, Currency: "EUR"},
+			to:   "USD",
+			want: money.Money{Amount: 1250, Currency: "USD"},
+		},
+		{
+			name: "Currency to base",
+			from: money.Money{Amount: 1250, Currency: "USD"},
+			to:   "eur",
+			want: money.Money{Amount: 1000, Currency: "EUR"},
+		},
+		{
+			name: "Cross rate with different minor units",
+			from: money.Money{Amount: 1250, Currency: "USD"},
+			to:   "CLP",
+			want: money.Money{Amount: 9000, Currency: "CLP"},
+		},
+		{
+			name: "Same currency without rate",
+			from: money.Money{Amount: 1000, Currency: "GBP"},
+			to:   "GBP",
+			want: money.Money{Amount: 1000, Currency: "GBP"},
+		},
+		{
+			name:    "Unknown currency",
+			from:    money.Money{Amount: 1000, Currency: "GBP"},
+			to:      "EUR",
+			wantErr: money.ErrUnknownRate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rates.Convert(tt.from, tt.to)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadRates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "rates.json")
+	data := []byte(`{"base": "eur", "date": "2021-10-01", "rates": {"USD": 1.16}}`)
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rates, err := money.LoadRates(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "EUR", rates.Base())
+	assert.Equal(t, "2021-10-01", rates.Date().Format("2006-01-02"))
+
+	_, err = money.LoadRates(filepath.Join(dir, "missing.json"))
+	assert.NotNil(t, err)
+
+	if err := ioutil.WriteFile(path, []byte(`{"base": "EUR", "rates": {"USD": -1}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = money.LoadRates(path)
+	assert.NotNil(t, err)
+}
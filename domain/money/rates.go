@@ -0,0 +1,105 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownRate = errors.New("no exchange rate for currency")
+)
+
+// Rates is an exchange rate table relative to a base currency. Each rate is
+// how many units of a currency one unit of the base currency buys.
+type Rates struct {
+	base  string
+	date  time.Time
+	rates map[string]float64
+}
+
+type ratesFile struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// NewRates builds a rate table for the given base currency.
+func NewRates(base string, date time.Time, rates map[string]float64) (Rates, error) {
+	if !IsValidCurrency(base) {
+		return Rates{}, ErrInvalidCurrency
+	}
+
+	r := Rates{
+		base:  strings.ToUpper(base),
+		date:  date,
+		rates: map[string]float64{strings.ToUpper(base): 1},
+	}
+	for currency, rate := range rates {
+		if !IsValidCurrency(currency) {
+			return Rates{}, ErrInvalidCurrency
+		}
+		if rate <= 0 {
+			return Rates{}, errors.New("exchange rate for " + currency + " must be positive")
+		}
+		r.rates[strings.ToUpper(currency)] = rate
+	}
+
+	return r, nil
+}
+
+// LoadRates reads a rate table from a local JSON file shaped like
+//
+//	{"base": "EUR", "date": "2021-10-01", "rates": {"USD": 1.16, "CLP": 940.5}}
+func LoadRates(path string) (Rates, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Rates{}, err
+	}
+
+	var f ratesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return Rates{}, err
+	}
+
+	var date time.Time
+	if f.Date != "" {
+		if date, err = time.Parse("2006-01-02", f.Date); err != nil {
+			return Rates{}, err
+		}
+	}
+
+	return NewRates(f.Base, date, f.Rates)
+}
+
+func (r Rates) Base() string {
+	return r.base
+}
+
+func (r Rates) Date() time.Time {
+	return r.date
+}
+
+// Convert changes an amount to another currency through the base currency.
+// Amounts already in the target currency are returned untouched, even when
+// the table is empty.
+func (r Rates) Convert(m Money, to string) (Money, error) {
+	to = strings.ToUpper(to)
+	if m.Currency == to {
+		return m, nil
+	}
+
+	from, ok := r.rates[m.Currency]
+	if !ok {
+		return Money{}, ErrUnknownRate
+	}
+
+	target, ok := r.rates[to]
+	if !ok {
+		return Money{}, ErrUnknownRate
+	}
+
+	return FromMajor(m.Major()/from*target, to), nil
+}
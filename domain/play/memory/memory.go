@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/play"
+)
+
+type MemoryRepository struct {
+	plays []memoryPlay
+
+	sync.Mutex
+}
+
+type memoryPlay struct {
+	ID       uuid.UUID `db:"id"`
+	RecordID uuid.UUID `db:"record_id"`
+	SongID   uuid.UUID `db:"song_id"`
+	PlayedAt time.Time `db:"played_at"`
+}
+
+func NewFromPlay(p play.Play) memoryPlay {
+	return memoryPlay{
+		ID:       p.GetID(),
+		RecordID: p.GetRecordID(),
+		SongID:   p.GetSongID(),
+		PlayedAt: p.GetPlayedAt(),
+	}
+}
+
+func (mp memoryPlay) ToPlay() play.Play {
+	p := play.Play{}
+
+	p.SetID(mp.ID)
+	p.SetRecordID(mp.RecordID)
+	p.SetSongID(mp.SongID)
+	p.SetPlayedAt(mp.PlayedAt)
+
+	return p
+}
+
+// Create a new memory repository
+func New(ctx context.Context) (*MemoryRepository, error) {
+	return &MemoryRepository{
+		plays: make([]memoryPlay, 0),
+	}, nil
+}
+
+func (mr *MemoryRepository) Add(p play.Play) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	mr.plays = append(mr.plays, NewFromPlay(p))
+
+	return nil
+}
+
+func (mr *MemoryRepository) AddBatch(plays []play.Play) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for _, p := range plays {
+		mr.plays = append(mr.plays, NewFromPlay(p))
+	}
+
+	return nil
+}
+
+func (mr *MemoryRepository) Update(p *play.Play) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, mp := range mr.plays {
+		if mp.ID == p.GetID() {
+			mr.plays[i] = NewFromPlay(*p)
+			return nil
+		}
+	}
+
+	return play.ErrPlayNotFound
+}
+
+func (mr *MemoryRepository) FindPlays(from, to time.Time) ([]play.Play, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	var pp []play.Play
+	for _, mp := range mr.plays {
+		p := mp.ToPlay()
+		if p.IsBetween(from, to) {
+			pp = append(pp, p)
+		}
+	}
+
+	return pp, nil
+}
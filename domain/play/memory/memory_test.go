@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/play"
+)
+
+func TestMemoryRepository_FindPlays(t *testing.T) {
+	repo, _ := New(context.Background())
+
+	day := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	p1, _ := play.NewPlay(uuid.New(), uuid.Nil, day)
+	p2, _ := play.NewPlay(uuid.New(), uuid.New(), day.AddDate(0, 0, 1))
+	p3, _ := play.NewPlay(uuid.New(), uuid.Nil, day.AddDate(0, 0, 2))
+
+	repo.Add(p1)
+	repo.AddBatch([]play.Play{p2, p3})
+
+	all, _ := repo.FindPlays(time.Time{}, time.Time{})
+	if len(all) != 3 {
+		t.Errorf("Expected 3 plays, got %d", len(all))
+	}
+
+	ranged, _ := repo.FindPlays(day.AddDate(0, 0, 1), day.AddDate(0, 0, 1))
+	if len(ranged) != 1 || ranged[0].GetSongID() != p2.GetSongID() {
+		t.Errorf("Expected only %v, got %v", p2, ranged)
+	}
+}
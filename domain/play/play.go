@@ -0,0 +1,205 @@
+package play
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrMissingValues = errors.New("missing value")
+	ErrPlayNotFound  = errors.New("play not found")
+)
+
+// Play is a single listen of a whole record or of one of its songs. Song
+// plays keep the record of the song so both can be counted.
+type Play struct {
+	id       uuid.UUID
+	recordID uuid.UUID
+	songID   uuid.UUID
+	playedAt time.Time
+}
+
+type PublicPlay struct {
+	ID       uuid.UUID  `json:"id,omitempty"`
+	RecordID uuid.UUID  `json:"recordId,omitempty"`
+	SongID   *uuid.UUID `json:"songId,omitempty"`
+	PlayedAt time.Time  `json:"playedAt"`
+}
+
+func NewPlay(recordID, songID uuid.UUID, playedAt time.Time) (Play, error) {
+	return NewPlayWithID(uuid.New(), recordID, songID, playedAt)
+}
+
+func NewPlayWithID(id, recordID, songID uuid.UUID, playedAt time.Time) (Play, error) {
+	var errs validation.Errors
+
+	if recordID == uuid.Nil {
+		errs = errs.Add("recordId", validation.CodeRequired, ErrMissingValues)
+	}
+	if playedAt.IsZero() {
+		errs = errs.Add("playedAt", validation.CodeRequired, ErrMissingValues)
+	}
+	if err := errs.Err(); err != nil {
+		return Play{}, err
+	}
+
+	return Play{
+		id:       id,
+		recordID: recordID,
+		songID:   songID,
+		playedAt: playedAt,
+	}, nil
+}
+
+func (p Play) ToPublic() PublicPlay {
+	pp := PublicPlay{
+		ID:       p.GetID(),
+		RecordID: p.GetRecordID(),
+		PlayedAt: p.GetPlayedAt(),
+	}
+	if p.songID != uuid.Nil {
+		songID := p.songID
+		pp.SongID = &songID
+	}
+
+	return pp
+}
+
+func ToPublicArray(plays []Play) []PublicPlay {
+	var pp []PublicPlay
+
+	for _, p := range plays {
+		pp = append(pp, p.ToPublic())
+	}
+	return pp
+}
+
+// IsBetween reports whether the play happened in the given range. Zero
+// bounds leave that side of the range open.
+func (p Play) IsBetween(from, to time.Time) bool {
+	if !from.IsZero() && p.playedAt.Before(from) {
+		return false
+	}
+	if !to.IsZero() && p.playedAt.After(to) {
+		return false
+	}
+
+	return true
+}
+
+func (p *Play) SetID(id uuid.UUID) {
+	p.id = id
+}
+
+func (p *Play) SetRecordID(recordID uuid.UUID) {
+	p.recordID = recordID
+}
+
+func (p *Play) SetSongID(songID uuid.UUID) {
+	p.songID = songID
+}
+
+func (p *Play) SetPlayedAt(playedAt time.Time) {
+	p.playedAt = playedAt
+}
+
+func (p Play) GetID() uuid.UUID {
+	return p.id
+}
+
+func (p Play) GetRecordID() uuid.UUID {
+	return p.recordID
+}
+
+func (p Play) GetSongID() uuid.UUID {
+	return p.songID
+}
+
+func (p Play) GetPlayedAt() time.Time {
+	return p.playedAt
+}
+
+// Stats is how often and how recently something was played.
+type Stats struct {
+	Count        int       `json:"count"`
+	LastPlayedAt time.Time `json:"lastPlayedAt"`
+}
+
+func (s Stats) add(p Play) Stats {
+	s.Count++
+	if p.playedAt.After(s.LastPlayedAt) {
+		s.LastPlayedAt = p.playedAt
+	}
+	return s
+}
+
+// ByRecord sums the plays of each record, counting both whole record plays
+// and plays of any of its songs.
+func ByRecord(plays []Play) map[uuid.UUID]Stats {
+	stats := make(map[uuid.UUID]Stats)
+	for _, p := range plays {
+		stats[p.recordID] = stats[p.recordID].add(p)
+	}
+
+	return stats
+}
+
+// BySong sums the plays of each song. Whole record plays are left out.
+func BySong(plays []Play) map[uuid.UUID]Stats {
+	stats := make(map[uuid.UUID]Stats)
+	for _, p := range plays {
+		if p.songID == uuid.Nil {
+			continue
+		}
+		stats[p.songID] = stats[p.songID].add(p)
+	}
+
+	return stats
+}
+
+// Ranked is an id with its play stats.
+type Ranked struct {
+	ID uuid.UUID
+	Stats
+}
+
+// MostPlayed orders stats by play count, most recent first on ties.
+func MostPlayed(stats map[uuid.UUID]Stats) []Ranked {
+	ranked := rank(stats)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Count != ranked[j].Count {
+			return ranked[i].Count > ranked[j].Count
+		}
+		return ranked[i].LastPlayedAt.After(ranked[j].LastPlayedAt)
+	})
+
+	return ranked
+}
+
+// RecentlyPlayed orders stats by their last play, most recent first.
+func RecentlyPlayed(stats map[uuid.UUID]Stats) []Ranked {
+	ranked := rank(stats)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].LastPlayedAt.After(ranked[j].LastPlayedAt)
+	})
+
+	return ranked
+}
+
+func rank(stats map[uuid.UUID]Stats) []Ranked {
+	ranked := make([]Ranked, 0, len(stats))
+	for id, s := range stats {
+		ranked = append(ranked, Ranked{ID: id, Stats: s})
+	}
+
+	// map order is random, start from a stable order
+	sort.Slice(ranked, func(i, j int) bool {
+		return ranked[i].ID.String() < ranked[j].ID.String()
+	})
+
+	return ranked
+}
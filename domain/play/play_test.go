@@ -0,0 +1,88 @@
+package play_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/play"
+)
+
+func TestPlay_NewPlay(t *testing.T) {
+	tests := []struct {
+		name        string
+		recordID    uuid.UUID
+		playedAt    time.Time
+		expectedErr error
+	}{
+		{
+			name:     "Record play",
+			recordID: uuid.New(),
+			playedAt: time.Now(),
+		},
+		{
+			name:        "Missing record",
+			playedAt:    time.Now(),
+			expectedErr: play.ErrMissingValues,
+		},
+		{
+			name:        "Missing date",
+			recordID:    uuid.New(),
+			expectedErr: play.ErrMissingValues,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := play.NewPlay(tt.recordID, uuid.Nil, tt.playedAt)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestPlay_Stats(t *testing.T) {
+	day := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	r1, r2, r3 := uuid.New(), uuid.New(), uuid.New()
+	s1 := uuid.New()
+
+	newPlay := func(recordID, songID uuid.UUID, days int) play.Play {
+		p, _ := play.NewPlay(recordID, songID, day.AddDate(0, 0, days))
+		return p
+	}
+
+	plays := []play.Play{
+		newPlay(r1, uuid.Nil, 0),
+		newPlay(r1, s1, 1),
+		newPlay(r1, s1, 2),
+		newPlay(r2, uuid.Nil, 5),
+		newPlay(r3, uuid.Nil, 3),
+		newPlay(r3, uuid.Nil, 4),
+	}
+
+	byRecord := play.ByRecord(plays)
+	if byRecord[r1].Count != 3 || !byRecord[r1].LastPlayedAt.Equal(day.AddDate(0, 0, 2)) {
+		t.Errorf("Unexpected stats for r1: %v", byRecord[r1])
+	}
+
+	bySong := play.BySong(plays)
+	if len(bySong) != 1 || bySong[s1].Count != 2 {
+		t.Errorf("Unexpected song stats: %v", bySong)
+	}
+
+	most := play.MostPlayed(byRecord)
+	if most[0].ID != r1 || most[1].ID != r3 || most[2].ID != r2 {
+		t.Errorf("Unexpected most played order: %v", most)
+	}
+
+	recent := play.RecentlyPlayed(byRecord)
+	if recent[0].ID != r2 || recent[1].ID != r3 || recent[2].ID != r1 {
+		t.Errorf("Unexpected recently played order: %v", recent)
+	}
+
+	if plays[0].IsBetween(day.AddDate(0, 0, 1), time.Time{}) || !plays[3].IsBetween(day, day.AddDate(0, 0, 5)) {
+		t.Errorf("Unexpected range check")
+	}
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rodrwan/collection/domain/play"
+	"github.com/rodrwan/collection/pkg/pgbatch"
+)
+
+type IPostgresSQl interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+type PostgresRepository struct {
+	db IPostgresSQl
+}
+
+type (
+	SqlOpener func(string, string) (*sqlx.DB, error)
+)
+
+const insertPlayQuery = `INSERT INTO plays (id, record_id, song_id, played_at) VALUES (:id, :record_id, :song_id, :played_at)`
+
+type postgresPlay struct {
+	ID       uuid.UUID     `db:"id"`
+	RecordID uuid.UUID     `db:"record_id"`
+	SongID   uuid.NullUUID `db:"song_id"`
+	PlayedAt time.Time     `db:"played_at"`
+}
+
+func NewFromPlay(p play.Play) postgresPlay {
+	return postgresPlay{
+		ID:       p.GetID(),
+		RecordID: p.GetRecordID(),
+		SongID:   uuid.NullUUID{UUID: p.GetSongID(), Valid: p.GetSongID() != uuid.Nil},
+		PlayedAt: p.GetPlayedAt(),
+	}
+}
+
+func (pp postgresPlay) ToPlay() play.Play {
+	p := play.Play{}
+
+	p.SetID(pp.ID)
+	p.SetRecordID(pp.RecordID)
+	p.SetSongID(pp.SongID.UUID)
+	p.SetPlayedAt(pp.PlayedAt)
+
+	return p
+}
+
+// Create a new postgres repository
+func New(ctx context.Context, connectionString string, open SqlOpener) (*PostgresRepository, error) {
+	client, err := open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return &PostgresRepository{
+		db: client,
+	}, nil
+}
+
+// WithTx returns a repository that runs its statements in tx.
+func (pr *PostgresRepository) WithTx(tx *sqlx.Tx) *PostgresRepository {
+	return &PostgresRepository{
+		db: tx,
+	}
+}
+
+func (pr *PostgresRepository) Add(p play.Play) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.NamedExecContext(ctx, insertPlayQuery, NewFromPlay(p))
+	return err
+}
+
+// AddBatch inserts the plays with multi-row statements, in a transaction
+// when they take more than one, so they are stored all together or not at
+// all.
+func (pr *PostgresRepository) AddBatch(plays []play.Play) error {
+	if len(plays) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pps := make([]postgresPlay, 0, len(plays))
+	for _, p := range plays {
+		pps = append(pps, NewFromPlay(p))
+	}
+
+	return pgbatch.Insert(ctx, pr.db, insertPlayQuery, pps)
+}
+
+func (pr *PostgresRepository) Update(p *play.Play) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := pr.db.NamedExecContext(ctx, `UPDATE plays SET record_id = :record_id, song_id = :song_id, played_at = :played_at WHERE id = :id`, NewFromPlay(*p))
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return play.ErrPlayNotFound
+	}
+
+	return nil
+}
+
+func (pr *PostgresRepository) FindPlays(from, to time.Time) ([]play.Play, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	query := "SELECT * FROM plays WHERE ($1::timestamptz IS NULL OR played_at >= $1) AND ($2::timestamptz IS NULL OR played_at <= $2) ORDER BY played_at"
+
+	var pps []postgresPlay
+	if err := pr.db.SelectContext(ctx, &pps, query, nullTime(from), nullTime(to)); err != nil {
+		return []play.Play{}, err
+	}
+
+	var pp []play.Play
+	for _, p := range pps {
+		pp = append(pp, p.ToPlay())
+	}
+
+	return pp, nil
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
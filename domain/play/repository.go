@@ -0,0 +1,16 @@
+package play
+
+import (
+	"time"
+)
+
+type PlayRepository interface {
+	Add(Play) error
+	// AddBatch stores every play or none of them.
+	AddBatch([]Play) error
+	// Update changes the record or song a play is counted for.
+	Update(*Play) error
+	// FindPlays returns the plays in the given range, zero bounds leave
+	// that side open.
+	FindPlays(from, to time.Time) ([]Play, error)
+}
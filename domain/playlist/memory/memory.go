@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/playlist"
+)
+
+type MemoryRepository struct {
+	playlists []memoryPlaylist
+
+	sync.Mutex
+}
+
+type memoryPlaylist struct {
+	ID         uuid.UUID           `db:"id"`
+	Name       string              `db:"name"`
+	Duplicates playlist.Duplicates `db:"duplicates"`
+	SongIDs    []uuid.UUID         `db:"song_ids"`
+}
+
+func NewFromPlaylist(p playlist.Playlist) memoryPlaylist {
+	return memoryPlaylist{
+		ID:         p.GetID(),
+		Name:       p.GetName(),
+		Duplicates: p.GetDuplicates(),
+		SongIDs:    p.GetSongIDs(),
+	}
+}
+
+func (mp memoryPlaylist) ToPlaylist() playlist.Playlist {
+	p := playlist.Playlist{}
+
+	p.SetID(mp.ID)
+	p.SetName(mp.Name)
+	p.SetDuplicates(mp.Duplicates)
+	p.SetSongIDs(mp.SongIDs)
+
+	return p
+}
+
+// Create a new memory repository
+func New(ctx context.Context) (*MemoryRepository, error) {
+	return &MemoryRepository{
+		playlists: make([]memoryPlaylist, 0),
+	}, nil
+}
+
+func (mr *MemoryRepository) Get(id uuid.UUID) (playlist.Playlist, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for _, p := range mr.playlists {
+		if p.ID == id {
+			return p.ToPlaylist(), nil
+		}
+	}
+
+	return playlist.Playlist{}, playlist.ErrPlaylistNotFound
+}
+
+func (mr *MemoryRepository) Add(p playlist.Playlist) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	mr.playlists = append(mr.playlists, NewFromPlaylist(p))
+
+	return nil
+}
+
+func (mr *MemoryRepository) Update(p *playlist.Playlist) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, mp := range mr.playlists {
+		if mp.ID == p.GetID() {
+			mr.playlists[i] = NewFromPlaylist(*p)
+			return nil
+		}
+	}
+
+	return playlist.ErrPlaylistNotFound
+}
+
+func (mr *MemoryRepository) Delete(id uuid.UUID) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, mp := range mr.playlists {
+		if mp.ID == id {
+			mr.playlists = append(mr.playlists[:i], mr.playlists[i+1:]...)
+			return nil
+		}
+	}
+
+	return playlist.ErrPlaylistNotFound
+}
+
+func (mr *MemoryRepository) FindPlaylists() ([]playlist.Playlist, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	var pp []playlist.Playlist
+	for _, p := range mr.playlists {
+		pp = append(pp, p.ToPlaylist())
+	}
+
+	return pp, nil
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/playlist"
+)
+
+func TestMemoryRepository_Playlists(t *testing.T) {
+	repo, _ := New(context.Background())
+
+	p, _ := playlist.NewPlaylist("Sunday", playlist.DuplicatesSkip)
+	p.AddSongs(uuid.New(), uuid.New())
+	if err := repo.Add(p); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.Get(p.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.GetSongIDs()) != 2 || got.GetDuplicates() != playlist.DuplicatesSkip {
+		t.Errorf("Expected %v, got %v", p, got)
+	}
+
+	got.RemoveSong(1)
+	if err := repo.Update(&got); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = repo.Get(p.GetID())
+	if len(got.GetSongIDs()) != 1 {
+		t.Errorf("Expected 1 song after update, got %d", len(got.GetSongIDs()))
+	}
+
+	if err := repo.Delete(p.GetID()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(p.GetID()); err != playlist.ErrPlaylistNotFound {
+		t.Errorf("Expected error %v, got %v", playlist.ErrPlaylistNotFound, err)
+	}
+	if err := repo.Delete(p.GetID()); err != playlist.ErrPlaylistNotFound {
+		t.Errorf("Expected error %v, got %v", playlist.ErrPlaylistNotFound, err)
+	}
+}
@@ -0,0 +1,308 @@
+package playlist
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrMissingValues     = errors.New("missing value")
+	ErrInvalidDuplicates = errors.New("invalid duplicate policy")
+	ErrDuplicateSong     = errors.New("song already in playlist")
+	ErrInvalidPosition   = errors.New("position out of range")
+	ErrInvalidOrder      = errors.New("order must list exactly the songs of the playlist")
+	ErrPlaylistNotFound  = errors.New("playlist not found")
+)
+
+// Duplicates tells what happens when a song already in the playlist is
+// added again.
+type Duplicates string
+
+const (
+	// DuplicatesAllow keeps every copy, e.g. for a party mix.
+	DuplicatesAllow Duplicates = "allow"
+	// DuplicatesSkip silently ignores songs already in the playlist.
+	DuplicatesSkip Duplicates = "skip"
+	// DuplicatesReject fails with ErrDuplicateSong.
+	DuplicatesReject Duplicates = "reject"
+)
+
+// ParseDuplicates reads a policy ignoring case and surrounding spaces. An
+// empty value means DuplicatesAllow.
+func ParseDuplicates(value string) (Duplicates, error) {
+	switch d := Duplicates(strings.ToLower(strings.TrimSpace(value))); d {
+	case "":
+		return DuplicatesAllow, nil
+	case DuplicatesAllow, DuplicatesSkip, DuplicatesReject:
+		return d, nil
+	}
+
+	return "", ErrInvalidDuplicates
+}
+
+// Playlist is an ordered list of songs that can come from any record.
+type Playlist struct {
+	id         uuid.UUID
+	name       string
+	duplicates Duplicates
+	songIDs    []uuid.UUID
+}
+
+// PlaylistSong is an entry of a playlist with the details of its song.
+// Positions count from 1.
+type PlaylistSong struct {
+	Position int       `json:"position"`
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Length   int64     `json:"length"`
+	RecordID uuid.UUID `json:"recordId"`
+}
+
+type PublicPlaylist struct {
+	ID          uuid.UUID      `json:"id,omitempty"`
+	Name        string         `json:"name,omitempty"`
+	Duplicates  Duplicates     `json:"duplicates,omitempty"`
+	SongIDs     []uuid.UUID    `json:"songIds"`
+	Songs       []PlaylistSong `json:"songs,omitempty"`
+	TotalLength int64          `json:"totalLength"`
+}
+
+func NewPlaylist(name string, duplicates Duplicates) (Playlist, error) {
+	return NewPlaylistWithID(uuid.New(), name, duplicates)
+}
+
+func NewPlaylistWithID(id uuid.UUID, name string, duplicates Duplicates) (Playlist, error) {
+	if err := validate(name, duplicates); err != nil {
+		return Playlist{}, err
+	}
+
+	if duplicates == "" {
+		duplicates = DuplicatesAllow
+	}
+
+	return Playlist{
+		id:         id,
+		name:       strings.TrimSpace(name),
+		duplicates: duplicates,
+		songIDs:    make([]uuid.UUID, 0),
+	}, nil
+}
+
+// validate reports every invalid field of a playlist as validation.Errors.
+func validate(name string, duplicates Duplicates) error {
+	var errs validation.Errors
+
+	if strings.TrimSpace(name) == "" {
+		errs = errs.Add("name", validation.CodeRequired, ErrMissingValues)
+	}
+
+	if _, err := ParseDuplicates(string(duplicates)); err != nil {
+		errs = errs.Add("duplicates", validation.CodeInvalid, err)
+	}
+
+	return errs.Err()
+}
+
+// ToPublic returns the playlist with its song ids only. Use WithSongs to
+// fill in the song details and the total length.
+func (p Playlist) ToPublic() PublicPlaylist {
+	return PublicPlaylist{
+		ID:         p.GetID(),
+		Name:       p.GetName(),
+		Duplicates: p.GetDuplicates(),
+		SongIDs:    p.GetSongIDs(),
+	}
+}
+
+// WithSongs returns the public playlist with the details of each entry and
+// the total length. Songs missing from the given list are left out.
+func (p Playlist) WithSongs(songs []song.Song) PublicPlaylist {
+	byID := make(map[uuid.UUID]song.Song, len(songs))
+	for _, s := range songs {
+		byID[s.GetID()] = s
+	}
+
+	pp := p.ToPublic()
+	for i, id := range p.songIDs {
+		s, ok := byID[id]
+		if !ok {
+			continue
+		}
+		pp.Songs = append(pp.Songs, PlaylistSong{
+			Position: i + 1,
+			ID:       id,
+			Name:     s.GetName(),
+			Length:   s.GetLength(),
+			RecordID: s.GetRecordID(),
+		})
+		pp.TotalLength += s.GetLength()
+	}
+
+	return pp
+}
+
+func ToPublicArray(playlists []Playlist) []PublicPlaylist {
+	var pp []PublicPlaylist
+
+	for _, p := range playlists {
+		pp = append(pp, p.ToPublic())
+	}
+	return pp
+}
+
+// Edit renames the playlist and changes its duplicate policy, an empty one
+// leaves it unchanged. The policy applies to songs added from then on,
+// entries already there are kept.
+func (p *Playlist) Edit(name string, duplicates Duplicates) error {
+	if err := validate(name, duplicates); err != nil {
+		return err
+	}
+
+	if duplicates == "" {
+		duplicates = p.duplicates
+	}
+
+	p.name = strings.TrimSpace(name)
+	p.duplicates = duplicates
+	return nil
+}
+
+// AddSongs appends songs following the duplicate policy of the playlist and
+// returns how many were added. With DuplicatesReject nothing is added if
+// any of the songs is already there.
+func (p *Playlist) AddSongs(ids ...uuid.UUID) (int, error) {
+	seen := make(map[uuid.UUID]bool, len(p.songIDs)+len(ids))
+	for _, id := range p.songIDs {
+		seen[id] = true
+	}
+
+	var added []uuid.UUID
+	for _, id := range ids {
+		if seen[id] {
+			switch p.duplicates {
+			case DuplicatesSkip:
+				continue
+			case DuplicatesReject:
+				return 0, ErrDuplicateSong
+			}
+		}
+		seen[id] = true
+		added = append(added, id)
+	}
+
+	p.songIDs = append(p.songIDs, added...)
+	return len(added), nil
+}
+
+// ReplaceSong points the entries of song from at song to, e.g. when the
+// records they belong to are merged, and returns how many entries changed.
+// Unless the playlist allows duplicates, entries that would repeat song to
+// are removed instead.
+func (p *Playlist) ReplaceSong(from, to uuid.UUID) int {
+	seen := false
+	for _, id := range p.songIDs {
+		if id == to {
+			seen = true
+		}
+	}
+
+	ids := make([]uuid.UUID, 0, len(p.songIDs))
+	changed := 0
+	for _, id := range p.songIDs {
+		if id == from {
+			changed++
+			if seen && p.duplicates != DuplicatesAllow {
+				continue
+			}
+			id, seen = to, true
+		}
+		ids = append(ids, id)
+	}
+
+	p.songIDs = ids
+	return changed
+}
+
+// RemoveSong removes the entry at the given position, counting from 1.
+func (p *Playlist) RemoveSong(position int) error {
+	if position < 1 || position > len(p.songIDs) {
+		return ErrInvalidPosition
+	}
+
+	p.songIDs = append(p.songIDs[:position-1], p.songIDs[position:]...)
+	return nil
+}
+
+// Move moves the entry at position from to position to, shifting the
+// entries in between. Positions count from 1.
+func (p *Playlist) Move(from, to int) error {
+	if from < 1 || from > len(p.songIDs) || to < 1 || to > len(p.songIDs) {
+		return ErrInvalidPosition
+	}
+
+	id := p.songIDs[from-1]
+	ids := append(append([]uuid.UUID(nil), p.songIDs[:from-1]...), p.songIDs[from:]...)
+	ids = append(ids[:to-1], append([]uuid.UUID{id}, ids[to-1:]...)...)
+
+	p.songIDs = ids
+	return nil
+}
+
+// Reorder replaces the order of the playlist. The new order must hold the
+// same songs, repeated the same number of times.
+func (p *Playlist) Reorder(ids []uuid.UUID) error {
+	if len(ids) != len(p.songIDs) {
+		return ErrInvalidOrder
+	}
+
+	counts := make(map[uuid.UUID]int, len(ids))
+	for _, id := range p.songIDs {
+		counts[id]++
+	}
+	for _, id := range ids {
+		counts[id]--
+		if counts[id] < 0 {
+			return ErrInvalidOrder
+		}
+	}
+
+	p.songIDs = append([]uuid.UUID(nil), ids...)
+	return nil
+}
+
+func (p *Playlist) SetID(id uuid.UUID) {
+	p.id = id
+}
+
+func (p *Playlist) SetName(name string) {
+	p.name = name
+}
+
+func (p *Playlist) SetDuplicates(duplicates Duplicates) {
+	p.duplicates = duplicates
+}
+
+func (p *Playlist) SetSongIDs(ids []uuid.UUID) {
+	p.songIDs = append([]uuid.UUID(nil), ids...)
+}
+
+func (p Playlist) GetID() uuid.UUID {
+	return p.id
+}
+
+func (p Playlist) GetName() string {
+	return p.name
+}
+
+func (p Playlist) GetDuplicates() Duplicates {
+	return p.duplicates
+}
+
+// GetSongIDs returns a copy of the ordered song ids.
+func (p Playlist) GetSongIDs() []uuid.UUID {
+	return append([]uuid.UUID{}, p.songIDs...)
+}
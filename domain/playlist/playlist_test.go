@@ -0,0 +1,200 @@
+package playlist_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/playlist"
+	"github.com/rodrwan/collection/domain/song"
+)
+
+func TestPlaylist_NewPlaylist(t *testing.T) {
+	tests := []struct {
+		name        string
+		plName      string
+		duplicates  playlist.Duplicates
+		expectedErr error
+	}{
+		{
+			name:   "Default duplicate policy",
+			plName: "Sunday",
+		},
+		{
+			name:       "Reject duplicates",
+			plName:     "Sunday",
+			duplicates: playlist.DuplicatesReject,
+		},
+		{
+			name:        "Missing name",
+			plName:      "  ",
+			expectedErr: playlist.ErrMissingValues,
+		},
+		{
+			name:        "Invalid duplicate policy",
+			plName:      "Sunday",
+			duplicates:  playlist.Duplicates("merge"),
+			expectedErr: playlist.ErrInvalidDuplicates,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := playlist.NewPlaylist(tt.plName, tt.duplicates)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err == nil && p.GetDuplicates() == "" {
+				t.Errorf("Expected a duplicate policy to be set")
+			}
+		})
+	}
+}
+
+func TestPlaylist_AddSongs(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name          string
+		duplicates    playlist.Duplicates
+		expectedIDs   []uuid.UUID
+		expectedAdded int
+		expectedErr   error
+	}{
+		{
+			name:          "Allow",
+			duplicates:    playlist.DuplicatesAllow,
+			expectedIDs:   []uuid.UUID{a, a, b, a},
+			expectedAdded: 3,
+		},
+		{
+			name:          "Skip",
+			duplicates:    playlist.DuplicatesSkip,
+			expectedIDs:   []uuid.UUID{a, b},
+			expectedAdded: 1,
+		},
+		{
+			name:        "Reject",
+			duplicates:  playlist.DuplicatesReject,
+			expectedIDs: []uuid.UUID{a},
+			expectedErr: playlist.ErrDuplicateSong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, _ := playlist.NewPlaylist("Sunday", tt.duplicates)
+			p.AddSongs(a)
+
+			added, err := p.AddSongs(a, b, a)
+			if err != tt.expectedErr {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if added != tt.expectedAdded {
+				t.Errorf("Expected %d songs added, got %d", tt.expectedAdded, added)
+			}
+
+			ids := p.GetSongIDs()
+			if len(ids) != len(tt.expectedIDs) {
+				t.Fatalf("Expected %v, got %v", tt.expectedIDs, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.expectedIDs[i] {
+					t.Errorf("Expected %v, got %v", tt.expectedIDs, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaylist_Order(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	p, _ := playlist.NewPlaylist("Sunday", playlist.DuplicatesAllow)
+	p.AddSongs(a, b, c, a)
+
+	if err := p.Move(4, 2); err != nil {
+		t.Fatal(err)
+	}
+	assertOrder(t, p, a, a, b, c)
+
+	if err := p.Move(0, 2); err != playlist.ErrInvalidPosition {
+		t.Errorf("Expected error %v, got %v", playlist.ErrInvalidPosition, err)
+	}
+
+	if err := p.Reorder([]uuid.UUID{c, a, b, b}); err != playlist.ErrInvalidOrder {
+		t.Errorf("Expected error %v, got %v", playlist.ErrInvalidOrder, err)
+	}
+	if err := p.Reorder([]uuid.UUID{c, a, b}); err != playlist.ErrInvalidOrder {
+		t.Errorf("Expected error %v, got %v", playlist.ErrInvalidOrder, err)
+	}
+	if err := p.Reorder([]uuid.UUID{c, a, b, a}); err != nil {
+		t.Fatal(err)
+	}
+	assertOrder(t, p, c, a, b, a)
+
+	if err := p.RemoveSong(2); err != nil {
+		t.Fatal(err)
+	}
+	assertOrder(t, p, c, b, a)
+
+	if err := p.RemoveSong(4); err != playlist.ErrInvalidPosition {
+		t.Errorf("Expected error %v, got %v", playlist.ErrInvalidPosition, err)
+	}
+}
+
+func TestPlaylist_ReplaceSong(t *testing.T) {
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	p, _ := playlist.NewPlaylist("Sunday", playlist.DuplicatesAllow)
+	p.AddSongs(a, b, a, c)
+	if got := p.ReplaceSong(a, c); got != 2 {
+		t.Errorf("Expected 2 entries replaced, got %d", got)
+	}
+	assertOrder(t, p, c, b, c, c)
+
+	p, _ = playlist.NewPlaylist("Monday", playlist.DuplicatesSkip)
+	p.AddSongs(a, b, c)
+	if got := p.ReplaceSong(a, c); got != 1 {
+		t.Errorf("Expected 1 entry replaced, got %d", got)
+	}
+	assertOrder(t, p, b, c)
+
+	if got := p.ReplaceSong(a, c); got != 0 {
+		t.Errorf("Expected no entry replaced, got %d", got)
+	}
+}
+
+func TestPlaylist_WithSongs(t *testing.T) {
+	recordID := uuid.New()
+	s1, _ := song.NewSong("s1", 200, recordID)
+	s2, _ := song.NewSong("s2", 150, uuid.New())
+
+	p, _ := playlist.NewPlaylist("Sunday", playlist.DuplicatesAllow)
+	p.AddSongs(s1.GetID(), s2.GetID(), s1.GetID(), uuid.New())
+
+	pp := p.WithSongs([]song.Song{s1, s2})
+	if pp.TotalLength != 550 {
+		t.Errorf("Expected total length 550, got %d", pp.TotalLength)
+	}
+	if len(pp.Songs) != 3 || pp.Songs[2].Position != 3 || pp.Songs[2].RecordID != recordID {
+		t.Errorf("Unexpected songs %v", pp.Songs)
+	}
+	if len(pp.SongIDs) != 4 {
+		t.Errorf("Expected 4 song ids, got %d", len(pp.SongIDs))
+	}
+}
+
+func assertOrder(t *testing.T, p playlist.Playlist, expected ...uuid.UUID) {
+	t.Helper()
+
+	ids := p.GetSongIDs()
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, ids)
+	}
+	for i := range ids {
+		if ids[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, ids)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rodrwan/collection/domain/playlist"
+)
+
+type IPostgresSQl interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+type PostgresRepository struct {
+	db IPostgresSQl
+}
+
+type (
+	SqlOpener func(string, string) (*sqlx.DB, error)
+)
+
+// postgresPlaylist keeps the ordered song ids in a text[] column so repeated
+// songs keep their place.
+type postgresPlaylist struct {
+	ID         uuid.UUID      `db:"id"`
+	Name       string         `db:"name"`
+	Duplicates string         `db:"duplicates"`
+	SongIDs    pq.StringArray `db:"song_ids"`
+}
+
+func NewFromPlaylist(p playlist.Playlist) postgresPlaylist {
+	ids := p.GetSongIDs()
+	songIDs := make(pq.StringArray, 0, len(ids))
+	for _, id := range ids {
+		songIDs = append(songIDs, id.String())
+	}
+
+	return postgresPlaylist{
+		ID:         p.GetID(),
+		Name:       p.GetName(),
+		Duplicates: string(p.GetDuplicates()),
+		SongIDs:    songIDs,
+	}
+}
+
+func (pp postgresPlaylist) ToPlaylist() (playlist.Playlist, error) {
+	p := playlist.Playlist{}
+
+	ids := make([]uuid.UUID, 0, len(pp.SongIDs))
+	for _, s := range pp.SongIDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return playlist.Playlist{}, err
+		}
+		ids = append(ids, id)
+	}
+
+	p.SetID(pp.ID)
+	p.SetName(pp.Name)
+	p.SetDuplicates(playlist.Duplicates(pp.Duplicates))
+	p.SetSongIDs(ids)
+
+	return p, nil
+}
+
+// Create a new postgres repository
+func New(ctx context.Context, connectionString string, open SqlOpener) (*PostgresRepository, error) {
+	client, err := open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return &PostgresRepository{
+		db: client,
+	}, nil
+}
+
+// WithTx returns a repository that runs its statements in tx.
+func (pr *PostgresRepository) WithTx(tx *sqlx.Tx) *PostgresRepository {
+	return &PostgresRepository{
+		db: tx,
+	}
+}
+
+func (pr *PostgresRepository) Get(id uuid.UUID) (playlist.Playlist, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var p postgresPlaylist
+	if err := pr.db.GetContext(ctx, &p, "SELECT * FROM playlists WHERE id = $1", id); err != nil {
+		if err == sql.ErrNoRows {
+			return playlist.Playlist{}, playlist.ErrPlaylistNotFound
+		}
+		return playlist.Playlist{}, err
+	}
+
+	return p.ToPlaylist()
+}
+
+func (pr *PostgresRepository) Add(p playlist.Playlist) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.NamedExecContext(ctx, `INSERT INTO playlists (id, name, duplicates, song_ids) VALUES (:id, :name, :duplicates, :song_ids)`, NewFromPlaylist(p))
+	return err
+}
+
+func (pr *PostgresRepository) Update(p *playlist.Playlist) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := pr.db.NamedExecContext(ctx, `UPDATE playlists SET name = :name, duplicates = :duplicates, song_ids = :song_ids WHERE id = :id`, NewFromPlaylist(*p))
+	if err != nil {
+		return err
+	}
+
+	return notFoundIfNone(res)
+}
+
+func (pr *PostgresRepository) Delete(id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := pr.db.NamedExecContext(ctx, `DELETE FROM playlists WHERE id = :id`, map[string]interface{}{"id": id})
+	if err != nil {
+		return err
+	}
+
+	return notFoundIfNone(res)
+}
+
+func (pr *PostgresRepository) FindPlaylists() ([]playlist.Playlist, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var pps []postgresPlaylist
+	if err := pr.db.SelectContext(ctx, &pps, "SELECT * FROM playlists ORDER BY name"); err != nil {
+		return []playlist.Playlist{}, err
+	}
+
+	var pp []playlist.Playlist
+	for _, p := range pps {
+		pl, err := p.ToPlaylist()
+		if err != nil {
+			return []playlist.Playlist{}, err
+		}
+		pp = append(pp, pl)
+	}
+
+	return pp, nil
+}
+
+func notFoundIfNone(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return playlist.ErrPlaylistNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package playlist
+
+import (
+	"github.com/google/uuid"
+)
+
+type PlaylistRepository interface {
+	Get(uuid.UUID) (Playlist, error)
+	Add(Playlist) error
+	Update(*Playlist) error
+	Delete(uuid.UUID) error
+	FindPlaylists() ([]Playlist, error)
+}
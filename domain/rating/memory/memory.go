@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/rating"
+)
+
+type MemoryRepository struct {
+	reviews []memoryReview
+
+	sync.Mutex
+}
+
+type memoryReview struct {
+	ID        uuid.UUID      `db:"id"`
+	User      string         `db:"user_name"`
+	Subject   rating.Subject `db:"subject"`
+	SubjectID uuid.UUID      `db:"subject_id"`
+	Stars     float64        `db:"stars"`
+	Text      string         `db:"text"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+func NewFromReview(r rating.Review) memoryReview {
+	return memoryReview{
+		ID:        r.GetID(),
+		User:      r.GetUser(),
+		Subject:   r.GetSubject(),
+		SubjectID: r.GetSubjectID(),
+		Stars:     r.GetStars(),
+		Text:      r.GetText(),
+		UpdatedAt: r.GetUpdatedAt(),
+	}
+}
+
+func (mr memoryReview) ToReview() rating.Review {
+	r := rating.Review{}
+
+	r.SetID(mr.ID)
+	r.SetUser(mr.User)
+	r.SetSubject(mr.Subject)
+	r.SetSubjectID(mr.SubjectID)
+	r.SetStars(mr.Stars)
+	r.SetText(mr.Text)
+	r.SetUpdatedAt(mr.UpdatedAt)
+
+	return r
+}
+
+// Create a new memory repository
+func New(ctx context.Context) (*MemoryRepository, error) {
+	return &MemoryRepository{
+		reviews: make([]memoryReview, 0),
+	}, nil
+}
+
+func (mr *MemoryRepository) Save(r rating.Review) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	internal := NewFromReview(r)
+	for i, existing := range mr.reviews {
+		if existing.User == internal.User && existing.Subject == internal.Subject && existing.SubjectID == internal.SubjectID {
+			// keep the id of the first review so it stays stable
+			internal.ID = existing.ID
+			mr.reviews[i] = internal
+			return nil
+		}
+	}
+
+	mr.reviews = append(mr.reviews, internal)
+
+	return nil
+}
+
+func (mr *MemoryRepository) Update(r *rating.Review) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, existing := range mr.reviews {
+		if existing.ID == r.GetID() {
+			mr.reviews[i] = NewFromReview(*r)
+			return nil
+		}
+	}
+
+	return rating.ErrReviewNotFound
+}
+
+func (mr *MemoryRepository) Delete(id uuid.UUID) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, existing := range mr.reviews {
+		if existing.ID == id {
+			mr.reviews = append(mr.reviews[:i], mr.reviews[i+1:]...)
+			return nil
+		}
+	}
+
+	return rating.ErrReviewNotFound
+}
+
+func (mr *MemoryRepository) FindBySubject(subject rating.Subject, id uuid.UUID) ([]rating.Review, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	var rr []rating.Review
+	for _, r := range mr.reviews {
+		if r.Subject == subject && r.SubjectID == id {
+			rr = append(rr, r.ToReview())
+		}
+	}
+
+	return rr, nil
+}
+
+func (mr *MemoryRepository) FindReviews(subject rating.Subject) ([]rating.Review, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	var rr []rating.Review
+	for _, r := range mr.reviews {
+		if r.Subject == subject {
+			rr = append(rr, r.ToReview())
+		}
+	}
+
+	return rr, nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/rating"
+)
+
+func TestMemoryRepository_Save(t *testing.T) {
+	repo, _ := New(context.Background())
+
+	recordID := uuid.New()
+	first, _ := rating.NewReview("Percy", rating.SubjectRecord, recordID, 3, "ok", time.Now())
+	second, _ := rating.NewReview("percy ", rating.SubjectRecord, recordID, 4.5, "grew on me", time.Now())
+	other, _ := rating.NewReview("rodrigo", rating.SubjectRecord, recordID, 2, "", time.Now())
+	song, _ := rating.NewReview("percy", rating.SubjectSong, recordID, 1, "", time.Now())
+
+	for _, r := range []rating.Review{first, second, other, song} {
+		if err := repo.Save(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reviews, _ := repo.FindBySubject(rating.SubjectRecord, recordID)
+	if len(reviews) != 2 {
+		t.Fatalf("Expected 2 reviews, got %d", len(reviews))
+	}
+	if reviews[0].GetID() != first.GetID() || reviews[0].GetStars() != 4.5 {
+		t.Errorf("Expected the first review to be replaced, got %v", reviews[0])
+	}
+
+	songs, _ := repo.FindReviews(rating.SubjectSong)
+	if len(songs) != 1 {
+		t.Errorf("Expected 1 song review, got %d", len(songs))
+	}
+}
+
+func TestMemoryRepository_UpdateDelete(t *testing.T) {
+	repo, _ := New(context.Background())
+
+	from, to := uuid.New(), uuid.New()
+	review, _ := rating.NewReview("percy", rating.SubjectRecord, from, 3, "", time.Now())
+	repo.Save(review)
+
+	review.SetSubjectID(to)
+	if err := repo.Update(&review); err != nil {
+		t.Fatal(err)
+	}
+	if reviews, _ := repo.FindBySubject(rating.SubjectRecord, from); len(reviews) != 0 {
+		t.Errorf("Expected the review to move, got %v", reviews)
+	}
+	if reviews, _ := repo.FindBySubject(rating.SubjectRecord, to); len(reviews) != 1 {
+		t.Errorf("Expected 1 review, got %d", len(reviews))
+	}
+
+	if err := repo.Delete(review.GetID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Delete(review.GetID()); err != rating.ErrReviewNotFound {
+		t.Errorf("Expected error %v, got %v", rating.ErrReviewNotFound, err)
+	}
+	if err := repo.Update(&review); err != rating.ErrReviewNotFound {
+		t.Errorf("Expected error %v, got %v", rating.ErrReviewNotFound, err)
+	}
+}
@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rodrwan/collection/domain/rating"
+)
+
+type IPostgresSQl interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+type PostgresRepository struct {
+	db IPostgresSQl
+}
+
+type (
+	SqlOpener func(string, string) (*sqlx.DB, error)
+)
+
+type postgresReview struct {
+	ID        uuid.UUID `db:"id"`
+	User      string    `db:"user_name"`
+	Subject   string    `db:"subject"`
+	SubjectID uuid.UUID `db:"subject_id"`
+	Stars     float64   `db:"stars"`
+	Text      string    `db:"text"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func NewFromReview(r rating.Review) postgresReview {
+	return postgresReview{
+		ID:        r.GetID(),
+		User:      r.GetUser(),
+		Subject:   string(r.GetSubject()),
+		SubjectID: r.GetSubjectID(),
+		Stars:     r.GetStars(),
+		Text:      r.GetText(),
+		UpdatedAt: r.GetUpdatedAt(),
+	}
+}
+
+func (pr postgresReview) ToReview() rating.Review {
+	r := rating.Review{}
+
+	r.SetID(pr.ID)
+	r.SetUser(pr.User)
+	r.SetSubject(rating.Subject(pr.Subject))
+	r.SetSubjectID(pr.SubjectID)
+	r.SetStars(pr.Stars)
+	r.SetText(pr.Text)
+	r.SetUpdatedAt(pr.UpdatedAt)
+
+	return r
+}
+
+// Create a new postgres repository
+func New(ctx context.Context, connectionString string, open SqlOpener) (*PostgresRepository, error) {
+	client, err := open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return &PostgresRepository{
+		db: client,
+	}, nil
+}
+
+// WithTx returns a repository that runs its statements in tx.
+func (pr *PostgresRepository) WithTx(tx *sqlx.Tx) *PostgresRepository {
+	return &PostgresRepository{
+		db: tx,
+	}
+}
+
+// Save relies on the unique index on reviews (user_name, subject,
+// subject_id) to replace an earlier review of the same user.
+func (pr *PostgresRepository) Save(r rating.Review) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.NamedExecContext(ctx, `INSERT INTO reviews (id, user_name, subject, subject_id, stars, text, updated_at) VALUES (:id, :user_name, :subject, :subject_id, :stars, :text, :updated_at)
+		ON CONFLICT (user_name, subject, subject_id) DO UPDATE SET stars = EXCLUDED.stars, text = EXCLUDED.text, updated_at = EXCLUDED.updated_at`, NewFromReview(r))
+	return err
+}
+
+func (pr *PostgresRepository) Update(r *rating.Review) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := pr.db.NamedExecContext(ctx, `UPDATE reviews SET user_name = :user_name, subject = :subject, subject_id = :subject_id, stars = :stars, text = :text, updated_at = :updated_at WHERE id = :id`, NewFromReview(*r))
+	if err != nil {
+		return err
+	}
+
+	return notFoundIfNone(res)
+}
+
+func (pr *PostgresRepository) Delete(id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := pr.db.NamedExecContext(ctx, `DELETE FROM reviews WHERE id = :id`, map[string]interface{}{"id": id})
+	if err != nil {
+		return err
+	}
+
+	return notFoundIfNone(res)
+}
+
+func notFoundIfNone(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return rating.ErrReviewNotFound
+	}
+
+	return nil
+}
+
+func (pr *PostgresRepository) FindBySubject(subject rating.Subject, id uuid.UUID) ([]rating.Review, error) {
+	return pr.find("SELECT * FROM reviews WHERE subject = $1 AND subject_id = $2 ORDER BY updated_at DESC", string(subject), id)
+}
+
+func (pr *PostgresRepository) FindReviews(subject rating.Subject) ([]rating.Review, error) {
+	return pr.find("SELECT * FROM reviews WHERE subject = $1", string(subject))
+}
+
+func (pr *PostgresRepository) find(query string, args ...interface{}) ([]rating.Review, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var prs []postgresReview
+	if err := pr.db.SelectContext(ctx, &prs, query, args...); err != nil {
+		return []rating.Review{}, err
+	}
+
+	var rr []rating.Review
+	for _, r := range prs {
+		rr = append(rr, r.ToReview())
+	}
+
+	return rr, nil
+}
@@ -0,0 +1,210 @@
+package rating
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrMissingValues  = errors.New("missing value")
+	ErrInvalidStars   = errors.New("stars must be between 1 and 5 in half star steps")
+	ErrInvalidSubject = errors.New("invalid review subject")
+	ErrReviewTooLong  = errors.New("review is too long")
+	ErrReviewNotFound = errors.New("review not found")
+)
+
+// MaxReviewLength is the longest review text accepted, in characters.
+const MaxReviewLength = 1000
+
+// Subject is what a review is about.
+type Subject string
+
+const (
+	SubjectRecord Subject = "record"
+	SubjectSong   Subject = "song"
+)
+
+// Review is the rating, and optionally a short text, a user gives a record
+// or a song. Each user has at most one review per subject.
+type Review struct {
+	id        uuid.UUID
+	user      string
+	subject   Subject
+	subjectID uuid.UUID
+	stars     float64
+	text      string
+	updatedAt time.Time
+}
+
+type PublicReview struct {
+	ID        uuid.UUID `json:"id,omitempty"`
+	User      string    `json:"user,omitempty"`
+	Subject   Subject   `json:"subject,omitempty"`
+	SubjectID uuid.UUID `json:"subjectId,omitempty"`
+	Stars     float64   `json:"stars"`
+	Text      string    `json:"text,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func NewReview(user string, subject Subject, subjectID uuid.UUID, stars float64, text string, at time.Time) (Review, error) {
+	return NewReviewWithID(uuid.New(), user, subject, subjectID, stars, text, at)
+}
+
+func NewReviewWithID(id uuid.UUID, user string, subject Subject, subjectID uuid.UUID, stars float64, text string, at time.Time) (Review, error) {
+	var errs validation.Errors
+
+	user = NormalizeUser(user)
+	if user == "" {
+		errs = errs.Add("user", validation.CodeRequired, ErrMissingValues)
+	}
+
+	if subject != SubjectRecord && subject != SubjectSong {
+		errs = errs.Add("subject", validation.CodeInvalid, ErrInvalidSubject)
+	}
+	if subjectID == uuid.Nil {
+		errs = errs.Add("subjectId", validation.CodeRequired, ErrMissingValues)
+	}
+
+	if !IsValidStars(stars) {
+		errs = errs.Add("stars", validation.CodeOutOfRange, ErrInvalidStars)
+	}
+
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) > MaxReviewLength {
+		errs = errs.Add("text", validation.CodeOutOfRange, ErrReviewTooLong)
+	}
+
+	if err := errs.Err(); err != nil {
+		return Review{}, err
+	}
+
+	return Review{
+		id:        id,
+		user:      user,
+		subject:   subject,
+		subjectID: subjectID,
+		stars:     stars,
+		text:      text,
+		updatedAt: at,
+	}, nil
+}
+
+// NormalizeUser trims and lowercases a user name so reviews of the same
+// user are found regardless of how it was typed.
+func NormalizeUser(user string) string {
+	return strings.ToLower(strings.TrimSpace(user))
+}
+
+// IsValidStars reports whether stars is 1 to 5 in half star steps.
+func IsValidStars(stars float64) bool {
+	return stars >= 1 && stars <= 5 && stars*2 == math.Trunc(stars*2)
+}
+
+func (r Review) ToPublic() PublicReview {
+	return PublicReview{
+		ID:        r.GetID(),
+		User:      r.GetUser(),
+		Subject:   r.GetSubject(),
+		SubjectID: r.GetSubjectID(),
+		Stars:     r.GetStars(),
+		Text:      r.GetText(),
+		UpdatedAt: r.GetUpdatedAt(),
+	}
+}
+
+func ToPublicArray(reviews []Review) []PublicReview {
+	var rr []PublicReview
+
+	for _, r := range reviews {
+		rr = append(rr, r.ToPublic())
+	}
+	return rr
+}
+
+func (r *Review) SetID(id uuid.UUID) {
+	r.id = id
+}
+
+func (r *Review) SetUser(user string) {
+	r.user = user
+}
+
+func (r *Review) SetSubject(subject Subject) {
+	r.subject = subject
+}
+
+func (r *Review) SetSubjectID(subjectID uuid.UUID) {
+	r.subjectID = subjectID
+}
+
+func (r *Review) SetStars(stars float64) {
+	r.stars = stars
+}
+
+func (r *Review) SetText(text string) {
+	r.text = text
+}
+
+func (r *Review) SetUpdatedAt(updatedAt time.Time) {
+	r.updatedAt = updatedAt
+}
+
+func (r Review) GetID() uuid.UUID {
+	return r.id
+}
+
+func (r Review) GetUser() string {
+	return r.user
+}
+
+func (r Review) GetSubject() Subject {
+	return r.subject
+}
+
+func (r Review) GetSubjectID() uuid.UUID {
+	return r.subjectID
+}
+
+func (r Review) GetStars() float64 {
+	return r.stars
+}
+
+func (r Review) GetText() string {
+	return r.text
+}
+
+func (r Review) GetUpdatedAt() time.Time {
+	return r.updatedAt
+}
+
+// Summary is the average rating of a subject over every user.
+type Summary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// Summarize averages the reviews of each subject, rounding to two decimals.
+func Summarize(reviews []Review) map[uuid.UUID]Summary {
+	totals := make(map[uuid.UUID]float64)
+	counts := make(map[uuid.UUID]int)
+	for _, r := range reviews {
+		totals[r.subjectID] += r.stars
+		counts[r.subjectID]++
+	}
+
+	summaries := make(map[uuid.UUID]Summary, len(counts))
+	for id, count := range counts {
+		summaries[id] = Summary{
+			Average: math.Round(totals[id]/float64(count)*100) / 100,
+			Count:   count,
+		}
+	}
+
+	return summaries
+}
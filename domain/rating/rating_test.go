@@ -0,0 +1,109 @@
+package rating_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/rating"
+)
+
+func TestRating_NewReview(t *testing.T) {
+	tests := []struct {
+		name        string
+		user        string
+		subject     rating.Subject
+		stars       float64
+		text        string
+		expectedErr error
+	}{
+		{
+			name:    "Whole stars",
+			user:    "percy",
+			subject: rating.SubjectRecord,
+			stars:   4,
+		},
+		{
+			name:    "Half stars with review",
+			user:    "percy",
+			subject: rating.SubjectSong,
+			stars:   3.5,
+			text:    "Great bass line",
+		},
+		{
+			name:        "Missing user",
+			subject:     rating.SubjectRecord,
+			stars:       4,
+			expectedErr: rating.ErrMissingValues,
+		},
+		{
+			name:        "Too many stars",
+			user:        "percy",
+			subject:     rating.SubjectRecord,
+			stars:       5.5,
+			expectedErr: rating.ErrInvalidStars,
+		},
+		{
+			name:        "Quarter stars",
+			user:        "percy",
+			subject:     rating.SubjectRecord,
+			stars:       3.25,
+			expectedErr: rating.ErrInvalidStars,
+		},
+		{
+			name:        "Zero stars",
+			user:        "percy",
+			subject:     rating.SubjectRecord,
+			expectedErr: rating.ErrInvalidStars,
+		},
+		{
+			name:        "Review too long",
+			user:        "percy",
+			subject:     rating.SubjectRecord,
+			stars:       1,
+			text:        strings.Repeat("a", rating.MaxReviewLength+1),
+			expectedErr: rating.ErrReviewTooLong,
+		},
+		{
+			name:        "Unknown subject",
+			user:        "percy",
+			subject:     rating.Subject("artist"),
+			stars:       1,
+			expectedErr: rating.ErrInvalidSubject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := rating.NewReview(tt.user, tt.subject, uuid.New(), tt.stars, tt.text, time.Now())
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestRating_Summarize(t *testing.T) {
+	a, b := uuid.New(), uuid.New()
+
+	newReview := func(user string, id uuid.UUID, stars float64) rating.Review {
+		r, _ := rating.NewReview(user, rating.SubjectRecord, id, stars, "", time.Now())
+		return r
+	}
+
+	summaries := rating.Summarize([]rating.Review{
+		newReview("percy", a, 4),
+		newReview("rodrigo", a, 3.5),
+		newReview("ana", a, 5),
+		newReview("percy", b, 2),
+	})
+
+	if s := summaries[a]; s.Count != 3 || s.Average != 4.17 {
+		t.Errorf("Unexpected summary %v", s)
+	}
+	if s := summaries[b]; s.Count != 1 || s.Average != 2 {
+		t.Errorf("Unexpected summary %v", s)
+	}
+}
@@ -0,0 +1,17 @@
+package rating
+
+import (
+	"github.com/google/uuid"
+)
+
+type ReviewRepository interface {
+	// Save stores a review, replacing the review the same user already
+	// wrote about the same subject.
+	Save(Review) error
+	// Update rewrites the review with the same id, e.g. to move it to
+	// another subject.
+	Update(*Review) error
+	Delete(uuid.UUID) error
+	FindBySubject(Subject, uuid.UUID) ([]Review, error)
+	FindReviews(Subject) ([]Review, error)
+}
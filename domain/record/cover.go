@@ -0,0 +1,46 @@
+package record
+
+import (
+	"time"
+
+	"github.com/rodrwan/collection/domain/artwork"
+)
+
+// Cover is the cover art of a record. Its renditions are stored in a blob
+// store under Key, one per artwork size plus the original.
+type Cover struct {
+	Key         string    `json:"key"`
+	ContentType string    `json:"contentType"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// IsZero reports whether the record has no cover art.
+func (c Cover) IsZero() bool {
+	return c.Key == ""
+}
+
+// RenditionKey is the blob key of the named rendition, e.g.
+// covers/<id>/<version>/small.jpg.
+func (c Cover) RenditionKey(name string) string {
+	return c.Key + "/" + name + artwork.Extension(c.ContentType)
+}
+
+// RenditionKeys lists the blob keys of the original and every size.
+func (c Cover) RenditionKeys() map[string]string {
+	keys := map[string]string{
+		artwork.Original: c.RenditionKey(artwork.Original),
+	}
+	for _, size := range artwork.Sizes {
+		keys[size.Name] = c.RenditionKey(size.Name)
+	}
+
+	return keys
+}
+
+func (r *Record) SetCover(c Cover) {
+	r.cover = c
+}
+
+func (r Record) GetCover() Cover {
+	return r.cover
+}
@@ -0,0 +1,278 @@
+package record
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// DefaultDuplicateScore is the score from which two records are reported as
+// likely duplicates.
+const DefaultDuplicateScore = 0.8
+
+// minNameScore is the name similarity below which a pair is never a
+// duplicate, whatever the kind or tracklist say.
+const minNameScore = 0.5
+
+// Weights of each signal in the duplicate score. Without a tracklist on both
+// sides the name and kind share the whole score.
+const (
+	nameWeight  = 0.6
+	kindWeight  = 0.1
+	trackWeight = 0.3
+
+	nameOnlyWeight = 0.85
+	kindOnlyWeight = 0.15
+)
+
+var (
+	// articles are dropped at both ends so "The Wall" and "Wall, The" match.
+	articles = map[string]bool{"the": true, "a": true, "an": true}
+	// editionWords mark a bracketed or dashed suffix as an edition note,
+	// e.g. "(Remastered)" or " - 2011 Deluxe Edition".
+	editionWords = []string{
+		"remaster", "deluxe", "edition", "expanded", "anniversary", "reissue",
+		"bonus", "mono", "stereo", "version", "special", "collector", "legacy",
+	}
+
+	bracketed = regexp.MustCompile(`[\(\[][^\)\]]*[\)\]]`)
+)
+
+// NormalizeName reduces a record or song name to the words that identify it:
+// lower case, without punctuation, leading or trailing articles and edition
+// notes.
+func NormalizeName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	name = bracketed.ReplaceAllStringFunc(name, func(segment string) string {
+		if isEditionNote(segment) {
+			return " "
+		}
+		return segment
+	})
+
+	if i := strings.LastIndex(name, " - "); i > 0 && isEditionNote(name[i:]) {
+		name = name[:i]
+	}
+
+	name = strings.Replace(name, "&", " and ", -1)
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\'' || r == '’':
+			return -1
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			return r
+		}
+		return ' '
+	}, name)
+
+	words := strings.Fields(name)
+	if len(words) > 1 && articles[words[0]] {
+		words = words[1:]
+	}
+	if len(words) > 1 && articles[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+
+	return strings.Join(words, " ")
+}
+
+func isEditionNote(segment string) bool {
+	for _, word := range editionWords {
+		if strings.Contains(segment, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// NameSimilarity compares two names once normalized, from 0 for nothing in
+// common to 1 for the same name.
+func NameSimilarity(a, b string) float64 {
+	return similarity([]rune(NormalizeName(a)), []rune(NormalizeName(b)))
+}
+
+func similarity(a, b []rune) float64 {
+	longest := len(a)
+	if len(b) > longest {
+		longest = len(b)
+	}
+	if longest == 0 {
+		return 0
+	}
+
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// levenshtein counts the single rune edits that turn a into b.
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// TrackOverlap is the share of songs two tracklists have in common, by
+// normalized name. It is 0 when either list is empty.
+func TrackOverlap(a, b []string) float64 {
+	setA, setB := trackSet(a), trackSet(b)
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0
+	}
+
+	common := 0
+	for name := range setA {
+		if setB[name] {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(setA)+len(setB)-common)
+}
+
+func trackSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		if n := NormalizeName(name); n != "" {
+			set[n] = true
+		}
+	}
+	return set
+}
+
+// DuplicateRecord identifies a record of a duplicate pair.
+type DuplicateRecord struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	Kind string    `json:"kind"`
+}
+
+// Duplicate is a pair of records that look like the same release entered
+// twice, with the signals behind its score.
+type Duplicate struct {
+	Records      [2]DuplicateRecord `json:"records"`
+	Score        float64            `json:"score"`
+	NameScore    float64            `json:"nameScore"`
+	SameKind     bool               `json:"sameKind"`
+	TrackOverlap *float64           `json:"trackOverlap,omitempty"`
+}
+
+// ScoreDuplicate scores how likely two records are the same release given
+// their tracklists, which may be empty when unknown.
+func ScoreDuplicate(a, b Record, tracksA, tracksB []string) Duplicate {
+	d := Duplicate{
+		Records: [2]DuplicateRecord{
+			{ID: a.GetID(), Name: a.GetName(), Kind: a.GetKind()},
+			{ID: b.GetID(), Name: b.GetName(), Kind: b.GetKind()},
+		},
+		NameScore: RoundScore(NameSimilarity(a.GetName(), b.GetName())),
+		SameKind:  a.GetKind() == b.GetKind(),
+	}
+
+	kind := 0.0
+	if d.SameKind {
+		kind = 1
+	}
+
+	if len(tracksA) == 0 || len(tracksB) == 0 {
+		d.Score = RoundScore(nameOnlyWeight*d.NameScore + kindOnlyWeight*kind)
+		return d
+	}
+
+	overlap := RoundScore(TrackOverlap(tracksA, tracksB))
+	d.TrackOverlap = &overlap
+	d.Score = RoundScore(nameWeight*d.NameScore + kindWeight*kind + trackWeight*overlap)
+
+	return d
+}
+
+// FindDuplicates compares every pair of records and returns those scoring
+// at least minScore, best first. tracks holds the song names of each record.
+func FindDuplicates(records []Record, tracks map[uuid.UUID][]string, minScore float64) []Duplicate {
+	duplicates := make([]Duplicate, 0)
+	for i := 0; i < len(records); i++ {
+		for j := i + 1; j < len(records); j++ {
+			a, b := records[i], records[j]
+			if NameSimilarity(a.GetName(), b.GetName()) < minNameScore {
+				continue
+			}
+
+			d := ScoreDuplicate(a, b, tracks[a.GetID()], tracks[b.GetID()])
+			if d.Score >= minScore {
+				duplicates = append(duplicates, d)
+			}
+		}
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].Score > duplicates[j].Score
+	})
+
+	return duplicates
+}
+
+// RoundScore rounds a match score to the two decimals it is reported with.
+func RoundScore(score float64) float64 {
+	return math.Round(score*100) / 100
+}
+
+// Merge folds a duplicate into the record: tags, gradings and valuations
+// are combined, and the artist, status, genre, acquisition, placement,
+// cover and MusicBrainz link are taken from the duplicate only where the
+// record has none.
+func (r *Record) Merge(duplicate Record) {
+	r.SetTags(append(r.GetTags(), duplicate.GetTags()...))
+	r.SetGradingHistory(append(r.GetGradingHistory(), duplicate.GetGradingHistory()...))
+	r.SetValuations(append(r.GetValuations(), duplicate.GetValuations()...))
+
+	if r.GetArtist() == "" {
+		r.SetArtist(duplicate.GetArtist())
+	}
+	if r.GetStatus() == "" {
+		r.SetStatus(duplicate.GetStatus())
+	}
+	if r.GetGenreID() == uuid.Nil {
+		r.SetGenreID(duplicate.GetGenreID())
+	}
+	if r.GetAcquisition().IsZero() {
+		r.acquisition = duplicate.GetAcquisition()
+	}
+	if r.GetPlacement().IsZero() {
+		r.SetPlacement(duplicate.GetPlacement())
+	}
+	if r.GetCover().IsZero() {
+		r.SetCover(duplicate.GetCover())
+	}
+	if r.GetMusicBrainz().IsZero() {
+		r.SetMusicBrainz(duplicate.GetMusicBrainz())
+	}
+}
@@ -0,0 +1,120 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+)
+
+func TestDuplicates_NormalizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "The Wall", expected: "wall"},
+		{name: "Wall, The (Remastered)", expected: "wall"},
+		{name: "Abbey Road - 2019 Remix Deluxe Edition", expected: "abbey road"},
+		{name: "Simon & Garfunkel's Greatest Hits [Expanded]", expected: "simon and garfunkels greatest hits"},
+		{name: "Live at Leeds (Live)", expected: "live at leeds live"},
+		{name: "A", expected: "a"},
+		{name: "  ", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := record.NormalizeName(tt.name); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDuplicates_NameSimilarity(t *testing.T) {
+	if s := record.NameSimilarity("The Wall", "Wall, The (Remastered)"); s != 1 {
+		t.Errorf("Expected the same name, got %v", s)
+	}
+	if s := record.NameSimilarity("Abbey Road", "Abbey Raod"); s < 0.75 || s == 1 {
+		t.Errorf("Expected a close name, got %v", s)
+	}
+	if s := record.NameSimilarity("Blue", "Court and Spark"); s > 0.3 {
+		t.Errorf("Expected unrelated names, got %v", s)
+	}
+}
+
+func TestDuplicates_FindDuplicates(t *testing.T) {
+	wall, _ := record.NewRecord("The Wall", record.KindVinyl)
+	wallRemaster, _ := record.NewRecord("Wall, The (Remastered)", record.KindVinyl)
+	wallMP3, _ := record.NewRecord("The Wall", record.KindMP3)
+	blue, _ := record.NewRecord("Blue", record.KindVinyl)
+	bluesy, _ := record.NewRecord("Blues", record.KindVinyl)
+
+	tracks := map[uuid.UUID][]string{
+		wall.GetID():         {"In the Flesh?", "Hey You", "Comfortably Numb"},
+		wallRemaster.GetID(): {"In The Flesh", "Hey You", "Comfortably Numb (2011 Remaster)"},
+		blue.GetID():         {"All I Want", "River"},
+		bluesy.GetID():       {"Crossroads"},
+	}
+
+	duplicates := record.FindDuplicates([]record.Record{wall, wallRemaster, wallMP3, blue, bluesy}, tracks, record.DefaultDuplicateScore)
+	// the mp3 pairs with both vinyl records
+	if len(duplicates) != 3 {
+		t.Fatalf("Expected 3 duplicates, got %+v", duplicates)
+	}
+
+	first := duplicates[0]
+	if first.Records[0].ID != wall.GetID() || first.Records[1].ID != wallRemaster.GetID() {
+		t.Errorf("Expected the remaster first, got %+v", first.Records)
+	}
+	if first.Score != 1 || first.TrackOverlap == nil || *first.TrackOverlap != 1 {
+		t.Errorf("Expected a full match, got %+v", first)
+	}
+
+	second := duplicates[1]
+	if second.Records[1].ID != wallMP3.GetID() || second.SameKind || second.TrackOverlap != nil {
+		t.Errorf("Expected the mp3 without tracks, got %+v", second)
+	}
+	if second.Score != 0.85 {
+		t.Errorf("Expected a name only score of 0.85, got %v", second.Score)
+	}
+
+	if all := record.FindDuplicates([]record.Record{blue, bluesy}, tracks, 0); len(all) != 1 || all[0].Score >= 0.8 {
+		t.Errorf("Expected a low scoring pair, got %+v", all)
+	}
+}
+
+func TestDuplicates_Merge(t *testing.T) {
+	keep, _ := record.NewRecord("The Wall", record.KindVinyl)
+	keep.SetTags([]string{"rock"})
+	// stored before statuses existed
+	keep.SetStatus("")
+
+	duplicate, _ := record.NewRecord("Wall, The", record.KindVinyl)
+	duplicate.SetArtist("Pink Floyd")
+	duplicate.SetStatus(record.StatusWishlist)
+	duplicate.SetTags([]string{"Prog", "rock"})
+	duplicate.SetGenreID(uuid.New())
+	duplicate.SetCover(record.Cover{Key: "covers/x", ContentType: "image/png"})
+	duplicate.SetMusicBrainz(record.MusicBrainz{ReleaseID: "mbid"})
+
+	keep.Merge(duplicate)
+
+	if tags := keep.GetTags(); len(tags) != 2 || tags[0] != "prog" || tags[1] != "rock" {
+		t.Errorf("Expected merged tags, got %v", tags)
+	}
+	if keep.GetArtist() != "Pink Floyd" || keep.GetStatus() != record.StatusWishlist {
+		t.Errorf("Expected the artist and status of the duplicate, got %q and %q", keep.GetArtist(), keep.GetStatus())
+	}
+	if keep.GetGenreID() != duplicate.GetGenreID() {
+		t.Errorf("Expected the genre of the duplicate")
+	}
+	if keep.GetCover().Key != "covers/x" {
+		t.Errorf("Expected the cover of the duplicate, got %+v", keep.GetCover())
+	}
+	if keep.GetMusicBrainz().ReleaseID != "mbid" {
+		t.Errorf("Expected the MusicBrainz link of the duplicate, got %+v", keep.GetMusicBrainz())
+	}
+	if keep.GetName() != "The Wall" {
+		t.Errorf("Expected the name to be kept, got %s", keep.GetName())
+	}
+}
@@ -0,0 +1,126 @@
+package record
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidMinRating = errors.New("minimum rating must be between 1 and 5")
+)
+
+// Bounds of the MinRating filter, the range of review stars.
+const (
+	MinRatingLow  = 1
+	MinRatingHigh = 5
+)
+
+// Filter narrows down a record listing. Zero values match every record.
+type Filter struct {
+	Kind string
+	// GenreIDs matches records in any of the given genres. Callers expand a
+	// genre into its sub-genres before filtering.
+	GenreIDs []uuid.UUID
+	Tags     []string
+	// MinGrade keeps graded records whose media grade is at least MinGrade.
+	MinGrade Grade
+	Status   Status
+	// Seller matches the acquisition seller, ignoring case.
+	Seller string
+	// AcquiredFrom and AcquiredTo bound the acquisition date, inclusive.
+	AcquiredFrom time.Time
+	AcquiredTo   time.Time
+	// MinRating keeps rated records whose average rating is at least
+	// MinRating. It needs the ratings on PublicRecord, see MatchPublic.
+	MinRating float64
+	// Sort orders the listing, keeping the stored order when empty.
+	Sort Sort
+}
+
+// Match reports whether the record satisfies every condition of the filter.
+func (f Filter) Match(r Record) bool {
+	if f.Kind != "" && f.Kind != r.GetKind() {
+		return false
+	}
+
+	if len(f.GenreIDs) > 0 {
+		found := false
+		for _, id := range f.GenreIDs {
+			if id == r.GetGenreID() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for _, tag := range f.Tags {
+		if !r.HasTag(tag) {
+			return false
+		}
+	}
+
+	if f.MinGrade != "" && !r.GetMediaGrade().AtLeast(f.MinGrade) {
+		return false
+	}
+
+	if f.Status != "" && f.Status != r.currentStatus() {
+		return false
+	}
+
+	acquisition := r.GetAcquisition()
+	if f.Seller != "" && !strings.EqualFold(f.Seller, acquisition.Seller) {
+		return false
+	}
+
+	if !f.AcquiredFrom.IsZero() && (acquisition.Date.IsZero() || acquisition.Date.Before(f.AcquiredFrom)) {
+		return false
+	}
+
+	if !f.AcquiredTo.IsZero() && (acquisition.Date.IsZero() || acquisition.Date.After(f.AcquiredTo)) {
+		return false
+	}
+
+	return true
+}
+
+// MatchPublic checks the conditions that depend on data filled in on the
+// public record, such as the average rating.
+func (f Filter) MatchPublic(pr PublicRecord) bool {
+	if f.MinRating > 0 && (pr.AverageRating == nil || *pr.AverageRating < f.MinRating) {
+		return false
+	}
+
+	return true
+}
+
+// ApplyPublic returns the public records matching MatchPublic, sorted by the
+// filter sort order.
+func (f Filter) ApplyPublic(records []PublicRecord) []PublicRecord {
+	var rr []PublicRecord
+	for _, pr := range records {
+		if f.MatchPublic(pr) {
+			rr = append(rr, pr)
+		}
+	}
+
+	f.Sort.Apply(rr)
+	return rr
+}
+
+// Apply returns the records matching the filter, preserving their order.
+func (f Filter) Apply(records []Record) []Record {
+	var rr []Record
+	for _, r := range records {
+		if f.Match(r) {
+			rr = append(rr, r)
+		}
+	}
+
+	return rr
+}
@@ -0,0 +1,111 @@
+package record_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+)
+
+func TestFilter_Match(t *testing.T) {
+	genreID := uuid.New()
+
+	r, _ := record.NewRecord("r1", "vinyl")
+	r.SetGenreID(genreID)
+	r.SetTags([]string{"Summer", "dj set"})
+	r.Grade(record.GradeVeryGoodPlus, record.GradeVeryGood, "", time.Now())
+	r.SetAcquisition(record.Acquisition{
+		Date:   time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+		Seller: "Discogs",
+	})
+
+	tests := []struct {
+		name   string
+		filter record.Filter
+		want   bool
+	}{
+		{
+			name:   "Empty filter matches everything",
+			filter: record.Filter{},
+			want:   true,
+		},
+		{
+			name:   "Different kind",
+			filter: record.Filter{Kind: "mp3"},
+			want:   false,
+		},
+		{
+			name:   "Genre in the expanded list",
+			filter: record.Filter{GenreIDs: []uuid.UUID{uuid.New(), genreID}},
+			want:   true,
+		},
+		{
+			name:   "Genre not in the list",
+			filter: record.Filter{GenreIDs: []uuid.UUID{uuid.New()}},
+			want:   false,
+		},
+		{
+			name:   "Every tag must match, case insensitive",
+			filter: record.Filter{Tags: []string{"SUMMER", "dj set"}},
+			want:   true,
+		},
+		{
+			name:   "Missing tag",
+			filter: record.Filter{Tags: []string{"summer", "winter"}},
+			want:   false,
+		},
+		{
+			name:   "Media grade above the minimum",
+			filter: record.Filter{MinGrade: record.GradeVeryGood},
+			want:   true,
+		},
+		{
+			name:   "Media grade below the minimum",
+			filter: record.Filter{MinGrade: record.GradeNearMint},
+			want:   false,
+		},
+		{
+			name:   "Ownership status",
+			filter: record.Filter{Status: record.StatusOwned},
+			want:   true,
+		},
+		{
+			name:   "Other ownership status",
+			filter: record.Filter{Status: record.StatusSold},
+			want:   false,
+		},
+		{
+			name:   "Seller ignoring case",
+			filter: record.Filter{Seller: "discogs"},
+			want:   true,
+		},
+		{
+			name: "Acquired within range",
+			filter: record.Filter{
+				AcquiredFrom: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				AcquiredTo:   time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC),
+			},
+			want: true,
+		},
+		{
+			name:   "Acquired before range",
+			filter: record.Filter{AcquiredFrom: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(r); got != tt.want {
+				t.Errorf("Filter.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// records stored before statuses existed are owned
+	r.SetStatus("")
+	if !(record.Filter{Status: record.StatusOwned}).Match(r) {
+		t.Errorf("Filter.Match() = false for a record without status, want true")
+	}
+}
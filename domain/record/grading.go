@@ -0,0 +1,150 @@
+package record
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrInvalidGrade      = errors.New("invalid grade")
+	ErrGradingNotAllowed = errors.New("only vinyl records can be graded")
+	ErrMissingMediaGrade = errors.New("missing media grade")
+)
+
+// Grade is a condition grade on the Goldmine scale.
+type Grade string
+
+const (
+	GradeMint         Grade = "M"
+	GradeNearMint     Grade = "NM"
+	GradeVeryGoodPlus Grade = "VG+"
+	GradeVeryGood     Grade = "VG"
+	GradeGood         Grade = "G"
+	GradePoor         Grade = "P"
+)
+
+// grades lists the scale from worst to best, so the index is the rank.
+var grades = []Grade{
+	GradePoor,
+	GradeGood,
+	GradeVeryGood,
+	GradeVeryGoodPlus,
+	GradeNearMint,
+	GradeMint,
+}
+
+// ParseGrade reads a grade ignoring case and surrounding spaces.
+func ParseGrade(value string) (Grade, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+
+	for _, g := range grades {
+		if Grade(value) == g {
+			return g, nil
+		}
+	}
+
+	return "", ErrInvalidGrade
+}
+
+// Rank orders grades from 1 (P) to 6 (M). Unknown grades rank 0.
+func (g Grade) Rank() int {
+	for i, grade := range grades {
+		if g == grade {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// AtLeast reports whether g is as good as or better than min.
+func (g Grade) AtLeast(min Grade) bool {
+	return g.Rank() > 0 && g.Rank() >= min.Rank()
+}
+
+// Grading is an entry of a record grading history.
+type Grading struct {
+	Date   time.Time `json:"date"`
+	Media  Grade     `json:"media"`
+	Sleeve Grade     `json:"sleeve,omitempty"`
+	Notes  string    `json:"notes,omitempty"`
+}
+
+// Grade records the media and sleeve condition of a vinyl record at the
+// given date. The sleeve grade is optional for records without a cover.
+// The current grades are always the ones of the latest entry.
+func (r *Record) Grade(media, sleeve Grade, notes string, date time.Time) error {
+	if r.kind != KindVinyl {
+		return ErrGradingNotAllowed
+	}
+
+	var errs validation.Errors
+	switch {
+	case media == "":
+		errs = errs.Add("media", validation.CodeRequired, ErrMissingMediaGrade)
+	case media.Rank() == 0:
+		errs = errs.Add("media", validation.CodeInvalid, ErrInvalidGrade)
+	}
+	if sleeve != "" && sleeve.Rank() == 0 {
+		errs = errs.Add("sleeve", validation.CodeInvalid, ErrInvalidGrade)
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	history := append(r.GetGradingHistory(), Grading{
+		Date:   date,
+		Media:  media,
+		Sleeve: sleeve,
+		Notes:  notes,
+	})
+	r.SetGradingHistory(history)
+
+	return nil
+}
+
+// SetGradingHistory replaces the grading history, keeping it sorted by date.
+func (r *Record) SetGradingHistory(history []Grading) {
+	if len(history) == 0 {
+		r.gradingHistory = nil
+		return
+	}
+
+	r.gradingHistory = make([]Grading, len(history))
+	copy(r.gradingHistory, history)
+	sort.SliceStable(r.gradingHistory, func(i, j int) bool {
+		return r.gradingHistory[i].Date.Before(r.gradingHistory[j].Date)
+	})
+}
+
+func (r Record) GetGradingHistory() []Grading {
+	if len(r.gradingHistory) == 0 {
+		return nil
+	}
+
+	history := make([]Grading, len(r.gradingHistory))
+	copy(history, r.gradingHistory)
+	return history
+}
+
+// GetMediaGrade returns the latest media grade, empty when never graded.
+func (r Record) GetMediaGrade() Grade {
+	if len(r.gradingHistory) == 0 {
+		return ""
+	}
+
+	return r.gradingHistory[len(r.gradingHistory)-1].Media
+}
+
+// GetSleeveGrade returns the latest sleeve grade, empty when never graded.
+func (r Record) GetSleeveGrade() Grade {
+	if len(r.gradingHistory) == 0 {
+		return ""
+	}
+
+	return r.gradingHistory[len(r.gradingHistory)-1].Sleeve
+}
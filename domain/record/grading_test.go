@@ -0,0 +1,109 @@
+package record_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/domain/record"
+)
+
+func TestParseGrade(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    record.Grade
+		wantErr error
+	}{
+		{value: "M", want: record.GradeMint},
+		{value: "nm", want: record.GradeNearMint},
+		{value: "VG+", want: record.GradeVeryGoodPlus},
+		{value: "VG ", want: record.GradeVeryGood},
+		{value: "NM ", want: record.GradeNearMint},
+		{value: " vg", want: record.GradeVeryGood},
+		{value: "G", want: record.GradeGood},
+		{value: "P", want: record.GradePoor},
+		{value: "G+", wantErr: record.ErrInvalidGrade},
+		{value: "", wantErr: record.ErrInvalidGrade},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := record.ParseGrade(tt.value)
+			if err != tt.wantErr {
+				t.Errorf("ParseGrade() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseGrade() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGrade_AtLeast(t *testing.T) {
+	tests := []struct {
+		name  string
+		grade record.Grade
+		min   record.Grade
+		want  bool
+	}{
+		{name: "Better grade", grade: record.GradeNearMint, min: record.GradeVeryGoodPlus, want: true},
+		{name: "Same grade", grade: record.GradeVeryGoodPlus, min: record.GradeVeryGoodPlus, want: true},
+		{name: "Worse grade", grade: record.GradeVeryGood, min: record.GradeVeryGoodPlus, want: false},
+		{name: "Ungraded", grade: "", min: record.GradePoor, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.grade.AtLeast(tt.min); got != tt.want {
+				t.Errorf("Grade.AtLeast() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecord_Grade(t *testing.T) {
+	first := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		kind    string
+		media   record.Grade
+		sleeve  record.Grade
+		wantErr error
+	}{
+		{name: "Vinyl record", kind: "vinyl", media: record.GradeNearMint, sleeve: record.GradeVeryGood},
+		{name: "Vinyl without sleeve", kind: "vinyl", media: record.GradeNearMint},
+		{name: "Missing media grade", kind: "vinyl", sleeve: record.GradeVeryGood, wantErr: record.ErrMissingMediaGrade},
+		{name: "Invalid grade", kind: "vinyl", media: "A", wantErr: record.ErrInvalidGrade},
+		{name: "Not a vinyl", kind: "mp3", media: record.GradeMint, wantErr: record.ErrGradingNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := record.NewRecord("r1", tt.kind)
+			if err := r.Grade(tt.media, tt.sleeve, "", first); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Record.Grade() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("Latest entry wins regardless of insertion order", func(t *testing.T) {
+		r, _ := record.NewRecord("r1", "vinyl")
+		r.Grade(record.GradeVeryGood, record.GradeGood, "worn", second)
+		r.Grade(record.GradeNearMint, record.GradeNearMint, "bought", first)
+
+		if r.GetMediaGrade() != record.GradeVeryGood || r.GetSleeveGrade() != record.GradeGood {
+			t.Errorf("Expected VG/G, got %v/%v", r.GetMediaGrade(), r.GetSleeveGrade())
+		}
+
+		want := []record.Grading{
+			{Date: first, Media: record.GradeNearMint, Sleeve: record.GradeNearMint, Notes: "bought"},
+			{Date: second, Media: record.GradeVeryGood, Sleeve: record.GradeGood, Notes: "worn"},
+		}
+		if got := r.GetGradingHistory(); !reflect.DeepEqual(got, want) {
+			t.Errorf("Record.GetGradingHistory() = %v, want %v", got, want)
+		}
+	})
+}
@@ -16,28 +16,64 @@ type MemoryRepository struct {
 }
 
 type memoryRecord struct {
-	ID   uuid.UUID `db:"id"`
-	Name string    `db:"name"`
-	Kind string    `db:"kind"`
+	ID      uuid.UUID `db:"id"`
+	Name    string    `db:"name"`
+	Artist  string    `db:"artist"`
+	Kind    string    `db:"kind"`
+	GenreID uuid.UUID `db:"genre_id"`
+	Tags    []string  `db:"tags"`
+
+	GradingHistory []record.Grading `db:"grading_history"`
+
+	Status      record.Status      `db:"status"`
+	Acquisition record.Acquisition `db:"acquisition"`
+	Valuations  []record.Valuation `db:"valuations"`
+	Placement   record.Placement   `db:"placement"`
+	Cover       record.Cover       `db:"cover"`
+	MusicBrainz record.MusicBrainz `db:"musicbrainz"`
 }
 
 // NewFromCustomer takes in a aggregate and converts into internal structure
 func NewFromRecord(r record.Record) memoryRecord {
 	return memoryRecord{
-		ID:   r.GetID(),
-		Name: r.GetName(),
-		Kind: r.GetKind(),
+		ID:      r.GetID(),
+		Name:    r.GetName(),
+		Artist:  r.GetArtist(),
+		Kind:    r.GetKind(),
+		GenreID: r.GetGenreID(),
+		Tags:    r.GetTags(),
+
+		GradingHistory: r.GetGradingHistory(),
+
+		Status:      r.GetStatus(),
+		Acquisition: r.GetAcquisition(),
+		Valuations:  r.GetValuations(),
+		Placement:   r.GetPlacement(),
+		Cover:       r.GetCover(),
+		MusicBrainz: r.GetMusicBrainz(),
 	}
 }
 
-func (pr memoryRecord) ToRecord() record.Record {
+func (pr memoryRecord) ToRecord() (record.Record, error) {
 	r := record.Record{}
 
 	r.SetID(pr.ID)
 	r.SetName(pr.Name)
+	r.SetArtist(pr.Artist)
 	r.SetKind(pr.Kind)
+	r.SetGenreID(pr.GenreID)
+	r.SetTags(pr.Tags)
+	r.SetGradingHistory(pr.GradingHistory)
+	r.SetStatus(pr.Status)
+	if err := r.SetAcquisition(pr.Acquisition); err != nil {
+		return record.Record{}, err
+	}
+	r.SetValuations(pr.Valuations)
+	r.SetPlacement(pr.Placement)
+	r.SetCover(pr.Cover)
+	r.SetMusicBrainz(pr.MusicBrainz)
 
-	return r
+	return r, nil
 }
 
 // Create a new mongodb repository
@@ -53,7 +89,7 @@ func (mr *MemoryRepository) Get(id uuid.UUID) (record.Record, error) {
 
 	for _, rec := range mr.records {
 		if rec.ID == id {
-			return rec.ToRecord(), nil
+			return rec.ToRecord()
 		}
 	}
 
@@ -70,6 +106,17 @@ func (mr *MemoryRepository) Add(r record.Record) error {
 	return nil
 }
 
+func (mr *MemoryRepository) AddBatch(rr []record.Record) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for _, r := range rr {
+		mr.records = append(mr.records, NewFromRecord(r))
+	}
+
+	return nil
+}
+
 func (mr *MemoryRepository) FindRecords() ([]record.Record, error) {
 	mr.Lock()
 	defer mr.Unlock()
@@ -77,14 +124,42 @@ func (mr *MemoryRepository) FindRecords() ([]record.Record, error) {
 	// Convert to aggregate
 	var rr []record.Record
 	for _, r := range mr.records {
-		rr = append(rr, r.ToRecord())
+		rec, err := r.ToRecord()
+		if err != nil {
+			return []record.Record{}, err
+		}
+		rr = append(rr, rec)
 	}
 
 	return rr, nil
 }
 
 func (mr *MemoryRepository) Update(r *record.Record) error {
-	panic("to implement")
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, rec := range mr.records {
+		if rec.ID == r.GetID() {
+			mr.records[i] = NewFromRecord(*r)
+			return nil
+		}
+	}
+
+	return record.ErrRecordNotFound
+}
+
+func (mr *MemoryRepository) Delete(id uuid.UUID) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, rec := range mr.records {
+		if rec.ID == id {
+			mr.records = append(mr.records[:i], mr.records[i+1:]...)
+			return nil
+		}
+	}
+
+	return record.ErrRecordNotFound
 }
 
 func (mr *MemoryRepository) AddSong(id uuid.UUID, s *song.Song) error {
@@ -186,3 +186,83 @@ func TestMemoryRepository_FindRecords(t *testing.T) {
 		})
 	}
 }
+
+func TestMemoryRepository_Update(t *testing.T) {
+	r1, _ := record.NewRecord("r1", "vinyl")
+	r2, _ := record.NewRecord("r2", "vinyl")
+
+	mr := &MemoryRepository{
+		records: []memoryRecord{
+			NewFromRecord(r1),
+		},
+	}
+
+	genreID := uuid.New()
+	r1.SetGenreID(genreID)
+	r1.SetTags([]string{"Summer"})
+
+	tests := []struct {
+		name    string
+		rec     record.Record
+		wantErr error
+	}{
+		{
+			name:    "Update stored record",
+			rec:     r1,
+			wantErr: nil,
+		},
+		{
+			name:    "Update unknown record",
+			rec:     r2,
+			wantErr: record.ErrRecordNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := mr.Update(&tt.rec); err != tt.wantErr {
+				t.Errorf("MemoryRepository.Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	got, _ := mr.Get(r1.GetID())
+	if got.GetGenreID() != genreID || !reflect.DeepEqual(got.GetTags(), []string{"summer"}) {
+		t.Errorf("MemoryRepository.Update() stored %v", got)
+	}
+}
+
+func TestMemoryRepository_Delete(t *testing.T) {
+	r1, _ := record.NewRecord("r1", "vinyl")
+	r2, _ := record.NewRecord("r2", "vinyl")
+
+	mr, _ := New(context.Background())
+	mr.Add(r1)
+	mr.Add(r2)
+
+	if err := mr.Delete(r1.GetID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := mr.Delete(r1.GetID()); err != record.ErrRecordNotFound {
+		t.Errorf("MemoryRepository.Delete() error = %v, wantErr %v", err, record.ErrRecordNotFound)
+	}
+
+	records, _ := mr.FindRecords()
+	if len(records) != 1 || records[0].GetID() != r2.GetID() {
+		t.Errorf("MemoryRepository.Delete() left %v", records)
+	}
+}
+
+func TestMemoryRepository_AddBatch(t *testing.T) {
+	r1, _ := record.NewRecord("r1", "vinyl")
+	r2, _ := record.NewRecord("r2", "mp3")
+
+	mr, _ := New(context.Background())
+	if err := mr.AddBatch([]record.Record{r1, r2}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, _ := mr.FindRecords()
+	if len(records) != 2 || records[0].GetID() != r1.GetID() || records[1].GetID() != r2.GetID() {
+		t.Errorf("MemoryRepository.AddBatch() stored %v", records)
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"github.com/rodrwan/collection/domain/song"
 )
 
+// ErrMock is returned by every method of a mock configured to fail.
+var ErrMock = errors.New("something went wrong")
+
 // MOCKS
 type MockRecordRepository struct {
 	WithError bool
@@ -16,7 +19,7 @@ type MockRecordRepository struct {
 
 func (mrr MockRecordRepository) Get(id uuid.UUID) (record.Record, error) {
 	if mrr.WithError {
-		return record.Record{}, errors.New("something went wrong")
+		return record.Record{}, ErrMock
 	}
 
 	return record.NewRecordWithID(id, "lala", "vinyl")
@@ -24,7 +27,15 @@ func (mrr MockRecordRepository) Get(id uuid.UUID) (record.Record, error) {
 
 func (mrr MockRecordRepository) Add(rec record.Record) error {
 	if mrr.WithError {
-		return errors.New("something went wrong")
+		return ErrMock
+	}
+
+	return nil
+}
+
+func (mrr MockRecordRepository) AddBatch(recs []record.Record) error {
+	if mrr.WithError {
+		return ErrMock
 	}
 
 	return nil
@@ -32,7 +43,15 @@ func (mrr MockRecordRepository) Add(rec record.Record) error {
 
 func (mrr MockRecordRepository) Update(rec *record.Record) error {
 	if mrr.WithError {
-		return errors.New("something went wrong")
+		return ErrMock
+	}
+
+	return nil
+}
+
+func (mrr MockRecordRepository) Delete(id uuid.UUID) error {
+	if mrr.WithError {
+		return ErrMock
 	}
 
 	return nil
@@ -40,7 +59,7 @@ func (mrr MockRecordRepository) Update(rec *record.Record) error {
 
 func (mrr MockRecordRepository) FindRecords() ([]record.Record, error) {
 	if mrr.WithError {
-		return []record.Record{}, errors.New("something went wrong")
+		return []record.Record{}, ErrMock
 	}
 
 	return []record.Record{}, nil
@@ -48,7 +67,7 @@ func (mrr MockRecordRepository) FindRecords() ([]record.Record, error) {
 
 func (mrr MockRecordRepository) AddSong(id uuid.UUID, s *song.Song) error {
 	if mrr.WithError {
-		return errors.New("something went wrong")
+		return ErrMock
 	}
 
 	return nil
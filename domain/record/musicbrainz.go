@@ -0,0 +1,29 @@
+package record
+
+import "time"
+
+// MusicBrainz links a record to the MusicBrainz release it was matched
+// with. The release date is as precise as MusicBrainz knows it, e.g. "1979"
+// or "1979-11-30", and Score is the confidence of the match, from 0 to 1.
+type MusicBrainz struct {
+	ReleaseID      string    `json:"releaseId"`
+	ReleaseGroupID string    `json:"releaseGroupId,omitempty"`
+	ArtistCredit   string    `json:"artistCredit,omitempty"`
+	ArtistIDs      []string  `json:"artistIds,omitempty"`
+	ReleaseDate    string    `json:"releaseDate,omitempty"`
+	Score          float64   `json:"score"`
+	MatchedAt      time.Time `json:"matchedAt"`
+}
+
+// IsZero reports whether the record is not linked to MusicBrainz.
+func (m MusicBrainz) IsZero() bool {
+	return m.ReleaseID == ""
+}
+
+func (r *Record) SetMusicBrainz(m MusicBrainz) {
+	r.musicBrainz = m
+}
+
+func (r Record) GetMusicBrainz() MusicBrainz {
+	return r.musicBrainz
+}
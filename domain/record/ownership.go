@@ -0,0 +1,157 @@
+package record
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rodrwan/collection/domain/money"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrInvalidStatus     = errors.New("invalid ownership status")
+	ErrInvalidTransition = errors.New("ownership status transition not allowed")
+	ErrInvalidPrice      = errors.New("price cannot be negative")
+	ErrInvalidCurrency   = money.ErrInvalidCurrency
+)
+
+// Status is where a record stands in the ownership lifecycle.
+type Status string
+
+const (
+	StatusWishlist Status = "wishlist"
+	StatusOrdered  Status = "ordered"
+	StatusOwned    Status = "owned"
+	StatusSold     Status = "sold"
+)
+
+// transitions lists the statuses each status can move to. Orders can be
+// cancelled back to the wishlist and sold records can be bought back.
+var transitions = map[Status][]Status{
+	StatusWishlist: {StatusOrdered, StatusOwned},
+	StatusOrdered:  {StatusOwned, StatusWishlist},
+	StatusOwned:    {StatusSold},
+	StatusSold:     {StatusOwned, StatusWishlist},
+}
+
+// ParseStatus reads a status ignoring case and surrounding spaces.
+func ParseStatus(value string) (Status, error) {
+	status := Status(strings.ToLower(strings.TrimSpace(value)))
+	if _, ok := transitions[status]; !ok {
+		return "", ErrInvalidStatus
+	}
+
+	return status, nil
+}
+
+// CanTransitionTo reports whether a record can move from s to next.
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Acquisition describes when, where and for how much a record was bought.
+// Price is expressed in the currency minor unit, e.g. cents.
+type Acquisition struct {
+	Date     time.Time `json:"date"`
+	Price    int64     `json:"price,omitempty"`
+	Currency string    `json:"currency,omitempty"`
+	Seller   string    `json:"seller,omitempty"`
+	Notes    string    `json:"notes,omitempty"`
+}
+
+// IsZero reports whether no acquisition details were given.
+func (a Acquisition) IsZero() bool {
+	return a == Acquisition{}
+}
+
+// Validate reports every invalid acquisition field.
+func (a Acquisition) Validate() error {
+	var errs validation.Errors
+
+	if a.Price < 0 {
+		errs = errs.Add("acquisition.price", validation.CodeOutOfRange, ErrInvalidPrice)
+	}
+
+	switch {
+	case a.Currency == "" && a.Price > 0:
+		errs = errs.Add("acquisition.currency", validation.CodeRequired, ErrInvalidCurrency)
+	case a.Currency != "" && !money.IsValidCurrency(a.Currency):
+		errs = errs.Add("acquisition.currency", validation.CodeInvalid, ErrInvalidCurrency)
+	}
+
+	return errs.Err()
+}
+
+// Cost returns what was paid for the record.
+func (a Acquisition) Cost() money.Money {
+	return money.Money{
+		Amount:   a.Price,
+		Currency: a.Currency,
+	}
+}
+
+// TransitionTo moves the record to the next ownership status, following the
+// allowed lifecycle.
+func (r *Record) TransitionTo(next Status) error {
+	current := r.currentStatus()
+	if current == next {
+		r.status = next
+		return nil
+	}
+
+	if !current.CanTransitionTo(next) {
+		var errs validation.Errors
+		return errs.Add("status", validation.CodeNotAllowed, ErrInvalidTransition)
+	}
+
+	r.status = next
+	return nil
+}
+
+// SetStatus sets the status without checking the lifecycle, e.g. when the
+// record is created or loaded from storage.
+func (r *Record) SetStatus(status Status) {
+	r.status = status
+}
+
+func (r Record) GetStatus() Status {
+	return r.status
+}
+
+// IsOwned reports whether the record is in the collection. Records stored
+// before statuses existed have none and count as owned.
+func (r Record) IsOwned() bool {
+	return r.currentStatus() == StatusOwned
+}
+
+// currentStatus returns the status of the record, owned for records stored
+// before statuses existed.
+func (r Record) currentStatus() Status {
+	if r.status == "" {
+		return StatusOwned
+	}
+
+	return r.status
+}
+
+// SetAcquisition validates and replaces the acquisition details.
+func (r *Record) SetAcquisition(a Acquisition) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	a.Currency = strings.ToUpper(a.Currency)
+	r.acquisition = a
+	return nil
+}
+
+func (r Record) GetAcquisition() Acquisition {
+	return r.acquisition
+}
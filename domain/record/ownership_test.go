@@ -0,0 +1,141 @@
+package record_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/domain/record"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    record.Status
+		wantErr error
+	}{
+		{value: "owned", want: record.StatusOwned},
+		{value: " Wishlist ", want: record.StatusWishlist},
+		{value: "ORDERED", want: record.StatusOrdered},
+		{value: "sold", want: record.StatusSold},
+		{value: "lost", wantErr: record.ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := record.ParseStatus(tt.value)
+			if err != tt.wantErr {
+				t.Errorf("ParseStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecord_TransitionTo(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    record.Status
+		to      record.Status
+		wantErr error
+	}{
+		{name: "Order a wishlisted record", from: record.StatusWishlist, to: record.StatusOrdered},
+		{name: "Receive an order", from: record.StatusOrdered, to: record.StatusOwned},
+		{name: "Cancel an order", from: record.StatusOrdered, to: record.StatusWishlist},
+		{name: "Sell an owned record", from: record.StatusOwned, to: record.StatusSold},
+		{name: "Buy back a sold record", from: record.StatusSold, to: record.StatusOwned},
+		{name: "Same status", from: record.StatusOwned, to: record.StatusOwned},
+		{name: "Owned back to wishlist", from: record.StatusOwned, to: record.StatusWishlist, wantErr: record.ErrInvalidTransition},
+		{name: "Sell before owning", from: record.StatusWishlist, to: record.StatusSold, wantErr: record.ErrInvalidTransition},
+		{name: "Sell a record stored before statuses", from: "", to: record.StatusSold},
+		{name: "Own a record stored before statuses", from: "", to: record.StatusOwned},
+		{name: "Record stored before statuses back to wishlist", from: "", to: record.StatusWishlist, wantErr: record.ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := record.NewRecord("r1", "vinyl")
+			r.SetStatus(tt.from)
+
+			err := r.TransitionTo(tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Record.TransitionTo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			want := tt.to
+			if err != nil {
+				want = tt.from
+			}
+			if r.GetStatus() != want {
+				t.Errorf("Record.GetStatus() = %v, want %v", r.GetStatus(), want)
+			}
+		})
+	}
+}
+
+func TestRecord_IsOwned(t *testing.T) {
+	for status, want := range map[record.Status]bool{
+		"":                    true,
+		record.StatusOwned:    true,
+		record.StatusWishlist: false,
+		record.StatusOrdered:  false,
+		record.StatusSold:     false,
+	} {
+		r, _ := record.NewRecord("r1", "vinyl")
+		r.SetStatus(status)
+		if got := r.IsOwned(); got != want {
+			t.Errorf("Record.IsOwned() with status %q = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestRecord_SetAcquisition(t *testing.T) {
+	date := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		acquisition record.Acquisition
+		wantErr     error
+	}{
+		{
+			name:        "Full acquisition",
+			acquisition: record.Acquisition{Date: date, Price: 2500, Currency: "eur", Seller: "Discogs"},
+		},
+		{
+			name:        "Gift without price",
+			acquisition: record.Acquisition{Date: date, Notes: "birthday"},
+		},
+		{
+			name:        "Negative price",
+			acquisition: record.Acquisition{Price: -1, Currency: "EUR"},
+			wantErr:     record.ErrInvalidPrice,
+		},
+		{
+			name:        "Price without currency",
+			acquisition: record.Acquisition{Price: 100},
+			wantErr:     record.ErrInvalidCurrency,
+		},
+		{
+			name:        "Invalid currency",
+			acquisition: record.Acquisition{Price: 100, Currency: "EURO"},
+			wantErr:     record.ErrInvalidCurrency,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := record.NewRecord("r1", "vinyl")
+			if err := r.SetAcquisition(tt.acquisition); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Record.SetAcquisition() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	r, _ := record.NewRecord("r1", "vinyl")
+	r.SetAcquisition(record.Acquisition{Price: 2500, Currency: "eur"})
+	if got := r.GetAcquisition().Currency; got != "EUR" {
+		t.Errorf("Expected currency EUR, got %v", got)
+	}
+}
@@ -0,0 +1,46 @@
+package record
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var ErrInvalidPosition = errors.New("shelf position cannot be negative")
+
+// Placement is where a record physically lives: a shelf or slot of the
+// locations tree and, optionally, its position there counted from 1.
+type Placement struct {
+	LocationID uuid.UUID `json:"locationId"`
+	Position   int       `json:"position,omitempty"`
+}
+
+// IsZero reports whether the record has not been shelved.
+func (p Placement) IsZero() bool {
+	return p.LocationID == uuid.Nil
+}
+
+// PlaceAt shelves the record at the given location. A nil location takes
+// the record off the shelves.
+func (r *Record) PlaceAt(locationID uuid.UUID, position int) error {
+	if position < 0 {
+		var errs validation.Errors
+		return errs.Add("position", validation.CodeOutOfRange, ErrInvalidPosition)
+	}
+
+	if locationID == uuid.Nil {
+		position = 0
+	}
+
+	r.placement = Placement{LocationID: locationID, Position: position}
+	return nil
+}
+
+func (r *Record) SetPlacement(p Placement) {
+	r.placement = p
+}
+
+func (r Record) GetPlacement() Placement {
+	return r.placement
+}
@@ -3,12 +3,18 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/rodrwan/collection/domain/record"
 	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/pkg/pgbatch"
 )
 
 // ConnectionConfig ...
@@ -58,28 +64,202 @@ type (
 )
 
 type postgresRecord struct {
-	ID   uuid.UUID `db:"id"`
-	Name string    `db:"name"`
-	Kind string    `db:"kind"`
+	ID      uuid.UUID      `db:"id"`
+	Name    string         `db:"name"`
+	Artist  string         `db:"artist"`
+	Kind    string         `db:"kind"`
+	GenreID uuid.UUID      `db:"genre_id"`
+	Tags    pq.StringArray `db:"tags"`
+
+	GradingHistory gradingHistory `db:"grading_history"`
+
+	Status              string       `db:"status"`
+	AcquiredAt          sql.NullTime `db:"acquired_at"`
+	AcquisitionPrice    int64        `db:"acquisition_price"`
+	AcquisitionCurrency string       `db:"acquisition_currency"`
+	Seller              string       `db:"seller"`
+	AcquisitionNotes    string       `db:"acquisition_notes"`
+
+	Valuations valuations `db:"valuations"`
+
+	LocationID    uuid.UUID `db:"location_id"`
+	ShelfPosition int       `db:"shelf_position"`
+
+	CoverKey       string       `db:"cover_key"`
+	CoverType      string       `db:"cover_type"`
+	CoverUpdatedAt sql.NullTime `db:"cover_updated_at"`
+
+	MusicBrainz musicBrainz `db:"musicbrainz"`
+}
+
+// recordColumns lists the records table columns mapped by postgresRecord.
+var recordColumns = []string{
+	"id",
+	"name",
+	"kind",
+	"genre_id",
+	"tags",
+	"grading_history",
+	"status",
+	"acquired_at",
+	"acquisition_price",
+	"acquisition_currency",
+	"seller",
+	"acquisition_notes",
+	"valuations",
+	"location_id",
+	"shelf_position",
+	"cover_key",
+	"cover_type",
+	"cover_updated_at",
+	"artist",
+	"musicbrainz",
+}
+
+var (
+	insertRecordQuery = "INSERT INTO records (" + strings.Join(recordColumns, ", ") + ") VALUES (:" + strings.Join(recordColumns, ", :") + ")"
+	updateRecordQuery = "UPDATE records SET " + setClause(recordColumns[1:]) + " WHERE id = :id"
+)
+
+func setClause(columns []string) string {
+	assignments := make([]string, 0, len(columns))
+	for _, c := range columns {
+		assignments = append(assignments, c+" = :"+c)
+	}
+
+	return strings.Join(assignments, ", ")
+}
+
+// gradingHistory is stored as a jsonb column.
+type gradingHistory []record.Grading
+
+func (gh gradingHistory) Value() (driver.Value, error) {
+	return jsonValue(gh, len(gh))
+}
+
+func (gh *gradingHistory) Scan(src interface{}) error {
+	return scanJSON(src, gh)
+}
+
+// valuations is stored as a jsonb column.
+type valuations []record.Valuation
+
+func (v valuations) Value() (driver.Value, error) {
+	return jsonValue(v, len(v))
+}
+
+func (v *valuations) Scan(src interface{}) error {
+	return scanJSON(src, v)
+}
+
+// musicBrainz is stored as a jsonb column, null when the record is not
+// linked.
+type musicBrainz record.MusicBrainz
+
+func (m musicBrainz) Value() (driver.Value, error) {
+	if record.MusicBrainz(m).IsZero() {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
+
+func (m *musicBrainz) Scan(src interface{}) error {
+	return scanJSON(src, m)
+}
+
+// jsonValue encodes a slice for a jsonb column, storing empty slices as [].
+func jsonValue(v interface{}, length int) (driver.Value, error) {
+	if length == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(v)
+}
+
+func scanJSON(src interface{}, dest interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	}
+
+	return errors.New("unsupported jsonb value")
 }
 
 // NewFromCustomer takes in a aggregate and converts into internal structure
 func NewFromRecord(r record.Record) postgresRecord {
 	return postgresRecord{
-		ID:   r.GetID(),
-		Name: r.GetName(),
-		Kind: r.GetKind(),
+		ID:      r.GetID(),
+		Name:    r.GetName(),
+		Artist:  r.GetArtist(),
+		Kind:    r.GetKind(),
+		GenreID: r.GetGenreID(),
+		Tags:    pq.StringArray(r.GetTags()),
+
+		GradingHistory: gradingHistory(r.GetGradingHistory()),
+
+		Status: string(r.GetStatus()),
+		AcquiredAt: sql.NullTime{
+			Time:  r.GetAcquisition().Date,
+			Valid: !r.GetAcquisition().Date.IsZero(),
+		},
+		AcquisitionPrice:    r.GetAcquisition().Price,
+		AcquisitionCurrency: r.GetAcquisition().Currency,
+		Seller:              r.GetAcquisition().Seller,
+		AcquisitionNotes:    r.GetAcquisition().Notes,
+
+		Valuations: valuations(r.GetValuations()),
+
+		LocationID:    r.GetPlacement().LocationID,
+		ShelfPosition: r.GetPlacement().Position,
+
+		CoverKey:  r.GetCover().Key,
+		CoverType: r.GetCover().ContentType,
+		CoverUpdatedAt: sql.NullTime{
+			Time:  r.GetCover().UpdatedAt,
+			Valid: !r.GetCover().UpdatedAt.IsZero(),
+		},
+
+		MusicBrainz: musicBrainz(r.GetMusicBrainz()),
 	}
 }
 
-func (pr postgresRecord) ToRecord() record.Record {
+func (pr postgresRecord) ToRecord() (record.Record, error) {
 	r := record.Record{}
 
 	r.SetID(pr.ID)
 	r.SetName(pr.Name)
+	r.SetArtist(pr.Artist)
 	r.SetKind(pr.Kind)
+	r.SetGenreID(pr.GenreID)
+	r.SetTags(pr.Tags)
+	r.SetGradingHistory(pr.GradingHistory)
+	r.SetStatus(record.Status(pr.Status))
+	err := r.SetAcquisition(record.Acquisition{
+		Date:     pr.AcquiredAt.Time,
+		Price:    pr.AcquisitionPrice,
+		Currency: pr.AcquisitionCurrency,
+		Seller:   pr.Seller,
+		Notes:    pr.AcquisitionNotes,
+	})
+	if err != nil {
+		return record.Record{}, err
+	}
+	r.SetValuations(pr.Valuations)
+	r.SetPlacement(record.Placement{
+		LocationID: pr.LocationID,
+		Position:   pr.ShelfPosition,
+	})
+	r.SetCover(record.Cover{
+		Key:         pr.CoverKey,
+		ContentType: pr.CoverType,
+		UpdatedAt:   pr.CoverUpdatedAt.Time,
+	})
+	r.SetMusicBrainz(record.MusicBrainz(pr.MusicBrainz))
 
-	return r
+	return r, nil
 }
 
 // Create a new mongodb repository
@@ -100,17 +280,43 @@ func New(ctx context.Context, connectionString, database string, open SqlOpener)
 	}, nil
 }
 
+// ErrNoTransactions is returned by Begin when the repository does not run
+// on a database, as with the mock.
+var ErrNoTransactions = errors.New("the repository cannot begin transactions")
+
+// Begin starts a transaction on the database of the repository, for
+// statements that span repositories, with the default options when opts is
+// nil. See WithTx.
+func (mr *PostgresRepository) Begin(opts *sql.TxOptions) (*sqlx.Tx, error) {
+	db, ok := mr.db.(*sqlx.DB)
+	if !ok {
+		return nil, ErrNoTransactions
+	}
+
+	return db.BeginTxx(context.Background(), opts)
+}
+
+// WithTx returns a repository that runs its statements in tx.
+func (mr *PostgresRepository) WithTx(tx *sqlx.Tx) *PostgresRepository {
+	return &PostgresRepository{
+		db: tx,
+	}
+}
+
 func (mr *PostgresRepository) Get(id uuid.UUID) (record.Record, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	var r postgresRecord
-	if err := mr.db.GetContext(ctx, r, "SELECT * FROM records WHERE id = ?", id); err != nil {
+	if err := mr.db.GetContext(ctx, &r, "SELECT * FROM records WHERE id = $1", id); err != nil {
+		if err == sql.ErrNoRows {
+			return record.Record{}, record.ErrRecordNotFound
+		}
 		return record.Record{}, err
 	}
 
 	// Convert to aggregate
-	return r.ToRecord(), nil
+	return r.ToRecord()
 }
 
 func (mr *PostgresRepository) Add(r record.Record) error {
@@ -118,7 +324,7 @@ func (mr *PostgresRepository) Add(r record.Record) error {
 	defer cancel()
 
 	internal := NewFromRecord(r)
-	_, err := mr.db.NamedExecContext(ctx, `INSERT INTO records (id, name, kind) VALUES (:id, :name, :kind)`, internal)
+	_, err := mr.db.NamedExecContext(ctx, insertRecordQuery, internal)
 	if err != nil {
 		return err
 	}
@@ -126,28 +332,110 @@ func (mr *PostgresRepository) Add(r record.Record) error {
 	return nil
 }
 
+// AddBatch inserts the records with multi-row statements, in a transaction
+// when they take more than one, so they are stored all together or not at
+// all.
+func (mr *PostgresRepository) AddBatch(rr []record.Record) error {
+	if len(rr) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	internal := make([]postgresRecord, 0, len(rr))
+	for _, r := range rr {
+		internal = append(internal, NewFromRecord(r))
+	}
+
+	return pgbatch.Insert(ctx, mr.db, insertRecordQuery, internal)
+}
+
 func (mr *PostgresRepository) FindRecords() ([]record.Record, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	var r []*postgresRecord
-	if err := mr.db.SelectContext(ctx, r, "SELECT * FROM records"); err != nil {
+	if err := mr.db.SelectContext(ctx, &r, "SELECT * FROM records"); err != nil {
 		return []record.Record{}, err
 	}
 
 	// Convert to aggregate
 	var rr []record.Record
 	for _, r := range r {
-		rr = append(rr, r.ToRecord())
+		rec, err := r.ToRecord()
+		if err != nil {
+			return []record.Record{}, err
+		}
+		rr = append(rr, rec)
 	}
 
 	return rr, nil
 }
 
 func (mr *PostgresRepository) Update(r *record.Record) error {
-	panic("to implement")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	internal := NewFromRecord(*r)
+	res, err := mr.db.NamedExecContext(ctx, updateRecordQuery, internal)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return record.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (mr *PostgresRepository) Delete(id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := mr.db.NamedExecContext(ctx, "DELETE FROM records WHERE id = :id", map[string]interface{}{"id": id})
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return record.ErrRecordNotFound
+	}
+
+	return nil
 }
 
+// AddSong links the song to the record through the record_id column of the
+// songs table, where the song must already be stored.
 func (mr *PostgresRepository) AddSong(id uuid.UUID, s *song.Song) error {
-	panic("to implement")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := mr.db.NamedExecContext(ctx, "UPDATE songs SET record_id = :record_id WHERE id = :id", map[string]interface{}{
+		"id":        s.GetID(),
+		"record_id": id,
+	})
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return song.ErrSongNotFound
+	}
+
+	s.SetRecordID(id)
+	return nil
 }
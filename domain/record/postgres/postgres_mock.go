@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 )
 
@@ -32,7 +33,7 @@ func (mdb *MockDB) NamedExecContext(ctx context.Context, query string, arg inter
 	fmt.Println(arg)
 	mdb.callParams = []interface{}{query}
 	mdb.callParams = append(mdb.callParams, arg)
-	return nil, nil
+	return driver.RowsAffected(1), nil
 }
 
 func (mdb *MockDB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
@@ -2,36 +2,128 @@ package record
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/money"
 	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/domain/taxonomy"
+	"github.com/rodrwan/collection/domain/validation"
 )
 
 var (
 	ErrMissingValues  = errors.New("missing value")
+	ErrInvalidKind    = errors.New("invalid record kind")
 	ErrRecordNotFound = errors.New("record not found")
 )
 
+// Record kinds supported by the collection.
+const (
+	KindVinyl    = "vinyl"
+	KindMP3      = "mp3"
+	KindAAC      = "aac"
+	KindLossless = "lossless"
+	KindOgg      = "ogg"
+)
+
+var kinds = []string{KindVinyl, KindMP3, KindAAC, KindLossless, KindOgg}
+
+// Kinds lists every supported record kind.
+func Kinds() []string {
+	return append([]string(nil), kinds...)
+}
+
+// IsValidKind reports whether kind is one of the supported record kinds.
+func IsValidKind(kind string) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
 type Record struct {
-	id    uuid.UUID
-	name  string
-	kind  string
-	songs []*song.Song
+	id             uuid.UUID
+	name           string
+	artist         string
+	kind           string
+	genreID        uuid.UUID
+	tags           []string
+	gradingHistory []Grading
+	status         Status
+	acquisition    Acquisition
+	valuations     []Valuation
+	placement      Placement
+	cover          Cover
+	musicBrainz    MusicBrainz
+	songs          []*song.Song
 }
 
 type PublicRecord struct {
-	ID    uuid.UUID    `json:"id,omitempty"`
-	Name  string       `json:"name,omitempty"`
-	Kind  string       `json:"kind,omitempty"`
-	Songs []*song.Song `json:"songs,omitempty"`
+	ID             uuid.UUID    `json:"id,omitempty"`
+	Name           string       `json:"name,omitempty"`
+	Artist         string       `json:"artist,omitempty"`
+	Kind           string       `json:"kind,omitempty"`
+	GenreID        *uuid.UUID   `json:"genreId,omitempty"`
+	Tags           []string     `json:"tags,omitempty"`
+	MediaGrade     Grade        `json:"mediaGrade,omitempty"`
+	SleeveGrade    Grade        `json:"sleeveGrade,omitempty"`
+	GradingHistory []Grading    `json:"gradingHistory,omitempty"`
+	Status         Status       `json:"status,omitempty"`
+	Acquisition    *Acquisition `json:"acquisition,omitempty"`
+	EstimatedValue *money.Money `json:"estimatedValue,omitempty"`
+	ValueHistory   []Valuation  `json:"valueHistory,omitempty"`
+	Placement      *Placement   `json:"placement,omitempty"`
+	PlayCount      int          `json:"playCount"`
+	AverageRating  *float64     `json:"averageRating,omitempty"`
+	RatingCount    int          `json:"ratingCount,omitempty"`
+	Cover          *Cover       `json:"cover,omitempty"`
+	MusicBrainz    *MusicBrainz `json:"musicbrainz,omitempty"`
+	// Images maps each cover art rendition to its URL.
+	Images map[string]string `json:"images,omitempty"`
+	Songs  []*song.Song      `json:"songs,omitempty"`
 }
 
 func (r *Record) ToPublic() PublicRecord {
-	return PublicRecord{
-		ID:   r.GetID(),
-		Name: r.GetName(),
-		Kind: r.GetKind(),
+	pr := PublicRecord{
+		ID:     r.GetID(),
+		Name:   r.GetName(),
+		Artist: r.GetArtist(),
+		Kind:   r.GetKind(),
+		Tags:   r.GetTags(),
+
+		MediaGrade:     r.GetMediaGrade(),
+		SleeveGrade:    r.GetSleeveGrade(),
+		GradingHistory: r.GetGradingHistory(),
+		Status:         r.GetStatus(),
+		ValueHistory:   r.GetValuations(),
+	}
+	if r.genreID != uuid.Nil {
+		genreID := r.genreID
+		pr.GenreID = &genreID
+	}
+	if !r.acquisition.IsZero() {
+		acquisition := r.acquisition
+		pr.Acquisition = &acquisition
+	}
+	if value, ok := r.GetEstimatedValue(); ok {
+		pr.EstimatedValue = &value
 	}
+	if !r.placement.IsZero() {
+		placement := r.placement
+		pr.Placement = &placement
+	}
+	if !r.cover.IsZero() {
+		cover := r.cover
+		pr.Cover = &cover
+	}
+	if !r.musicBrainz.IsZero() {
+		mb := r.musicBrainz
+		pr.MusicBrainz = &mb
+	}
+
+	return pr
 }
 
 func (pr *PublicRecord) ToRecord() *Record {
@@ -39,7 +131,27 @@ func (pr *PublicRecord) ToRecord() *Record {
 
 	r.SetID(pr.ID)
 	r.SetName(pr.Name)
+	r.SetArtist(pr.Artist)
 	r.SetKind(pr.Kind)
+	if pr.GenreID != nil {
+		r.SetGenreID(*pr.GenreID)
+	}
+	r.SetTags(pr.Tags)
+	r.SetGradingHistory(pr.GradingHistory)
+	r.SetStatus(pr.Status)
+	if pr.Acquisition != nil {
+		r.acquisition = *pr.Acquisition
+	}
+	r.SetValuations(pr.ValueHistory)
+	if pr.Placement != nil {
+		r.SetPlacement(*pr.Placement)
+	}
+	if pr.Cover != nil {
+		r.SetCover(*pr.Cover)
+	}
+	if pr.MusicBrainz != nil {
+		r.SetMusicBrainz(*pr.MusicBrainz)
+	}
 
 	return r
 }
@@ -54,29 +166,40 @@ func ToPublicArray(records []Record) []PublicRecord {
 }
 
 func NewRecord(name, kind string) (Record, error) {
-	if name == "" {
-		return Record{}, ErrMissingValues
+	return NewRecordWithID(uuid.New(), name, kind)
+}
+
+func NewRecordWithID(id uuid.UUID, name, kind string) (Record, error) {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	if err := validate(name, kind); err != nil {
+		return Record{}, err
 	}
 
 	return Record{
-		id:    uuid.New(),
-		name:  name,
-		kind:  kind,
-		songs: make([]*song.Song, 0),
+		id:     id,
+		name:   name,
+		kind:   kind,
+		status: StatusOwned,
+		songs:  make([]*song.Song, 0),
 	}, nil
 }
 
-func NewRecordWithID(id uuid.UUID, name, kind string) (Record, error) {
-	if name == "" {
-		return Record{}, ErrMissingValues
+// validate reports every invalid field of a new record as validation.Errors.
+func validate(name, kind string) error {
+	var errs validation.Errors
+
+	if strings.TrimSpace(name) == "" {
+		errs = errs.Add("name", validation.CodeRequired, ErrMissingValues)
 	}
 
-	return Record{
-		id:    id,
-		name:  name,
-		kind:  kind,
-		songs: make([]*song.Song, 0),
-	}, nil
+	switch {
+	case kind == "":
+		errs = errs.Add("kind", validation.CodeRequired, ErrMissingValues)
+	case !IsValidKind(kind):
+		errs = errs.Add("kind", validation.CodeInvalid, ErrInvalidKind)
+	}
+
+	return errs.Err()
 }
 
 func (r *Record) AddSong(song *song.Song) error {
@@ -93,10 +216,25 @@ func (r *Record) SetName(name string) {
 	r.name = name
 }
 
+// SetArtist sets who the record is by. It is optional, imports fill it in
+// when the source knows it.
+func (r *Record) SetArtist(artist string) {
+	r.artist = strings.TrimSpace(artist)
+}
+
 func (r *Record) SetKind(kind string) {
 	r.kind = kind
 }
 
+func (r *Record) SetGenreID(genreID uuid.UUID) {
+	r.genreID = genreID
+}
+
+// SetTags replaces the record tags, normalizing them on the way in.
+func (r *Record) SetTags(tags []string) {
+	r.tags = taxonomy.NormalizeTags(tags)
+}
+
 func (r Record) GetID() uuid.UUID {
 	return r.id
 }
@@ -105,6 +243,10 @@ func (r Record) GetName() string {
 	return r.name
 }
 
+func (r Record) GetArtist() string {
+	return r.artist
+}
+
 func (r Record) GetKind() string {
 	return r.kind
 }
@@ -112,3 +254,26 @@ func (r Record) GetKind() string {
 func (r Record) GetSongs() []*song.Song {
 	return r.songs
 }
+
+func (r Record) GetGenreID() uuid.UUID {
+	return r.genreID
+}
+
+func (r Record) GetTags() []string {
+	return r.tags
+}
+
+// HasTag reports whether the record is tagged with the given tag.
+func (r Record) HasTag(tag string) bool {
+	tags := taxonomy.NormalizeTags([]string{tag})
+	if len(tags) == 0 {
+		return false
+	}
+
+	for _, t := range r.tags {
+		if t == tags[0] {
+			return true
+		}
+	}
+	return false
+}
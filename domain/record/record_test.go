@@ -1,6 +1,7 @@
 package record_test
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -38,7 +39,7 @@ func TestRecord_NewRecord(t *testing.T) {
 			// Create a new customer
 			_, err := record.NewRecord(tc.name, tc.kind)
 			// Check if the error matches the expected error
-			if err != tc.expectedErr {
+			if !errors.Is(err, tc.expectedErr) {
 				t.Errorf("Expected error %v, got %v", tc.expectedErr, err)
 			}
 
@@ -90,7 +91,7 @@ func TestNewSongWithID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := record.NewRecordWithID(tt.args.id, tt.args.name, tt.args.kind)
 			if err != nil {
-				if err != tt.wantErr {
+				if !errors.Is(err, tt.wantErr) {
 					t.Errorf("NewRecordWithID() error = %v, wantErr %v", err, tt.wantErr)
 				}
 				return
@@ -8,7 +8,10 @@ import (
 type RecordRepository interface {
 	Get(uuid.UUID) (Record, error)
 	Add(Record) error
+	// AddBatch adds several records at once, all of them or none.
+	AddBatch([]Record) error
 	Update(*Record) error
+	Delete(uuid.UUID) error
 	FindRecords() ([]Record, error)
 	AddSong(uuid.UUID, *song.Song) error
 }
@@ -0,0 +1,65 @@
+package record
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+var ErrInvalidSort = errors.New("invalid sort order")
+
+// Sort is a listing order: a field name, optionally prefixed with "-" for
+// descending order, e.g. "-rating".
+type Sort string
+
+// sortFields compares two public records on each sortable field.
+var sortFields = map[string]func(a, b PublicRecord) bool{
+	"name": func(a, b PublicRecord) bool {
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	},
+	"rating": func(a, b PublicRecord) bool {
+		return rating(a) < rating(b)
+	},
+	"plays": func(a, b PublicRecord) bool {
+		return a.PlayCount < b.PlayCount
+	},
+}
+
+// ParseSort checks the field of a sort order.
+func ParseSort(value string) (Sort, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if _, ok := sortFields[strings.TrimPrefix(value, "-")]; !ok && value != "" {
+		return "", ErrInvalidSort
+	}
+
+	return Sort(value), nil
+}
+
+// Apply sorts the records in place. Records without a rating sort last
+// when sorting by rating in either direction.
+func (s Sort) Apply(records []PublicRecord) {
+	field := strings.TrimPrefix(string(s), "-")
+	less, ok := sortFields[field]
+	if !ok {
+		return
+	}
+
+	descending := strings.HasPrefix(string(s), "-")
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if field == "rating" && (a.AverageRating == nil) != (b.AverageRating == nil) {
+			return a.AverageRating != nil
+		}
+		if descending {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+}
+
+func rating(pr PublicRecord) float64 {
+	if pr.AverageRating == nil {
+		return 0
+	}
+	return *pr.AverageRating
+}
@@ -0,0 +1,64 @@
+package record_test
+
+import (
+	"testing"
+
+	"github.com/rodrwan/collection/domain/record"
+)
+
+func TestSort_Apply(t *testing.T) {
+	four, three := 4.0, 3.0
+	records := func() []record.PublicRecord {
+		return []record.PublicRecord{
+			{Name: "b", AverageRating: &three, PlayCount: 5},
+			{Name: "C"},
+			{Name: "a", AverageRating: &four, PlayCount: 1},
+		}
+	}
+
+	tests := []struct {
+		sort     string
+		expected []string
+	}{
+		{sort: "name", expected: []string{"a", "b", "C"}},
+		{sort: "-name", expected: []string{"C", "b", "a"}},
+		{sort: "rating", expected: []string{"b", "a", "C"}},
+		{sort: "-rating", expected: []string{"a", "b", "C"}},
+		{sort: "-plays", expected: []string{"b", "a", "C"}},
+		{sort: "", expected: []string{"b", "C", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			s, err := record.ParseSort(tt.sort)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := records()
+			s.Apply(rr)
+			for i, name := range tt.expected {
+				if rr[i].Name != name {
+					t.Errorf("Expected %v, got %v", tt.expected, rr)
+					break
+				}
+			}
+		})
+	}
+
+	if _, err := record.ParseSort("-price"); err != record.ErrInvalidSort {
+		t.Errorf("Expected error %v, got %v", record.ErrInvalidSort, err)
+	}
+}
+
+func TestFilter_MatchPublic(t *testing.T) {
+	four := 4.0
+	filter := record.Filter{MinRating: 3.5}
+
+	if !filter.MatchPublic(record.PublicRecord{AverageRating: &four}) {
+		t.Errorf("Expected a 4 star record to match")
+	}
+	if filter.MatchPublic(record.PublicRecord{}) {
+		t.Errorf("Expected an unrated record not to match")
+	}
+}
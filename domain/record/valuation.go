@@ -0,0 +1,75 @@
+package record
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rodrwan/collection/domain/money"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrInvalidValue = errors.New("estimated value cannot be negative")
+)
+
+// Valuation is a dated estimate of what a record is worth.
+type Valuation struct {
+	Date   time.Time   `json:"date"`
+	Value  money.Money `json:"value"`
+	Source string      `json:"source,omitempty"`
+}
+
+// AddValuation appends an estimate to the record price history.
+func (r *Record) AddValuation(v Valuation) error {
+	var errs validation.Errors
+
+	if v.Value.Amount < 0 {
+		errs = errs.Add("value.amount", validation.CodeOutOfRange, ErrInvalidValue)
+	}
+	if !money.IsValidCurrency(v.Value.Currency) {
+		errs = errs.Add("value.currency", validation.CodeInvalid, money.ErrInvalidCurrency)
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	v.Value.Currency = strings.ToUpper(v.Value.Currency)
+	r.SetValuations(append(r.GetValuations(), v))
+
+	return nil
+}
+
+// SetValuations replaces the price history, keeping it sorted by date.
+func (r *Record) SetValuations(valuations []Valuation) {
+	if len(valuations) == 0 {
+		r.valuations = nil
+		return
+	}
+
+	r.valuations = make([]Valuation, len(valuations))
+	copy(r.valuations, valuations)
+	sort.SliceStable(r.valuations, func(i, j int) bool {
+		return r.valuations[i].Date.Before(r.valuations[j].Date)
+	})
+}
+
+func (r Record) GetValuations() []Valuation {
+	if len(r.valuations) == 0 {
+		return nil
+	}
+
+	valuations := make([]Valuation, len(r.valuations))
+	copy(valuations, r.valuations)
+	return valuations
+}
+
+// GetEstimatedValue returns the latest estimate, if the record has any.
+func (r Record) GetEstimatedValue() (money.Money, bool) {
+	if len(r.valuations) == 0 {
+		return money.Money{}, false
+	}
+
+	return r.valuations[len(r.valuations)-1].Value, true
+}
@@ -0,0 +1,52 @@
+package record_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/domain/money"
+	"github.com/rodrwan/collection/domain/record"
+)
+
+func TestRecord_AddValuation(t *testing.T) {
+	first := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   money.Money
+		wantErr error
+	}{
+		{name: "Valid value", value: money.Money{Amount: 2500, Currency: "eur"}},
+		{name: "Negative value", value: money.Money{Amount: -1, Currency: "EUR"}, wantErr: record.ErrInvalidValue},
+		{name: "Missing currency", value: money.Money{Amount: 2500}, wantErr: money.ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := record.NewRecord("r1", "vinyl")
+			if err := r.AddValuation(record.Valuation{Date: first, Value: tt.value}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Record.AddValuation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("Latest estimate is the record value", func(t *testing.T) {
+		r, _ := record.NewRecord("r1", "vinyl")
+		if _, ok := r.GetEstimatedValue(); ok {
+			t.Errorf("Expected no estimated value")
+		}
+
+		r.AddValuation(record.Valuation{Date: second, Value: money.Money{Amount: 4000, Currency: "EUR"}})
+		r.AddValuation(record.Valuation{Date: first, Value: money.Money{Amount: 2500, Currency: "EUR"}})
+
+		value, ok := r.GetEstimatedValue()
+		if !ok || value.Amount != 4000 {
+			t.Errorf("Record.GetEstimatedValue() = %v, want 4000", value)
+		}
+		if got := r.GetValuations(); len(got) != 2 || !got[0].Date.Equal(first) {
+			t.Errorf("Record.GetValuations() = %v", got)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/release"
+)
+
+type MemoryRepository struct {
+	releases []memoryRelease
+
+	sync.Mutex
+}
+
+type memoryRelease struct {
+	ID       uuid.UUID         `db:"id"`
+	Name     string            `db:"name"`
+	Variants []release.Variant `db:"variants"`
+}
+
+func NewFromRelease(r release.Release) memoryRelease {
+	return memoryRelease{
+		ID:       r.GetID(),
+		Name:     r.GetName(),
+		Variants: r.GetVariants(),
+	}
+}
+
+func (mr memoryRelease) ToRelease() release.Release {
+	r := release.Release{}
+
+	r.SetID(mr.ID)
+	r.SetName(mr.Name)
+	r.SetVariants(append([]release.Variant(nil), mr.Variants...))
+
+	return r
+}
+
+// Create a new memory repository
+func New(ctx context.Context) (*MemoryRepository, error) {
+	return &MemoryRepository{
+		releases: make([]memoryRelease, 0),
+	}, nil
+}
+
+func (mr *MemoryRepository) Get(id uuid.UUID) (release.Release, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for _, r := range mr.releases {
+		if r.ID == id {
+			return r.ToRelease(), nil
+		}
+	}
+
+	return release.Release{}, release.ErrReleaseNotFound
+}
+
+func (mr *MemoryRepository) Add(r release.Release) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	mr.releases = append(mr.releases, NewFromRelease(r))
+
+	return nil
+}
+
+func (mr *MemoryRepository) Update(r *release.Release) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, m := range mr.releases {
+		if m.ID == r.GetID() {
+			mr.releases[i] = NewFromRelease(*r)
+			return nil
+		}
+	}
+
+	return release.ErrReleaseNotFound
+}
+
+func (mr *MemoryRepository) Delete(id uuid.UUID) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, m := range mr.releases {
+		if m.ID == id {
+			mr.releases = append(mr.releases[:i], mr.releases[i+1:]...)
+			return nil
+		}
+	}
+
+	return release.ErrReleaseNotFound
+}
+
+func (mr *MemoryRepository) FindReleases() ([]release.Release, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	var rr []release.Release
+	for _, r := range mr.releases {
+		rr = append(rr, r.ToRelease())
+	}
+
+	return rr, nil
+}
+
+func (mr *MemoryRepository) FindByRecord(recordID uuid.UUID) (release.Release, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for _, m := range mr.releases {
+		for _, v := range m.Variants {
+			if v.RecordID == recordID {
+				return m.ToRelease(), nil
+			}
+		}
+	}
+
+	return release.Release{}, release.ErrRecordNoRelease
+}
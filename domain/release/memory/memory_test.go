@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/release"
+)
+
+func TestMemoryRepository_Releases(t *testing.T) {
+	repo, _ := New(context.Background())
+
+	original := uuid.New()
+	r, _ := release.NewRelease("Kind of Blue")
+	r.AddVariant(release.Variant{RecordID: original, Relation: release.RelationOriginal})
+	if err := repo.Add(r); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.FindByRecord(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetID() != r.GetID() {
+		t.Errorf("Expected release %s, got %s", r.GetID(), got.GetID())
+	}
+
+	reissue := uuid.New()
+	got.AddVariant(release.Variant{RecordID: reissue, Relation: release.RelationReissue, Of: original})
+	if err := repo.Update(&got); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.FindByRecord(reissue); err != nil {
+		t.Errorf("Expected the reissue to be found, got %v", err)
+	}
+	if _, err := repo.FindByRecord(uuid.New()); err != release.ErrRecordNoRelease {
+		t.Errorf("Expected error %v, got %v", release.ErrRecordNoRelease, err)
+	}
+
+	if err := repo.Delete(r.GetID()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(r.GetID()); err != release.ErrReleaseNotFound {
+		t.Errorf("Expected error %v, got %v", release.ErrReleaseNotFound, err)
+	}
+}
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rodrwan/collection/domain/release"
+)
+
+type IPostgresSQl interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+type PostgresRepository struct {
+	db IPostgresSQl
+}
+
+type (
+	SqlOpener func(string, string) (*sqlx.DB, error)
+)
+
+type postgresRelease struct {
+	ID       uuid.UUID `db:"id"`
+	Name     string    `db:"name"`
+	Variants variants  `db:"variants"`
+}
+
+// variants is stored as a jsonb column.
+type variants []release.Variant
+
+func (v variants) Value() (driver.Value, error) {
+	if len(v) == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(v)
+}
+
+func (v *variants) Scan(src interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, v)
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	}
+
+	return errors.New("unsupported jsonb value")
+}
+
+func NewFromRelease(r release.Release) postgresRelease {
+	return postgresRelease{
+		ID:       r.GetID(),
+		Name:     r.GetName(),
+		Variants: variants(r.GetVariants()),
+	}
+}
+
+func (pr postgresRelease) ToRelease() release.Release {
+	r := release.Release{}
+
+	r.SetID(pr.ID)
+	r.SetName(pr.Name)
+	r.SetVariants(pr.Variants)
+
+	return r
+}
+
+// Create a new postgres repository
+func New(ctx context.Context, connectionString string, open SqlOpener) (*PostgresRepository, error) {
+	client, err := open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return &PostgresRepository{
+		db: client,
+	}, nil
+}
+
+// WithTx returns a repository that runs its statements in tx.
+func (pr *PostgresRepository) WithTx(tx *sqlx.Tx) *PostgresRepository {
+	return &PostgresRepository{
+		db: tx,
+	}
+}
+
+func (pr *PostgresRepository) Get(id uuid.UUID) (release.Release, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var r postgresRelease
+	if err := pr.db.GetContext(ctx, &r, "SELECT * FROM releases WHERE id = $1", id); err != nil {
+		if err == sql.ErrNoRows {
+			return release.Release{}, release.ErrReleaseNotFound
+		}
+		return release.Release{}, err
+	}
+
+	return r.ToRelease(), nil
+}
+
+func (pr *PostgresRepository) Add(r release.Release) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.NamedExecContext(ctx, `INSERT INTO releases (id, name, variants) VALUES (:id, :name, :variants)`, NewFromRelease(r))
+	return err
+}
+
+func (pr *PostgresRepository) Update(r *release.Release) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := pr.db.NamedExecContext(ctx, `UPDATE releases SET name = :name, variants = :variants WHERE id = :id`, NewFromRelease(*r))
+	if err != nil {
+		return err
+	}
+
+	return notFoundIfNone(res)
+}
+
+func (pr *PostgresRepository) Delete(id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := pr.db.NamedExecContext(ctx, `DELETE FROM releases WHERE id = :id`, map[string]interface{}{"id": id})
+	if err != nil {
+		return err
+	}
+
+	return notFoundIfNone(res)
+}
+
+func (pr *PostgresRepository) FindReleases() ([]release.Release, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var prs []postgresRelease
+	if err := pr.db.SelectContext(ctx, &prs, "SELECT * FROM releases ORDER BY name"); err != nil {
+		return []release.Release{}, err
+	}
+
+	var rr []release.Release
+	for _, r := range prs {
+		rr = append(rr, r.ToRelease())
+	}
+
+	return rr, nil
+}
+
+func (pr *PostgresRepository) FindByRecord(recordID uuid.UUID) (release.Release, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	contains, err := json.Marshal([]map[string]uuid.UUID{{"recordId": recordID}})
+	if err != nil {
+		return release.Release{}, err
+	}
+
+	var r postgresRelease
+	if err := pr.db.GetContext(ctx, &r, "SELECT * FROM releases WHERE variants @> $1::jsonb LIMIT 1", string(contains)); err != nil {
+		if err == sql.ErrNoRows {
+			return release.Release{}, release.ErrRecordNoRelease
+		}
+		return release.Release{}, err
+	}
+
+	return r.ToRelease(), nil
+}
+
+func notFoundIfNone(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return release.ErrReleaseNotFound
+	}
+
+	return nil
+}
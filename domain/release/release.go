@@ -0,0 +1,255 @@
+package release
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrMissingValues    = errors.New("missing value")
+	ErrInvalidRelation  = errors.New("invalid variant relation")
+	ErrOriginalExists   = errors.New("release already has an original")
+	ErrAlreadyVariant   = errors.New("record is already a variant of the release")
+	ErrUnknownVariant   = errors.New("related record is not a variant of the release")
+	ErrVariantNotFound  = errors.New("variant not found")
+	ErrVariantInUse     = errors.New("other variants are related to this one")
+	ErrReleaseNotFound  = errors.New("release not found")
+	ErrRecordInRelease  = errors.New("record already belongs to a release")
+	ErrRecordNoRelease  = errors.New("record does not belong to any release")
+	ErrSelfRelation     = errors.New("a variant cannot be related to itself")
+	ErrOriginalRelation = errors.New("the original is not related to other variants")
+)
+
+// Relation tells how a variant relates to another variant of the same
+// master release.
+type Relation string
+
+const (
+	// RelationOriginal is the first pressing, the one the others derive from.
+	RelationOriginal Relation = "original"
+	RelationReissue  Relation = "reissue-of"
+	RelationRemaster Relation = "remaster-of"
+	RelationDigital  Relation = "digital-copy-of"
+)
+
+// ParseRelation reads a relation ignoring case and surrounding spaces.
+func ParseRelation(value string) (Relation, error) {
+	switch r := Relation(strings.ToLower(strings.TrimSpace(value))); r {
+	case RelationOriginal, RelationReissue, RelationRemaster, RelationDigital:
+		return r, nil
+	}
+
+	return "", ErrInvalidRelation
+}
+
+// Variant is a record that is a pressing of a master release. Of is the
+// variant it derives from and is empty for the original.
+type Variant struct {
+	RecordID uuid.UUID `json:"recordId"`
+	Relation Relation  `json:"relation"`
+	Of       uuid.UUID `json:"of,omitempty"`
+}
+
+// Release is a master release grouping the records that are pressings of
+// the same album, e.g. an original vinyl, its remaster and an mp3 rip.
+type Release struct {
+	id       uuid.UUID
+	name     string
+	variants []Variant
+}
+
+// PublicVariant is a variant with the details of its record.
+type PublicVariant struct {
+	Variant
+	Name   string        `json:"name,omitempty"`
+	Kind   string        `json:"kind,omitempty"`
+	Status record.Status `json:"status,omitempty"`
+}
+
+type PublicRelease struct {
+	ID       uuid.UUID       `json:"id,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	Variants []PublicVariant `json:"variants"`
+	// Formats lists the distinct kinds of the owned variants and
+	// OwnedFormats counts them.
+	Formats      []string `json:"formats"`
+	OwnedFormats int      `json:"ownedFormats"`
+}
+
+func NewRelease(name string) (Release, error) {
+	return NewReleaseWithID(uuid.New(), name)
+}
+
+func NewReleaseWithID(id uuid.UUID, name string) (Release, error) {
+	if strings.TrimSpace(name) == "" {
+		var errs validation.Errors
+		return Release{}, errs.Add("name", validation.CodeRequired, ErrMissingValues).Err()
+	}
+
+	return Release{
+		id:       id,
+		name:     strings.TrimSpace(name),
+		variants: make([]Variant, 0),
+	}, nil
+}
+
+// AddVariant adds a record to the release. A release has at most one
+// original and every other variant derives from a variant already in it.
+func (r *Release) AddVariant(v Variant) error {
+	var errs validation.Errors
+
+	if v.RecordID == uuid.Nil {
+		errs = errs.Add("recordId", validation.CodeRequired, ErrMissingValues)
+	} else if r.HasVariant(v.RecordID) {
+		errs = errs.Add("recordId", validation.CodeNotAllowed, ErrAlreadyVariant)
+	}
+
+	relation, err := ParseRelation(string(v.Relation))
+	if err != nil {
+		errs = errs.Add("relation", validation.CodeInvalid, err)
+	}
+
+	switch {
+	case relation == "":
+	case relation == RelationOriginal && r.Original() != uuid.Nil:
+		errs = errs.Add("relation", validation.CodeNotAllowed, ErrOriginalExists)
+	case relation == RelationOriginal && v.Of != uuid.Nil:
+		errs = errs.Add("of", validation.CodeNotAllowed, ErrOriginalRelation)
+	case relation == RelationOriginal:
+	case v.Of == uuid.Nil:
+		errs = errs.Add("of", validation.CodeRequired, ErrMissingValues)
+	case v.Of == v.RecordID:
+		errs = errs.Add("of", validation.CodeInvalid, ErrSelfRelation)
+	case !r.HasVariant(v.Of):
+		errs = errs.Add("of", validation.CodeInvalid, ErrUnknownVariant)
+	}
+
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	v.Relation = relation
+	r.variants = append(r.variants, v)
+	return nil
+}
+
+// RemoveVariant takes a record out of the release. Variants deriving from
+// it have to be removed first.
+func (r *Release) RemoveVariant(recordID uuid.UUID) error {
+	index := -1
+	for i, v := range r.variants {
+		if v.RecordID == recordID {
+			index = i
+		}
+		if v.Of == recordID {
+			return ErrVariantInUse
+		}
+	}
+
+	if index < 0 {
+		return ErrVariantNotFound
+	}
+
+	r.variants = append(r.variants[:index], r.variants[index+1:]...)
+	return nil
+}
+
+// HasVariant reports whether the record is a variant of the release.
+func (r Release) HasVariant(recordID uuid.UUID) bool {
+	for _, v := range r.variants {
+		if v.RecordID == recordID {
+			return true
+		}
+	}
+	return false
+}
+
+// Original returns the record of the original pressing, if known.
+func (r Release) Original() uuid.UUID {
+	for _, v := range r.variants {
+		if v.Relation == RelationOriginal {
+			return v.RecordID
+		}
+	}
+	return uuid.Nil
+}
+
+func (r *Release) SetID(id uuid.UUID) {
+	r.id = id
+}
+
+func (r *Release) SetName(name string) {
+	r.name = name
+}
+
+func (r *Release) SetVariants(variants []Variant) {
+	r.variants = variants
+}
+
+func (r Release) GetID() uuid.UUID {
+	return r.id
+}
+
+func (r Release) GetName() string {
+	return r.name
+}
+
+func (r Release) GetVariants() []Variant {
+	return append([]Variant(nil), r.variants...)
+}
+
+// ToPublic returns the release with its variants only. Use WithRecords to
+// fill in the record details and the format counts.
+func (r Release) ToPublic() PublicRelease {
+	pr := PublicRelease{
+		ID:       r.GetID(),
+		Name:     r.GetName(),
+		Variants: make([]PublicVariant, 0, len(r.variants)),
+		Formats:  make([]string, 0),
+	}
+	for _, v := range r.variants {
+		pr.Variants = append(pr.Variants, PublicVariant{Variant: v})
+	}
+
+	return pr
+}
+
+// WithRecords returns the public release with the details of each variant
+// and the formats it is owned in. Variants missing from the given records
+// are kept without details.
+func (r Release) WithRecords(records []record.Record) PublicRelease {
+	byID := make(map[uuid.UUID]record.Record, len(records))
+	for _, rec := range records {
+		byID[rec.GetID()] = rec
+	}
+
+	pr := r.ToPublic()
+	formats := make(map[string]bool)
+	for i, v := range pr.Variants {
+		rec, ok := byID[v.RecordID]
+		if !ok {
+			continue
+		}
+
+		pr.Variants[i].Name = rec.GetName()
+		pr.Variants[i].Kind = rec.GetKind()
+		pr.Variants[i].Status = rec.GetStatus()
+
+		if rec.IsOwned() {
+			formats[rec.GetKind()] = true
+		}
+	}
+
+	for kind := range formats {
+		pr.Formats = append(pr.Formats, kind)
+	}
+	sort.Strings(pr.Formats)
+	pr.OwnedFormats = len(pr.Formats)
+
+	return pr
+}
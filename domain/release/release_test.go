@@ -0,0 +1,143 @@
+package release_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/release"
+)
+
+func TestRelease_NewRelease(t *testing.T) {
+	if _, err := release.NewRelease("  "); !errors.Is(err, release.ErrMissingValues) {
+		t.Errorf("Expected error %v, got %v", release.ErrMissingValues, err)
+	}
+
+	r, err := release.NewRelease(" Kind of Blue ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.GetName() != "Kind of Blue" {
+		t.Errorf("Expected a trimmed name, got %q", r.GetName())
+	}
+}
+
+func TestRelease_AddVariant(t *testing.T) {
+	original, remaster := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name        string
+		variant     release.Variant
+		expectedErr error
+	}{
+		{
+			name:    "Reissue of the original",
+			variant: release.Variant{RecordID: uuid.New(), Relation: "Reissue-Of", Of: original},
+		},
+		{
+			name:    "Digital copy of a remaster",
+			variant: release.Variant{RecordID: uuid.New(), Relation: release.RelationDigital, Of: remaster},
+		},
+		{
+			name:        "Second original",
+			variant:     release.Variant{RecordID: uuid.New(), Relation: release.RelationOriginal},
+			expectedErr: release.ErrOriginalExists,
+		},
+		{
+			name:        "Already a variant",
+			variant:     release.Variant{RecordID: remaster, Relation: release.RelationReissue, Of: original},
+			expectedErr: release.ErrAlreadyVariant,
+		},
+		{
+			name:        "Unknown relation",
+			variant:     release.Variant{RecordID: uuid.New(), Relation: "bootleg-of", Of: original},
+			expectedErr: release.ErrInvalidRelation,
+		},
+		{
+			name:        "Missing related variant",
+			variant:     release.Variant{RecordID: uuid.New(), Relation: release.RelationReissue},
+			expectedErr: release.ErrMissingValues,
+		},
+		{
+			name:        "Related to a record outside the release",
+			variant:     release.Variant{RecordID: uuid.New(), Relation: release.RelationReissue, Of: uuid.New()},
+			expectedErr: release.ErrUnknownVariant,
+		},
+		{
+			name:        "Missing record",
+			variant:     release.Variant{Relation: release.RelationReissue, Of: original},
+			expectedErr: release.ErrMissingValues,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := release.NewRelease("Kind of Blue")
+			r.AddVariant(release.Variant{RecordID: original, Relation: release.RelationOriginal})
+			r.AddVariant(release.Variant{RecordID: remaster, Relation: release.RelationRemaster, Of: original})
+
+			err := r.AddVariant(tt.variant)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if err == nil && !r.HasVariant(tt.variant.RecordID) {
+				t.Errorf("Expected %s to be a variant", tt.variant.RecordID)
+			}
+		})
+	}
+}
+
+func TestRelease_RemoveVariant(t *testing.T) {
+	original, reissue := uuid.New(), uuid.New()
+
+	r, _ := release.NewRelease("Kind of Blue")
+	r.AddVariant(release.Variant{RecordID: original, Relation: release.RelationOriginal})
+	r.AddVariant(release.Variant{RecordID: reissue, Relation: release.RelationReissue, Of: original})
+
+	if err := r.RemoveVariant(original); err != release.ErrVariantInUse {
+		t.Errorf("Expected error %v, got %v", release.ErrVariantInUse, err)
+	}
+	if err := r.RemoveVariant(uuid.New()); err != release.ErrVariantNotFound {
+		t.Errorf("Expected error %v, got %v", release.ErrVariantNotFound, err)
+	}
+	if err := r.RemoveVariant(reissue); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RemoveVariant(original); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.GetVariants()) != 0 {
+		t.Errorf("Expected no variants, got %v", r.GetVariants())
+	}
+}
+
+func TestRelease_WithRecords(t *testing.T) {
+	vinyl, _ := record.NewRecord("Kind of Blue", record.KindVinyl)
+	reissue, _ := record.NewRecord("Kind of Blue (1997)", record.KindVinyl)
+	mp3, _ := record.NewRecord("Kind of Blue", record.KindMP3)
+	// stored before statuses existed, still owned
+	mp3.SetStatus("")
+	sold, _ := record.NewRecord("Kind of Blue", record.KindMP3)
+	sold.SetStatus(record.StatusSold)
+
+	r, _ := release.NewRelease("Kind of Blue")
+	r.AddVariant(release.Variant{RecordID: vinyl.GetID(), Relation: release.RelationOriginal})
+	r.AddVariant(release.Variant{RecordID: reissue.GetID(), Relation: release.RelationReissue, Of: vinyl.GetID()})
+	r.AddVariant(release.Variant{RecordID: sold.GetID(), Relation: release.RelationDigital, Of: vinyl.GetID()})
+
+	pr := r.WithRecords([]record.Record{vinyl, reissue, sold})
+	if pr.OwnedFormats != 1 || !reflect.DeepEqual(pr.Formats, []string{record.KindVinyl}) {
+		t.Errorf("Expected to be owned in vinyl only, got %v", pr.Formats)
+	}
+	if pr.Variants[1].Name != "Kind of Blue (1997)" || pr.Variants[1].Relation != release.RelationReissue {
+		t.Errorf("Unexpected variant %+v", pr.Variants[1])
+	}
+
+	r.AddVariant(release.Variant{RecordID: mp3.GetID(), Relation: release.RelationDigital, Of: reissue.GetID()})
+	pr = r.WithRecords([]record.Record{vinyl, reissue, sold, mp3})
+	if pr.OwnedFormats != 2 || !reflect.DeepEqual(pr.Formats, []string{record.KindMP3, record.KindVinyl}) {
+		t.Errorf("Expected to be owned in mp3 and vinyl, got %v", pr.Formats)
+	}
+}
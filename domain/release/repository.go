@@ -0,0 +1,16 @@
+package release
+
+import (
+	"github.com/google/uuid"
+)
+
+type ReleaseRepository interface {
+	Get(uuid.UUID) (Release, error)
+	Add(Release) error
+	Update(*Release) error
+	Delete(uuid.UUID) error
+	FindReleases() ([]Release, error)
+	// FindByRecord returns the release the record is a variant of, or
+	// ErrRecordNoRelease.
+	FindByRecord(recordID uuid.UUID) (Release, error)
+}
@@ -19,7 +19,15 @@ type memorySong struct {
 	ID       uuid.UUID `db:"id"`
 	Name     string    `db:"name"`
 	Length   int64     `db:"length"`
+	Tags     []string  `db:"tags"`
 	RecordID uuid.UUID `db:"record_id"`
+
+	Track         int    `db:"track"`
+	MusicBrainzID string `db:"musicbrainz_id"`
+	Path          string `db:"path"`
+	Artist        string `db:"artist"`
+	ITunesID      string `db:"itunes_id"`
+	ITunesPlays   int    `db:"itunes_plays"`
 }
 
 func NewFromSong(s song.Song) memorySong {
@@ -27,7 +35,15 @@ func NewFromSong(s song.Song) memorySong {
 		ID:       s.GetID(),
 		Name:     s.GetName(),
 		Length:   s.GetLength(),
+		Tags:     s.GetTags(),
 		RecordID: s.GetRecordID(),
+
+		Track:         s.GetTrack(),
+		MusicBrainzID: s.GetMusicBrainzID(),
+		Path:          s.GetPath(),
+		Artist:        s.GetArtist(),
+		ITunesID:      s.GetITunesID(),
+		ITunesPlays:   s.GetITunesPlays(),
 	}
 }
 
@@ -37,7 +53,14 @@ func (pr memorySong) ToSong() song.Song {
 	s.SetID(pr.ID)
 	s.SetName(pr.Name)
 	s.SetLength(pr.Length)
+	s.SetTags(pr.Tags)
 	s.SetRecordID(pr.RecordID)
+	s.SetTrack(pr.Track)
+	s.SetMusicBrainzID(pr.MusicBrainzID)
+	s.SetPath(pr.Path)
+	s.SetArtist(pr.Artist)
+	s.SetITunesID(pr.ITunesID)
+	s.SetITunesPlays(pr.ITunesPlays)
 
 	return s
 }
@@ -74,6 +97,17 @@ func (mr *MemoryRepository) Add(s song.Song) error {
 	return nil
 }
 
+func (mr *MemoryRepository) AddBatch(ss []song.Song) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for _, s := range ss {
+		mr.songs = append(mr.songs, NewFromSong(s))
+	}
+
+	return nil
+}
+
 func (mr *MemoryRepository) FindRecords() ([]song.Song, error) {
 	mr.Lock()
 	defer mr.Unlock()
@@ -89,7 +123,41 @@ func (mr *MemoryRepository) FindRecords() ([]song.Song, error) {
 func (mr *MemoryRepository) Update(s *song.Song) error {
 	mr.Lock()
 	defer mr.Unlock()
-	panic("to implement")
+
+	for i, ms := range mr.songs {
+		if ms.ID == s.GetID() {
+			mr.songs[i] = NewFromSong(*s)
+			return nil
+		}
+	}
+
+	return song.ErrSongNotFound
+}
+
+func (mr *MemoryRepository) Delete(id uuid.UUID) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for i, ms := range mr.songs {
+		if ms.ID == id {
+			mr.songs = append(mr.songs[:i], mr.songs[i+1:]...)
+			return nil
+		}
+	}
+
+	return song.ErrSongNotFound
+}
+
+func (mr *MemoryRepository) FindSongs() ([]song.Song, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	var ss []song.Song
+	for _, s := range mr.songs {
+		ss = append(ss, s.ToSong())
+	}
+
+	return ss, nil
 }
 
 func (mr *MemoryRepository) FindSongsByRecord(id uuid.UUID) ([]song.Song, error) {
@@ -206,12 +206,38 @@ func TestMemoryRepository_Update(t *testing.T) {
 		s *song.Song
 	}
 
+	recordID := uuid.New()
+	s1, _ := song.NewSong("s1", 10, recordID)
+	s2, _ := song.NewSong("s2", 20, recordID)
+	s1.SetTags([]string{"live"})
+
 	tests := []struct {
 		name    string
 		fields  fields
 		args    args
 		wantErr bool
-	}{}
+	}{
+		{
+			name: "Update stored song",
+			fields: fields{
+				songs: []memorySong{NewFromSong(s1)},
+			},
+			args: args{
+				s: &s1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Update unknown song",
+			fields: fields{
+				songs: []memorySong{NewFromSong(s1)},
+			},
+			args: args{
+				s: &s2,
+			},
+			wantErr: true,
+		},
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mr := &MemoryRepository{
@@ -290,3 +316,20 @@ func TestMemoryRepository_FindSongsByRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestMemoryRepository_Delete(t *testing.T) {
+	repo, _ := New(context.Background())
+
+	s, _ := song.NewSong("Hey You", 280, uuid.New())
+	repo.Add(s)
+
+	if err := repo.Delete(s.GetID()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Get(s.GetID()); err != song.ErrSongNotFound {
+		t.Errorf("Expected error %v, got %v", song.ErrSongNotFound, err)
+	}
+	if err := repo.Delete(s.GetID()); err != song.ErrSongNotFound {
+		t.Errorf("Expected error %v, got %v", song.ErrSongNotFound, err)
+	}
+}
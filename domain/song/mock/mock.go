@@ -29,6 +29,14 @@ func (mrr MockSongRepository) Add(rec song.Song) error {
 	return nil
 }
 
+func (mrr MockSongRepository) AddBatch(songs []song.Song) error {
+	if mrr.WithError {
+		return errors.New("something went wrong")
+	}
+
+	return nil
+}
+
 func (mrr MockSongRepository) Update(rec *song.Song) error {
 	if mrr.WithError {
 		return errors.New("something went wrong")
@@ -37,6 +45,14 @@ func (mrr MockSongRepository) Update(rec *song.Song) error {
 	return nil
 }
 
+func (mrr MockSongRepository) Delete(id uuid.UUID) error {
+	if mrr.WithError {
+		return errors.New("something went wrong")
+	}
+
+	return nil
+}
+
 func (mrr MockSongRepository) FindRecords() ([]song.Song, error) {
 	if mrr.WithError {
 		return []song.Song{}, errors.New("something went wrong")
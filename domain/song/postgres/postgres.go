@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/pkg/pgbatch"
+)
+
+type IPostgresSQl interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+type PostgresRepository struct {
+	db IPostgresSQl
+}
+
+type (
+	SqlOpener func(string, string) (*sqlx.DB, error)
+)
+
+const insertSongQuery = `INSERT INTO songs (id, name, length, tags, record_id, track, musicbrainz_id, path, artist, itunes_id, itunes_plays) VALUES (:id, :name, :length, :tags, :record_id, :track, :musicbrainz_id, :path, :artist, :itunes_id, :itunes_plays)`
+
+type postgresSong struct {
+	ID       uuid.UUID      `db:"id"`
+	Name     string         `db:"name"`
+	Length   int64          `db:"length"`
+	Tags     pq.StringArray `db:"tags"`
+	RecordID uuid.UUID      `db:"record_id"`
+
+	Track         int    `db:"track"`
+	MusicBrainzID string `db:"musicbrainz_id"`
+	Path          string `db:"path"`
+	Artist        string `db:"artist"`
+	ITunesID      string `db:"itunes_id"`
+	ITunesPlays   int    `db:"itunes_plays"`
+}
+
+func NewFromSong(s song.Song) postgresSong {
+	return postgresSong{
+		ID:       s.GetID(),
+		Name:     s.GetName(),
+		Length:   s.GetLength(),
+		Tags:     pq.StringArray(s.GetTags()),
+		RecordID: s.GetRecordID(),
+
+		Track:         s.GetTrack(),
+		MusicBrainzID: s.GetMusicBrainzID(),
+		Path:          s.GetPath(),
+		Artist:        s.GetArtist(),
+		ITunesID:      s.GetITunesID(),
+		ITunesPlays:   s.GetITunesPlays(),
+	}
+}
+
+func (ps postgresSong) ToSong() song.Song {
+	s := song.Song{}
+
+	s.SetID(ps.ID)
+	s.SetName(ps.Name)
+	s.SetLength(ps.Length)
+	s.SetTags(ps.Tags)
+	s.SetRecordID(ps.RecordID)
+	s.SetTrack(ps.Track)
+	s.SetMusicBrainzID(ps.MusicBrainzID)
+	s.SetPath(ps.Path)
+	s.SetArtist(ps.Artist)
+	s.SetITunesID(ps.ITunesID)
+	s.SetITunesPlays(ps.ITunesPlays)
+
+	return s
+}
+
+// Create a new postgres repository
+func New(ctx context.Context, connectionString string, open SqlOpener) (*PostgresRepository, error) {
+	client, err := open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return &PostgresRepository{
+		db: client,
+	}, nil
+}
+
+// WithTx returns a repository that runs its statements in tx.
+func (pr *PostgresRepository) WithTx(tx *sqlx.Tx) *PostgresRepository {
+	return &PostgresRepository{
+		db: tx,
+	}
+}
+
+func (pr *PostgresRepository) Get(id uuid.UUID) (song.Song, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var s postgresSong
+	if err := pr.db.GetContext(ctx, &s, "SELECT * FROM songs WHERE id = $1", id); err != nil {
+		if err == sql.ErrNoRows {
+			return song.Song{}, song.ErrSongNotFound
+		}
+		return song.Song{}, err
+	}
+
+	return s.ToSong(), nil
+}
+
+func (pr *PostgresRepository) Add(s song.Song) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.NamedExecContext(ctx, insertSongQuery, NewFromSong(s))
+	return err
+}
+
+// AddBatch inserts the songs with multi-row statements, in a transaction
+// when they take more than one, so they are stored all together or not at
+// all.
+func (pr *PostgresRepository) AddBatch(ss []song.Song) error {
+	if len(ss) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	internal := make([]postgresSong, 0, len(ss))
+	for _, s := range ss {
+		internal = append(internal, NewFromSong(s))
+	}
+
+	return pgbatch.Insert(ctx, pr.db, insertSongQuery, internal)
+}
+
+func (pr *PostgresRepository) Update(s *song.Song) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := pr.db.NamedExecContext(ctx, `UPDATE songs SET name = :name, length = :length, tags = :tags, record_id = :record_id, track = :track, musicbrainz_id = :musicbrainz_id, path = :path, artist = :artist, itunes_id = :itunes_id, itunes_plays = :itunes_plays WHERE id = :id`, NewFromSong(*s))
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return song.ErrSongNotFound
+	}
+
+	return nil
+}
+
+func (pr *PostgresRepository) Delete(id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	res, err := pr.db.NamedExecContext(ctx, `DELETE FROM songs WHERE id = :id`, map[string]interface{}{"id": id})
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return song.ErrSongNotFound
+	}
+
+	return nil
+}
+
+func (pr *PostgresRepository) FindSongs() ([]song.Song, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var pss []postgresSong
+	if err := pr.db.SelectContext(ctx, &pss, "SELECT * FROM songs"); err != nil {
+		return []song.Song{}, err
+	}
+
+	var ss []song.Song
+	for _, s := range pss {
+		ss = append(ss, s.ToSong())
+	}
+
+	return ss, nil
+}
+
+func (pr *PostgresRepository) FindSongsByRecord(id uuid.UUID) ([]song.Song, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var pss []postgresSong
+	if err := pr.db.SelectContext(ctx, &pss, "SELECT * FROM songs WHERE record_id = $1 ORDER BY track = 0, track, lower(name)", id); err != nil {
+		return []song.Song{}, err
+	}
+
+	var ss []song.Song
+	for _, s := range pss {
+		ss = append(ss, s.ToSong())
+	}
+
+	return ss, nil
+}
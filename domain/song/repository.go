@@ -7,6 +7,10 @@ import (
 type SongRepository interface {
 	Get(uuid.UUID) (Song, error)
 	Add(Song) error
+	// AddBatch adds several songs at once, all of them or none.
+	AddBatch([]Song) error
 	Update(*Song) error
+	Delete(uuid.UUID) error
+	FindSongs() ([]Song, error)
 	FindSongsByRecord(uuid.UUID) ([]Song, error)
 }
@@ -2,12 +2,17 @@ package song
 
 import (
 	"errors"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/taxonomy"
+	"github.com/rodrwan/collection/domain/validation"
 )
 
 var (
 	ErrMissingValues = errors.New("missing value")
+	ErrInvalidLength = errors.New("length cannot be negative")
 	ErrSongNotFound  = errors.New("song not found")
 )
 
@@ -15,24 +20,48 @@ type Song struct {
 	id     uuid.UUID
 	name   string
 	length int64
+	tags   []string
+	// track is the position of the song on its record, 0 when unknown.
+	track int
+	// musicBrainzID is the MusicBrainz recording the song was matched with.
+	musicBrainzID string
+	// path is where the file of the song is, relative to the music library
+	// and slash separated. Empty when the song has no known file.
+	path string
+	// artist is who performs the song, empty when it is the artist of its
+	// record.
+	artist string
+	// itunesID is the persistent ID of the iTunes track the song was
+	// imported from, and itunesPlays the play count of that track when it
+	// was last imported.
+	itunesID    string
+	itunesPlays int
 
 	recordID uuid.UUID
 }
 
+type PublicSong struct {
+	ID            uuid.UUID `json:"id,omitempty"`
+	Name          string    `json:"name,omitempty"`
+	Length        int64     `json:"length"`
+	Tags          []string  `json:"tags,omitempty"`
+	Track         int       `json:"track,omitempty"`
+	RecordID      uuid.UUID `json:"recordId,omitempty"`
+	MusicBrainzID string    `json:"musicbrainzId,omitempty"`
+	Path          string    `json:"path,omitempty"`
+	Artist        string    `json:"artist,omitempty"`
+	ITunesID      string    `json:"itunesId,omitempty"`
+	ITunesPlays   int       `json:"itunesPlays,omitempty"`
+	PlayCount     int       `json:"playCount"`
+}
+
 func NewSong(name string, length int64, recordID uuid.UUID) (Song, error) {
-	if name == "" {
-		return Song{}, ErrMissingValues
-	}
-	return Song{
-		name:     name,
-		length:   length,
-		recordID: recordID,
-	}, nil
+	return NewSongWithID(uuid.New(), name, length, recordID)
 }
 
 func NewSongWithID(id uuid.UUID, name string, length int64, recordID uuid.UUID) (Song, error) {
-	if name == "" {
-		return Song{}, ErrMissingValues
+	if err := validate(name, length); err != nil {
+		return Song{}, err
 	}
 
 	return Song{
@@ -43,6 +72,58 @@ func NewSongWithID(id uuid.UUID, name string, length int64, recordID uuid.UUID)
 	}, nil
 }
 
+// validate reports every invalid field of a new song as validation.Errors.
+func validate(name string, length int64) error {
+	var errs validation.Errors
+
+	if strings.TrimSpace(name) == "" {
+		errs = errs.Add("name", validation.CodeRequired, ErrMissingValues)
+	}
+
+	if length < 0 {
+		errs = errs.Add("length", validation.CodeOutOfRange, ErrInvalidLength)
+	}
+
+	return errs.Err()
+}
+
+func (s Song) ToPublic() PublicSong {
+	return PublicSong{
+		ID:            s.GetID(),
+		Name:          s.GetName(),
+		Length:        s.GetLength(),
+		Tags:          s.GetTags(),
+		Track:         s.GetTrack(),
+		RecordID:      s.GetRecordID(),
+		MusicBrainzID: s.GetMusicBrainzID(),
+		Path:          s.GetPath(),
+		Artist:        s.GetArtist(),
+		ITunesID:      s.GetITunesID(),
+		ITunesPlays:   s.GetITunesPlays(),
+	}
+}
+
+func ToPublicArray(songs []Song) []PublicSong {
+	var ss []PublicSong
+
+	for _, s := range songs {
+		ss = append(ss, s.ToPublic())
+	}
+	return ss
+}
+
+// SortByTrack orders songs by their position on the record, songs with an
+// unknown track last, and by name when the tracks are the same.
+func SortByTrack(songs []Song) {
+	sort.SliceStable(songs, func(i, j int) bool {
+		a, b := songs[i].GetTrack(), songs[j].GetTrack()
+		if a != b {
+			return b == 0 || a != 0 && a < b
+		}
+		return strings.ToLower(songs[i].GetName()) < strings.ToLower(songs[j].GetName())
+	})
+}
+
 func (s Song) GetID() uuid.UUID {
 	return s.id
 }
@@ -59,6 +140,34 @@ func (s Song) GetRecordID() uuid.UUID {
 	return s.recordID
 }
 
+func (s Song) GetTags() []string {
+	return s.tags
+}
+
+func (s Song) GetTrack() int {
+	return s.track
+}
+
+func (s Song) GetMusicBrainzID() string {
+	return s.musicBrainzID
+}
+
+func (s Song) GetPath() string {
+	return s.path
+}
+
+func (s Song) GetArtist() string {
+	return s.artist
+}
+
+func (s Song) GetITunesID() string {
+	return s.itunesID
+}
+
+func (s Song) GetITunesPlays() int {
+	return s.itunesPlays
+}
+
 func (s *Song) SetID(id uuid.UUID) {
 	s.id = id
 }
@@ -74,3 +183,32 @@ func (s *Song) SetLength(length int64) {
 func (s *Song) SetRecordID(recordID uuid.UUID) {
 	s.recordID = recordID
 }
+
+func (s *Song) SetTrack(track int) {
+	s.track = track
+}
+
+func (s *Song) SetMusicBrainzID(id string) {
+	s.musicBrainzID = id
+}
+
+func (s *Song) SetPath(path string) {
+	s.path = path
+}
+
+func (s *Song) SetArtist(artist string) {
+	s.artist = strings.TrimSpace(artist)
+}
+
+func (s *Song) SetITunesID(id string) {
+	s.itunesID = id
+}
+
+func (s *Song) SetITunesPlays(plays int) {
+	s.itunesPlays = plays
+}
+
+// SetTags replaces the song tags, normalizing them on the way in.
+func (s *Song) SetTags(tags []string) {
+	s.tags = taxonomy.NormalizeTags(tags)
+}
@@ -1,6 +1,7 @@
 package song_test
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
@@ -42,7 +43,7 @@ func TestSong_NewSong(t *testing.T) {
 			// Create a new customer
 			_, err := song.NewSong(tc.name, tc.length, tc.recordId)
 			// Check if the error matches the expected error
-			if err != tc.expectedErr {
+			if !errors.Is(err, tc.expectedErr) {
 				t.Errorf("Expected error %v, got %v", tc.expectedErr, err)
 			}
 
@@ -100,7 +101,7 @@ func TestNewSongWithID(t *testing.T) {
 			got, err := song.NewSongWithID(tt.args.id, tt.args.name, tt.args.length, tt.args.recordID)
 			fmt.Println(err != nil)
 			if err != nil {
-				if err != tt.wantErr {
+				if !errors.Is(err, tt.wantErr) {
 					t.Errorf("NewSongWithID() error = %v, wantErr %v", err, tt.wantErr)
 				}
 				return
@@ -369,3 +370,26 @@ func TestSong_SetRecordID(t *testing.T) {
 		})
 	}
 }
+
+func TestSortByTrack(t *testing.T) {
+	var songs []song.Song
+	for _, tc := range []struct {
+		name  string
+		track int
+	}{{"Mother", 0}, {"Hey You", 2}, {"another brick", 0}, {"In the Flesh?", 1}, {"Goodbye", 2}} {
+		s, _ := song.NewSong(tc.name, 0, uuid.Nil)
+		s.SetTrack(tc.track)
+		songs = append(songs, s)
+	}
+
+	song.SortByTrack(songs)
+
+	var got []string
+	for _, s := range songs {
+		got = append(got, s.GetName())
+	}
+	want := []string{"In the Flesh?", "Goodbye", "Hey You", "another brick", "Mother"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SortByTrack() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/taxonomy"
+)
+
+type MemoryRepository struct {
+	genres []memoryGenre
+
+	sync.Mutex
+}
+
+type memoryGenre struct {
+	ID       uuid.UUID `db:"id"`
+	Name     string    `db:"name"`
+	ParentID uuid.UUID `db:"parent_id"`
+}
+
+func NewFromGenre(g taxonomy.Genre) memoryGenre {
+	return memoryGenre{
+		ID:       g.GetID(),
+		Name:     g.GetName(),
+		ParentID: g.GetParentID(),
+	}
+}
+
+func (mg memoryGenre) ToGenre() taxonomy.Genre {
+	g := taxonomy.Genre{}
+
+	g.SetID(mg.ID)
+	g.SetName(mg.Name)
+	g.SetParentID(mg.ParentID)
+
+	return g
+}
+
+// Create a new memory repository
+func New(ctx context.Context) (*MemoryRepository, error) {
+	return &MemoryRepository{
+		genres: make([]memoryGenre, 0),
+	}, nil
+}
+
+func (mr *MemoryRepository) Get(id uuid.UUID) (taxonomy.Genre, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	for _, g := range mr.genres {
+		if g.ID == id {
+			return g.ToGenre(), nil
+		}
+	}
+
+	return taxonomy.Genre{}, taxonomy.ErrGenreNotFound
+}
+
+func (mr *MemoryRepository) Add(g taxonomy.Genre) error {
+	mr.Lock()
+	defer mr.Unlock()
+
+	mr.genres = append(mr.genres, NewFromGenre(g))
+
+	return nil
+}
+
+func (mr *MemoryRepository) FindGenres() ([]taxonomy.Genre, error) {
+	mr.Lock()
+	defer mr.Unlock()
+
+	var gg []taxonomy.Genre
+	for _, g := range mr.genres {
+		gg = append(gg, g.ToGenre())
+	}
+
+	return gg, nil
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/taxonomy"
+)
+
+func TestMemory_GetGenre(t *testing.T) {
+	type testCase struct {
+		name        string
+		id          uuid.UUID
+		expectedErr error
+	}
+
+	g, err := taxonomy.NewGenre("House", uuid.Nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo := MemoryRepository{
+		genres: []memoryGenre{
+			NewFromGenre(g),
+		},
+	}
+
+	testCases := []testCase{
+		{
+			name:        "No Genre By ID",
+			id:          uuid.MustParse("f47ac10b-58cc-0372-8567-0e02b2c3d479"),
+			expectedErr: taxonomy.ErrGenreNotFound,
+		}, {
+			name:        "Genre By ID",
+			id:          g.GetID(),
+			expectedErr: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := repo.Get(tc.id)
+			if err != tc.expectedErr {
+				t.Errorf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestMemoryRepository_FindGenres(t *testing.T) {
+	repo, _ := New(context.Background())
+
+	parent, _ := taxonomy.NewGenre("Electronic", uuid.Nil)
+	child, _ := taxonomy.NewGenre("House", parent.GetID())
+	for _, g := range []taxonomy.Genre{parent, child} {
+		if err := repo.Add(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := repo.FindGenres()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 genres, got %d", len(got))
+	}
+	if got[1].GetParentID() != parent.GetID() {
+		t.Errorf("Expected parent %v, got %v", parent.GetID(), got[1].GetParentID())
+	}
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rodrwan/collection/domain/taxonomy"
+)
+
+type IPostgresSQl interface {
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+type PostgresRepository struct {
+	db IPostgresSQl
+}
+
+type (
+	SqlOpener func(string, string) (*sqlx.DB, error)
+)
+
+type postgresGenre struct {
+	ID       uuid.UUID `db:"id"`
+	Name     string    `db:"name"`
+	ParentID uuid.UUID `db:"parent_id"`
+}
+
+func NewFromGenre(g taxonomy.Genre) postgresGenre {
+	return postgresGenre{
+		ID:       g.GetID(),
+		Name:     g.GetName(),
+		ParentID: g.GetParentID(),
+	}
+}
+
+func (pg postgresGenre) ToGenre() taxonomy.Genre {
+	g := taxonomy.Genre{}
+
+	g.SetID(pg.ID)
+	g.SetName(pg.Name)
+	g.SetParentID(pg.ParentID)
+
+	return g
+}
+
+// Create a new postgres repository
+func New(ctx context.Context, connectionString string, open SqlOpener) (*PostgresRepository, error) {
+	client, err := open("postgres", connectionString)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return &PostgresRepository{
+		db: client,
+	}, nil
+}
+
+// WithTx returns a repository that runs its statements in tx.
+func (pr *PostgresRepository) WithTx(tx *sqlx.Tx) *PostgresRepository {
+	return &PostgresRepository{
+		db: tx,
+	}
+}
+
+func (pr *PostgresRepository) Get(id uuid.UUID) (taxonomy.Genre, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var g postgresGenre
+	if err := pr.db.GetContext(ctx, &g, "SELECT * FROM genres WHERE id = $1", id); err != nil {
+		if err == sql.ErrNoRows {
+			return taxonomy.Genre{}, taxonomy.ErrGenreNotFound
+		}
+		return taxonomy.Genre{}, err
+	}
+
+	return g.ToGenre(), nil
+}
+
+func (pr *PostgresRepository) Add(g taxonomy.Genre) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pr.db.NamedExecContext(ctx, `INSERT INTO genres (id, name, parent_id) VALUES (:id, :name, :parent_id)`, NewFromGenre(g))
+	return err
+}
+
+func (pr *PostgresRepository) FindGenres() ([]taxonomy.Genre, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var pgs []postgresGenre
+	if err := pr.db.SelectContext(ctx, &pgs, "SELECT * FROM genres ORDER BY name"); err != nil {
+		return []taxonomy.Genre{}, err
+	}
+
+	var gg []taxonomy.Genre
+	for _, g := range pgs {
+		gg = append(gg, g.ToGenre())
+	}
+
+	return gg, nil
+}
@@ -0,0 +1,11 @@
+package taxonomy
+
+import (
+	"github.com/google/uuid"
+)
+
+type GenreRepository interface {
+	Get(uuid.UUID) (Genre, error)
+	Add(Genre) error
+	FindGenres() ([]Genre, error)
+}
@@ -0,0 +1,189 @@
+package taxonomy
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrMissingValues = errors.New("missing value")
+	ErrGenreNotFound = errors.New("genre not found")
+)
+
+// Genre is a node of the genre tree, e.g. Electronic > House > Deep House.
+// Top level genres have a nil parent.
+type Genre struct {
+	id       uuid.UUID
+	name     string
+	parentID uuid.UUID
+}
+
+type PublicGenre struct {
+	ID       uuid.UUID  `json:"id,omitempty"`
+	Name     string     `json:"name,omitempty"`
+	ParentID *uuid.UUID `json:"parentId,omitempty"`
+}
+
+func NewGenre(name string, parentID uuid.UUID) (Genre, error) {
+	return NewGenreWithID(uuid.New(), name, parentID)
+}
+
+func NewGenreWithID(id uuid.UUID, name string, parentID uuid.UUID) (Genre, error) {
+	if strings.TrimSpace(name) == "" {
+		var errs validation.Errors
+		return Genre{}, errs.Add("name", validation.CodeRequired, ErrMissingValues)
+	}
+
+	return Genre{
+		id:       id,
+		name:     strings.TrimSpace(name),
+		parentID: parentID,
+	}, nil
+}
+
+func (g Genre) ToPublic() PublicGenre {
+	pg := PublicGenre{
+		ID:   g.GetID(),
+		Name: g.GetName(),
+	}
+	if g.parentID != uuid.Nil {
+		parentID := g.parentID
+		pg.ParentID = &parentID
+	}
+
+	return pg
+}
+
+func ToPublicArray(genres []Genre) []PublicGenre {
+	var gg []PublicGenre
+
+	for _, g := range genres {
+		gg = append(gg, g.ToPublic())
+	}
+	return gg
+}
+
+func (g *Genre) SetID(id uuid.UUID) {
+	g.id = id
+}
+
+func (g *Genre) SetName(name string) {
+	g.name = name
+}
+
+func (g *Genre) SetParentID(parentID uuid.UUID) {
+	g.parentID = parentID
+}
+
+func (g Genre) GetID() uuid.UUID {
+	return g.id
+}
+
+func (g Genre) GetName() string {
+	return g.name
+}
+
+func (g Genre) GetParentID() uuid.UUID {
+	return g.parentID
+}
+
+// Descendants returns the id of the given genre followed by the ids of all
+// of its sub-genres, at any depth.
+func Descendants(genres []Genre, id uuid.UUID) []uuid.UUID {
+	children := make(map[uuid.UUID][]uuid.UUID)
+	for _, g := range genres {
+		children[g.parentID] = append(children[g.parentID], g.id)
+	}
+
+	ids := []uuid.UUID{id}
+	seen := map[uuid.UUID]bool{id: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range children[ids[i]] {
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			ids = append(ids, child)
+		}
+	}
+
+	return ids
+}
+
+// Path returns the chain of genres from the top level down to the given
+// genre. It returns ErrGenreNotFound when the genre or one of its ancestors
+// is unknown.
+func Path(genres []Genre, id uuid.UUID) ([]Genre, error) {
+	byID := make(map[uuid.UUID]Genre, len(genres))
+	for _, g := range genres {
+		byID[g.id] = g
+	}
+
+	var path []Genre
+	for current := id; current != uuid.Nil; {
+		g, ok := byID[current]
+		if !ok {
+			return nil, ErrGenreNotFound
+		}
+		// guard against cycles in badly stored data
+		if len(path) > len(genres) {
+			break
+		}
+		path = append([]Genre{g}, path...)
+		current = g.parentID
+	}
+
+	return path, nil
+}
+
+// NormalizeTags lowercases and trims free-form tags, dropping empty and
+// repeated ones. The result is sorted so stored tags compare equal.
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+
+	var normalized []string
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		normalized = append(normalized, t)
+	}
+	sort.Strings(normalized)
+
+	return normalized
+}
+
+// TagCount is the number of times a tag is used across the collection.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// CountTags counts every tag in the given tag sets, most used first.
+func CountTags(tagSets ...[]string) []TagCount {
+	counts := make(map[string]int)
+	for _, tags := range tagSets {
+		for _, t := range tags {
+			counts[t]++
+		}
+	}
+
+	tc := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tc = append(tc, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tc, func(i, j int) bool {
+		if tc[i].Count != tc[j].Count {
+			return tc[i].Count > tc[j].Count
+		}
+		return tc[i].Tag < tc[j].Tag
+	})
+
+	return tc
+}
@@ -0,0 +1,174 @@
+package taxonomy_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/taxonomy"
+)
+
+func TestTaxonomy_NewGenre(t *testing.T) {
+	type testCase struct {
+		test        string
+		name        string
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			test:        "Empty Name validation",
+			name:        "  ",
+			expectedErr: taxonomy.ErrMissingValues,
+		}, {
+			test:        "Valid Name",
+			name:        "House",
+			expectedErr: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.test, func(t *testing.T) {
+			_, err := taxonomy.NewGenre(tc.name, uuid.Nil)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestGenre_ToPublic(t *testing.T) {
+	parentID := uuid.New()
+	top, _ := taxonomy.NewGenre("Electronic", uuid.Nil)
+	child, _ := taxonomy.NewGenre("House", parentID)
+
+	tests := []struct {
+		name  string
+		genre taxonomy.Genre
+		want  taxonomy.PublicGenre
+	}{
+		{
+			name:  "Top level genre has no parent",
+			genre: top,
+			want:  taxonomy.PublicGenre{ID: top.GetID(), Name: "Electronic"},
+		},
+		{
+			name:  "Sub-genre keeps its parent",
+			genre: child,
+			want:  taxonomy.PublicGenre{ID: child.GetID(), Name: "House", ParentID: &parentID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.genre.ToPublic(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Genre.ToPublic() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescendants(t *testing.T) {
+	electronic, _ := taxonomy.NewGenre("Electronic", uuid.Nil)
+	house, _ := taxonomy.NewGenre("House", electronic.GetID())
+	deepHouse, _ := taxonomy.NewGenre("Deep House", house.GetID())
+	techno, _ := taxonomy.NewGenre("Techno", electronic.GetID())
+	rock, _ := taxonomy.NewGenre("Rock", uuid.Nil)
+
+	genres := []taxonomy.Genre{electronic, house, deepHouse, techno, rock}
+
+	tests := []struct {
+		name string
+		id   uuid.UUID
+		want []uuid.UUID
+	}{
+		{
+			name: "Top level genre includes every level below",
+			id:   electronic.GetID(),
+			want: []uuid.UUID{electronic.GetID(), house.GetID(), techno.GetID(), deepHouse.GetID()},
+		},
+		{
+			name: "Sub-genre includes its own children only",
+			id:   house.GetID(),
+			want: []uuid.UUID{house.GetID(), deepHouse.GetID()},
+		},
+		{
+			name: "Leaf genre is only itself",
+			id:   rock.GetID(),
+			want: []uuid.UUID{rock.GetID()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := taxonomy.Descendants(genres, tt.id); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Descendants() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	electronic, _ := taxonomy.NewGenre("Electronic", uuid.Nil)
+	house, _ := taxonomy.NewGenre("House", electronic.GetID())
+	deepHouse, _ := taxonomy.NewGenre("Deep House", house.GetID())
+	genres := []taxonomy.Genre{deepHouse, electronic, house}
+
+	got, err := taxonomy.Path(genres, deepHouse.GetID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, g := range got {
+		names = append(names, g.GetName())
+	}
+	if want := []string{"Electronic", "House", "Deep House"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Path() = %v, want %v", names, want)
+	}
+
+	if _, err := taxonomy.Path(genres, uuid.New()); err != taxonomy.ErrGenreNotFound {
+		t.Errorf("Expected error %v, got %v", taxonomy.ErrGenreNotFound, err)
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{
+			name: "Nil tags",
+			tags: nil,
+			want: nil,
+		},
+		{
+			name: "Lowercase, trim, dedupe and sort",
+			tags: []string{" Summer ", "dj set", "summer", ""},
+			want: []string{"dj set", "summer"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := taxonomy.NormalizeTags(tt.tags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountTags(t *testing.T) {
+	got := taxonomy.CountTags([]string{"a", "b"}, []string{"b"}, nil, []string{"c", "b"})
+	want := []taxonomy.TagCount{
+		{Tag: "b", Count: 3},
+		{Tag: "a", Count: 1},
+		{Tag: "c", Count: 1},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CountTags() = %v, want %v", got, want)
+	}
+}
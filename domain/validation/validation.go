@@ -0,0 +1,106 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+)
+
+// Machine readable codes describing why a field failed validation.
+const (
+	CodeRequired   = "required"
+	CodeInvalid    = "invalid"
+	CodeOutOfRange = "out_of_range"
+	CodeNotAllowed = "not_allowed"
+)
+
+// FieldError describes a single field that failed validation. It wraps the
+// domain error so callers can keep matching on it with errors.Is.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	err error
+}
+
+func (fe FieldError) Error() string {
+	return fe.Field + ": " + fe.Message
+}
+
+func (fe FieldError) Unwrap() error {
+	return fe.err
+}
+
+// Errors collects every field that failed validation.
+type Errors []FieldError
+
+// Add appends a failing field, using err as its message.
+func (e Errors) Add(field, code string, err error) Errors {
+	return append(e, FieldError{
+		Field:   field,
+		Code:    code,
+		Message: err.Error(),
+		err:     err,
+	})
+}
+
+// Merge appends the fields of another validation, prefixing them with the
+// given path, e.g. songs[2]. Any other error is added as an invalid prefix.
+func (e Errors) Merge(prefix string, other error) Errors {
+	if other == nil {
+		return e
+	}
+
+	errs, ok := other.(Errors)
+	if !ok {
+		return e.Add(prefix, CodeInvalid, other)
+	}
+
+	for _, fe := range errs {
+		if prefix != "" {
+			fe.Field = prefix + "." + fe.Field
+		}
+		e = append(e, fe)
+	}
+	return e
+}
+
+// Err returns nil when no field failed, so it can be returned directly.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fe := range e {
+		messages = append(messages, fe.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether any of the failing fields wraps target.
+func (e Errors) Is(target error) bool {
+	for _, fe := range e {
+		if errors.Is(fe.err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Has reports whether the given field failed validation.
+func (e Errors) Has(field string) bool {
+	for _, fe := range e {
+		if fe.Field == field {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package validation_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rodrwan/collection/domain/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errMissing = errors.New("missing value")
+	errInvalid = errors.New("invalid value")
+)
+
+func TestErrors_Err(t *testing.T) {
+	var errs validation.Errors
+	assert.Nil(t, errs.Err())
+
+	errs = errs.Add("name", validation.CodeRequired, errMissing)
+	errs = errs.Add("kind", validation.CodeInvalid, errInvalid)
+
+	err := errs.Err()
+	assert.NotNil(t, err)
+	assert.Equal(t, "name: missing value; kind: invalid value", err.Error())
+	assert.True(t, errors.Is(err, errMissing))
+	assert.True(t, errors.Is(err, errInvalid))
+	assert.False(t, errors.Is(err, errors.New("missing value")))
+
+	wrapped := errs.Add("length", validation.CodeOutOfRange, fmt.Errorf("%w: over an hour", errInvalid))
+	assert.True(t, errors.Is(wrapped.Err(), errInvalid))
+
+	var verrs validation.Errors
+	assert.True(t, errors.As(err, &verrs))
+	assert.True(t, verrs.Has("kind"))
+	assert.Equal(t, validation.CodeRequired, verrs[0].Code)
+}
+
+func TestErrors_Merge(t *testing.T) {
+	var inner validation.Errors
+	inner = inner.Add("name", validation.CodeRequired, errMissing)
+
+	var errs validation.Errors
+	errs = errs.Merge("songs[1]", inner.Err())
+	errs = errs.Merge("songs[2]", nil)
+	errs = errs.Merge("songs[3]", errInvalid)
+
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, "songs[1].name", errs[0].Field)
+	assert.Equal(t, "songs[3]", errs[1].Field)
+	assert.Equal(t, validation.CodeInvalid, errs[1].Code)
+	assert.True(t, errors.Is(errs.Err(), errMissing))
+}
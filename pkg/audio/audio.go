@@ -0,0 +1,113 @@
+// Package audio holds what the audio file readers under it have in common:
+// the tags describing a track and how long it plays.
+package audio
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tags are the descriptive tags of a track. Numbers are 0 when unknown.
+type Tags struct {
+	Title       string
+	Artist      string
+	AlbumArtist string
+	Album       string
+	Genre       string
+	Year        int
+	Track       int
+	TrackTotal  int
+	Disc        int
+	DiscTotal   int
+}
+
+// Fill sets the tags missing from t from other, e.g. those of an older tag
+// format found in the same file.
+func (t *Tags) Fill(other Tags) {
+	fillString(&t.Title, other.Title)
+	fillString(&t.Artist, other.Artist)
+	fillString(&t.AlbumArtist, other.AlbumArtist)
+	fillString(&t.Album, other.Album)
+	fillString(&t.Genre, other.Genre)
+	fillInt(&t.Year, other.Year)
+	fillInt(&t.Track, other.Track)
+	fillInt(&t.TrackTotal, other.TrackTotal)
+	fillInt(&t.Disc, other.Disc)
+	fillInt(&t.DiscTotal, other.DiscTotal)
+}
+
+func fillString(s *string, value string) {
+	if *s == "" {
+		*s = value
+	}
+}
+
+func fillInt(n *int, value int) {
+	if *n == 0 {
+		*n = value
+	}
+}
+
+// PictureFrontCover is the picture type of front covers, as numbered by
+// ID3v2 APIC frames and FLAC PICTURE blocks.
+const PictureFrontCover = 3
+
+// Picture is an image embedded in an audio file.
+type Picture struct {
+	Type        int
+	MIMEType    string
+	Description string
+	Data        []byte
+}
+
+// Track is an audio file once read.
+type Track struct {
+	Tags
+	Duration time.Duration
+	Pictures []Picture
+}
+
+// Cover returns the front cover among the embedded pictures, or the first
+// picture when none is marked as such.
+func (t Track) Cover() (Picture, bool) {
+	for _, p := range t.Pictures {
+		if p.Type == PictureFrontCover {
+			return p, true
+		}
+	}
+	if len(t.Pictures) > 0 {
+		return t.Pictures[0], true
+	}
+	return Picture{}, false
+}
+
+// Seconds is the duration rounded to the second, the unit of song lengths.
+func (t Track) Seconds() int64 {
+	return int64(t.Duration.Round(time.Second) / time.Second)
+}
+
+// ParsePosition reads a track or disc position such as "3" or "3/12",
+// giving 0 for the parts that are missing or not numbers.
+func ParsePosition(value string) (n, total int) {
+	parts := strings.SplitN(strings.TrimSpace(value), "/", 2)
+	n, _ = strconv.Atoi(strings.TrimSpace(parts[0]))
+	if len(parts) == 2 {
+		total, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
+	}
+	return n, total
+}
+
+// ParseYear reads the year of a date such as "1973" or "1973-03-01".
+func ParseYear(value string) int {
+	value = strings.TrimSpace(value)
+	if len(value) < 4 {
+		return 0
+	}
+
+	year, err := strconv.Atoi(value[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
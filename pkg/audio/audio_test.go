@@ -0,0 +1,73 @@
+package audio_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/audio"
+)
+
+func TestParsePosition(t *testing.T) {
+	tests := []struct {
+		value     string
+		wantN     int
+		wantTotal int
+	}{
+		{value: "3", wantN: 3},
+		{value: "3/12", wantN: 3, wantTotal: 12},
+		{value: " 03 / 12 ", wantN: 3, wantTotal: 12},
+		{value: "A1", wantN: 0},
+		{value: "", wantN: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			n, total := audio.ParsePosition(tt.value)
+			if n != tt.wantN || total != tt.wantTotal {
+				t.Errorf("ParsePosition() = %d, %d, want %d, %d", n, total, tt.wantN, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestTrack_Seconds(t *testing.T) {
+	track := audio.Track{Duration: 279600 * time.Millisecond}
+	if got := track.Seconds(); got != 280 {
+		t.Errorf("Seconds() = %d, want 280", got)
+	}
+}
+
+func TestTags_Fill(t *testing.T) {
+	tags := audio.Tags{Title: "Río", Track: 1}
+	tags.Fill(audio.Tags{Title: "Rio", Artist: "Duran Duran", Track: 2, Year: 1982})
+
+	want := audio.Tags{Title: "Río", Artist: "Duran Duran", Track: 1, Year: 1982}
+	if tags != want {
+		t.Errorf("Fill() = %+v, want %+v", tags, want)
+	}
+}
+
+func TestTrack_Cover(t *testing.T) {
+	back := audio.Picture{Type: 4, Data: []byte("back")}
+	front := audio.Picture{Type: audio.PictureFrontCover, Data: []byte("front")}
+
+	tests := []struct {
+		name     string
+		pictures []audio.Picture
+		want     string
+		wantOK   bool
+	}{
+		{name: "Front cover", pictures: []audio.Picture{back, front}, want: "front", wantOK: true},
+		{name: "First picture", pictures: []audio.Picture{back}, want: "back", wantOK: true},
+		{name: "No pictures"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := audio.Track{Pictures: tt.pictures}.Cover()
+			if ok != tt.wantOK || string(got.Data) != tt.want {
+				t.Errorf("Cover() = %q, %v, want %q, %v", got.Data, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
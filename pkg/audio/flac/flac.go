@@ -0,0 +1,289 @@
+// Package flac reads the metadata blocks of FLAC files: the STREAMINFO
+// block for the duration, the Vorbis comment for the tags and the embedded
+// pictures. The Vorbis comment and picture parsers are shared with Ogg
+// files, which use the same structures.
+package flac
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/audio"
+)
+
+var (
+	ErrNotFLAC           = errors.New("not a flac file")
+	ErrInvalidBlock      = errors.New("invalid flac metadata block")
+	ErrInvalidComment    = errors.New("invalid vorbis comment")
+	ErrInvalidPicture    = errors.New("invalid flac picture")
+	ErrMissingStreamInfo = errors.New("flac file without streaminfo")
+)
+
+// Metadata block types.
+const (
+	BlockStreamInfo    = 0
+	BlockVorbisComment = 4
+	BlockPicture       = 6
+)
+
+// streamInfoSize is the size of the STREAMINFO block data.
+const streamInfoSize = 34
+
+// Read reads the tags, duration and pictures of a FLAC stream. Reading
+// stops at the last metadata block, before the audio frames.
+func Read(r io.Reader) (audio.Track, error) {
+	var track audio.Track
+	br := bufio.NewReader(r)
+
+	if err := skipID3v2(br); err != nil {
+		return track, err
+	}
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != "fLaC" {
+		return track, ErrNotFLAC
+	}
+
+	header := make([]byte, 4)
+	hasStreamInfo := false
+	for last := false; !last; {
+		if _, err := io.ReadFull(br, header); err != nil {
+			return track, ErrInvalidBlock
+		}
+		last = header[0]&0x80 != 0
+		size := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+
+		switch header[0] & 0x7f {
+		case BlockStreamInfo, BlockVorbisComment, BlockPicture:
+			data := make([]byte, size)
+			if _, err := io.ReadFull(br, data); err != nil {
+				return track, ErrInvalidBlock
+			}
+			if err := ReadBlock(&track, header[0]&0x7f, data); err != nil {
+				return track, err
+			}
+			hasStreamInfo = hasStreamInfo || header[0]&0x7f == BlockStreamInfo
+		default:
+			if _, err := br.Discard(size); err != nil {
+				return track, ErrInvalidBlock
+			}
+		}
+	}
+
+	if !hasStreamInfo {
+		return track, ErrMissingStreamInfo
+	}
+
+	return track, nil
+}
+
+// ReadFile reads the tags, duration and pictures of a FLAC file.
+func ReadFile(path string) (audio.Track, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return audio.Track{}, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// ReadBlock adds what a metadata block tells to the track. Blocks of other
+// types are ignored.
+func ReadBlock(track *audio.Track, blockType byte, data []byte) error {
+	switch blockType {
+	case BlockStreamInfo:
+		duration, err := ParseStreamInfo(data)
+		if err != nil {
+			return err
+		}
+		track.Duration = duration
+	case BlockVorbisComment:
+		tags, pictures, err := ParseVorbisComment(data)
+		if err != nil {
+			return err
+		}
+		track.Tags = tags
+		track.Pictures = append(track.Pictures, pictures...)
+	case BlockPicture:
+		picture, err := ParsePicture(data)
+		if err != nil {
+			return err
+		}
+		track.Pictures = append(track.Pictures, picture)
+	}
+
+	return nil
+}
+
+// ParseStreamInfo reads the duration of a STREAMINFO block, its total
+// number of samples over its sample rate. It is 0 when the encoder did not
+// know the number of samples.
+func ParseStreamInfo(data []byte) (time.Duration, error) {
+	if len(data) < streamInfoSize {
+		return 0, ErrInvalidBlock
+	}
+
+	sampleRate := int64(data[10])<<12 | int64(data[11])<<4 | int64(data[12])>>4
+	samples := int64(data[13]&0x0f)<<32 | int64(binary.BigEndian.Uint32(data[14:18]))
+	if sampleRate == 0 {
+		return 0, ErrInvalidBlock
+	}
+
+	return time.Duration(samples * int64(time.Second) / sampleRate), nil
+}
+
+// ParseVorbisComment reads the tags of a Vorbis comment, and the pictures
+// stored base64 encoded in METADATA_BLOCK_PICTURE fields. Of repeated
+// fields the first one wins.
+func ParseVorbisComment(data []byte) (audio.Tags, []audio.Picture, error) {
+	var tags audio.Tags
+	var pictures []audio.Picture
+
+	r := leReader{data: data}
+	vendor := r.uint32()
+	r.skip(int(vendor))
+	count := r.uint32()
+	if r.err != nil {
+		return tags, nil, ErrInvalidComment
+	}
+
+	fields := make(map[string]string)
+	for i := uint32(0); i < count; i++ {
+		length := r.uint32()
+		field := r.bytes(int(length))
+		if r.err != nil {
+			return tags, nil, ErrInvalidComment
+		}
+
+		eq := strings.IndexByte(string(field), '=')
+		if eq <= 0 {
+			continue
+		}
+		key := strings.ToUpper(string(field[:eq]))
+		value := strings.TrimSpace(string(field[eq+1:]))
+
+		if key == "METADATA_BLOCK_PICTURE" {
+			raw, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				continue
+			}
+			if picture, err := ParsePicture(raw); err == nil {
+				pictures = append(pictures, picture)
+			}
+			continue
+		}
+
+		if _, ok := fields[key]; !ok && value != "" {
+			fields[key] = value
+		}
+	}
+
+	tags.Title = fields["TITLE"]
+	tags.Artist = fields["ARTIST"]
+	tags.AlbumArtist = first(fields["ALBUMARTIST"], fields["ALBUM ARTIST"])
+	tags.Album = fields["ALBUM"]
+	tags.Genre = fields["GENRE"]
+	tags.Year = audio.ParseYear(first(fields["DATE"], fields["YEAR"]))
+	tags.Track, tags.TrackTotal = audio.ParsePosition(fields["TRACKNUMBER"])
+	tags.Disc, tags.DiscTotal = audio.ParsePosition(fields["DISCNUMBER"])
+	if total, _ := audio.ParsePosition(first(fields["TRACKTOTAL"], fields["TOTALTRACKS"])); total > 0 {
+		tags.TrackTotal = total
+	}
+	if total, _ := audio.ParsePosition(first(fields["DISCTOTAL"], fields["TOTALDISCS"])); total > 0 {
+		tags.DiscTotal = total
+	}
+
+	return tags, pictures, nil
+}
+
+func first(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ParsePicture reads a PICTURE block, also found in Vorbis comments.
+func ParsePicture(data []byte) (audio.Picture, error) {
+	r := beReader{leReader{data: data}}
+
+	var p audio.Picture
+	p.Type = int(r.uint32())
+	p.MIMEType = string(r.bytes(int(r.uint32())))
+	p.Description = string(r.bytes(int(r.uint32())))
+	// width, height, colour depth and number of colours
+	r.bytes(16)
+	p.Data = r.bytes(int(r.uint32()))
+
+	if r.err != nil {
+		return audio.Picture{}, ErrInvalidPicture
+	}
+	return p, nil
+}
+
+// skipID3v2 skips the ID3v2 tag some taggers put before the FLAC marker.
+func skipID3v2(br *bufio.Reader) error {
+	header, err := br.Peek(10)
+	if err != nil || string(header[:3]) != "ID3" {
+		return nil
+	}
+
+	size := int(header[6]&0x7f)<<21 | int(header[7]&0x7f)<<14 | int(header[8]&0x7f)<<7 | int(header[9]&0x7f)
+	if header[5]&0x10 != 0 {
+		size += 10
+	}
+	if _, err := br.Discard(10 + size); err != nil {
+		return ErrNotFLAC
+	}
+	return nil
+}
+
+// leReader and beReader read the fields of a block, remembering the first
+// read past its end.
+type leReader struct {
+	data []byte
+	err  error
+}
+
+func (r *leReader) bytes(n int) []byte {
+	if r.err != nil || n < 0 || n > len(r.data) {
+		r.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *leReader) skip(n int) {
+	r.bytes(n)
+}
+
+func (r *leReader) uint32() uint32 {
+	b := r.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+type beReader struct {
+	leReader
+}
+
+func (r *beReader) uint32() uint32 {
+	b := r.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
@@ -0,0 +1,153 @@
+package flac_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/audio"
+	"github.com/rodrwan/collection/pkg/audio/flac"
+)
+
+// streamInfo returns a STREAMINFO block of the given number of samples at
+// 44.1 kHz.
+func streamInfo(samples uint32) []byte {
+	data := make([]byte, 34)
+	// 44100 Hz, 2 channels, 16 bits per sample
+	data[10], data[11], data[12], data[13] = 0x0a, 0xc4, 0x42, 0xf0
+	binary.BigEndian.PutUint32(data[14:], samples)
+	return data
+}
+
+// vorbisComment returns a Vorbis comment of the given fields.
+func vorbisComment(fields ...string) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(len("test")))
+	buf.WriteString("test")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(fields)))
+	for _, f := range fields {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(f)))
+		buf.WriteString(f)
+	}
+	return buf.Bytes()
+}
+
+// picture returns a PICTURE block of the given type and data.
+func picture(pictureType uint32, data string) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, pictureType)
+	binary.Write(&buf, binary.BigEndian, uint32(len("image/png")))
+	buf.WriteString("image/png")
+	binary.Write(&buf, binary.BigEndian, uint32(0))
+	buf.Write(make([]byte, 16))
+	binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+	buf.WriteString(data)
+	return buf.Bytes()
+}
+
+// block returns a metadata block header followed by its data.
+func block(blockType byte, last bool, data []byte) []byte {
+	if last {
+		blockType |= 0x80
+	}
+	n := len(data)
+	return append([]byte{blockType, byte(n >> 16), byte(n >> 8), byte(n)}, data...)
+}
+
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		want     audio.Tags
+		duration time.Duration
+		pictures int
+		wantErr  error
+	}{
+		{
+			name: "Vorbis comment and picture",
+			data: join(
+				[]byte("fLaC"),
+				block(flac.BlockStreamInfo, false, streamInfo(441000)),
+				block(1, false, make([]byte, 8)),
+				block(flac.BlockVorbisComment, false, vorbisComment(
+					"TITLE=Hey You", "artist=Pink Floyd", "ALBUMARTIST=Pink Floyd", "ALBUM=The Wall",
+					"GENRE=Rock", "DATE=1979-11-30", "TRACKNUMBER=3", "TRACKTOTAL=13", "DISCNUMBER=2/2",
+					"TITLE=Ignored",
+				)),
+				block(flac.BlockPicture, true, picture(audio.PictureFrontCover, "png")),
+			),
+			want: audio.Tags{
+				Title: "Hey You", Artist: "Pink Floyd", AlbumArtist: "Pink Floyd", Album: "The Wall",
+				Genre: "Rock", Year: 1979, Track: 3, TrackTotal: 13, Disc: 2, DiscTotal: 2,
+			},
+			duration: 10 * time.Second,
+			pictures: 1,
+		},
+		{
+			name: "Picture in Vorbis comment after ID3v2 tag",
+			data: join(
+				[]byte{'I', 'D', '3', 3, 0, 0, 0, 0, 0, 4}, []byte("junk"),
+				[]byte("fLaC"),
+				block(flac.BlockStreamInfo, false, streamInfo(22050)),
+				block(flac.BlockVorbisComment, true, vorbisComment(
+					"TITLE=Mother", "METADATA_BLOCK_PICTURE="+base64.StdEncoding.EncodeToString(picture(0, "png")),
+				)),
+			),
+			want:     audio.Tags{Title: "Mother"},
+			duration: 500 * time.Millisecond,
+			pictures: 1,
+		},
+		{
+			name:    "Not FLAC",
+			data:    []byte("RIFF0000WAVE"),
+			wantErr: flac.ErrNotFLAC,
+		},
+		{
+			name:    "Truncated block",
+			data:    join([]byte("fLaC"), block(flac.BlockStreamInfo, true, streamInfo(1))[:20]),
+			wantErr: flac.ErrInvalidBlock,
+		},
+		{
+			name:    "Broken Vorbis comment",
+			data:    join([]byte("fLaC"), block(flac.BlockStreamInfo, false, streamInfo(1)), block(flac.BlockVorbisComment, true, []byte{9, 9})),
+			wantErr: flac.ErrInvalidComment,
+		},
+		{
+			name:    "No STREAMINFO",
+			data:    join([]byte("fLaC"), block(flac.BlockVorbisComment, true, vorbisComment("TITLE=Mother"))),
+			wantErr: flac.ErrMissingStreamInfo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := flac.Read(bytes.NewReader(tt.data))
+			if err != tt.wantErr {
+				t.Fatalf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Tags != tt.want {
+				t.Errorf("Read() tags = %+v, want %+v", got.Tags, tt.want)
+			}
+			if got.Duration != tt.duration {
+				t.Errorf("Read() duration = %v, want %v", got.Duration, tt.duration)
+			}
+			if len(got.Pictures) != tt.pictures {
+				t.Fatalf("Read() pictures = %d, want %d", len(got.Pictures), tt.pictures)
+			}
+			for _, p := range got.Pictures {
+				if p.MIMEType != "image/png" || string(p.Data) != "png" {
+					t.Errorf("Read() picture = %+v", p)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package mp3
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/rodrwan/collection/pkg/audio"
+)
+
+const (
+	id3v2HeaderSize = 10
+	id3v1Size       = 128
+)
+
+// ID3v2 header flags.
+const (
+	flagUnsynchronisation = 0x80
+	flagExtendedHeader    = 0x40
+	flagFooter            = 0x10
+)
+
+// id3v2Frames maps the frame ids of every version to the tag they hold.
+// Version 2.2 uses three letter ids.
+var id3v2Frames = map[string]string{
+	"TIT2": "title", "TT2": "title",
+	"TPE1": "artist", "TP1": "artist",
+	"TPE2": "albumartist", "TP2": "albumartist",
+	"TALB": "album", "TAL": "album",
+	"TCON": "genre", "TCO": "genre",
+	"TRCK": "track", "TRK": "track",
+	"TPOS": "disc", "TPA": "disc",
+	"TYER": "year", "TYE": "year",
+	"TDRC": "year",
+}
+
+// id3v2Size reads the size of the ID3v2 tag at the start of the header,
+// header and footer included, or 0 when there is none.
+func id3v2Size(header []byte) int64 {
+	if len(header) < id3v2HeaderSize || string(header[:3]) != "ID3" {
+		return 0
+	}
+
+	size := int64(syncsafe(header[6:10])) + id3v2HeaderSize
+	if header[3] >= 4 && header[5]&flagFooter != 0 {
+		size += id3v2HeaderSize
+	}
+	return size
+}
+
+// parseID3v2 reads the text frames of a whole ID3v2 tag, header included.
+// Frames that are compressed, encrypted or unknown are skipped.
+func parseID3v2(tag []byte) audio.Tags {
+	if len(tag) < id3v2HeaderSize {
+		return audio.Tags{}
+	}
+
+	version, flags := tag[3], tag[5]
+	body := tag[id3v2HeaderSize:]
+	if int(syncsafe(tag[6:10])) < len(body) {
+		body = body[:syncsafe(tag[6:10])]
+	}
+
+	// Before 2.4 unsynchronisation applies to the whole tag.
+	if version < 4 && flags&flagUnsynchronisation != 0 {
+		body = resync(body)
+	}
+
+	if flags&flagExtendedHeader != 0 && len(body) >= 4 {
+		size := int(binary.BigEndian.Uint32(body[:4])) + 4
+		if version >= 4 {
+			size = int(syncsafe(body[:4]))
+		}
+		if size > len(body) {
+			return audio.Tags{}
+		}
+		body = body[size:]
+	}
+
+	idSize, headerSize := 4, 10
+	if version == 2 {
+		idSize, headerSize = 3, 6
+	}
+
+	var tags audio.Tags
+	for len(body) >= headerSize && body[0] != 0 {
+		id := string(body[:idSize])
+
+		var size int
+		var frameFlags uint16
+		switch version {
+		case 2:
+			size = int(body[3])<<16 | int(body[4])<<8 | int(body[5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(body[4:8]))
+			frameFlags = binary.BigEndian.Uint16(body[8:10])
+		default:
+			size = int(syncsafe(body[4:8]))
+			frameFlags = binary.BigEndian.Uint16(body[8:10])
+		}
+
+		if size < 0 || headerSize+size > len(body) {
+			break
+		}
+		data := body[headerSize : headerSize+size]
+		body = body[headerSize+size:]
+
+		field, ok := id3v2Frames[id]
+		if !ok {
+			continue
+		}
+
+		data, ok = frameData(version, flags, frameFlags, data)
+		if !ok {
+			continue
+		}
+
+		setTag(&tags, field, decodeText(data))
+	}
+
+	return tags
+}
+
+// frameData undoes the frame level encodings of a frame, reporting false for
+// those that cannot be read: compressed or encrypted ones.
+func frameData(version, tagFlags byte, flags uint16, data []byte) ([]byte, bool) {
+	switch version {
+	case 3:
+		// compression, encryption
+		if flags&0x00c0 != 0 {
+			return nil, false
+		}
+		// grouping identity
+		if flags&0x0020 != 0 && len(data) > 0 {
+			data = data[1:]
+		}
+	case 4:
+		// compression, encryption
+		if flags&0x000c != 0 {
+			return nil, false
+		}
+		// grouping identity
+		if flags&0x0040 != 0 && len(data) > 0 {
+			data = data[1:]
+		}
+		if flags&0x0002 != 0 || tagFlags&flagUnsynchronisation != 0 {
+			data = resync(data)
+		}
+		// data length indicator
+		if flags&0x0001 != 0 && len(data) >= 4 {
+			data = data[4:]
+		}
+	}
+
+	return data, true
+}
+
+func setTag(tags *audio.Tags, field, value string) {
+	if value == "" {
+		return
+	}
+
+	switch field {
+	case "title":
+		tags.Title = value
+	case "artist":
+		tags.Artist = value
+	case "albumartist":
+		tags.AlbumArtist = value
+	case "album":
+		tags.Album = value
+	case "genre":
+		tags.Genre = genreName(value)
+	case "track":
+		tags.Track, tags.TrackTotal = audio.ParsePosition(value)
+	case "disc":
+		tags.Disc, tags.DiscTotal = audio.ParsePosition(value)
+	case "year":
+		tags.Year = audio.ParseYear(value)
+	}
+}
+
+// decodeText decodes a text frame, its first byte telling the encoding. Of
+// several null separated values only the first is kept.
+func decodeText(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	var text string
+	switch encoding, data := data[0], data[1:]; encoding {
+	case 1:
+		text = decodeUTF16(data, true)
+	case 2:
+		text = decodeUTF16(data, false)
+	case 3:
+		text = string(data)
+	default:
+		text = latin1(data)
+	}
+
+	if i := strings.IndexByte(text, 0); i >= 0 {
+		text = text[:i]
+	}
+	return strings.TrimSpace(text)
+}
+
+// decodeUTF16 decodes UTF-16 text, big endian unless a byte order mark says
+// otherwise.
+func decodeUTF16(data []byte, bom bool) string {
+	var order binary.ByteOrder = binary.BigEndian
+	if bom && len(data) >= 2 {
+		switch {
+		case data[0] == 0xff && data[1] == 0xfe:
+			order = binary.LittleEndian
+			data = data[2:]
+		case data[0] == 0xfe && data[1] == 0xff:
+			data = data[2:]
+		}
+	}
+
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, order.Uint16(data[i:]))
+	}
+	return string(utf16.Decode(units))
+}
+
+func latin1(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// resync undoes unsynchronisation, the 0x00 inserted after every 0xff.
+func resync(data []byte) []byte {
+	return bytes.Replace(data, []byte{0xff, 0x00}, []byte{0xff}, -1)
+}
+
+// syncsafe reads a 28 bit integer stored in the low 7 bits of 4 bytes.
+func syncsafe(b []byte) uint32 {
+	return uint32(b[0]&0x7f)<<21 | uint32(b[1]&0x7f)<<14 | uint32(b[2]&0x7f)<<7 | uint32(b[3]&0x7f)
+}
+
+// parseID3v1 reads the fixed size tag at the end of a file, which ID3v1.1
+// extends with a track number at the end of the comment.
+func parseID3v1(tag []byte) audio.Tags {
+	text := func(b []byte) string {
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		return strings.TrimSpace(latin1(b))
+	}
+
+	tags := audio.Tags{
+		Title:  text(tag[3:33]),
+		Artist: text(tag[33:63]),
+		Album:  text(tag[63:93]),
+		Year:   audio.ParseYear(text(tag[93:97])),
+	}
+
+	if tag[125] == 0 && tag[126] != 0 {
+		tags.Track = int(tag[126])
+	}
+	if int(tag[127]) < len(id3v1Genres) {
+		tags.Genre = id3v1Genres[tag[127]]
+	}
+
+	return tags
+}
+
+// genreName resolves the genre references of ID3v2, "(17)" or "17", to the
+// ID3v1 genre names. Other values are kept as they are.
+func genreName(value string) string {
+	ref := value
+	if strings.HasPrefix(ref, "(") {
+		if end := strings.Index(ref, ")"); end > 0 {
+			if rest := strings.TrimSpace(ref[end+1:]); rest != "" {
+				return rest
+			}
+			ref = ref[1:end]
+		}
+	}
+
+	if n, err := strconv.Atoi(ref); err == nil && n >= 0 && n < len(id3v1Genres) {
+		return id3v1Genres[n]
+	}
+	return value
+}
+
+// id3v1Genres are the genres of ID3v1 with the Winamp extensions, by number.
+var id3v1Genres = []string{
+	"Blues", "Classic Rock", "Country", "Dance", "Disco", "Funk", "Grunge",
+	"Hip-Hop", "Jazz", "Metal", "New Age", "Oldies", "Other", "Pop", "R&B",
+	"Rap", "Reggae", "Rock", "Techno", "Industrial", "Alternative", "Ska",
+	"Death Metal", "Pranks", "Soundtrack", "Euro-Techno", "Ambient",
+	"Trip-Hop", "Vocal", "Jazz+Funk", "Fusion", "Trance", "Classical",
+	"Instrumental", "Acid", "House", "Game", "Sound Clip", "Gospel", "Noise",
+	"AlternRock", "Bass", "Soul", "Punk", "Space", "Meditative",
+	"Instrumental Pop", "Instrumental Rock", "Ethnic", "Gothic", "Darkwave",
+	"Techno-Industrial", "Electronic", "Pop-Folk", "Eurodance", "Dream",
+	"Southern Rock", "Comedy", "Cult", "Gangsta", "Top 40", "Christian Rap",
+	"Pop/Funk", "Jungle", "Native American", "Cabaret", "New Wave",
+	"Psychadelic", "Rave", "Showtunes", "Trailer", "Lo-Fi", "Tribal",
+	"Acid Punk", "Acid Jazz", "Polka", "Retro", "Musical", "Rock & Roll",
+	"Hard Rock", "Folk", "Folk-Rock", "National Folk", "Swing", "Fast Fusion",
+	"Bebob", "Latin", "Revival", "Celtic", "Bluegrass", "Avantgarde",
+	"Gothic Rock", "Progressive Rock", "Psychedelic Rock", "Symphonic Rock",
+	"Slow Rock", "Big Band", "Chorus", "Easy Listening", "Acoustic", "Humour",
+	"Speech", "Chanson", "Opera", "Chamber Music", "Sonata", "Symphony",
+	"Booty Bass", "Primus", "Porn Groove", "Satire", "Slow Jam", "Club",
+	"Tango", "Samba", "Folklore", "Ballad", "Power Ballad", "Rhythmic Soul",
+	"Freestyle", "Duet", "Punk Rock", "Drum Solo", "A capella", "Euro-House",
+	"Dance Hall",
+}
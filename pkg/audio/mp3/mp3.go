@@ -0,0 +1,305 @@
+// Package mp3 reads the tags and duration of MPEG audio files: ID3v2 and
+// ID3v1 tags, and the MPEG frame headers, using the Xing, Info or VBRI
+// header of the first frame when there is one.
+package mp3
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/audio"
+)
+
+var (
+	ErrNoFrames = errors.New("no mpeg audio frames found")
+)
+
+// maxSyncSearch bounds how far past the tags the first frame is looked for,
+// so files that are not MPEG audio are not read whole.
+const maxSyncSearch = 64 * 1024
+
+// Read reads the tags and duration of the MPEG audio file of the given size.
+// ID3v2 tags win over ID3v1 tags.
+func Read(r io.ReaderAt, size int64) (audio.Track, error) {
+	var track audio.Track
+
+	// A file too short for a whole ID3v2 header has no tag.
+	header := make([]byte, id3v2HeaderSize)
+	n, err := r.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		return track, err
+	}
+
+	start := id3v2Size(header[:n])
+	if start > size {
+		start = size
+	}
+	if start > 0 {
+		tag := make([]byte, start)
+		if _, err := r.ReadAt(tag, 0); err != nil && err != io.EOF {
+			return track, err
+		}
+		track.Tags = parseID3v2(tag)
+	}
+
+	end := size
+	if size-start >= id3v1Size {
+		tag := make([]byte, id3v1Size)
+		if _, err := r.ReadAt(tag, size-id3v1Size); err != nil && err != io.EOF {
+			return track, err
+		}
+		if string(tag[:3]) == "TAG" {
+			track.Fill(parseID3v1(tag))
+			end -= id3v1Size
+		}
+	}
+
+	duration, err := readDuration(io.NewSectionReader(r, start, end-start))
+	if err != nil {
+		return track, err
+	}
+	track.Duration = duration
+
+	return track, nil
+}
+
+// ReadFile reads the tags and duration of an MPEG audio file.
+func ReadFile(path string) (audio.Track, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return audio.Track{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return audio.Track{}, err
+	}
+
+	return Read(f, info.Size())
+}
+
+// frame is an MPEG audio frame header.
+type frame struct {
+	version    byte // 1, 2 or 25 for 2.5
+	layer      byte // 1, 2 or 3
+	bitrate    int  // bits per second
+	sampleRate int
+	padding    bool
+	mono       bool
+}
+
+var (
+	// bitrates in kbps by [version 1 or 2][layer - 1][index]. MPEG 2.5 uses
+	// the MPEG 2 ones.
+	bitrates = [2][3][16]int{
+		{
+			{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+			{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+			{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+		},
+		{
+			{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+			{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+			{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		},
+	}
+	sampleRates = map[byte][3]int{
+		1:  {44100, 48000, 32000},
+		2:  {22050, 24000, 16000},
+		25: {11025, 12000, 8000},
+	}
+)
+
+// parseFrame reads a frame header, reporting false when the bytes are not
+// one. Free format bitrates are not supported.
+func parseFrame(b []byte) (frame, bool) {
+	h := binary.BigEndian.Uint32(b)
+	if h&0xffe00000 != 0xffe00000 {
+		return frame{}, false
+	}
+
+	var f frame
+	switch (h >> 19) & 3 {
+	case 0:
+		f.version = 25
+	case 2:
+		f.version = 2
+	case 3:
+		f.version = 1
+	default:
+		return frame{}, false
+	}
+
+	layer := (h >> 17) & 3
+	if layer == 0 {
+		return frame{}, false
+	}
+	f.layer = byte(4 - layer)
+
+	index := (h >> 12) & 0xf
+	rateIndex := (h >> 10) & 3
+	if index == 0 || index == 0xf || rateIndex == 3 {
+		return frame{}, false
+	}
+
+	table := 0
+	if f.version != 1 {
+		table = 1
+	}
+	f.bitrate = bitrates[table][f.layer-1][index] * 1000
+	f.sampleRate = sampleRates[f.version][rateIndex]
+	f.padding = (h>>9)&1 == 1
+	f.mono = (h>>6)&3 == 3
+
+	return f, true
+}
+
+// samples is the number of samples per channel a frame decodes to.
+func (f frame) samples() int {
+	switch {
+	case f.layer == 1:
+		return 384
+	case f.layer == 3 && f.version != 1:
+		return 576
+	}
+	return 1152
+}
+
+// length is the size of the frame in bytes, header included.
+func (f frame) length() int {
+	if f.layer == 1 {
+		n := 12 * f.bitrate / f.sampleRate
+		if f.padding {
+			n++
+		}
+		return n * 4
+	}
+
+	n := f.samples() / 8 * f.bitrate / f.sampleRate
+	if f.padding {
+		n++
+	}
+	return n
+}
+
+func (f frame) duration(frames int64) time.Duration {
+	return time.Duration(frames * int64(f.samples()) * int64(time.Second) / int64(f.sampleRate))
+}
+
+// sideInfo is the size of the layer III side information that follows the
+// header, after which the Xing or Info header is found.
+func (f frame) sideInfo() int {
+	switch {
+	case f.version == 1 && f.mono:
+		return 17
+	case f.version == 1:
+		return 32
+	case f.mono:
+		return 9
+	}
+	return 17
+}
+
+// readDuration finds the first frame and either reads the frame count of
+// its VBR header or walks every frame, resynchronising past garbage.
+func readDuration(r io.Reader) (time.Duration, error) {
+	br := bufio.NewReaderSize(r, 16*1024)
+
+	first, err := findFrame(br, maxSyncSearch)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := br.Peek(first.length())
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	if frames, ok := vbrFrames(first, data); ok {
+		return first.duration(frames), nil
+	}
+
+	var total time.Duration
+	f := first
+	for {
+		if _, err := br.Discard(f.length()); err != nil {
+			break
+		}
+		total += f.duration(1)
+
+		next, err := findFrame(br, -1)
+		if err != nil {
+			break
+		}
+		f = next
+	}
+
+	return total, nil
+}
+
+// findFrame skips to the next frame header, trying at most limit bytes when
+// limit is not negative. The first frame has to be followed by another one
+// so that stray sync bits in the data are not taken for a frame.
+func findFrame(br *bufio.Reader, limit int) (frame, error) {
+	for skipped := 0; limit < 0 || skipped < limit; skipped++ {
+		b, err := br.Peek(4)
+		if err != nil {
+			return frame{}, ErrNoFrames
+		}
+
+		if f, ok := parseFrame(b); ok {
+			if limit < 0 || followedByFrame(br, f) {
+				return f, nil
+			}
+		}
+
+		if _, err := br.Discard(1); err != nil {
+			return frame{}, ErrNoFrames
+		}
+	}
+
+	return frame{}, ErrNoFrames
+}
+
+// followedByFrame reports whether another frame of the same kind follows
+// f, or the data ends with it.
+func followedByFrame(br *bufio.Reader, f frame) bool {
+	n := f.length()
+	b, err := br.Peek(n + 4)
+	if err != nil {
+		return len(b) == n
+	}
+
+	next, ok := parseFrame(b[n:])
+	return ok && next.version == f.version && next.layer == f.layer && next.sampleRate == f.sampleRate
+}
+
+// vbrFrames reads the frame count of the Xing, Info or VBRI header found in
+// the first frame of variable bitrate files, and of some constant bitrate
+// ones.
+func vbrFrames(f frame, data []byte) (int64, bool) {
+	if f.layer != 3 {
+		return 0, false
+	}
+
+	if at := 4 + f.sideInfo(); len(data) >= at+12 {
+		id := string(data[at : at+4])
+		if id == "Xing" || id == "Info" {
+			flags := binary.BigEndian.Uint32(data[at+4:])
+			if flags&1 == 0 {
+				return 0, false
+			}
+			return int64(binary.BigEndian.Uint32(data[at+8:])), true
+		}
+	}
+
+	if at := 4 + 32; len(data) >= at+18 && string(data[at:at+4]) == "VBRI" {
+		return int64(binary.BigEndian.Uint32(data[at+14:])), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,174 @@
+package mp3_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/audio"
+	"github.com/rodrwan/collection/pkg/audio/mp3"
+)
+
+// frames returns n MPEG 1 layer III frames at 128 kbps and 44.1 kHz, 417
+// bytes each.
+func frames(n int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		frame := make([]byte, 417)
+		copy(frame, []byte{0xff, 0xfb, 0x90, 0x00})
+		buf.Write(frame)
+	}
+	return buf.Bytes()
+}
+
+// xingFrame returns a mono frame whose Xing header counts the given frames.
+func xingFrame(count uint32) []byte {
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xff, 0xfb, 0x90, 0xc0})
+	copy(frame[4+17:], "Xing")
+	binary.BigEndian.PutUint32(frame[4+17+4:], 1)
+	binary.BigEndian.PutUint32(frame[4+17+8:], count)
+	return frame
+}
+
+func syncsafe(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7f), byte(n >> 14 & 0x7f), byte(n >> 7 & 0x7f), byte(n & 0x7f)}
+}
+
+// id3v23 builds an ID3v2.3 tag of text frames, id and value pairs.
+func id3v23(pairs ...string) []byte {
+	var body bytes.Buffer
+	for i := 0; i+1 < len(pairs); i += 2 {
+		data := append([]byte{3}, pairs[i+1]...)
+		body.WriteString(pairs[i])
+		binary.Write(&body, binary.BigEndian, uint32(len(data)))
+		body.Write([]byte{0, 0})
+		body.Write(data)
+	}
+	// padding
+	body.Write(make([]byte, 16))
+
+	tag := append([]byte{'I', 'D', '3', 3, 0, 0}, syncsafe(body.Len())...)
+	return append(tag, body.Bytes()...)
+}
+
+// id3v24UTF16 builds an ID3v2.4 tag with a single UTF-16 text frame.
+func id3v24UTF16(id, value string) []byte {
+	data := []byte{1, 0xff, 0xfe}
+	for _, r := range value {
+		data = append(data, byte(r), 0)
+	}
+
+	var body bytes.Buffer
+	body.WriteString(id)
+	body.Write(syncsafe(len(data)))
+	body.Write([]byte{0, 0})
+	body.Write(data)
+
+	tag := append([]byte{'I', 'D', '3', 4, 0, 0}, syncsafe(body.Len())...)
+	return append(tag, body.Bytes()...)
+}
+
+func id3v1(title, artist, album, year string, track, genre byte) []byte {
+	tag := make([]byte, 128)
+	copy(tag, "TAG")
+	copy(tag[3:], title)
+	copy(tag[33:], artist)
+	copy(tag[63:], album)
+	copy(tag[93:], year)
+	tag[126] = track
+	tag[127] = genre
+	return tag
+}
+
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func TestRead(t *testing.T) {
+	// 383 frames of 1152 samples at 44.1 kHz last 10.005 seconds.
+	const cbr = 10005 * time.Millisecond
+
+	tests := []struct {
+		name     string
+		data     []byte
+		want     audio.Tags
+		duration time.Duration
+		wantErr  error
+	}{
+		{
+			name: "ID3v2.3 tags",
+			data: join(
+				id3v23("TIT2", "Hey You", "TPE1", "Pink Floyd", "TALB", "The Wall", "TRCK", "3/13", "TPOS", "2/2", "TYER", "1979", "TCON", "(17)"),
+				frames(383),
+			),
+			want: audio.Tags{
+				Title: "Hey You", Artist: "Pink Floyd", Album: "The Wall", Genre: "Rock",
+				Year: 1979, Track: 3, TrackTotal: 13, Disc: 2, DiscTotal: 2,
+			},
+			duration: cbr,
+		},
+		{
+			name: "ID3v2.4 UTF-16 tag filled from ID3v1",
+			data: join(
+				id3v24UTF16("TIT2", "Río"),
+				frames(383),
+				id3v1("Rio", "Duran Duran", "Rio", "1982", 1, 13),
+			),
+			want: audio.Tags{
+				Title: "Río", Artist: "Duran Duran", Album: "Rio", Genre: "Pop",
+				Year: 1982, Track: 1,
+			},
+			duration: cbr,
+		},
+		{
+			name:     "Garbage before and between frames",
+			data:     join([]byte{0xff, 0xfb, 0x00, 1, 2, 3}, frames(200), []byte{0, 0, 0xff}, frames(183)),
+			duration: cbr,
+		},
+		{
+			name:     "Xing header",
+			data:     join(xingFrame(3828), frames(10)),
+			duration: time.Duration(3828 * 1152 * int64(time.Second) / 44100),
+		},
+		{
+			name:    "Truncated ID3v2 header",
+			data:    []byte("ID3"),
+			wantErr: mp3.ErrNoFrames,
+		},
+		{
+			name:    "Truncated ID3v2 header with version",
+			data:    []byte("ID3\x03\x00"),
+			wantErr: mp3.ErrNoFrames,
+		},
+		{
+			name:    "ID3v2 tag larger than the file",
+			data:    []byte("ID3\x03\x00\x00\x00\x00\x00\x7f"),
+			wantErr: mp3.ErrNoFrames,
+		},
+		{
+			name:    "Not MPEG audio",
+			data:    join(id3v23("TIT2", "Nothing"), bytes.Repeat([]byte("not audio"), 100)),
+			wantErr: mp3.ErrNoFrames,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mp3.Read(bytes.NewReader(tt.data), int64(len(tt.data)))
+			if err != tt.wantErr {
+				t.Fatalf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if tt.want != (audio.Tags{}) && got.Tags != tt.want {
+				t.Errorf("Read() tags = %+v, want %+v", got.Tags, tt.want)
+			}
+			if d := got.Duration - tt.duration; d < -time.Millisecond || d > time.Millisecond {
+				t.Errorf("Read() duration = %v, want %v", got.Duration, tt.duration)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+// Package ogg reads the tags and duration of Ogg files holding a Vorbis or
+// FLAC stream. Tags come from the comment header packets and the duration
+// from the granule position of the last page.
+package ogg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/audio"
+	"github.com/rodrwan/collection/pkg/audio/flac"
+)
+
+var (
+	ErrNotOgg            = errors.New("not an ogg file")
+	ErrUnsupportedStream = errors.New("unsupported ogg stream, only vorbis and flac are read")
+	ErrInvalidHeader     = errors.New("invalid ogg stream header")
+)
+
+// Codec is the codec of the first logical stream of an Ogg file.
+type Codec string
+
+const (
+	CodecVorbis Codec = "vorbis"
+	CodecFLAC   Codec = "flac"
+)
+
+const (
+	pageHeaderSize = 27
+	// lastPageSearch is how much of the end of the file is searched for the
+	// last page.
+	lastPageSearch = 64 * 1024
+	// maxPacketSize bounds a header packet, pictures included.
+	maxPacketSize = 16 << 20
+)
+
+var capturePattern = []byte("OggS")
+
+// Read reads the codec, tags, duration and pictures of the Ogg file of the
+// given size.
+func Read(r io.ReaderAt, size int64) (audio.Track, Codec, error) {
+	var track audio.Track
+
+	packets := &packetReader{r: bufio.NewReader(io.NewSectionReader(r, 0, size))}
+
+	id, err := packets.next()
+	if err != nil {
+		return track, "", err
+	}
+
+	var codec Codec
+	var sampleRate int64
+	switch {
+	case bytes.HasPrefix(id, []byte("\x01vorbis")) && len(id) >= 16:
+		codec = CodecVorbis
+		sampleRate = int64(binary.LittleEndian.Uint32(id[12:16]))
+
+		comment, err := packets.next()
+		if err != nil {
+			return track, codec, err
+		}
+		if !bytes.HasPrefix(comment, []byte("\x03vorbis")) {
+			return track, codec, ErrInvalidHeader
+		}
+		track.Tags, track.Pictures, err = flac.ParseVorbisComment(comment[7:])
+		if err != nil {
+			return track, codec, err
+		}
+
+	case bytes.HasPrefix(id, []byte("\x7fFLAC")) && len(id) >= 13+4+34:
+		codec = CodecFLAC
+		// mapping version, header packet count, "fLaC", STREAMINFO block
+		info := id[13+4:]
+		if string(id[9:13]) != "fLaC" {
+			return track, codec, ErrInvalidHeader
+		}
+		if track.Duration, err = flac.ParseStreamInfo(info); err != nil {
+			return track, codec, err
+		}
+		sampleRate = int64(info[10])<<12 | int64(info[11])<<4 | int64(info[12])>>4
+
+		for last := id[13]&0x80 != 0; !last; {
+			block, err := packets.next()
+			if err != nil || len(block) < 4 {
+				break
+			}
+			last = block[0]&0x80 != 0
+			if err := flac.ReadBlock(&track, block[0]&0x7f, block[4:]); err != nil {
+				return track, codec, err
+			}
+		}
+
+	default:
+		return track, "", ErrUnsupportedStream
+	}
+
+	// STREAMINFO may not know the number of samples, the last page does.
+	if sampleRate > 0 {
+		granule, err := lastGranule(r, size, packets.serial)
+		if err != nil {
+			return track, codec, err
+		}
+		if granule > 0 {
+			track.Duration = time.Duration(granule * int64(time.Second) / sampleRate)
+		}
+	}
+
+	return track, codec, nil
+}
+
+// ReadFile reads the codec, tags, duration and pictures of an Ogg file.
+func ReadFile(path string) (audio.Track, Codec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return audio.Track{}, "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return audio.Track{}, "", err
+	}
+
+	return Read(f, info.Size())
+}
+
+// packetReader assembles the packets of the first logical stream from its
+// pages, following the lacing values of their segment tables.
+type packetReader struct {
+	r       *bufio.Reader
+	serial  uint32
+	started bool
+	// segments left of the current page
+	segments []byte
+}
+
+func (pr *packetReader) next() ([]byte, error) {
+	var packet []byte
+	for {
+		if len(pr.segments) == 0 {
+			if err := pr.readPage(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		size := int(pr.segments[0])
+		pr.segments = pr.segments[1:]
+
+		if len(packet)+size > maxPacketSize {
+			return nil, ErrInvalidHeader
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(pr.r, data); err != nil {
+			return nil, ErrInvalidHeader
+		}
+		packet = append(packet, data...)
+
+		// a lacing value below 255 ends the packet
+		if size < 255 {
+			return packet, nil
+		}
+	}
+}
+
+// readPage reads the header of the next page of the stream, skipping the
+// pages of other logical streams.
+func (pr *packetReader) readPage() error {
+	for {
+		header := make([]byte, pageHeaderSize)
+		if _, err := io.ReadFull(pr.r, header); err != nil {
+			if !pr.started {
+				return ErrNotOgg
+			}
+			return ErrInvalidHeader
+		}
+		if !bytes.Equal(header[:4], capturePattern) {
+			return ErrNotOgg
+		}
+
+		table := make([]byte, header[26])
+		if _, err := io.ReadFull(pr.r, table); err != nil {
+			return ErrInvalidHeader
+		}
+
+		serial := binary.LittleEndian.Uint32(header[14:18])
+		if !pr.started {
+			pr.started = true
+			pr.serial = serial
+		}
+
+		if serial != pr.serial {
+			size := 0
+			for _, s := range table {
+				size += int(s)
+			}
+			if _, err := pr.r.Discard(size); err != nil {
+				return ErrInvalidHeader
+			}
+			continue
+		}
+
+		pr.segments = table
+		return nil
+	}
+}
+
+// lastGranule finds the granule position of the last page of the stream,
+// its number of samples, in the end of the file.
+func lastGranule(r io.ReaderAt, size int64, serial uint32) (int64, error) {
+	start := size - lastPageSearch
+	if start < 0 {
+		start = 0
+	}
+
+	tail := make([]byte, size-start)
+	if _, err := r.ReadAt(tail, start); err != nil && err != io.EOF {
+		return 0, err
+	}
+
+	for i := bytes.LastIndex(tail, capturePattern); i >= 0; i = bytes.LastIndex(tail[:i], capturePattern) {
+		if len(tail)-i < pageHeaderSize {
+			continue
+		}
+		page := tail[i:]
+		if binary.LittleEndian.Uint32(page[14:18]) != serial {
+			continue
+		}
+		granule := int64(binary.LittleEndian.Uint64(page[6:14]))
+		if granule >= 0 {
+			return granule, nil
+		}
+	}
+
+	return 0, nil
+}
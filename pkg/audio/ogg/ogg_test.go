@@ -0,0 +1,172 @@
+package ogg_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/audio"
+	"github.com/rodrwan/collection/pkg/audio/ogg"
+)
+
+// page returns an Ogg page of the given stream holding whole packets. The
+// checksum is left out, it is not verified.
+func page(serial uint32, granule int64, packets ...[]byte) []byte {
+	var table, body []byte
+	for _, p := range packets {
+		n := len(p)
+		for ; n >= 255; n -= 255 {
+			table = append(table, 255)
+		}
+		table = append(table, byte(n))
+		body = append(body, p...)
+	}
+
+	header := make([]byte, 27)
+	copy(header, "OggS")
+	binary.LittleEndian.PutUint64(header[6:], uint64(granule))
+	binary.LittleEndian.PutUint32(header[14:], serial)
+	header[26] = byte(len(table))
+
+	return bytes.Join([][]byte{header, table, body}, nil)
+}
+
+// vorbisComment returns a Vorbis comment of the given fields.
+func vorbisComment(fields ...string) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(len("test")))
+	buf.WriteString("test")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(fields)))
+	for _, f := range fields {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(f)))
+		buf.WriteString(f)
+	}
+	return buf.Bytes()
+}
+
+// vorbisID returns a Vorbis identification header at 48 kHz.
+func vorbisID() []byte {
+	id := make([]byte, 30)
+	copy(id, "\x01vorbis")
+	binary.LittleEndian.PutUint32(id[12:], 48000)
+	return id
+}
+
+// flacID returns an Ogg FLAC identification header whose STREAMINFO
+// block, at 44.1 kHz, does not know the number of samples.
+func flacID() []byte {
+	id := []byte("\x7fFLAC\x01\x00\x00\x01fLaC\x00\x00\x00\x22")
+	info := make([]byte, 34)
+	info[10], info[11], info[12], info[13] = 0x0a, 0xc4, 0x42, 0xf0
+	return append(id, info...)
+}
+
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func TestRead(t *testing.T) {
+	comment := append([]byte("\x03vorbis"), vorbisComment("TITLE=Hey You", "ARTIST=Pink Floyd", "ALBUM=The Wall", "TRACKNUMBER=3/13")...)
+	// a comment spanning several segments
+	longComment := append([]byte("\x03vorbis"), vorbisComment("TITLE="+string(bytes.Repeat([]byte("a"), 600)))...)
+
+	tests := []struct {
+		name      string
+		data      []byte
+		codec     ogg.Codec
+		wantTitle string
+		duration  time.Duration
+		wantErr   error
+	}{
+		{
+			name: "Vorbis",
+			data: join(
+				page(7, 0, vorbisID()),
+				page(7, 0, comment, []byte("\x05vorbis")),
+				page(7, 240000, make([]byte, 100)),
+				page(7, 480000, make([]byte, 100)),
+			),
+			codec:     ogg.CodecVorbis,
+			wantTitle: "Hey You",
+			duration:  10 * time.Second,
+		},
+		{
+			name: "Vorbis with pages of another stream",
+			data: join(
+				page(7, 0, vorbisID()),
+				page(8, 0, []byte("other")),
+				page(7, 0, longComment),
+				page(7, 96000, make([]byte, 10)),
+				page(8, 999999, []byte("other")),
+			),
+			codec:     ogg.CodecVorbis,
+			wantTitle: string(bytes.Repeat([]byte("a"), 600)),
+			duration:  2 * time.Second,
+		},
+		{
+			name: "FLAC",
+			data: join(
+				page(1, 0, flacID()),
+				page(1, 0, append([]byte{0x84, 0, 0, 0}, vorbisComment("TITLE=Mother")...)),
+				page(1, 220500, make([]byte, 10)),
+			),
+			codec:     ogg.CodecFLAC,
+			wantTitle: "Mother",
+			duration:  5 * time.Second,
+		},
+		{
+			name:    "Opus",
+			data:    page(1, 0, []byte("OpusHead\x01\x02")),
+			wantErr: ogg.ErrUnsupportedStream,
+		},
+		{
+			name:    "Not Ogg",
+			data:    []byte("fLaC\x00\x00\x00\x22"),
+			wantErr: ogg.ErrNotOgg,
+		},
+		{
+			name:    "Missing comment",
+			data:    page(7, 0, vorbisID(), []byte("\x05vorbis")),
+			wantErr: ogg.ErrInvalidHeader,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, codec, err := ogg.Read(bytes.NewReader(tt.data), int64(len(tt.data)))
+			if err != tt.wantErr {
+				t.Fatalf("Read() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if codec != tt.codec {
+				t.Errorf("Read() codec = %v, want %v", codec, tt.codec)
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("Read() title = %q, want %q", got.Title, tt.wantTitle)
+			}
+			if got.Duration != tt.duration {
+				t.Errorf("Read() duration = %v, want %v", got.Duration, tt.duration)
+			}
+		})
+	}
+}
+
+func TestRead_Tags(t *testing.T) {
+	data := join(
+		page(7, 0, vorbisID()),
+		page(7, 0, append([]byte("\x03vorbis"), vorbisComment("TITLE=Hey You", "ARTIST=Pink Floyd", "ALBUM=The Wall", "TRACKNUMBER=3/13")...)),
+	)
+
+	got, _, err := ogg.Read(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := audio.Tags{Title: "Hey You", Artist: "Pink Floyd", Album: "The Wall", Track: 3, TrackTotal: 13}
+	if got.Tags != want {
+		t.Errorf("Read() tags = %+v, want %+v", got.Tags, want)
+	}
+}
@@ -0,0 +1,286 @@
+// Package backup reads and writes collection archives: a gzip compressed
+// tar holding a manifest.json followed by one JSON file per section, each
+// an array of items. The manifest records the version of the format and
+// the item count and SHA-256 checksum of every section, so an archive is
+// verified as a whole before any of it is used.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"time"
+)
+
+// Format identifies collection archives in their manifest.
+const Format = "collection-backup"
+
+// Version is the version of the archive format written by Writer. Readers
+// accept archives up to this version.
+const Version = 1
+
+// manifestFile is the name of the manifest in the archive.
+const manifestFile = "manifest.json"
+
+// MaxEntrySize caps the size of each file of an archive, as Read holds
+// them in memory.
+var MaxEntrySize int64 = 512 << 20
+
+var (
+	ErrNotABackup         = errors.New("not a collection backup")
+	ErrUnsupportedVersion = errors.New("unsupported backup version")
+	ErrDuplicateSection   = errors.New("duplicate backup section")
+	ErrEntryTooLarge      = errors.New("backup entry is too large")
+)
+
+// Manifest describes an archive.
+type Manifest struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	Sections  []Section `json:"sections"`
+}
+
+// Section is a file of the archive, e.g. the records.
+type Section struct {
+	Name   string `json:"name"`
+	File   string `json:"file"`
+	Count  int    `json:"count"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Count is the number of items of a section, 0 when the archive has no
+// such section.
+func (m Manifest) Count(name string) int {
+	for _, s := range m.Sections {
+		if s.Name == name {
+			return s.Count
+		}
+	}
+	return 0
+}
+
+// ChecksumError is a section whose content does not match the manifest.
+type ChecksumError struct {
+	Section string
+}
+
+func (e *ChecksumError) Error() string {
+	return "backup section " + e.Section + " is corrupt, checksum mismatch"
+}
+
+// Writer writes an archive. Sections are kept in memory until Close, which
+// writes the manifest ahead of them.
+type Writer struct {
+	w        io.Writer
+	manifest Manifest
+	data     [][]byte
+}
+
+// NewWriter starts an archive created at the given time.
+func NewWriter(w io.Writer, createdAt time.Time) *Writer {
+	return &Writer{
+		w: w,
+		manifest: Manifest{
+			Format:    Format,
+			Version:   Version,
+			CreatedAt: createdAt.UTC(),
+			Sections:  []Section{},
+		},
+	}
+}
+
+// Add adds a section holding items, which must be a slice.
+func (w *Writer) Add(name string, items interface{}) error {
+	v := reflect.ValueOf(items)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("backup section %s: items must be a slice, not %T", name, items)
+	}
+	for _, s := range w.manifest.Sections {
+		if s.Name == name {
+			return fmt.Errorf("%w: %s", ErrDuplicateSection, name)
+		}
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("backup section %s: %w", name, err)
+	}
+	if v.IsNil() {
+		data = []byte("[]")
+	}
+
+	sum := sha256.Sum256(data)
+	w.manifest.Sections = append(w.manifest.Sections, Section{
+		Name:   name,
+		File:   name + ".json",
+		Count:  v.Len(),
+		Size:   int64(len(data)),
+		SHA256: hex.EncodeToString(sum[:]),
+	})
+	w.data = append(w.data, data)
+
+	return nil
+}
+
+// Close writes the archive and returns its manifest. It does not close the
+// underlying writer.
+func (w *Writer) Close() (Manifest, error) {
+	manifest, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	gz := gzip.NewWriter(w.w)
+	tw := tar.NewWriter(gz)
+
+	if err := writeFile(tw, manifestFile, manifest, w.manifest.CreatedAt); err != nil {
+		return Manifest{}, err
+	}
+	for i, s := range w.manifest.Sections {
+		if err := writeFile(tw, s.File, w.data[i], w.manifest.CreatedAt); err != nil {
+			return Manifest{}, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return Manifest{}, err
+	}
+	if err := gz.Close(); err != nil {
+		return Manifest{}, err
+	}
+
+	return w.manifest, nil
+}
+
+func writeFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err := tw.Write(data)
+	return err
+}
+
+// Archive is an archive read and verified by Read.
+type Archive struct {
+	Manifest Manifest
+	files    map[string][]byte
+}
+
+// Read reads a whole archive and verifies it against its manifest: the
+// format, the version and the size and checksum of every section.
+func Read(r io.Reader) (*Archive, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrNotABackup
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err == tar.ErrHeader {
+			return nil, ErrNotABackup
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if hdr.Size > MaxEntrySize {
+			return nil, fmt.Errorf("%w: %s", ErrEntryTooLarge, hdr.Name)
+		}
+		data, err := ioutil.ReadAll(io.LimitReader(tr, MaxEntrySize+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > MaxEntrySize {
+			return nil, fmt.Errorf("%w: %s", ErrEntryTooLarge, hdr.Name)
+		}
+		files[hdr.Name] = data
+	}
+
+	data, ok := files[manifestFile]
+	if !ok {
+		return nil, ErrNotABackup
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil || manifest.Format != Format {
+		return nil, ErrNotABackup
+	}
+	if manifest.Version < 1 || manifest.Version > Version {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, manifest.Version)
+	}
+
+	seen := make(map[string]bool, len(manifest.Sections))
+	for _, s := range manifest.Sections {
+		if seen[s.Name] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateSection, s.Name)
+		}
+		seen[s.Name] = true
+
+		data, ok := files[s.File]
+		if !ok || int64(len(data)) != s.Size {
+			return nil, &ChecksumError{Section: s.Name}
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != s.SHA256 {
+			return nil, &ChecksumError{Section: s.Name}
+		}
+	}
+
+	return &Archive{Manifest: manifest, files: files}, nil
+}
+
+// Has reports whether the archive has a section.
+func (a *Archive) Has(name string) bool {
+	_, ok := a.section(name)
+	return ok
+}
+
+// Decode decodes the items of a section into v, a pointer to a slice. A
+// section missing from the archive leaves v untouched.
+func (a *Archive) Decode(name string, v interface{}) error {
+	s, ok := a.section(name)
+	if !ok {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(a.files[s.File]))
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("backup section %s: %w", name, err)
+	}
+	return nil
+}
+
+func (a *Archive) section(name string) (Section, bool) {
+	for _, s := range a.Manifest.Sections {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Section{}, false
+}
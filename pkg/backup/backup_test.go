@@ -0,0 +1,226 @@
+package backup_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/backup"
+)
+
+type item struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func writeArchive(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := backup.NewWriter(&buf, time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+	if err := w.Add("records", []item{{1, "Rumours"}, {2, "Tusk"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Add("songs", []item(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// rewrite rewrites every file of an archive through fn, dropping the files
+// for which it returns nil.
+func rewrite(t *testing.T, archive []byte, fn func(name string, data []byte) []byte) []byte {
+	t.Helper()
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+
+	var buf bytes.Buffer
+	out := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(out)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data = fn(hdr.Name, data)
+		if data == nil {
+			continue
+		}
+		hdr.Size = int64(len(data))
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(data)
+	}
+	tw.Close()
+	out.Close()
+
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	a, err := backup.Read(bytes.NewReader(writeArchive(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := a.Manifest
+	if m.Format != backup.Format || m.Version != backup.Version || !m.CreatedAt.Equal(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)) {
+		t.Errorf("Manifest = %+v", m)
+	}
+	if m.Count("records") != 2 || m.Count("songs") != 0 || m.Count("loans") != 0 {
+		t.Errorf("Count() = %d, %d, %d", m.Count("records"), m.Count("songs"), m.Count("loans"))
+	}
+
+	var records []item
+	if err := a.Decode("records", &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[1] != (item{2, "Tusk"}) {
+		t.Errorf("Decode(records) = %+v", records)
+	}
+
+	songs := []item{{9, "untouched"}}
+	if err := a.Decode("songs", &songs); err != nil {
+		t.Fatal(err)
+	}
+	if len(songs) != 0 {
+		t.Errorf("Decode(songs) = %+v, want empty", songs)
+	}
+
+	loans := []item{{9, "untouched"}}
+	if a.Has("loans") {
+		t.Error("Has(loans) = true")
+	}
+	if err := a.Decode("loans", &loans); err != nil || len(loans) != 1 {
+		t.Errorf("Decode(loans) = %+v, %v, want it untouched", loans, err)
+	}
+}
+
+func TestWriter_Add(t *testing.T) {
+	w := backup.NewWriter(ioutil.Discard, time.Now())
+	if err := w.Add("records", item{1, "Rumours"}); err == nil {
+		t.Error("Add() of a non slice succeeded")
+	}
+	if err := w.Add("records", []item{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Add("records", []item{}); !errors.Is(err, backup.ErrDuplicateSection) {
+		t.Errorf("Add() of a duplicate section = %v, want %v", err, backup.ErrDuplicateSection)
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	archive := writeArchive(t)
+
+	tests := []struct {
+		name  string
+		input []byte
+		check func(error) bool
+	}{
+		{
+			name:  "NotGzip",
+			input: []byte("id,name\n1,Rumours\n"),
+			check: func(err error) bool { return err == backup.ErrNotABackup },
+		},
+		{
+			name: "NotTar",
+			input: func() []byte {
+				var buf bytes.Buffer
+				gz := gzip.NewWriter(&buf)
+				gz.Write([]byte(strings.Repeat("not a tar ", 100)))
+				gz.Close()
+				return buf.Bytes()
+			}(),
+			check: func(err error) bool { return err == backup.ErrNotABackup },
+		},
+		{
+			name: "MissingManifest",
+			input: rewrite(t, archive, func(name string, data []byte) []byte {
+				if name == "manifest.json" {
+					return nil
+				}
+				return data
+			}),
+			check: func(err error) bool { return err == backup.ErrNotABackup },
+		},
+		{
+			name: "FutureVersion",
+			input: rewrite(t, archive, func(name string, data []byte) []byte {
+				if name == "manifest.json" {
+					return bytes.Replace(data, []byte(`"version": 1`), []byte(`"version": 2`), 1)
+				}
+				return data
+			}),
+			check: func(err error) bool { return errors.Is(err, backup.ErrUnsupportedVersion) },
+		},
+		{
+			name: "CorruptSection",
+			input: rewrite(t, archive, func(name string, data []byte) []byte {
+				if name == "records.json" {
+					return bytes.Replace(data, []byte("Tusk"), []byte("Tuks"), 1)
+				}
+				return data
+			}),
+			check: func(err error) bool {
+				var ce *backup.ChecksumError
+				return errors.As(err, &ce) && ce.Section == "records"
+			},
+		},
+		{
+			name: "MissingSection",
+			input: rewrite(t, archive, func(name string, data []byte) []byte {
+				if name == "songs.json" {
+					return nil
+				}
+				return data
+			}),
+			check: func(err error) bool {
+				var ce *backup.ChecksumError
+				return errors.As(err, &ce) && ce.Section == "songs"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := backup.Read(bytes.NewReader(tt.input))
+			if !tt.check(err) {
+				t.Errorf("Read() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRead_EntryTooLarge(t *testing.T) {
+	archive := writeArchive(t)
+
+	defer func(max int64) { backup.MaxEntrySize = max }(backup.MaxEntrySize)
+	backup.MaxEntrySize = 16
+
+	_, err := backup.Read(bytes.NewReader(archive))
+	if !errors.Is(err, backup.ErrEntryTooLarge) {
+		t.Errorf("Read() error = %v, want %v", err, backup.ErrEntryTooLarge)
+	}
+}
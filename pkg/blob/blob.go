@@ -0,0 +1,26 @@
+// Package blob defines where binary files such as cover art are kept.
+package blob
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// Object is an open blob. Callers must close Body.
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+// Store keeps blobs under slash separated keys, e.g. covers/<id>/small.jpg.
+type Store interface {
+	Put(key, contentType string, body io.Reader) error
+	Get(key string) (Object, error)
+	Delete(key string) error
+}
@@ -0,0 +1,120 @@
+// Package local stores blobs as files below a root directory.
+package local
+
+import (
+	"io"
+	"io/ioutil"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/rodrwan/collection/pkg/blob"
+)
+
+type Store struct {
+	root string
+}
+
+// New creates the root directory if needed.
+func New(root string) (*Store, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+
+	return &Store{root: root}, nil
+}
+
+// Put writes the blob to a temporary file first and renames it into place,
+// so readers never see a partial file. The content type is derived from the
+// key extension when reading back.
+func (s *Store) Put(key, contentType string, body io.Reader) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
+
+func (s *Store) Get(key string) (blob.Object, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return blob.Object{}, err
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return blob.Object{}, blob.ErrNotFound
+		}
+		return blob.Object{}, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return blob.Object{}, err
+	}
+	if info.IsDir() {
+		f.Close()
+		return blob.Object{}, blob.ErrNotFound
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return blob.Object{
+		Body:        f,
+		ContentType: contentType,
+		Size:        info.Size(),
+	}, nil
+}
+
+func (s *Store) Delete(key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil {
+		if os.IsNotExist(err) {
+			return blob.ErrNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// path maps a key to a file below the root, rejecting keys that would
+// escape it.
+func (s *Store) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if key == "" || clean == "/" || strings.Contains(key, "\\") || clean != "/"+key {
+		return "", blob.ErrInvalidKey
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(clean[1:])), nil
+}
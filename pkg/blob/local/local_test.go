@@ -0,0 +1,53 @@
+package local
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rodrwan/collection/pkg/blob"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Put("covers/a/small.png", "image/png", strings.NewReader("png")); err != nil {
+		t.Fatal(err)
+	}
+
+	obj, err := store.Get("covers/a/small.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := ioutil.ReadAll(obj.Body)
+	obj.Body.Close()
+	if string(data) != "png" || obj.ContentType != "image/png" || obj.Size != 3 {
+		t.Errorf("Unexpected object %q %s %d", data, obj.ContentType, obj.Size)
+	}
+
+	if err := store.Delete("covers/a/small.png"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("covers/a/small.png"); err != blob.ErrNotFound {
+		t.Errorf("Expected error %v, got %v", blob.ErrNotFound, err)
+	}
+	if _, err := store.Get("covers/a"); err != blob.ErrNotFound {
+		t.Errorf("Expected error %v, got %v", blob.ErrNotFound, err)
+	}
+
+	for _, key := range []string{"", "../secret", "covers/../../secret", "/etc/passwd", "covers//a"} {
+		if err := store.Put(key, "text/plain", strings.NewReader("x")); err != blob.ErrInvalidKey {
+			t.Errorf("Expected key %q to be rejected, got %v", key, err)
+		}
+	}
+}
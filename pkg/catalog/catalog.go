@@ -0,0 +1,404 @@
+// Package catalog renders a read-only copy of the collection as a static
+// HTML site: an index of every record, indexes by kind, artist and genre,
+// a page per record and a search that runs in the browser. Pages only link
+// to each other by relative paths so the site can be served from any
+// directory, or opened from a file share without a web server.
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Names of the groups of records with no artist or no genre.
+const (
+	UnknownArtist = "Unknown artist"
+	NoGenre       = "No genre"
+)
+
+// Site is what the catalog is rendered from.
+type Site struct {
+	Title       string
+	GeneratedAt time.Time
+	Records     []Record
+}
+
+// Record is a record of the catalog. Status is left out of the pages when
+// it is "owned". Cover and Thumbnail are zero for records without cover
+// art.
+type Record struct {
+	ID        string
+	Name      string
+	Artist    string
+	Kind      string
+	Genre     string
+	Tags      []string
+	Status    string
+	Songs     []Song
+	Cover     Image
+	Thumbnail Image
+}
+
+// Song is a song of a record, its length in seconds. Track is its
+// position on the record, 0 when unknown.
+type Song struct {
+	Track  int
+	Name   string
+	Length int64
+}
+
+// Image is a picture copied into the site, opened when the site is
+// written.
+type Image struct {
+	Ext  string
+	Open func() (io.ReadCloser, error)
+}
+
+// IsZero reports whether there is no image.
+func (i Image) IsZero() bool {
+	return i.Open == nil
+}
+
+// Summary tells what Write wrote.
+type Summary struct {
+	Pages   int
+	Records int
+	Images  int
+}
+
+// group is the records of a kind, an artist or a genre.
+type group struct {
+	Name    string
+	Path    string
+	Records []*page
+}
+
+// page is a record with the paths of its page and images.
+type page struct {
+	Record
+	Path      string
+	CoverPath string
+	ThumbPath string
+	Length    int64
+}
+
+// writer writes the files of a site below dir.
+type writer struct {
+	dir     string
+	site    Site
+	summary Summary
+}
+
+// Write renders the site into dir, creating it when missing. Files written
+// by a previous run are replaced; pages of records that are gone are left
+// behind, so write into an empty directory to publish a clean copy.
+func Write(dir string, site Site) (Summary, error) {
+	w := &writer{dir: dir, site: site}
+
+	pages := make([]*page, 0, len(site.Records))
+	for _, r := range site.Records {
+		p := &page{Record: r, Path: "records/" + slug(r.ID) + ".html"}
+		for _, s := range r.Songs {
+			p.Length += s.Length
+		}
+		pages = append(pages, p)
+	}
+	sort.SliceStable(pages, func(i, j int) bool {
+		a, b := pages[i], pages[j]
+		if sortKey(a.Artist) != sortKey(b.Artist) {
+			return sortKey(a.Artist) < sortKey(b.Artist)
+		}
+		return sortKey(a.Name) < sortKey(b.Name)
+	})
+
+	for _, p := range pages {
+		if !p.Cover.IsZero() {
+			path := "covers/" + slug(p.ID) + p.Cover.Ext
+			if err := w.copy(path, p.Cover); err != nil {
+				return w.summary, fmt.Errorf("cover of %s: %w", p.Name, err)
+			}
+			p.CoverPath = path
+		}
+		if !p.Thumbnail.IsZero() {
+			path := "covers/" + slug(p.ID) + "-small" + p.Thumbnail.Ext
+			if err := w.copy(path, p.Thumbnail); err != nil {
+				return w.summary, fmt.Errorf("thumbnail of %s: %w", p.Name, err)
+			}
+			p.ThumbPath = path
+		}
+	}
+
+	kinds := groupBy(pages, "kinds", func(p *page) string { return p.Kind })
+	artists := groupBy(pages, "artists", func(p *page) string {
+		if strings.TrimSpace(p.Artist) == "" {
+			return UnknownArtist
+		}
+		return p.Artist
+	})
+	genres := groupBy(pages, "genres", func(p *page) string {
+		if strings.TrimSpace(p.Genre) == "" {
+			return NoGenre
+		}
+		return p.Genre
+	})
+
+	if err := w.file("style.css", func(out io.Writer) error {
+		_, err := io.WriteString(out, styleCSS)
+		return err
+	}); err != nil {
+		return w.summary, err
+	}
+	if err := w.file("search.js", func(out io.Writer) error {
+		_, err := io.WriteString(out, searchJS)
+		return err
+	}); err != nil {
+		return w.summary, err
+	}
+	if err := w.file("search-index.js", func(out io.Writer) error {
+		return writeSearchIndex(out, pages)
+	}); err != nil {
+		return w.summary, err
+	}
+
+	if err := w.page("index.html", "index", map[string]interface{}{
+		"Records": pages,
+		"Kinds":   kinds,
+		"Artists": artists,
+		"Genres":  genres,
+	}); err != nil {
+		return w.summary, err
+	}
+
+	for _, index := range []struct {
+		dir, title string
+		groups     []*group
+	}{
+		{"kinds", "Kinds", kinds},
+		{"artists", "Artists", artists},
+		{"genres", "Genres", genres},
+	} {
+		if err := w.page(index.dir+"/index.html", "groups", map[string]interface{}{
+			"Title":  index.title,
+			"Groups": index.groups,
+		}); err != nil {
+			return w.summary, err
+		}
+		for _, g := range index.groups {
+			if err := w.page(g.Path, "group", map[string]interface{}{
+				"Title":   g.Name,
+				"Section": index.title,
+				"Index":   index.dir + "/index.html",
+				"Records": g.Records,
+			}); err != nil {
+				return w.summary, err
+			}
+		}
+	}
+
+	for _, p := range pages {
+		if err := w.page(p.Path, "record", map[string]interface{}{
+			"Title":  p.Name,
+			"Record": p,
+			"Artist": groupOf(artists, p),
+			"Kind":   groupOf(kinds, p),
+			"Genre":  groupOf(genres, p),
+		}); err != nil {
+			return w.summary, err
+		}
+	}
+	w.summary.Records = len(pages)
+
+	return w.summary, nil
+}
+
+// page renders a template into a page at path, a slash separated path
+// relative to the root of the site.
+func (w *writer) page(path, name string, data map[string]interface{}) error {
+	data["Site"] = w.site
+	data["Root"] = strings.Repeat("../", strings.Count(path, "/"))
+	if _, ok := data["Title"]; !ok {
+		data["Title"] = w.site.Title
+	}
+
+	if err := w.file(path, func(out io.Writer) error {
+		return templates.ExecuteTemplate(out, name, data)
+	}); err != nil {
+		return err
+	}
+	w.summary.Pages++
+	return nil
+}
+
+// copy copies an image into the site.
+func (w *writer) copy(path string, image Image) error {
+	in, err := image.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := w.file(path, func(out io.Writer) error {
+		_, err := io.Copy(out, in)
+		return err
+	}); err != nil {
+		return err
+	}
+	w.summary.Images++
+	return nil
+}
+
+func (w *writer) file(path string, write func(io.Writer) error) error {
+	name := filepath.Join(w.dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// groupBy groups the pages by the name key gives them, the groups sorted
+// by name and given a page under dir.
+func groupBy(pages []*page, dir string, key func(*page) string) []*group {
+	byKey := make(map[string]*group)
+	var groups []*group
+	for _, p := range pages {
+		name := key(p)
+		k := sortKey(name)
+		g, ok := byKey[k]
+		if !ok {
+			g = &group{Name: name}
+			byKey[k] = g
+			groups = append(groups, g)
+		}
+		g.Records = append(g.Records, p)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return sortKey(groups[i].Name) < sortKey(groups[j].Name)
+	})
+
+	used := make(map[string]bool)
+	for _, g := range groups {
+		s := slug(g.Name)
+		for n := 2; used[s]; n++ {
+			s = fmt.Sprintf("%s-%d", slug(g.Name), n)
+		}
+		used[s] = true
+		g.Path = dir + "/" + s + ".html"
+	}
+
+	return groups
+}
+
+// groupOf is the group holding the page.
+func groupOf(groups []*group, p *page) *group {
+	for _, g := range groups {
+		for _, r := range g.Records {
+			if r == p {
+				return g
+			}
+		}
+	}
+	return nil
+}
+
+// searchEntry is a record in the search index, with short keys to keep
+// the index small.
+type searchEntry struct {
+	Name   string   `json:"n"`
+	Artist string   `json:"a,omitempty"`
+	Kind   string   `json:"k"`
+	Genre  string   `json:"g,omitempty"`
+	Songs  []string `json:"s,omitempty"`
+	Tags   []string `json:"t,omitempty"`
+	Path   string   `json:"p"`
+}
+
+// writeSearchIndex writes the records as a script, which unlike a JSON
+// file can be loaded from pages opened without a web server.
+func writeSearchIndex(out io.Writer, pages []*page) error {
+	entries := make([]searchEntry, 0, len(pages))
+	for _, p := range pages {
+		e := searchEntry{
+			Name:   p.Name,
+			Artist: p.Artist,
+			Kind:   p.Kind,
+			Genre:  p.Genre,
+			Tags:   p.Tags,
+			Path:   p.Path,
+		}
+		for _, s := range p.Songs {
+			e.Songs = append(e.Songs, s.Name)
+		}
+		entries = append(entries, e)
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(out, "window.catalogIndex = %s;\n", data)
+	return err
+}
+
+// sortKey orders names ignoring case and a leading "The".
+func sortKey(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	return strings.TrimPrefix(key, "the ")
+}
+
+// slug turns a name into a file name of lowercase letters, digits and
+// dashes.
+func slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			dash = false
+		case b.Len() > 0 && !dash:
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	s := strings.TrimSuffix(b.String(), "-")
+	if s == "" {
+		return "untitled"
+	}
+	return s
+}
+
+// duration formats a length in seconds as m:ss, or h:mm:ss from an hour.
+func duration(seconds int64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+var templates = template.Must(template.New("catalog").Funcs(template.FuncMap{
+	"duration": duration,
+	"inc":      func(i int) int { return i + 1 },
+}).Parse(pageTemplates))
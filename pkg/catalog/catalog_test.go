@@ -0,0 +1,150 @@
+package catalog_test
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/catalog"
+)
+
+func image(data string) catalog.Image {
+	return catalog.Image{
+		Ext: ".jpg",
+		Open: func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(strings.NewReader(data)), nil
+		},
+	}
+}
+
+func read(t *testing.T, dir, path string) string {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	site := catalog.Site{
+		Title:       "Our Records",
+		GeneratedAt: time.Date(2021, 5, 6, 7, 8, 0, 0, time.UTC),
+		Records: []catalog.Record{
+			{
+				ID:     "b1",
+				Name:   "The Wall",
+				Artist: "Pink Floyd",
+				Kind:   "vinyl",
+				Genre:  "Rock / Progressive Rock",
+				Tags:   []string{"classic", "gatefold"},
+				Status: "owned",
+				Songs: []catalog.Song{
+					{Name: "In the Flesh?", Length: 199},
+					{Track: 14, Name: "Hey You", Length: 280},
+				},
+				Cover:     image("full"),
+				Thumbnail: image("small"),
+			},
+			{ID: "a2", Name: "Blue", Artist: "Joni Mitchell", Kind: "mp3", Status: "wishlist"},
+			{ID: "c3", Name: "<Untitled>", Kind: "mp3"},
+		},
+	}
+
+	summary, err := catalog.Write(dir, site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// index, 3 group indexes, 2 kinds, 3 artists, 2 genres and 3 records
+	if summary.Pages != 14 || summary.Records != 3 || summary.Images != 2 {
+		t.Errorf("Write() = %+v", summary)
+	}
+
+	index := read(t, dir, "index.html")
+	for _, want := range []string{
+		"<title>Our Records</title>",
+		`href="records/a2.html"`,
+		`src="covers/b1-small.jpg"`,
+		"mp3 · wishlist",
+		"&lt;Untitled&gt;",
+		"Generated 2021-05-06 07:08",
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index.html does not contain %q", want)
+		}
+	}
+	// records are sorted by artist, unknown artists first
+	if strings.Index(index, "records/c3.html") > strings.Index(index, "records/a2.html") ||
+		strings.Index(index, "records/a2.html") > strings.Index(index, "records/b1.html") {
+		t.Error("index.html records are not sorted by artist")
+	}
+
+	page := read(t, dir, "records/b1.html")
+	for _, want := range []string{
+		`<title>The Wall · Our Records</title>`,
+		`href="../style.css"`,
+		`src="../covers/b1.jpg"`,
+		`href="../artists/pink-floyd.html"`,
+		`href="../genres/rock-progressive-rock.html"`,
+		`href="../kinds/vinyl.html"`,
+		"<td>1</td><td>In the Flesh?</td>",
+		"<td>14</td><td>Hey You</td><td>4:40</td>",
+		"<dd>7:59</dd>",
+		"classic, gatefold",
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("records/b1.html does not contain %q", want)
+		}
+	}
+	if strings.Contains(page, "<dt>Status</dt>") {
+		t.Error("records/b1.html shows the owned status")
+	}
+
+	if got := read(t, dir, "covers/b1.jpg"); got != "full" {
+		t.Errorf("covers/b1.jpg = %q", got)
+	}
+	if !strings.Contains(read(t, dir, "artists/index.html"), `href="../artists/unknown-artist.html"`) {
+		t.Error("artists/index.html does not link the records without artist")
+	}
+	if !strings.Contains(read(t, dir, "genres/no-genre.html"), "records/a2.html") {
+		t.Error("genres/no-genre.html does not list the records without genre")
+	}
+
+	search := read(t, dir, "search-index.js")
+	if !strings.HasPrefix(search, "window.catalogIndex = [") || !strings.Contains(search, `"s":["In the Flesh?","Hey You"]`) {
+		t.Errorf("search-index.js = %s", search)
+	}
+}
+
+func TestWrite_ImageError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	broken := errors.New("blob not found")
+	_, err = catalog.Write(dir, catalog.Site{Records: []catalog.Record{{
+		ID:   "a1",
+		Name: "Blue",
+		Kind: "mp3",
+		Cover: catalog.Image{Ext: ".jpg", Open: func() (io.ReadCloser, error) {
+			return nil, broken
+		}},
+	}}})
+	if !errors.Is(err, broken) {
+		t.Errorf("Write() error = %v, want %v", err, broken)
+	}
+}
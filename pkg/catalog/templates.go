@@ -0,0 +1,170 @@
+package catalog
+
+// pageTemplates are the templates of the pages. Every page is given Site,
+// Title and Root, the relative path back to the root of the site.
+const pageTemplates = `
+{{define "header"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{if ne .Title .Site.Title}}{{.Title}} · {{end}}{{.Site.Title}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<header>
+<a class="home" href="{{.Root}}index.html">{{.Site.Title}}</a>
+<nav>
+<a href="{{.Root}}kinds/index.html">Kinds</a>
+<a href="{{.Root}}artists/index.html">Artists</a>
+<a href="{{.Root}}genres/index.html">Genres</a>
+</nav>
+</header>
+<main>
+{{end}}
+
+{{define "footer"}}</main>
+<footer>Generated {{.Site.GeneratedAt.Format "2006-01-02 15:04"}}</footer>
+</body>
+</html>
+{{end}}
+
+{{define "records"}}<ul class="records">
+{{range .Records}}<li>
+<a href="{{$.Root}}{{.Path}}">{{if .ThumbPath}}<img src="{{$.Root}}{{.ThumbPath}}" alt="" loading="lazy">{{else}}<span class="nocover"></span>{{end}}
+<span class="name">{{.Name}}</span>
+<span class="artist">{{if .Artist}}{{.Artist}}{{else}}Unknown artist{{end}}</span>
+<span class="kind">{{.Kind}}{{if and .Status (ne .Status "owned")}} · {{.Status}}{{end}}</span></a>
+</li>
+{{end}}</ul>
+{{end}}
+
+{{define "index"}}{{template "header" .}}
+<h1>{{.Site.Title}}</h1>
+<p>{{len .Records}} records in {{len .Kinds}} kinds by {{len .Artists}} artists.</p>
+<form class="search" onsubmit="return false">
+<input id="search" type="search" placeholder="Search records, artists, songs and tags" autocomplete="off">
+</form>
+<ul id="results" class="results" hidden></ul>
+{{template "records" .}}
+<script src="search-index.js"></script>
+<script src="search.js"></script>
+{{template "footer" .}}{{end}}
+
+{{define "groups"}}{{template "header" .}}
+<h1>{{.Title}}</h1>
+<ul class="groups">
+{{range .Groups}}<li><a href="{{$.Root}}{{.Path}}">{{.Name}}</a> <span class="count">{{len .Records}}</span></li>
+{{end}}</ul>
+{{template "footer" .}}{{end}}
+
+{{define "group"}}{{template "header" .}}
+<p class="crumbs"><a href="{{.Root}}{{.Index}}">{{.Section}}</a></p>
+<h1>{{.Title}}</h1>
+{{template "records" .}}
+{{template "footer" .}}{{end}}
+
+{{define "record"}}{{template "header" .}}
+{{with .Record}}<article class="record">
+{{if .CoverPath}}<img class="cover" src="{{$.Root}}{{.CoverPath}}" alt="Cover of {{.Name}}">{{end}}
+<div>
+<h1>{{.Name}}</h1>
+<p class="artist"><a href="{{$.Root}}{{$.Artist.Path}}">{{$.Artist.Name}}</a></p>
+<dl>
+<dt>Kind</dt><dd><a href="{{$.Root}}{{$.Kind.Path}}">{{.Kind}}</a></dd>
+<dt>Genre</dt><dd><a href="{{$.Root}}{{$.Genre.Path}}">{{$.Genre.Name}}</a></dd>
+{{if and .Status (ne .Status "owned")}}<dt>Status</dt><dd>{{.Status}}</dd>
+{{end}}{{if .Tags}}<dt>Tags</dt><dd>{{range $i, $t := .Tags}}{{if $i}}, {{end}}{{$t}}{{end}}</dd>
+{{end}}{{if .Length}}<dt>Length</dt><dd>{{duration .Length}}</dd>
+{{end}}</dl>
+</div>
+</article>
+{{if .Songs}}<table class="songs">
+<thead><tr><th>#</th><th>Song</th><th>Length</th></tr></thead>
+<tbody>
+{{range $i, $s := .Songs}}<tr><td>{{if $s.Track}}{{$s.Track}}{{else}}{{inc $i}}{{end}}</td><td>{{$s.Name}}</td><td>{{duration $s.Length}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}{{end}}
+{{template "footer" .}}{{end}}
+`
+
+const styleCSS = `* { box-sizing: border-box; }
+body { margin: 0; font: 15px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #222; background: #fafafa; }
+header { display: flex; align-items: center; justify-content: space-between; padding: 12px 24px; background: #222; }
+header a { color: #eee; text-decoration: none; margin-left: 16px; }
+header a.home { font-weight: bold; margin-left: 0; }
+main { max-width: 1100px; margin: 0 auto; padding: 16px 24px; }
+footer { max-width: 1100px; margin: 0 auto; padding: 16px 24px; color: #888; font-size: 13px; }
+a { color: #1a5fb4; }
+.search input { width: 100%; padding: 8px 12px; font-size: 16px; border: 1px solid #ccc; border-radius: 4px; }
+.results { list-style: none; padding: 0; }
+.results li { padding: 6px 0; border-bottom: 1px solid #eee; }
+.results .meta { color: #666; font-size: 13px; margin-left: 8px; }
+.records { list-style: none; padding: 0; display: grid; grid-template-columns: repeat(auto-fill, minmax(160px, 1fr)); gap: 16px; }
+.records a { display: block; color: inherit; text-decoration: none; }
+.records img, .records .nocover { display: block; width: 100%; aspect-ratio: 1; object-fit: cover; background: #ddd; border-radius: 4px; }
+.records span { display: block; }
+.records .name { font-weight: bold; margin-top: 4px; }
+.records .artist, .records .kind { color: #666; font-size: 13px; }
+.groups { columns: 3 200px; }
+.count { color: #888; font-size: 13px; }
+.crumbs { margin-bottom: 0; }
+.record { display: flex; gap: 24px; flex-wrap: wrap; }
+.record .cover { width: 300px; max-width: 100%; border-radius: 4px; }
+.record dt { float: left; clear: left; width: 80px; color: #666; }
+.record dd { margin-left: 90px; }
+.songs { border-collapse: collapse; margin-top: 24px; min-width: 50%; }
+.songs th, .songs td { text-align: left; padding: 4px 12px 4px 0; border-bottom: 1px solid #eee; }
+`
+
+// searchJS filters window.catalogIndex, loaded from search-index.js, as the
+// search box is typed in. Every word must be found in the name, artist,
+// kind, genre, tags or songs of a record.
+const searchJS = `(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("results");
+  if (!input || !results || !window.catalogIndex) {
+    return;
+  }
+
+  var entries = window.catalogIndex.map(function (e) {
+    var text = [e.n, e.a, e.k, e.g].concat(e.t || [], e.s || []).join(" ");
+    return { entry: e, text: text.toLowerCase() };
+  });
+
+  function render(words) {
+    results.innerHTML = "";
+    if (words.length === 0) {
+      results.hidden = true;
+      return;
+    }
+
+    var found = entries.filter(function (e) {
+      return words.every(function (w) { return e.text.indexOf(w) >= 0; });
+    });
+    found.slice(0, 100).forEach(function (e) {
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.href = e.entry.p;
+      a.textContent = e.entry.n;
+      var meta = document.createElement("span");
+      meta.className = "meta";
+      meta.textContent = [e.entry.a, e.entry.k].filter(Boolean).join(" · ");
+      li.appendChild(a);
+      li.appendChild(meta);
+      results.appendChild(li);
+    });
+    if (found.length === 0) {
+      var none = document.createElement("li");
+      none.textContent = "No records found.";
+      results.appendChild(none);
+    }
+    results.hidden = false;
+  }
+
+  input.addEventListener("input", function () {
+    render(input.value.toLowerCase().split(/\s+/).filter(Boolean));
+  });
+})();
+`
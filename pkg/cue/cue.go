@@ -0,0 +1,331 @@
+// Package cue reads CUE sheets, the track layout of a disc rip: the audio
+// files it is made of and where each track starts in them.
+package cue
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FramesPerSecond is the number of CD frames in a second, the unit of CUE
+// timestamps.
+const FramesPerSecond = 75
+
+var (
+	ErrNoTracks          = errors.New("cue sheet has no tracks")
+	ErrTrackWithoutFile  = errors.New("track before any FILE")
+	ErrIndexOutsideTrack = errors.New("INDEX outside of a TRACK")
+	ErrInvalidTime       = errors.New("invalid timestamp, want mm:ss:ff")
+	ErrInvalidNumber     = errors.New("invalid number")
+	ErrMissingStart      = errors.New("track has no INDEX 01")
+	ErrUnterminated      = errors.New("unterminated quoted string")
+)
+
+// Sheet is a parsed CUE sheet. Genre and Date come from the REM comments
+// most rippers write.
+type Sheet struct {
+	Title      string
+	Performer  string
+	Songwriter string
+	Catalog    string
+	Genre      string
+	Date       string
+	Files      []File
+}
+
+// File is an audio file of the sheet and the tracks it holds. Type is
+// WAVE, MP3, AIFF and so on, WAVE being used for any lossless file.
+type File struct {
+	Name   string
+	Type   string
+	Tracks []Track
+}
+
+// Track is a track of a file. Title and Performer are empty when the sheet
+// only sets them for the whole disc.
+type Track struct {
+	Number     int
+	Type       string
+	Title      string
+	Performer  string
+	Songwriter string
+	ISRC       string
+	Indexes    []Index
+}
+
+// Index is a position within the file of a track: index 0 starts the gap
+// before it and index 1 the track itself.
+type Index struct {
+	Number int
+	Time   Time
+}
+
+// Time is a position in a file in CD frames.
+type Time int64
+
+// ParseTime reads a timestamp written as mm:ss:ff, minutes being allowed
+// past 99.
+func ParseTime(value string) (Time, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return 0, ErrInvalidTime
+	}
+
+	var n [3]int64
+	for i, p := range parts {
+		v, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || v < 0 {
+			return 0, ErrInvalidTime
+		}
+		n[i] = v
+	}
+	if n[1] >= 60 || n[2] >= FramesPerSecond {
+		return 0, ErrInvalidTime
+	}
+
+	return Time((n[0]*60+n[1])*FramesPerSecond + n[2]), nil
+}
+
+// Duration is the time as a duration from the start of the file.
+func (t Time) Duration() time.Duration {
+	return time.Duration(t) * time.Second / FramesPerSecond
+}
+
+func (t Time) String() string {
+	frames := int64(t)
+	return fmt.Sprintf("%02d:%02d:%02d", frames/FramesPerSecond/60, frames/FramesPerSecond%60, frames%FramesPerSecond)
+}
+
+// Start is where the track itself starts, its index 1.
+func (t Track) Start() (Time, bool) {
+	for _, i := range t.Indexes {
+		if i.Number == 1 {
+			return i.Time, true
+		}
+	}
+	return 0, false
+}
+
+// Lengths are the lengths of the tracks of the file, each running up to
+// the start of the next one so that gaps belong to the track before them.
+// The last track runs up to the end of the file, duration, and is 0 when
+// the duration is not known.
+func (f File) Lengths(duration time.Duration) []time.Duration {
+	lengths := make([]time.Duration, len(f.Tracks))
+	for i, t := range f.Tracks {
+		start, _ := t.Start()
+
+		end := duration
+		if i+1 < len(f.Tracks) {
+			next, _ := f.Tracks[i+1].Start()
+			end = next.Duration()
+		}
+
+		if length := end - start.Duration(); length > 0 {
+			lengths[i] = length
+		}
+	}
+	return lengths
+}
+
+// Tracks lists the tracks of every file in order.
+func (s Sheet) Tracks() []Track {
+	var tracks []Track
+	for _, f := range s.Files {
+		tracks = append(tracks, f.Tracks...)
+	}
+	return tracks
+}
+
+// LineError is a line of the sheet that could not be read.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Parse reads a CUE sheet. Sheets are expected in UTF-8, lines that are
+// not are read as Latin-1, which older rippers write. Unknown commands are
+// skipped.
+func Parse(r io.Reader) (Sheet, error) {
+	var (
+		sheet Sheet
+		file  *File
+		track *Track
+		line  int
+	)
+
+	in := bufio.NewScanner(r)
+	in.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for in.Scan() {
+		line++
+		text := in.Text()
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if !utf8.ValidString(text) {
+			text = latin1(text)
+		}
+
+		fields, err := split(text)
+		if err != nil {
+			return Sheet{}, &LineError{Line: line, Err: err}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		fail := func(err error) (Sheet, error) {
+			return Sheet{}, &LineError{Line: line, Err: err}
+		}
+		arg := func(i int) string {
+			if i < len(fields) {
+				return fields[i]
+			}
+			return ""
+		}
+		// Unquoted values of more than one word are taken whole.
+		rest := func(i int) string {
+			if i < len(fields) {
+				return strings.Join(fields[i:], " ")
+			}
+			return ""
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "REM":
+			switch strings.ToUpper(arg(1)) {
+			case "GENRE":
+				sheet.Genre = rest(2)
+			case "DATE":
+				sheet.Date = arg(2)
+			}
+		case "CATALOG":
+			sheet.Catalog = arg(1)
+		case "TITLE":
+			if track != nil {
+				track.Title = rest(1)
+			} else {
+				sheet.Title = rest(1)
+			}
+		case "PERFORMER":
+			if track != nil {
+				track.Performer = rest(1)
+			} else {
+				sheet.Performer = rest(1)
+			}
+		case "SONGWRITER":
+			if track != nil {
+				track.Songwriter = rest(1)
+			} else {
+				sheet.Songwriter = rest(1)
+			}
+		case "FILE":
+			sheet.Files = append(sheet.Files, File{Name: arg(1), Type: strings.ToUpper(arg(2))})
+			file = &sheet.Files[len(sheet.Files)-1]
+			track = nil
+		case "TRACK":
+			if file == nil {
+				return fail(ErrTrackWithoutFile)
+			}
+			n, err := strconv.Atoi(arg(1))
+			if err != nil || n < 1 {
+				return fail(ErrInvalidNumber)
+			}
+			file.Tracks = append(file.Tracks, Track{Number: n, Type: strings.ToUpper(arg(2))})
+			track = &file.Tracks[len(file.Tracks)-1]
+		case "ISRC":
+			if track != nil {
+				track.ISRC = arg(1)
+			}
+		case "INDEX":
+			if track == nil {
+				return fail(ErrIndexOutsideTrack)
+			}
+			n, err := strconv.Atoi(arg(1))
+			if err != nil || n < 0 {
+				return fail(ErrInvalidNumber)
+			}
+			t, err := ParseTime(arg(2))
+			if err != nil {
+				return fail(err)
+			}
+			track.Indexes = append(track.Indexes, Index{Number: n, Time: t})
+		}
+	}
+	if err := in.Err(); err != nil {
+		return Sheet{}, err
+	}
+
+	tracks := 0
+	for _, f := range sheet.Files {
+		for _, t := range f.Tracks {
+			if _, ok := t.Start(); !ok {
+				return Sheet{}, fmt.Errorf("track %d: %w", t.Number, ErrMissingStart)
+			}
+			tracks++
+		}
+	}
+	if tracks == 0 {
+		return Sheet{}, ErrNoTracks
+	}
+
+	return sheet, nil
+}
+
+// split splits a line into fields, double quotes grouping words.
+func split(line string) ([]string, error) {
+	var (
+		fields []string
+		b      strings.Builder
+		quoted bool
+		inWord bool
+	)
+
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			inWord = true
+		case !quoted && (r == ' ' || r == '\t' || r == '\r'):
+			if inWord {
+				fields = append(fields, b.String())
+				b.Reset()
+				inWord = false
+			}
+		default:
+			b.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quoted {
+		return nil, ErrUnterminated
+	}
+	if inWord {
+		fields = append(fields, b.String())
+	}
+
+	return fields, nil
+}
+
+// latin1 decodes a Latin-1 string, every byte being a code point.
+func latin1(s string) string {
+	runes := make([]rune, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		runes = append(runes, rune(s[i]))
+	}
+	return string(runes)
+}
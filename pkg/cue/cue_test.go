@@ -0,0 +1,148 @@
+package cue_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/cue"
+)
+
+const sheet = "\ufeff" + `REM GENRE "Progressive Rock"
+REM DATE 1973
+REM COMMENT "ExactAudioCopy v1.6"
+PERFORMER "Pink Floyd"
+TITLE "The Dark Side of the Moon"
+FILE "Pink Floyd - The Dark Side of the Moon.flac" WAVE
+  TRACK 01 AUDIO
+    TITLE "Speak to Me"
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "Breathe (In the Air)"
+    PERFORMER "Pink Floyd"
+    INDEX 00 01:06:50
+    INDEX 01 01:07:32
+  TRACK 03 AUDIO
+    TITLE On the Run
+    ISRC GBN9Y1100075
+    INDEX 01 03:56:20
+FILE "bonus.mp3" MP3
+  TRACK 04 AUDIO
+    TITLE "Time"
+    INDEX 01 00:00:00
+`
+
+func TestParse(t *testing.T) {
+	s, err := cue.Parse(strings.NewReader(strings.Replace(sheet, "\n", "\r\n", -1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Title != "The Dark Side of the Moon" || s.Performer != "Pink Floyd" || s.Genre != "Progressive Rock" || s.Date != "1973" {
+		t.Errorf("Parse() = %+v", s)
+	}
+	if len(s.Files) != 2 || s.Files[0].Name != "Pink Floyd - The Dark Side of the Moon.flac" || s.Files[0].Type != "WAVE" || s.Files[1].Type != "MP3" {
+		t.Fatalf("Files = %+v", s.Files)
+	}
+
+	tracks := s.Tracks()
+	if len(tracks) != 4 {
+		t.Fatalf("Tracks() = %+v", tracks)
+	}
+	if tr := tracks[1]; tr.Number != 2 || tr.Title != "Breathe (In the Air)" || tr.Performer != "Pink Floyd" || len(tr.Indexes) != 2 {
+		t.Errorf("track 2 = %+v", tr)
+	}
+	if tr := tracks[2]; tr.Title != "On the Run" || tr.ISRC != "GBN9Y1100075" {
+		t.Errorf("track 3 = %+v", tr)
+	}
+
+	start, ok := tracks[1].Start()
+	if !ok || start.String() != "01:07:32" {
+		t.Errorf("Start() = %v, %v", start, ok)
+	}
+
+	lengths := s.Files[0].Lengths(7 * time.Minute)
+	want := []time.Duration{
+		67*time.Second + 32*time.Second/75,
+		2*time.Minute + 49*time.Second - 12*time.Second/75,
+		3*time.Minute + 4*time.Second - 20*time.Second/75,
+	}
+	for i := range want {
+		if lengths[i] != want[i] {
+			t.Errorf("Lengths()[%d] = %v, want %v", i, lengths[i], want[i])
+		}
+	}
+
+	if lengths := s.Files[0].Lengths(0); lengths[2] != 0 {
+		t.Errorf("Lengths(0) of the last track = %v, want 0", lengths[2])
+	}
+}
+
+func TestParse_Latin1(t *testing.T) {
+	s, err := cue.Parse(strings.NewReader("TITLE \"Caf\xe9 Tacuba\"\nFILE a.wav WAVE\nTRACK 1 AUDIO\nINDEX 1 00:00:00\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Title != "Café Tacuba" {
+		t.Errorf("Title = %q, want %q", s.Title, "Café Tacuba")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		err   error
+	}{
+		{"00:00:00", 0, nil},
+		{"01:02:75", 0, cue.ErrInvalidTime},
+		{"03:56:20", 3*time.Minute + 56*time.Second + 20*time.Second/75, nil},
+		{"120:00:00", 2 * time.Hour, nil},
+		{"01:60:00", 0, cue.ErrInvalidTime},
+		{"1:2", 0, cue.ErrInvalidTime},
+		{"aa:00:00", 0, cue.ErrInvalidTime},
+	}
+
+	for _, tt := range tests {
+		got, err := cue.ParseTime(tt.value)
+		if err != tt.err {
+			t.Errorf("ParseTime(%q) error = %v, want %v", tt.value, err, tt.err)
+			continue
+		}
+		if got.Duration() != tt.want {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.value, got.Duration(), tt.want)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   error
+		line  int
+	}{
+		{"Empty", "", cue.ErrNoTracks, 0},
+		{"TrackWithoutFile", "TRACK 01 AUDIO\n", cue.ErrTrackWithoutFile, 1},
+		{"IndexOutsideTrack", "FILE a.wav WAVE\nINDEX 01 00:00:00\n", cue.ErrIndexOutsideTrack, 2},
+		{"InvalidTime", "FILE a.wav WAVE\nTRACK 01 AUDIO\nINDEX 01 00:00\n", cue.ErrInvalidTime, 3},
+		{"InvalidNumber", "FILE a.wav WAVE\nTRACK one AUDIO\n", cue.ErrInvalidNumber, 2},
+		{"Unterminated", "TITLE \"Rumours\n", cue.ErrUnterminated, 1},
+		{"MissingStart", "FILE a.wav WAVE\nTRACK 01 AUDIO\nINDEX 00 00:00:00\n", cue.ErrMissingStart, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cue.Parse(strings.NewReader(tt.input))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.err)
+			}
+
+			var lineErr *cue.LineError
+			if errors.As(err, &lineErr) != (tt.line > 0) || (tt.line > 0 && lineErr.Line != tt.line) {
+				t.Errorf("Parse() error = %v, want it on line %d", err, tt.line)
+			}
+		})
+	}
+}
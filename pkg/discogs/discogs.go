@@ -0,0 +1,212 @@
+// Package discogs reads the collection export of Discogs, the CSV file
+// offered under Collection > Export.
+package discogs
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotAnExport = errors.New("not a discogs collection export, missing Artist, Title or Format column")
+)
+
+// Columns of the export read by Reader.
+const (
+	ColumnCatalogNumber   = "Catalog#"
+	ColumnArtist          = "Artist"
+	ColumnTitle           = "Title"
+	ColumnLabel           = "Label"
+	ColumnFormat          = "Format"
+	ColumnRating          = "Rating"
+	ColumnReleased        = "Released"
+	ColumnReleaseID       = "release_id"
+	ColumnFolder          = "CollectionFolder"
+	ColumnDateAdded       = "Date Added"
+	ColumnMediaCondition  = "Collection Media Condition"
+	ColumnSleeveCondition = "Collection Sleeve Condition"
+	ColumnNotes           = "Collection Notes"
+)
+
+// dateAddedLayout is the layout of the Date Added column.
+const dateAddedLayout = "2006-01-02 15:04:05"
+
+// Item is a row of the export, one copy of a release in the collection.
+type Item struct {
+	// Line is the line of the row in the file, the header being line 1.
+	Line            int
+	CatalogNumber   string
+	Artist          string
+	Title           string
+	Label           string
+	Format          string
+	Rating          int
+	Released        string
+	ReleaseID       string
+	Folder          string
+	DateAdded       time.Time
+	MediaCondition  string
+	SleeveCondition string
+	Notes           string
+}
+
+// Reader reads the items of an export one by one.
+type Reader struct {
+	r     *csv.Reader
+	index map[string]int
+	line  int
+}
+
+// NewReader reads the header of an export.
+func NewReader(r io.Reader) (*Reader, error) {
+	in := csv.NewReader(r)
+	in.FieldsPerRecord = -1
+
+	header, err := in.Read()
+	if err == io.EOF {
+		return nil, ErrNotAnExport
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		index[strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))] = i
+	}
+
+	for _, required := range []string{ColumnArtist, ColumnTitle, ColumnFormat} {
+		if _, ok := index[required]; !ok {
+			return nil, ErrNotAnExport
+		}
+	}
+
+	return &Reader{r: in, index: index, line: 1}, nil
+}
+
+// RowError is a row that could not be read. Reading can go on with the
+// next one.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Read returns the next item, or io.EOF at the end of the export. Rows that
+// cannot be read give a *RowError.
+func (r *Reader) Read() (Item, error) {
+	r.line++
+	item := Item{Line: r.line}
+
+	row, err := r.r.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return item, &RowError{Line: item.Line, Err: parseErr.Err}
+		}
+		return item, err
+	}
+
+	get := func(column string) string {
+		i, ok := r.index[column]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	item.CatalogNumber = get(ColumnCatalogNumber)
+	item.Artist = CleanArtist(get(ColumnArtist))
+	item.Title = get(ColumnTitle)
+	item.Label = get(ColumnLabel)
+	item.Format = get(ColumnFormat)
+	item.Released = get(ColumnReleased)
+	item.ReleaseID = get(ColumnReleaseID)
+	item.Folder = get(ColumnFolder)
+	item.MediaCondition = get(ColumnMediaCondition)
+	item.SleeveCondition = get(ColumnSleeveCondition)
+	item.Notes = get(ColumnNotes)
+
+	if value := get(ColumnRating); value != "" {
+		rating, err := strconv.Atoi(value)
+		if err != nil {
+			return item, &RowError{Line: item.Line, Err: errors.New("invalid rating " + strconv.Quote(value))}
+		}
+		item.Rating = rating
+	}
+
+	if value := get(ColumnDateAdded); value != "" {
+		date, err := time.Parse(dateAddedLayout, value)
+		if err != nil {
+			return item, &RowError{Line: item.Line, Err: errors.New("invalid date added " + strconv.Quote(value))}
+		}
+		item.DateAdded = date
+	}
+
+	return item, nil
+}
+
+// artistSuffix matches the number Discogs appends to tell apart artists
+// sharing a name, e.g. "Nirvana (2)", and the star marking a name variation.
+var artistSuffix = regexp.MustCompile(`(\s+\(\d+\))|\*$`)
+
+// CleanArtist removes the Discogs disambiguation marks from artist names.
+func CleanArtist(artist string) string {
+	return strings.TrimSpace(artistSuffix.ReplaceAllString(artist, ""))
+}
+
+// Formats splits a format such as "2xLP, Album, RE + CD" into its
+// descriptions, dropping quantities: [LP Album RE CD].
+func Formats(format string) []string {
+	var formats []string
+	for _, part := range strings.Split(format, "+") {
+		for _, f := range strings.Split(part, ",") {
+			f = strings.TrimSpace(f)
+			if i := strings.Index(f, "x"); i > 0 && isDigits(f[:i]) {
+				f = f[i+1:]
+			}
+			if f != "" {
+				formats = append(formats, f)
+			}
+		}
+	}
+	return formats
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// ConditionCode extracts the grade abbreviation of a condition such as
+// "Very Good Plus (VG+)" or "Near Mint (NM or M-)". Conditions without one,
+// like "Generic" or "No Cover", give an empty code.
+func ConditionCode(condition string) string {
+	open := strings.LastIndex(condition, "(")
+	end := strings.LastIndex(condition, ")")
+	if open < 0 || end < open {
+		return ""
+	}
+
+	fields := strings.Fields(condition[open+1 : end])
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,142 @@
+package discogs_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/discogs"
+)
+
+const export = "Catalog#,Artist,Title,Label,Format,Rating,Released,release_id,CollectionFolder,Date Added,Collection Media Condition,Collection Sleeve Condition,Collection Notes\n" +
+	"SHVL 804,Pink Floyd,The Dark Side Of The Moon,Harvest,\"LP, Album, Gat\",5,1973,1873013,Uncategorized,2021-03-04 10:20:30,Very Good Plus (VG+),Near Mint (NM or M-),Small seam split\n" +
+	"none,Nirvana (2),Nevermind,DGC,\"File, MP3, Album\",x,1991,123,Uncategorized,,,,\n"
+
+func TestReader_Read(t *testing.T) {
+	r, err := discogs.NewReader(strings.NewReader("\ufeff" + export))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	item, err := r.Read()
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := discogs.Item{
+		Line:            2,
+		CatalogNumber:   "SHVL 804",
+		Artist:          "Pink Floyd",
+		Title:           "The Dark Side Of The Moon",
+		Label:           "Harvest",
+		Format:          "LP, Album, Gat",
+		Rating:          5,
+		Released:        "1973",
+		ReleaseID:       "1873013",
+		Folder:          "Uncategorized",
+		DateAdded:       time.Date(2021, 3, 4, 10, 20, 30, 0, time.UTC),
+		MediaCondition:  "Very Good Plus (VG+)",
+		SleeveCondition: "Near Mint (NM or M-)",
+		Notes:           "Small seam split",
+	}
+	if !reflect.DeepEqual(item, want) {
+		t.Errorf("Read() = %+v, want %+v", item, want)
+	}
+
+	item, err = r.Read()
+	var rowErr *discogs.RowError
+	if !errors.As(err, &rowErr) || rowErr.Line != 3 {
+		t.Errorf("Read() error = %v, want a row error on line 3", err)
+	}
+	if item.Line != 3 {
+		t.Errorf("Read() line = %d, want 3", item.Line)
+	}
+
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("Read() error = %v, want io.EOF", err)
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{name: "Export", data: export},
+		{name: "Empty file", data: "", wantErr: discogs.ErrNotAnExport},
+		{name: "Other CSV", data: "name,kind\nBlue,vinyl\n", wantErr: discogs.ErrNotAnExport},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := discogs.NewReader(strings.NewReader(tt.data))
+			if err != tt.wantErr {
+				t.Errorf("NewReader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCleanArtist(t *testing.T) {
+	tests := []struct {
+		artist string
+		want   string
+	}{
+		{artist: "Pink Floyd", want: "Pink Floyd"},
+		{artist: "Nirvana (2)", want: "Nirvana"},
+		{artist: "Prince*", want: "Prince"},
+		{artist: "Sun Ra (3)*", want: "Sun Ra"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.artist, func(t *testing.T) {
+			if got := discogs.CleanArtist(tt.artist); got != tt.want {
+				t.Errorf("CleanArtist() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{format: "LP, Album, RE", want: []string{"LP", "Album", "RE"}},
+		{format: "2xLP, Album + CD", want: []string{"LP", "Album", "CD"}},
+		{format: `Vinyl, 7", 45 RPM`, want: []string{"Vinyl", `7"`, "45 RPM"}},
+		{format: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := discogs.Formats(tt.format); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Formats() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConditionCode(t *testing.T) {
+	tests := []struct {
+		condition string
+		want      string
+	}{
+		{condition: "Mint (M)", want: "M"},
+		{condition: "Near Mint (NM or M-)", want: "NM"},
+		{condition: "Very Good Plus (VG+)", want: "VG+"},
+		{condition: "Generic", want: ""},
+		{condition: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			if got := discogs.ConditionCode(tt.condition); got != tt.want {
+				t.Errorf("ConditionCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+// Package itunes reads the library file iTunes and Apple Music export as
+// "Library.xml": an XML property list whose Tracks dictionary holds every
+// song, video and podcast episode with its metadata and play statistics.
+package itunes
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidPlist = errors.New("invalid property list")
+	ErrNotALibrary  = errors.New("not an iTunes library, the property list has no Tracks")
+)
+
+// Library is the part of a library file the collection uses.
+type Library struct {
+	ApplicationVersion string
+	MusicFolder        string
+	Tracks             []Track
+}
+
+// Track is an item of the library. Numbers are 0 and times zero when the
+// library does not have them.
+type Track struct {
+	ID           int
+	PersistentID string
+	Name         string
+	Artist       string
+	AlbumArtist  string
+	Album        string
+	Genre        string
+	// Kind describes the file, e.g. "MPEG audio file" or "AAC audio file",
+	// in the language of the application that wrote the library.
+	Kind string
+	// Type is "File" for local files, "Remote" for the Apple Music
+	// catalog and "URL" for streams.
+	Type        string
+	Location    string
+	TotalTime   time.Duration
+	DiscNumber  int
+	TrackNumber int
+	Year        int
+	PlayCount   int
+	DateAdded   time.Time
+	PlayDate    time.Time
+	Compilation bool
+	Podcast     bool
+	Video       bool
+}
+
+// Ext is the lowercase extension of the file of the track, e.g. ".m4a",
+// or "" when it has no file.
+func (t Track) Ext() string {
+	u, err := url.Parse(t.Location)
+	if err != nil || t.Location == "" {
+		return ""
+	}
+
+	p := u.Path
+	if i := strings.LastIndex(p, "/"); i >= 0 {
+		p = p[i+1:]
+	}
+	if i := strings.LastIndex(p, "."); i >= 0 {
+		return strings.ToLower(p[i:])
+	}
+	return ""
+}
+
+// Seconds is the length of the track rounded to whole seconds.
+func (t Track) Seconds() int64 {
+	return int64(t.TotalTime.Round(time.Second) / time.Second)
+}
+
+// Read reads a library file. Tracks are returned by ID, which is the order
+// they were added in.
+func Read(r io.Reader) (Library, error) {
+	d := xml.NewDecoder(r)
+
+	root, err := decodePlist(d)
+	if err != nil {
+		return Library{}, err
+	}
+
+	dict, ok := root.(map[string]interface{})
+	if !ok {
+		return Library{}, ErrNotALibrary
+	}
+	items, ok := dict["Tracks"].(map[string]interface{})
+	if !ok {
+		return Library{}, ErrNotALibrary
+	}
+
+	lib := Library{
+		ApplicationVersion: stringValue(dict, "Application Version"),
+		MusicFolder:        stringValue(dict, "Music Folder"),
+		Tracks:             make([]Track, 0, len(items)),
+	}
+	for key, item := range items {
+		t, ok := item.(map[string]interface{})
+		if !ok {
+			return Library{}, fmt.Errorf("%w: track %s is not a dict", ErrInvalidPlist, key)
+		}
+		lib.Tracks = append(lib.Tracks, newTrack(t))
+	}
+	sort.Slice(lib.Tracks, func(i, j int) bool {
+		return lib.Tracks[i].ID < lib.Tracks[j].ID
+	})
+
+	return lib, nil
+}
+
+func newTrack(t map[string]interface{}) Track {
+	return Track{
+		ID:           int(intValue(t, "Track ID")),
+		PersistentID: stringValue(t, "Persistent ID"),
+		Name:         stringValue(t, "Name"),
+		Artist:       stringValue(t, "Artist"),
+		AlbumArtist:  stringValue(t, "Album Artist"),
+		Album:        stringValue(t, "Album"),
+		Genre:        stringValue(t, "Genre"),
+		Kind:         stringValue(t, "Kind"),
+		Type:         stringValue(t, "Track Type"),
+		Location:     stringValue(t, "Location"),
+		TotalTime:    time.Duration(intValue(t, "Total Time")) * time.Millisecond,
+		DiscNumber:   int(intValue(t, "Disc Number")),
+		TrackNumber:  int(intValue(t, "Track Number")),
+		Year:         int(intValue(t, "Year")),
+		PlayCount:    int(intValue(t, "Play Count")),
+		DateAdded:    timeValue(t, "Date Added"),
+		PlayDate:     timeValue(t, "Play Date UTC"),
+		Compilation:  boolValue(t, "Compilation"),
+		Podcast:      boolValue(t, "Podcast"),
+		Video:        boolValue(t, "Has Video") || boolValue(t, "Movie") || boolValue(t, "TV Show") || boolValue(t, "Music Video"),
+	}
+}
+
+func stringValue(dict map[string]interface{}, key string) string {
+	s, _ := dict[key].(string)
+	return s
+}
+
+func intValue(dict map[string]interface{}, key string) int64 {
+	switch v := dict[key].(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+func boolValue(dict map[string]interface{}, key string) bool {
+	b, _ := dict[key].(bool)
+	return b
+}
+
+func timeValue(dict map[string]interface{}, key string) time.Time {
+	t, _ := dict[key].(time.Time)
+	return t
+}
+
+// decodePlist decodes the value of a plist document: dicts become maps,
+// arrays slices, and integer, real, true/false, date and data elements
+// int64, float64, bool, time.Time and []byte.
+func decodePlist(d *xml.Decoder) (interface{}, error) {
+	inPlist := false
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%w: no plist element", ErrInvalidPlist)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPlist, err)
+		}
+
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !inPlist {
+			if se.Name.Local != "plist" {
+				return nil, fmt.Errorf("%w: root element is %s", ErrInvalidPlist, se.Name.Local)
+			}
+			inPlist = true
+			continue
+		}
+		return decodeValue(d, se)
+	}
+}
+
+func decodeValue(d *xml.Decoder, se xml.StartElement) (interface{}, error) {
+	switch se.Name.Local {
+	case "dict":
+		return decodeDict(d)
+	case "array":
+		return decodeArray(d)
+	case "true", "false":
+		if err := d.Skip(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPlist, err)
+		}
+		return se.Name.Local == "true", nil
+	}
+
+	text, err := elementText(d)
+	if err != nil {
+		return nil, err
+	}
+
+	switch se.Name.Local {
+	case "string":
+		return text, nil
+	case "integer":
+		n, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: integer %q", ErrInvalidPlist, text)
+		}
+		return n, nil
+	case "real":
+		f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: real %q", ErrInvalidPlist, text)
+		}
+		return f, nil
+	case "date":
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("%w: date %q", ErrInvalidPlist, text)
+		}
+		return t, nil
+	case "data":
+		data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+		if err != nil {
+			return nil, fmt.Errorf("%w: data: %v", ErrInvalidPlist, err)
+		}
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown element %s", ErrInvalidPlist, se.Name.Local)
+}
+
+func decodeDict(d *xml.Decoder) (map[string]interface{}, error) {
+	dict := make(map[string]interface{})
+	key, hasKey := "", false
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPlist, unexpectedEOF(err))
+		}
+
+		switch t := tok.(type) {
+		case xml.EndElement:
+			if hasKey {
+				return nil, fmt.Errorf("%w: key %q has no value", ErrInvalidPlist, key)
+			}
+			return dict, nil
+		case xml.StartElement:
+			if !hasKey {
+				if t.Name.Local != "key" {
+					return nil, fmt.Errorf("%w: %s where a key was expected", ErrInvalidPlist, t.Name.Local)
+				}
+				if key, err = elementText(d); err != nil {
+					return nil, err
+				}
+				hasKey = true
+				continue
+			}
+
+			value, err := decodeValue(d, t)
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = value
+			hasKey = false
+		}
+	}
+}
+
+func decodeArray(d *xml.Decoder) ([]interface{}, error) {
+	var array []interface{}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPlist, unexpectedEOF(err))
+		}
+
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return array, nil
+		case xml.StartElement:
+			value, err := decodeValue(d, t)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+	}
+}
+
+// elementText reads the text of the element just started, up to its end.
+func elementText(d *xml.Decoder) (string, error) {
+	var b strings.Builder
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidPlist, unexpectedEOF(err))
+		}
+
+		switch t := tok.(type) {
+		case xml.CharData:
+			b.Write(t)
+		case xml.EndElement:
+			return b.String(), nil
+		case xml.StartElement:
+			return "", fmt.Errorf("%w: unexpected %s element", ErrInvalidPlist, t.Name.Local)
+		}
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
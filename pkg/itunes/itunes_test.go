@@ -0,0 +1,147 @@
+package itunes_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/itunes"
+)
+
+const library = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple Computer//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Major Version</key><integer>1</integer>
+	<key>Application Version</key><string>12.9.5.5</string>
+	<key>Show Content Ratings</key><true/>
+	<key>Music Folder</key><string>file:///Users/ana/Music/iTunes/iTunes%20Media/</string>
+	<key>Tracks</key>
+	<dict>
+		<key>1042</key>
+		<dict>
+			<key>Track ID</key><integer>1042</integer>
+			<key>Name</key><string>Hey You</string>
+			<key>Artist</key><string>Pink Floyd</string>
+			<key>Album</key><string>The Wall</string>
+			<key>Kind</key><string>AAC audio file</string>
+			<key>Total Time</key><integer>280493</integer>
+			<key>Disc Number</key><integer>2</integer>
+			<key>Track Number</key><integer>1</integer>
+			<key>Date Added</key><date>2009-05-01T10:00:00Z</date>
+			<key>Play Count</key><integer>12</integer>
+			<key>Play Date UTC</key><date>2020-01-02T03:04:05Z</date>
+			<key>Persistent ID</key><string>5E6B3F1A2C4D8E90</string>
+			<key>Track Type</key><string>File</string>
+			<key>Location</key><string>file:///Users/ana/Music/iTunes/iTunes%20Media/Music/Pink%20Floyd/The%20Wall/2-01%20Hey%20You.M4A</string>
+		</dict>
+		<key>1040</key>
+		<dict>
+			<key>Track ID</key><integer>1040</integer>
+			<key>Name</key><string>Tom &amp; Jerry</string>
+			<key>Rating</key><real>80.5</real>
+			<key>Compilation</key><true/>
+			<key>Has Video</key><false/>
+			<key>Artwork</key><data>
+				aGVs
+				bG8=
+			</data>
+		</dict>
+		<key>1044</key>
+		<dict>
+			<key>Track ID</key><integer>1044</integer>
+			<key>Name</key><string>Episode 1</string>
+			<key>Podcast</key><true/>
+			<key>Track Type</key><string>URL</string>
+		</dict>
+	</dict>
+	<key>Playlists</key>
+	<array>
+		<dict>
+			<key>Name</key><string>Library</string>
+			<key>Playlist Items</key>
+			<array>
+				<dict><key>Track ID</key><integer>1042</integer></dict>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>
+`
+
+func TestRead(t *testing.T) {
+	lib, err := itunes.Read(strings.NewReader(library))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lib.ApplicationVersion != "12.9.5.5" || lib.MusicFolder != "file:///Users/ana/Music/iTunes/iTunes%20Media/" {
+		t.Errorf("Read() = %+v", lib)
+	}
+	if len(lib.Tracks) != 3 {
+		t.Fatalf("Read() got %d tracks, want 3", len(lib.Tracks))
+	}
+
+	ids := []int{lib.Tracks[0].ID, lib.Tracks[1].ID, lib.Tracks[2].ID}
+	if ids[0] != 1040 || ids[1] != 1042 || ids[2] != 1044 {
+		t.Errorf("tracks are not sorted by ID: %v", ids)
+	}
+
+	want := itunes.Track{
+		ID:           1042,
+		PersistentID: "5E6B3F1A2C4D8E90",
+		Name:         "Hey You",
+		Artist:       "Pink Floyd",
+		Album:        "The Wall",
+		Kind:         "AAC audio file",
+		Type:         "File",
+		Location:     "file:///Users/ana/Music/iTunes/iTunes%20Media/Music/Pink%20Floyd/The%20Wall/2-01%20Hey%20You.M4A",
+		TotalTime:    280493 * time.Millisecond,
+		DiscNumber:   2,
+		TrackNumber:  1,
+		PlayCount:    12,
+		DateAdded:    time.Date(2009, 5, 1, 10, 0, 0, 0, time.UTC),
+		PlayDate:     time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if got := lib.Tracks[1]; got != want {
+		t.Errorf("Read() track = %+v, want %+v", got, want)
+	}
+	if got := lib.Tracks[1].Ext(); got != ".m4a" {
+		t.Errorf("Ext() = %q, want .m4a", got)
+	}
+	if got := lib.Tracks[1].Seconds(); got != 280 {
+		t.Errorf("Seconds() = %d, want 280", got)
+	}
+
+	if got := lib.Tracks[0]; got.Name != "Tom & Jerry" || !got.Compilation || got.Video || got.Ext() != "" {
+		t.Errorf("Read() track = %+v", got)
+	}
+	if got := lib.Tracks[2]; !got.Podcast {
+		t.Errorf("Read() track = %+v, want a podcast", got)
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	tests := []struct {
+		description string
+		data        string
+		err         error
+	}{
+		{"not xml", "Artist,Title\n", itunes.ErrInvalidPlist},
+		{"other root", "<html><body/></html>", itunes.ErrInvalidPlist},
+		{"truncated", `<plist><dict><key>Tracks</key><dict>`, itunes.ErrInvalidPlist},
+		{"key without value", `<plist><dict><key>Tracks</key></dict></plist>`, itunes.ErrInvalidPlist},
+		{"bad integer", `<plist><dict><key>Tracks</key><dict><key>1</key><dict><key>Track ID</key><integer>one</integer></dict></dict></dict></plist>`, itunes.ErrInvalidPlist},
+		{"bad date", `<plist><dict><key>Date</key><date>yesterday</date></dict></plist>`, itunes.ErrInvalidPlist},
+		{"track not a dict", `<plist><dict><key>Tracks</key><dict><key>1</key><string>x</string></dict></dict></plist>`, itunes.ErrInvalidPlist},
+		{"no tracks", `<plist><dict><key>Playlists</key><array/></dict></plist>`, itunes.ErrNotALibrary},
+		{"not a dict", `<plist><array/></plist>`, itunes.ErrNotALibrary},
+	}
+
+	for _, tt := range tests {
+		if _, err := itunes.Read(strings.NewReader(tt.data)); !errors.Is(err, tt.err) {
+			t.Errorf("%s: Read() error = %v, want %v", tt.description, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Package musicbrainz reads the releases of a MusicBrainz JSON data dump:
+// the mbdump/release file of release.tar.xz, one release per line, as is
+// or gzip compressed.
+package musicbrainz
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNotADump = errors.New("not a musicbrainz release dump")
+)
+
+// Release is a release of the dump, with the fields used to match it.
+type Release struct {
+	ID           string         `json:"id"`
+	Title        string         `json:"title"`
+	Date         string         `json:"date"`
+	ArtistCredit []ArtistCredit `json:"artist-credit"`
+	ReleaseGroup ReleaseGroup   `json:"release-group"`
+	Media        []Medium       `json:"media"`
+}
+
+// ArtistCredit is an artist credited on a release, followed by the phrase
+// joining it to the next one, e.g. " & ".
+type ArtistCredit struct {
+	Name       string `json:"name"`
+	JoinPhrase string `json:"joinphrase"`
+	Artist     Artist `json:"artist"`
+}
+
+type Artist struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ReleaseGroup struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// Medium is a disc or side set of a release.
+type Medium struct {
+	Position int     `json:"position"`
+	Format   string  `json:"format"`
+	Tracks   []Track `json:"tracks"`
+}
+
+// Track is a track of a medium. Lengths are in milliseconds, 0 when
+// unknown.
+type Track struct {
+	ID        string    `json:"id"`
+	Number    string    `json:"number"`
+	Position  int       `json:"position"`
+	Title     string    `json:"title"`
+	Length    int64     `json:"length"`
+	Recording Recording `json:"recording"`
+}
+
+type Recording struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Length int64  `json:"length"`
+}
+
+// Artist is the artist credit as printed on the release, e.g.
+// "Simon & Garfunkel".
+func (r Release) Artist() string {
+	var b strings.Builder
+	for _, c := range r.ArtistCredit {
+		name := c.Name
+		if name == "" {
+			name = c.Artist.Name
+		}
+		b.WriteString(name)
+		b.WriteString(c.JoinPhrase)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// ArtistIDs lists the ids of the credited artists.
+func (r Release) ArtistIDs() []string {
+	var ids []string
+	for _, c := range r.ArtistCredit {
+		if c.Artist.ID != "" {
+			ids = append(ids, c.Artist.ID)
+		}
+	}
+	return ids
+}
+
+// Tracks lists the tracks of every medium in order.
+func (r Release) Tracks() []Track {
+	var tracks []Track
+	for _, m := range r.Media {
+		tracks = append(tracks, m.Tracks...)
+	}
+	return tracks
+}
+
+// Seconds is the length of the track rounded to the second, the unit of
+// song lengths, falling back to the length of its recording.
+func (t Track) Seconds() int64 {
+	length := t.Length
+	if length <= 0 {
+		length = t.Recording.Length
+	}
+	if length <= 0 {
+		return 0
+	}
+	return (length + 500) / 1000
+}
+
+// LineError is a line of the dump that is not a release.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return "line " + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Reader reads the releases of a dump one by one.
+type Reader struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NewReader reads a dump, decompressing it when it is gzip compressed.
+func NewReader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReaderSize(r, 64*1024)
+
+	magic, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		br = bufio.NewReaderSize(gz, 64*1024)
+	}
+
+	return &Reader{r: br}, nil
+}
+
+// Read returns the next release, or io.EOF at the end of the dump. Blank
+// lines are skipped and lines that are not releases give a *LineError.
+func (r *Reader) Read() (Release, error) {
+	for {
+		line, err := r.r.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return Release{}, err
+		}
+		r.line++
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var release Release
+		if err := json.Unmarshal(line, &release); err != nil {
+			return Release{}, &LineError{Line: r.line, Err: err}
+		}
+		if release.ID == "" || release.Title == "" {
+			return Release{}, &LineError{Line: r.line, Err: ErrNotADump}
+		}
+
+		return release, nil
+	}
+}
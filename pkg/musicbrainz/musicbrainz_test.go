@@ -0,0 +1,96 @@
+package musicbrainz_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/rodrwan/collection/pkg/musicbrainz"
+)
+
+const dump = `{"id":"r1","title":"Bridge Over Troubled Water","date":"1970-01-26","artist-credit":[{"name":"Simon","joinphrase":" & ","artist":{"id":"a1","name":"Paul Simon"}},{"name":"","joinphrase":"","artist":{"id":"a2","name":"Garfunkel"}}],"release-group":{"id":"g1"},"media":[{"position":1,"tracks":[{"id":"t1","number":"1","position":1,"title":"Bridge Over Troubled Water","length":291400,"recording":{"id":"rec1"}},{"id":"t2","number":"2","position":2,"title":"El Condor Pasa","length":null,"recording":{"id":"rec2","length":186600}}]},{"position":2,"tracks":[{"id":"t3","title":"Cecilia","recording":{"id":"rec3"}}]}]}
+
+{"id":"r2","title":"Rumours"}
+`
+
+func TestReader(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(dump))
+	w.Close()
+
+	inputs := map[string]io.Reader{
+		"Plain": strings.NewReader(dump),
+		"Gzip":  &gz,
+	}
+
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			r, err := musicbrainz.NewReader(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			release, err := r.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if release.ID != "r1" || release.ReleaseGroup.ID != "g1" || release.Date != "1970-01-26" {
+				t.Errorf("Read() = %+v", release)
+			}
+			if got := release.Artist(); got != "Simon & Garfunkel" {
+				t.Errorf("Artist() = %q, want %q", got, "Simon & Garfunkel")
+			}
+			if ids := release.ArtistIDs(); len(ids) != 2 || ids[1] != "a2" {
+				t.Errorf("ArtistIDs() = %v", ids)
+			}
+
+			tracks := release.Tracks()
+			if len(tracks) != 3 || tracks[2].Title != "Cecilia" {
+				t.Fatalf("Tracks() = %+v", tracks)
+			}
+			for i, want := range []int64{291, 187, 0} {
+				if got := tracks[i].Seconds(); got != want {
+					t.Errorf("Seconds() of track %d = %d, want %d", i+1, got, want)
+				}
+			}
+
+			if release, err = r.Read(); err != nil || release.Title != "Rumours" {
+				t.Errorf("Read() = %+v, %v", release, err)
+			}
+			if _, err = r.Read(); err != io.EOF {
+				t.Errorf("Read() error = %v, want EOF", err)
+			}
+		})
+	}
+}
+
+func TestReader_LineError(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{name: "Not JSON", input: "{\"id\":\"r1\",\"title\":\"x\"}\nnot json\n"},
+		{name: "Not a release", input: "{\"id\":\"r1\",\"title\":\"x\"}\n{\"name\":\"Pink Floyd\"}\n", wantErr: musicbrainz.ErrNotADump},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := musicbrainz.NewReader(strings.NewReader(tt.input))
+			r.Read()
+
+			_, err := r.Read()
+			var lineErr *musicbrainz.LineError
+			if !errors.As(err, &lineErr) || lineErr.Line != 2 {
+				t.Fatalf("Read() error = %v, want a *LineError on line 2", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Read() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
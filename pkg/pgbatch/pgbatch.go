@@ -0,0 +1,106 @@
+// Package pgbatch inserts many rows with multi-row statements that stay
+// under the number of bind parameters postgres accepts in a statement.
+package pgbatch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// MaxParameters is the number of bind parameters postgres accepts in a
+// single statement.
+const MaxParameters = 65535
+
+var ErrNotASlice = errors.New("rows must be a slice")
+
+// Execer runs named statements, as *sqlx.DB and *sqlx.Tx do.
+type Execer interface {
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+}
+
+// Beginner starts transactions, as *sqlx.DB does.
+type Beginner interface {
+	BeginTxx(ctx context.Context, opts *sql.TxOptions) (*sqlx.Tx, error)
+}
+
+// Insert inserts rows, a slice of structs, with a named INSERT query, as
+// many rows per statement as fit under MaxParameters. When db can begin a
+// transaction the statements run in one, so the rows are stored all
+// together or not at all. Otherwise db is expected to be a transaction
+// already.
+func Insert(ctx context.Context, db Execer, query string, rows interface{}) error {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice {
+		return ErrNotASlice
+	}
+	if v.Len() == 0 {
+		return nil
+	}
+
+	size := RowsPerStatement(query)
+	if b, ok := db.(Beginner); ok && v.Len() > size {
+		tx, err := b.BeginTxx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err := insertChunks(ctx, tx, query, v, size); err != nil {
+			tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	}
+
+	return insertChunks(ctx, db, query, v, size)
+}
+
+func insertChunks(ctx context.Context, db Execer, query string, rows reflect.Value, size int) error {
+	for start := 0; start < rows.Len(); start += size {
+		end := start + size
+		if end > rows.Len() {
+			end = rows.Len()
+		}
+		if _, err := db.NamedExecContext(ctx, query, rows.Slice(start, end).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RowsPerStatement is how many rows of the named query fit in a statement.
+func RowsPerStatement(query string) int {
+	params := Parameters(query)
+	if params == 0 {
+		return MaxParameters
+	}
+
+	return MaxParameters / params
+}
+
+// Parameters counts the named parameters of a query, written as :name.
+// Casts, written as ::type, are not parameters.
+func Parameters(query string) int {
+	n := 0
+	for i := 0; i < len(query); i++ {
+		if query[i] != ':' {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == ':' {
+			i++
+			continue
+		}
+		if i+1 < len(query) && isNameStart(query[i+1]) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func isNameStart(b byte) bool {
+	return b == '_' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
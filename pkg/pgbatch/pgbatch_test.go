@@ -0,0 +1,72 @@
+package pgbatch_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/rodrwan/collection/pkg/pgbatch"
+)
+
+type row struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+type execer struct {
+	chunks []int
+	fail   bool
+}
+
+func (e *execer) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	if e.fail {
+		return nil, errors.New("exec failed")
+	}
+	e.chunks = append(e.chunks, len(arg.([]row)))
+	return driver.RowsAffected(len(arg.([]row))), nil
+}
+
+func TestParameters(t *testing.T) {
+	for query, want := range map[string]int{
+		"INSERT INTO t (id, name) VALUES (:id, :name)":        2,
+		"INSERT INTO t (id, at) VALUES (:id, :at::timestamp)": 2,
+		"SELECT 1":   0,
+		"SELECT ':'": 0,
+	} {
+		if got := pgbatch.Parameters(query); got != want {
+			t.Errorf("Parameters(%q) = %d, want %d", query, got, want)
+		}
+	}
+}
+
+func TestInsert(t *testing.T) {
+	const query = "INSERT INTO t (id, name) VALUES (:id, :name)"
+	per := pgbatch.RowsPerStatement(query)
+	if per != pgbatch.MaxParameters/2 {
+		t.Fatalf("RowsPerStatement = %d, want %d", per, pgbatch.MaxParameters/2)
+	}
+
+	rows := make([]row, per*2+1)
+	db := &execer{}
+	if err := pgbatch.Insert(context.Background(), db, query, rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(db.chunks) != 3 || db.chunks[0] != per || db.chunks[2] != 1 {
+		t.Errorf("chunks = %v", db.chunks)
+	}
+
+	db = &execer{}
+	if err := pgbatch.Insert(context.Background(), db, query, []row{}); err != nil || len(db.chunks) != 0 {
+		t.Errorf("empty insert: err %v, chunks %v", err, db.chunks)
+	}
+
+	if err := pgbatch.Insert(context.Background(), &execer{fail: true}, query, rows); err == nil {
+		t.Error("expected the exec error")
+	}
+
+	if err := pgbatch.Insert(context.Background(), db, query, row{}); err != pgbatch.ErrNotASlice {
+		t.Errorf("got %v, want ErrNotASlice", err)
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"io/ioutil"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/artwork"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/pkg/blob"
+	"github.com/rodrwan/collection/services"
+)
+
+// maxCoverSize is the largest cover art upload accepted, in bytes. It stays
+// well below config.BodyLimit so a cover of this size, with its multipart
+// envelope, reaches the handler instead of being cut by the body limit.
+const maxCoverSize = 4 * 1024 * 1024
+
+// UploadRecordCoverById takes the image in the "cover" field of a
+// multipart form. Its type is sniffed from the data, whatever the form
+// part claims.
+func (srv Server) UploadRecordCoverById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	file, err := c.FormFile("cover")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if file.Size > maxCoverSize {
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge, "cover art is too large")
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	rec, err := srv.collectionService.UploadRecordCover(id, data)
+	if err != nil {
+		switch err {
+		case record.ErrRecordNotFound:
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		case artwork.ErrUnsupportedType:
+			return fiber.NewError(fiber.StatusUnsupportedMediaType, err.Error())
+		case artwork.ErrImageTooLarge:
+			return fiber.NewError(fiber.StatusRequestEntityTooLarge, err.Error())
+		case artwork.ErrInvalidImage:
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		case services.ErrNoBlobStore:
+			return fiber.NewError(fiber.StatusNotImplemented, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":     true,
+		"record": rec,
+	})
+}
+
+// GetBlob serves a stored file, the rest of the path being its key.
+func (srv Server) GetBlob(c *fiber.Ctx) error {
+	obj, err := srv.collectionService.OpenBlob(c.Params("*"))
+	if err != nil {
+		switch err {
+		case blob.ErrNotFound, blob.ErrInvalidKey:
+			return fiber.NewError(fiber.StatusNotFound, "Not found")
+		case services.ErrNoBlobStore:
+			return fiber.NewError(fiber.StatusNotImplemented, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, obj.ContentType)
+	// keys change with every upload so the content never does
+	c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+
+	return c.SendStream(obj.Body, int(obj.Size))
+}
@@ -0,0 +1,198 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"log"
+	"mime/multipart"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func coverForm(contentType string, data []byte) (*bytes.Buffer, string) {
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="cover"; filename="cover"`)
+	header.Set("Content-Type", contentType)
+	part, _ := w.CreatePart(header)
+	part.Write(data)
+	w.Close()
+
+	return body, w.FormDataContentType()
+}
+
+func TestServer_Artwork(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blobs")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithLocalBlobStore(dir, "/blobs"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/UploadRecordCoverById/:id", srv.UploadRecordCoverById)
+	app.Get("/blobs/*", srv.GetBlob)
+
+	rec, _ := collectionService.AddRecord(uuid.New(), "r1", "vinyl")
+
+	var cover bytes.Buffer
+	png.Encode(&cover, image.NewRGBA(image.Rect(0, 0, 400, 400)))
+
+	tests := []struct {
+		description  string
+		route        string
+		contentType  string
+		data         []byte
+		expectedCode int
+		expectedOk   bool
+	}{
+		{
+			description:  "upload cover with a generic part type",
+			route:        fmt.Sprintf("/UploadRecordCoverById/%s", rec.ID),
+			contentType:  "application/octet-stream",
+			data:         cover.Bytes(),
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "upload cover",
+			route:        fmt.Sprintf("/UploadRecordCoverById/%s", rec.ID),
+			contentType:  "image/png",
+			data:         cover.Bytes(),
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "upload unsupported type",
+			route:        fmt.Sprintf("/UploadRecordCoverById/%s", rec.ID),
+			contentType:  "image/gif",
+			data:         []byte("GIF89a"),
+			expectedCode: 415,
+			expectedOk:   false,
+		},
+		{
+			description:  "upload image claiming to be png",
+			route:        fmt.Sprintf("/UploadRecordCoverById/%s", rec.ID),
+			contentType:  "image/png",
+			data:         []byte("GIF89a"),
+			expectedCode: 415,
+			expectedOk:   false,
+		},
+		{
+			description:  "upload broken image",
+			route:        fmt.Sprintf("/UploadRecordCoverById/%s", rec.ID),
+			contentType:  "image/png",
+			data:         cover.Bytes()[:40],
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:  "upload to unknown record",
+			route:        fmt.Sprintf("/UploadRecordCoverById/%s", uuid.New()),
+			contentType:  "image/png",
+			data:         cover.Bytes(),
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+	}
+
+	type response struct {
+		Ok     bool                `json:"ok,omitempty"`
+		Record record.PublicRecord `json:"record,omitempty"`
+	}
+
+	var uploaded record.PublicRecord
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			body, contentType := coverForm(test.contentType, test.data)
+			req := httptest.NewRequest(fiber.MethodPost, test.route, body)
+			req.Header.Set("Content-Type", contentType)
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			data, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(data, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+
+			if test.description == "upload cover" {
+				uploaded = r.Record
+				assert.Len(t, r.Record.Images, 3)
+			}
+		})
+	}
+
+	t.Run("get thumbnail", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodGet, uploaded.Images["small"], nil)
+		resp, _ := app.Test(req, 1000)
+		defer resp.Body.Close()
+
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+
+		cfg, err := png.DecodeConfig(resp.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, 150, cfg.Width)
+	})
+
+	t.Run("largest cover fits the body limit", func(t *testing.T) {
+		limited := fiber.New(config.NewFiberConfig)
+		limited.Post("/UploadRecordCoverById/:id", server.LimitBody(config.BodyLimit), srv.UploadRecordCoverById)
+
+		for size, want := range map[int]int{4 << 20: 415, 4<<20 + 1: 413} {
+			data := append([]byte("GIF89a"), make([]byte, size-6)...)
+			body, contentType := coverForm("image/gif", data)
+			req := httptest.NewRequest(fiber.MethodPost, fmt.Sprintf("/UploadRecordCoverById/%s", rec.ID), body)
+			req.Header.Set("Content-Type", contentType)
+
+			resp, _ := limited.Test(req, 5000)
+			resp.Body.Close()
+			assert.Equalf(t, want, resp.StatusCode, "cover of %d bytes", size)
+		}
+	})
+
+	t.Run("get missing blob", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodGet, "/blobs/covers/missing.png", nil)
+		resp, _ := app.Test(req, 1000)
+		defer resp.Body.Close()
+
+		assert.Equal(t, 404, resp.StatusCode)
+	})
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rodrwan/collection/pkg/backup"
+	"github.com/rodrwan/collection/services"
+)
+
+// BackupCollection downloads the whole collection as a backup archive, to
+// be restored with RestoreCollection or collection restore.
+func (srv Server) BackupCollection(c *fiber.Ctx) error {
+	var buf bytes.Buffer
+	manifest, err := srv.collectionService.Backup(&buf)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, "application/gzip")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="collection-`+manifest.CreatedAt.Format("20060102-150405")+`.tar.gz"`)
+
+	return c.Send(buf.Bytes())
+}
+
+// RestoreCollection restores the backup archive sent as the request body
+// into the collection, which must be empty.
+func (srv Server) RestoreCollection(c *fiber.Ctx) error {
+	body := requestBody(c)
+
+	manifest, err := srv.collectionService.Restore(body)
+	if err != nil {
+		var checksumErr *backup.ChecksumError
+		switch {
+		case err == services.ErrCollectionNotEmpty:
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		case err == backup.ErrNotABackup,
+			errors.Is(err, backup.ErrUnsupportedVersion),
+			errors.Is(err, backup.ErrDuplicateSection),
+			errors.As(err, &checksumErr),
+			errors.Is(err, services.ErrBackupInvalid),
+			errors.Is(err, services.ErrBackupUnsupported):
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":       true,
+		"manifest": manifest,
+	})
+}
@@ -0,0 +1,101 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Backup(t *testing.T) {
+	newApp := func() (*fiber.App, *services.CollectionService) {
+		app := fiber.New(config.NewFiberConfig)
+		collectionService, err := services.NewCollectionService(
+			services.WithRecordMemoryRepository(),
+			services.WithSongMemoryRepository(),
+		)
+		if err != nil {
+			log.Fatal(err)
+		}
+		srv, err := server.NewServer(collectionService)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		app.Get("/BackupCollection", srv.BackupCollection)
+		app.Post("/RestoreCollection", srv.RestoreCollection)
+		return app, collectionService
+	}
+
+	src, srcService := newApp()
+	rec, _ := srcService.AddRecord(uuid.New(), "The Wall", "vinyl")
+	srcService.AddSongToRecord(rec.ToRecord(), "Hey You", 280)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/BackupCollection", nil)
+	resp, _ := src.Test(req, 1000)
+	archive, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "application/gzip", resp.Header.Get("Content-Type"))
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), ".tar.gz")
+
+	dst, dstService := newApp()
+
+	tests := []struct {
+		description  string
+		data         []byte
+		expectedCode int
+		expectedOk   bool
+	}{
+		{
+			description:  "not a backup",
+			data:         []byte("name,kind\nThe Wall,vinyl\n"),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "restore",
+			data:         archive,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "restore twice",
+			data:         archive,
+			expectedCode: 409,
+			expectedOk:   false,
+		},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(fiber.MethodPost, "/RestoreCollection", bytes.NewReader(test.data))
+		req.Header.Set("Content-Type", "application/gzip")
+
+		resp, _ := dst.Test(req, 1000)
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+
+		var out struct {
+			Ok bool
+		}
+		json.Unmarshal(body, &out)
+		assert.Equalf(t, test.expectedOk, out.Ok, test.description)
+	}
+
+	songs, err := dstService.FindSongsByRecord(rec.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(songs))
+	assert.Equal(t, "Hey You", songs[0].Name)
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rodrwan/collection/services"
+)
+
+// BulkCreateRecords creates the records, with their songs, sent as newline
+// delimited JSON and streams back a JSON result per line as batches are
+// written: ?batchSize=100
+//
+// The import runs while the response is written, so its status is always
+// 200. Should a batch fail to be written, the last line of the response is
+// {"ok": false, "error": "..."} and nothing after that batch is imported.
+func (srv Server) BulkCreateRecords(c *fiber.Ctx) error {
+	batchSize := services.DefaultBulkBatchSize
+	if value := c.Query("batchSize"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "batchSize must be a positive number")
+		}
+		batchSize = size
+	}
+
+	body := requestBody(c)
+
+	c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		enc := json.NewEncoder(w)
+
+		err := srv.collectionService.ImportNDJSON(body, batchSize, func(results []services.BulkResult) error {
+			for _, r := range results {
+				if err := enc.Encode(r); err != nil {
+					return err
+				}
+			}
+			return w.Flush()
+		})
+		if err != nil {
+			enc.Encode(fiber.Map{
+				"ok":    false,
+				"error": err.Error(),
+			})
+			w.Flush()
+		}
+	})
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package server_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_BulkCreateRecords(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/BulkCreateRecords", srv.BulkCreateRecords)
+
+	var large strings.Builder
+	for i := 0; i < 50; i++ {
+		large.WriteString(`{"name":"Record","kind":"vinyl","songs":[{"name":"Song","length":200}]}` + "\n")
+	}
+	// Blank lines take the body over the body limit.
+	large.WriteString(strings.Repeat(strings.Repeat(" ", 1023)+"\n", config.BodyLimit/1024+1))
+
+	tests := []struct {
+		description     string
+		route           string
+		data            string
+		expectedCode    int
+		expectedLines   int
+		expectedFailed  int
+		expectedRecords int
+	}{
+		{
+			description:  "invalid batch size",
+			route:        "/BulkCreateRecords?batchSize=0",
+			data:         `{"name":"Blue","kind":"vinyl"}`,
+			expectedCode: 400,
+		},
+		{
+			description:     "small body",
+			route:           "/BulkCreateRecords",
+			data:            `{"name":"Blue","kind":"vinyl"}` + "\n" + `{"name":"Blue","kind":"cd"}` + "\n",
+			expectedCode:    200,
+			expectedLines:   2,
+			expectedFailed:  1,
+			expectedRecords: 1,
+		},
+		{
+			description:     "streamed body",
+			route:           "/BulkCreateRecords?batchSize=7",
+			data:            large.String(),
+			expectedCode:    200,
+			expectedLines:   50,
+			expectedRecords: 51,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, test.route, strings.NewReader(test.data))
+			req.Header.Set("Content-Type", "application/x-ndjson")
+
+			resp, _ := app.Test(req, 10000)
+			defer resp.Body.Close()
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			if resp.StatusCode != 200 {
+				return
+			}
+
+			lines, failed := 0, 0
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				var result services.BulkResult
+				if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+					log.Fatal(err)
+				}
+				lines++
+				if !result.OK {
+					failed++
+				}
+			}
+
+			assert.Equalf(t, test.expectedLines, lines, test.description)
+			assert.Equalf(t, test.expectedFailed, failed, test.description)
+
+			records, _ := collectionService.FindAllRecord()
+			assert.Lenf(t, records, test.expectedRecords, test.description)
+		})
+	}
+}
+
+func TestServer_BulkCreateRecordsLimits(t *testing.T) {
+	line := `{"name":"Record","kind":"vinyl"}` + "\n"
+	body := strings.Repeat(line, 20)
+
+	collectionService, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+	srv, _ := server.NewServer(collectionService)
+
+	app := fiber.New(config.NewFiberConfig)
+	app.Post("/BulkCreateRecords", srv.BulkCreateRecords)
+	app.Use(server.LimitBody(config.BodyLimit))
+	app.Post("/CreateRecord", srv.CreateRecord)
+
+	// Handlers after LimitBody do not read bodies over the body limit.
+	large := `{"name":"Blue","kind":"vinyl"}` + strings.Repeat(" ", config.BodyLimit)
+	for _, chunked := range []bool{false, true} {
+		req := httptest.NewRequest(fiber.MethodPost, "/CreateRecord", strings.NewReader(large))
+		req.Header.Set("Content-Type", "application/json")
+		if chunked {
+			req.ContentLength = -1
+			req.TransferEncoding = []string{"chunked"}
+		}
+		resp, err := app.Test(req, 10000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equalf(t, fiber.StatusRequestEntityTooLarge, resp.StatusCode, "chunked %v", chunked)
+	}
+
+	req := httptest.NewRequest(fiber.MethodPost, "/CreateRecord", strings.NewReader(`{"name":"Blue","kind":"vinyl"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = -1
+	req.TransferEncoding = []string{"chunked"}
+	resp, err := app.Test(req, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	// Streamed bodies stop at MaxStreamedBody.
+	defer func(max int64) { server.MaxStreamedBody = max }(server.MaxStreamedBody)
+	server.MaxStreamedBody = int64(len(line) * 10)
+
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodPost, "/BulkCreateRecords?batchSize=5", strings.NewReader(body)), 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var last map[string]interface{}
+	lines := 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines++
+		last = nil
+		json.Unmarshal(scanner.Bytes(), &last)
+	}
+	assert.Equal(t, 11, lines)
+	assert.Equal(t, false, last["ok"])
+	assert.Equal(t, fiber.ErrRequestEntityTooLarge.Message, last["error"])
+}
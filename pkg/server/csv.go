@@ -0,0 +1,65 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rodrwan/collection/services"
+)
+
+// ExportRecordsCsv downloads the collection as CSV:
+// ?layout=songs|records&columns=name,kind,songs
+func (srv Server) ExportRecordsCsv(c *fiber.Ctx) error {
+	layout, err := services.ParseCSVLayout(c.Query("layout"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	var buf bytes.Buffer
+	if err := srv.collectionService.ExportCSV(&buf, layout, splitList(c.Query("columns"))); err != nil {
+		if errors.Is(err, services.ErrInvalidCSVColumn) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="collection.csv"`)
+
+	return c.Send(buf.Bytes())
+}
+
+// ImportRecordsCsv imports the CSV sent as the request body:
+// ?dryRun=true&map=Album:name,Track:song_name
+func (srv Server) ImportRecordsCsv(c *fiber.Ctx) error {
+	opts := services.CSVImportOptions{}
+
+	if value := c.Query("dryRun"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		opts.DryRun = dryRun
+	}
+
+	columns, err := services.ParseCSVColumnMap(c.Query("map"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	opts.Columns = columns
+
+	report, err := srv.collectionService.ImportCSV(bytes.NewReader(c.Body()), opts)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCSVColumn) || err == services.ErrMissingCSVColumn {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":     true,
+		"report": report,
+	})
+}
@@ -0,0 +1,136 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_CSV(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Get("/ExportRecordsCsv", srv.ExportRecordsCsv)
+	app.Post("/ImportRecordsCsv", srv.ImportRecordsCsv)
+
+	rec, _ := collectionService.AddRecord(uuid.New(), "The Wall", "vinyl")
+	collectionService.AddSongToRecord(rec.ToRecord(), "Hey You", 280)
+
+	t.Run("export", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodGet, "/ExportRecordsCsv?layout=records&columns=name,kind,songs", nil)
+		resp, _ := app.Test(req, 1000)
+		defer resp.Body.Close()
+
+		body, _ := ioutil.ReadAll(resp.Body)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), "text/csv"))
+		assert.Equal(t, "name,kind,songs\nThe Wall,vinyl,Hey You:280\n", string(body))
+	})
+
+	tests := []struct {
+		description     string
+		route           string
+		method          string
+		data            []byte
+		expectedCode    int
+		expectedOk      bool
+		expectedRecords int
+		expectedErrors  int
+	}{
+		{
+			description:  "export with invalid layout",
+			route:        "/ExportRecordsCsv?layout=albums",
+			method:       fiber.MethodGet,
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "export with unknown column",
+			route:        "/ExportRecordsCsv?columns=name,price",
+			method:       fiber.MethodGet,
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:     "import dry run",
+			route:           "/ImportRecordsCsv?dryRun=true&map=Album:name",
+			method:          fiber.MethodPost,
+			data:            []byte("Album,kind\nBlue,vinyl\nCourt and Spark,cd\n"),
+			expectedCode:    200,
+			expectedOk:      true,
+			expectedRecords: 1,
+			expectedErrors:  1,
+		},
+		{
+			description:  "import without name column",
+			route:        "/ImportRecordsCsv",
+			method:       fiber.MethodPost,
+			data:         []byte("Album,kind\nBlue,vinyl\n"),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:     "import",
+			route:           "/ImportRecordsCsv",
+			method:          fiber.MethodPost,
+			data:            []byte("name,kind,songs\nBlue,vinyl,River:240|All I Want:200\n"),
+			expectedCode:    200,
+			expectedOk:      true,
+			expectedRecords: 1,
+		},
+	}
+
+	type response struct {
+		Ok     bool                  `json:"ok,omitempty"`
+		Report services.ImportReport `json:"report,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "text/csv")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+			assert.Equalf(t, test.expectedRecords, r.Report.Records, test.description)
+			assert.Equalf(t, test.expectedErrors, len(r.Report.Errors), test.description)
+		})
+	}
+
+	records, _ := collectionService.FindAllRecord()
+	assert.Len(t, records, 2)
+}
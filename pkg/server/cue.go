@@ -0,0 +1,81 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rodrwan/collection/domain/validation"
+	"github.com/rodrwan/collection/pkg/cue"
+	"github.com/rodrwan/collection/services"
+)
+
+// maxCUESize is the largest cue sheet upload accepted, in bytes.
+const maxCUESize = 1024 * 1024
+
+// ImportCueSheet creates the record of the cue sheet in the "cue" field of
+// a multipart form: ?kind=lossless&dir=Artist/Album&dryRun=true
+// dir is where the audio files of the sheet are in the music library, read
+// for the length of the last track of each file.
+func (srv Server) ImportCueSheet(c *fiber.Ctx) error {
+	opts := services.CUEImportOptions{Kind: c.Query("kind")}
+
+	if value := c.Query("dryRun"); value != "" {
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		opts.DryRun = dryRun
+	}
+
+	if dir := c.Query("dir"); dir != "" {
+		path, err := srv.collectionService.LibraryPath(dir)
+		if err != nil {
+			switch err {
+			case services.ErrNoLibrary:
+				return fiber.NewError(fiber.StatusNotImplemented, err.Error())
+			case services.ErrOutsideLibrary:
+				return fiber.NewError(fiber.StatusBadRequest, err.Error())
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		opts.Dir = path
+	}
+
+	file, err := c.FormFile("cue")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if file.Size > maxCUESize {
+		return fiber.NewError(fiber.StatusRequestEntityTooLarge, "cue sheet is too large")
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	defer f.Close()
+
+	imported, err := srv.collectionService.ImportCUE(f, opts)
+	if err != nil {
+		var lineErr *cue.LineError
+		var errs validation.Errors
+		switch {
+		case errors.As(err, &lineErr), errors.As(err, &errs),
+			errors.Is(err, cue.ErrNoTracks), errors.Is(err, cue.ErrMissingStart),
+			err == services.ErrUnknownCUEKind:
+			return badRequest(c, err)
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	status := fiber.StatusOK
+	if imported.Created && !imported.DryRun {
+		status = fiber.StatusCreated
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"ok":     true,
+		"import": imported,
+	})
+}
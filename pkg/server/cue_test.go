@@ -0,0 +1,134 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"mime/multipart"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+const cueSheet = `PERFORMER "Joni Mitchell"
+TITLE "Blue"
+FILE "Blue.flac" WAVE
+  TRACK 01 AUDIO
+    TITLE "All I Want"
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "My Old Man"
+    INDEX 01 03:32:40
+`
+
+func cueForm(data string) (*bytes.Buffer, string) {
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	part, _ := w.CreateFormFile("cue", "Blue.cue")
+	part.Write([]byte(data))
+	w.Close()
+
+	return body, w.FormDataContentType()
+}
+
+func TestServer_ImportCueSheet(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/ImportCueSheet", srv.ImportCueSheet)
+
+	tests := []struct {
+		description    string
+		route          string
+		data           string
+		expectedCode   int
+		expectedOk     bool
+		expectedTracks int
+	}{
+		{
+			description:    "dry run",
+			route:          "/ImportCueSheet?dryRun=true",
+			data:           cueSheet,
+			expectedCode:   200,
+			expectedOk:     true,
+			expectedTracks: 2,
+		},
+		{
+			description:  "library dir without a library",
+			route:        "/ImportCueSheet?dir=Joni%20Mitchell/Blue",
+			data:         cueSheet,
+			expectedCode: 501,
+			expectedOk:   false,
+		},
+		{
+			description:  "not a cue sheet",
+			route:        "/ImportCueSheet",
+			data:         "FILE Blue.flac WAVE\nINDEX 01 00:00:00\n",
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "invalid kind",
+			route:        "/ImportCueSheet?kind=cassette",
+			data:         cueSheet,
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:    "import",
+			route:          "/ImportCueSheet",
+			data:           cueSheet,
+			expectedCode:   201,
+			expectedOk:     true,
+			expectedTracks: 2,
+		},
+		{
+			description:    "import again",
+			route:          "/ImportCueSheet",
+			data:           cueSheet,
+			expectedCode:   200,
+			expectedOk:     true,
+			expectedTracks: 2,
+		},
+	}
+
+	for _, test := range tests {
+		body, contentType := cueForm(test.data)
+		req := httptest.NewRequest(fiber.MethodPost, test.route, body)
+		req.Header.Set("Content-Type", contentType)
+
+		resp, _ := app.Test(req, 1000)
+		data, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+
+		var out struct {
+			Ok     bool
+			Import services.CUEImport
+		}
+		json.Unmarshal(data, &out)
+		assert.Equalf(t, test.expectedOk, out.Ok, test.description)
+		assert.Equalf(t, test.expectedTracks, len(out.Import.Tracks), test.description)
+	}
+
+	records, _ := collectionService.FindAllRecord()
+	assert.Equal(t, 1, len(records))
+	assert.Equal(t, "Joni Mitchell", records[0].Artist)
+}
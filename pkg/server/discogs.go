@@ -0,0 +1,34 @@
+package server
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rodrwan/collection/pkg/discogs"
+)
+
+// ImportDiscogsCollection imports the Discogs collection export sent as the
+// request body: ?dryRun=true
+func (srv Server) ImportDiscogsCollection(c *fiber.Ctx) error {
+	dryRun := false
+	if value := c.Query("dryRun"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	summary, err := srv.collectionService.ImportDiscogs(bytes.NewReader(c.Body()), dryRun)
+	if err != nil {
+		if err == discogs.ErrNotAnExport {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":      true,
+		"summary": summary,
+	})
+}
@@ -0,0 +1,124 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_ImportDiscogsCollection(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/ImportDiscogsCollection", srv.ImportDiscogsCollection)
+
+	export := "Catalog#,Artist,Title,Label,Format,Rating,Released,release_id,CollectionFolder,Date Added,Collection Media Condition,Collection Sleeve Condition,Collection Notes\n" +
+		"SHVL 804,Pink Floyd,The Wall,Harvest,\"2xLP, Album\",,1979,1,Uncategorized,2021-03-04 10:20:30,Mint (M),Mint (M),\n" +
+		"none,Joni Mitchell,Blue,Reprise,\"CD, Album\",,1971,2,Uncategorized,,,,\n"
+
+	tests := []struct {
+		description     string
+		route           string
+		data            []byte
+		expectedCode    int
+		expectedOk      bool
+		expectedCreated int
+		expectedSkipped int
+		expectedFailed  int
+	}{
+		{
+			description:  "invalid dry run",
+			route:        "/ImportDiscogsCollection?dryRun=maybe",
+			data:         []byte(export),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "not an export",
+			route:        "/ImportDiscogsCollection",
+			data:         []byte("name,kind\nBlue,vinyl\n"),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:     "dry run",
+			route:           "/ImportDiscogsCollection?dryRun=true",
+			data:            []byte(export),
+			expectedCode:    200,
+			expectedOk:      true,
+			expectedCreated: 1,
+			expectedFailed:  1,
+		},
+		{
+			description:     "import",
+			route:           "/ImportDiscogsCollection",
+			data:            []byte(export),
+			expectedCode:    200,
+			expectedOk:      true,
+			expectedCreated: 1,
+			expectedFailed:  1,
+		},
+		{
+			description:     "import again",
+			route:           "/ImportDiscogsCollection",
+			data:            []byte(export),
+			expectedCode:    200,
+			expectedOk:      true,
+			expectedSkipped: 1,
+			expectedFailed:  1,
+		},
+	}
+
+	type response struct {
+		Ok      bool                   `json:"ok,omitempty"`
+		Summary services.ImportSummary `json:"summary,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "text/csv")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+			assert.Equalf(t, test.expectedCreated, len(r.Summary.Created), test.description)
+			assert.Equalf(t, test.expectedSkipped, len(r.Summary.Skipped), test.description)
+			assert.Equalf(t, test.expectedFailed, len(r.Summary.Failed), test.description)
+		})
+	}
+
+	records, _ := collectionService.FindAllRecord()
+	assert.Len(t, records, 1)
+}
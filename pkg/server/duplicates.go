@@ -0,0 +1,62 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/services"
+)
+
+// GetDuplicateRecords reports likely duplicates scoring at least
+// ?minScore=, record.DefaultDuplicateScore by default.
+func (srv Server) GetDuplicateRecords(c *fiber.Ctx) error {
+	minScore := record.DefaultDuplicateScore
+	if value := c.Query("minScore"); value != "" {
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil || score < 0 || score > 1 {
+			return fiber.NewError(fiber.StatusBadRequest, "minScore must be a number between 0 and 1")
+		}
+		minScore = score
+	}
+
+	duplicates, err := srv.collectionService.FindDuplicateRecords(minScore)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":         true,
+		"duplicates": duplicates,
+	})
+}
+
+func (srv Server) MergeRecords(c *fiber.Ctx) error {
+	params := new(struct {
+		KeepID      uuid.UUID
+		DuplicateID uuid.UUID
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	merge, err := srv.collectionService.MergeRecords(params.KeepID, params.DuplicateID)
+	if err != nil {
+		switch err {
+		case record.ErrRecordNotFound:
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		case services.ErrMergeSameRecord:
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		case services.ErrDuplicateInRelease, services.ErrMergeBothLent:
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":    true,
+		"merge": merge,
+	})
+}
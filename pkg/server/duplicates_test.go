@@ -0,0 +1,141 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Duplicates(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Get("/GetDuplicateRecords", srv.GetDuplicateRecords)
+	app.Post("/MergeRecords", srv.MergeRecords)
+
+	wall, _ := collectionService.AddRecord(uuid.New(), "The Wall", "vinyl")
+	dup, _ := collectionService.AddRecord(uuid.New(), "Wall, The (Remastered)", "vinyl")
+	collectionService.AddRecord(uuid.New(), "The Wal", "mp3")
+
+	tests := []struct {
+		description  string
+		route        string
+		method       string
+		data         []byte
+		expectedCode int
+		expectedOk   bool
+		expectedLen  int
+	}{
+		{
+			description:  "duplicates report",
+			route:        "/GetDuplicateRecords",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  1,
+		},
+		{
+			description:  "duplicates report with lower score",
+			route:        "/GetDuplicateRecords?minScore=0.6",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  3,
+		},
+		{
+			description:  "duplicates report with invalid score",
+			route:        "/GetDuplicateRecords?minScore=2",
+			method:       fiber.MethodGet,
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "merge into itself",
+			route:        "/MergeRecords",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "keepId": "%s", "duplicateId": "%s" }`, wall.ID, wall.ID)),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "merge unknown record",
+			route:        "/MergeRecords",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "keepId": "%s", "duplicateId": "%s" }`, wall.ID, uuid.New())),
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+		{
+			description:  "merge duplicate",
+			route:        "/MergeRecords",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "keepId": "%s", "duplicateId": "%s" }`, wall.ID, dup.ID)),
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "duplicates report after merge",
+			route:        "/GetDuplicateRecords",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  0,
+		},
+	}
+
+	type response struct {
+		Ok         bool               `json:"ok,omitempty"`
+		Duplicates []record.Duplicate `json:"duplicates,omitempty"`
+		Merge      services.Merge     `json:"merge,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+			assert.Equalf(t, test.expectedLen, len(r.Duplicates), test.description)
+
+			if test.description == "merge duplicate" {
+				assert.Equal(t, wall.ID, r.Merge.Record.ID)
+			}
+		})
+	}
+}
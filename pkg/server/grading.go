@@ -0,0 +1,36 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func (srv Server) GradeRecordById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		Media  string
+		Sleeve string
+		Notes  string
+		Date   time.Time
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	record, err := srv.collectionService.GradeRecord(id, params.Media, params.Sleeve, params.Notes, params.Date)
+	if err != nil {
+		return badRequest(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":     true,
+		"record": record,
+	})
+}
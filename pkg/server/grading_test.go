@@ -0,0 +1,143 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_GradeRecordById(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/GradeRecordById/:id", srv.GradeRecordById)
+	app.Get("/GetRecords", srv.GetRecords)
+
+	vinyl, _ := collectionService.AddRecord(uuid.New(), "v1", "vinyl")
+	mp3, _ := collectionService.AddRecord(uuid.New(), "m1", "mp3")
+
+	tests := []struct {
+		description  string
+		route        string
+		method       string
+		data         []byte
+		expectedCode int
+		expectedOk   bool
+	}{
+		{
+			description:  "grade vinyl",
+			route:        fmt.Sprintf("/GradeRecordById/%s", vinyl.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "media": "NM", "sleeve": "VG+", "notes": "light scuffs", "date": "2021-03-01T00:00:00Z" }`),
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "grade mp3",
+			route:        fmt.Sprintf("/GradeRecordById/%s", mp3.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "media": "NM" }`),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "invalid grade",
+			route:        fmt.Sprintf("/GradeRecordById/%s", vinyl.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "media": "A" }`),
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:  "filter by minimum grade with an unescaped plus sign",
+			route:        "/GetRecords?minGrade=VG+",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "filter by minimum grade with an escaped plus sign",
+			route:        "/GetRecords?minGrade=VG%2B",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "filter by minimum grade with a trailing space",
+			route:        "/GetRecords?minGrade=NM%20",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "filter by a grade with a plus sign it does not have",
+			route:        "/GetRecords?minGrade=NM+",
+			method:       fiber.MethodGet,
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "filter by invalid grade",
+			route:        "/GetRecords?minGrade=lala",
+			method:       fiber.MethodGet,
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+	}
+
+	type response struct {
+		Ok      bool `json:"ok,omitempty"`
+		Records []struct {
+			ID string `json:"id"`
+		} `json:"records,omitempty"`
+		Error string `json:"error,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+			if test.method == fiber.MethodGet && r.Ok {
+				assert.Equalf(t, 1, len(r.Records), test.description)
+				assert.Equalf(t, vinyl.ID.String(), r.Records[0].ID, test.description)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rodrwan/collection/pkg/itunes"
+)
+
+// ImportITunesLibrary imports the iTunes or Apple Music "Library.xml" sent
+// as the request body: ?dryRun=true
+func (srv Server) ImportITunesLibrary(c *fiber.Ctx) error {
+	dryRun := false
+	if value := c.Query("dryRun"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	body := requestBody(c)
+
+	result, err := srv.collectionService.ImportITunes(body, dryRun)
+	if err != nil {
+		if errors.Is(err, itunes.ErrInvalidPlist) || errors.Is(err, itunes.ErrNotALibrary) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":     true,
+		"import": result,
+	})
+}
@@ -0,0 +1,139 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_ImportITunesLibrary(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithPlayMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/ImportITunesLibrary", srv.ImportITunesLibrary)
+
+	library := `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0"><dict><key>Tracks</key><dict>
+<key>1</key><dict><key>Track ID</key><integer>1</integer><key>Name</key><string>Hey You</string>
+<key>Artist</key><string>Pink Floyd</string><key>Album</key><string>The Wall</string>
+<key>Kind</key><string>AAC audio file</string><key>Total Time</key><integer>280000</integer>
+<key>Play Count</key><integer>2</integer><key>Play Date UTC</key><date>2020-01-02T03:04:05Z</date></dict>
+<key>2</key><dict><key>Track ID</key><integer>2</integer><key>Name</key><string>Episode 1</string>
+<key>Podcast</key><true/></dict>
+</dict></dict></plist>`
+
+	tests := []struct {
+		description     string
+		route           string
+		data            []byte
+		expectedCode    int
+		expectedOk      bool
+		expectedCreated int
+		expectedPlays   int
+		expectedSkipped int
+	}{
+		{
+			description:  "invalid dry run",
+			route:        "/ImportITunesLibrary?dryRun=maybe",
+			data:         []byte(library),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "not a property list",
+			route:        "/ImportITunesLibrary",
+			data:         []byte("name,kind\nBlue,vinyl\n"),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "not a library",
+			route:        "/ImportITunesLibrary",
+			data:         []byte(`<plist><dict><key>Playlists</key><array/></dict></plist>`),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:     "dry run",
+			route:           "/ImportITunesLibrary?dryRun=true",
+			data:            []byte(library),
+			expectedCode:    200,
+			expectedOk:      true,
+			expectedCreated: 1,
+			expectedPlays:   2,
+			expectedSkipped: 1,
+		},
+		{
+			description:     "import",
+			route:           "/ImportITunesLibrary",
+			data:            []byte(library),
+			expectedCode:    200,
+			expectedOk:      true,
+			expectedCreated: 1,
+			expectedPlays:   2,
+			expectedSkipped: 1,
+		},
+		{
+			description:     "import again",
+			route:           "/ImportITunesLibrary",
+			data:            []byte(library),
+			expectedCode:    200,
+			expectedOk:      true,
+			expectedSkipped: 2,
+		},
+	}
+
+	type response struct {
+		Ok     bool                  `json:"ok,omitempty"`
+		Import services.ITunesImport `json:"import,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "application/xml")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+			assert.Equalf(t, test.expectedCreated, len(r.Import.Created), test.description)
+			assert.Equalf(t, test.expectedPlays, r.Import.Plays, test.description)
+			assert.Equalf(t, test.expectedSkipped, len(r.Import.Skipped), test.description)
+		})
+	}
+
+	records, _ := collectionService.FindAllRecord()
+	assert.Len(t, records, 1)
+}
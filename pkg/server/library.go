@@ -0,0 +1,57 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/services"
+)
+
+// ScanLibrary starts scanning the music library in the background:
+// ?dryRun=true
+func (srv Server) ScanLibrary(c *fiber.Ctx) error {
+	dryRun := false
+	if value := c.Query("dryRun"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	job, err := srv.collectionService.StartLibraryScan(dryRun)
+	if err != nil {
+		switch err {
+		case services.ErrNoLibrary:
+			return fiber.NewError(fiber.StatusNotImplemented, err.Error())
+		case services.ErrScanRunning:
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"ok":   true,
+		"scan": job,
+	})
+}
+
+func (srv Server) GetLibraryScanById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	job, err := srv.collectionService.FindLibraryScan(id)
+	if err != nil {
+		if err == services.ErrScanNotFound {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":   true,
+		"scan": job,
+	})
+}
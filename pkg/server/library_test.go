@@ -0,0 +1,93 @@
+package server_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_ScanLibrary(t *testing.T) {
+	dir, err := ioutil.TempDir("", "library")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	newApp := func(cfgs ...services.CollectionConfiguration) *fiber.App {
+		app := fiber.New(config.NewFiberConfig)
+		collectionService, err := services.NewCollectionService(append(cfgs,
+			services.WithRecordMemoryRepository(),
+			services.WithSongMemoryRepository(),
+		)...)
+		if err != nil {
+			log.Fatal(err)
+		}
+		srv, err := server.NewServer(collectionService)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		app.Post("/ScanLibrary", srv.ScanLibrary)
+		app.Get("/GetLibraryScanById/:id", srv.GetLibraryScanById)
+		return app
+	}
+
+	type response struct {
+		Ok   bool             `json:"ok,omitempty"`
+		Scan services.ScanJob `json:"scan,omitempty"`
+	}
+
+	call := func(app *fiber.App, method, route string) (int, response) {
+		resp, _ := app.Test(httptest.NewRequest(method, route, nil), 1000)
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var r response
+		if err := json.Unmarshal(body, &r); err != nil {
+			log.Fatal(err)
+		}
+		return resp.StatusCode, r
+	}
+
+	code, _ := call(newApp(), fiber.MethodPost, "/ScanLibrary")
+	assert.Equal(t, 501, code)
+
+	app := newApp(services.WithMusicLibrary(dir))
+
+	code, _ = call(app, fiber.MethodPost, "/ScanLibrary?dryRun=maybe")
+	assert.Equal(t, 400, code)
+
+	code, r := call(app, fiber.MethodPost, "/ScanLibrary?dryRun=true")
+	assert.Equal(t, 202, code)
+	assert.True(t, r.Ok)
+
+	id := r.Scan.ID.String()
+	for i := 0; i < 100 && r.Scan.Status != services.ScanDone; i++ {
+		time.Sleep(10 * time.Millisecond)
+		code, r = call(app, fiber.MethodGet, "/GetLibraryScanById/"+id)
+		assert.Equal(t, 200, code)
+	}
+	assert.Equal(t, services.ScanDone, r.Scan.Status)
+	assert.True(t, r.Scan.Result.DryRun)
+
+	code, _ = call(app, fiber.MethodGet, "/GetLibraryScanById/not-an-id")
+	assert.Equal(t, 400, code)
+
+	code, _ = call(app, fiber.MethodGet, "/GetLibraryScanById/"+uuid.New().String())
+	assert.Equal(t, 404, code)
+}
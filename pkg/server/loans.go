@@ -0,0 +1,98 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/loan"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/services"
+)
+
+func (srv Server) LendRecordById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		Borrower string
+		LentAt   time.Time
+		DueAt    time.Time
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	l, err := srv.collectionService.LendRecord(id, params.Borrower, params.LentAt, params.DueAt)
+	if err != nil {
+		switch err {
+		case record.ErrRecordNotFound:
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		case loan.ErrAlreadyLent, services.ErrRecordNotOwned:
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		return badRequest(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"ok":   true,
+		"loan": l,
+	})
+}
+
+func (srv Server) ReturnRecordById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		ReturnedAt time.Time
+	})
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&params); err != nil {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+		}
+	}
+
+	l, err := srv.collectionService.ReturnRecord(id, params.ReturnedAt)
+	if err != nil {
+		if err == services.ErrRecordNotLent {
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		return badRequest(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":   true,
+		"loan": l,
+	})
+}
+
+func (srv Server) GetCurrentLoans(c *fiber.Ctx) error {
+	loans, err := srv.collectionService.FindCurrentLoans()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":    true,
+		"loans": loans,
+	})
+}
+
+func (srv Server) GetOverdueLoans(c *fiber.Ctx) error {
+	loans, err := srv.collectionService.FindOverdueLoans(time.Now().UTC())
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":    true,
+		"loans": loans,
+	})
+}
@@ -0,0 +1,149 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Loans(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithLoanMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/LendRecordById/:id", srv.LendRecordById)
+	app.Post("/ReturnRecordById/:id", srv.ReturnRecordById)
+	app.Get("/GetCurrentLoans", srv.GetCurrentLoans)
+	app.Get("/GetOverdueLoans", srv.GetOverdueLoans)
+
+	rec, _ := collectionService.AddRecord(uuid.New(), "r1", "vinyl")
+	lentAt := time.Now().UTC().AddDate(0, 0, -30).Format(time.RFC3339)
+	dueAt := time.Now().UTC().AddDate(0, 0, -16).Format(time.RFC3339)
+
+	tests := []struct {
+		description  string
+		route        string
+		method       string
+		data         []byte
+		expectedCode int
+		expectedOk   bool
+		expectedLen  int
+	}{
+		{
+			description:  "lend record",
+			route:        fmt.Sprintf("/LendRecordById/%s", rec.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "borrower": "Percy", "lentAt": "%s", "dueAt": "%s" }`, lentAt, dueAt)),
+			expectedCode: 201,
+			expectedOk:   true,
+		},
+		{
+			description:  "lend record twice",
+			route:        fmt.Sprintf("/LendRecordById/%s", rec.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "borrower": "Rodrigo" }`),
+			expectedCode: 409,
+			expectedOk:   false,
+		},
+		{
+			description:  "lend unknown record",
+			route:        fmt.Sprintf("/LendRecordById/%s", uuid.New()),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "borrower": "Rodrigo" }`),
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+		{
+			description:  "current loans",
+			route:        "/GetCurrentLoans",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  1,
+		},
+		{
+			description:  "overdue loans",
+			route:        "/GetOverdueLoans",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  1,
+		},
+		{
+			description:  "return record",
+			route:        fmt.Sprintf("/ReturnRecordById/%s", rec.ID),
+			method:       fiber.MethodPost,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "return record not lent",
+			route:        fmt.Sprintf("/ReturnRecordById/%s", rec.ID),
+			method:       fiber.MethodPost,
+			expectedCode: 409,
+			expectedOk:   false,
+		},
+		{
+			description:  "no current loans after return",
+			route:        "/GetCurrentLoans",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  0,
+		},
+	}
+
+	type response struct {
+		Ok    bool          `json:"ok,omitempty"`
+		Loans []interface{} `json:"loans,omitempty"`
+		Error string        `json:"error,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+			if test.method == fiber.MethodGet {
+				assert.Equalf(t, test.expectedLen, len(r.Loans), test.description)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/location"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/services"
+)
+
+func (srv Server) CreateLocation(c *fiber.Ctx) error {
+	params := new(struct {
+		Name     string
+		Kind     string
+		ParentID uuid.UUID
+		Position int
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	// unknown kinds are passed through so they are reported as field errors
+	kind, err := location.ParseKind(params.Kind)
+	if err != nil {
+		kind = location.Kind(params.Kind)
+	}
+
+	l, err := srv.collectionService.AddLocation(params.Name, kind, params.ParentID, params.Position)
+	if err != nil {
+		if err == location.ErrLocationNotFound {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return badRequest(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"ok":       true,
+		"location": l,
+	})
+}
+
+func (srv Server) GetLocations(c *fiber.Ctx) error {
+	locations, err := srv.collectionService.FindAllLocations()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":        true,
+		"locations": locations,
+	})
+}
+
+func (srv Server) AssignRecordLocationById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		LocationID uuid.UUID
+		Position   int
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	rec, err := srv.collectionService.AssignRecordLocation(id, params.LocationID, params.Position)
+	if err != nil {
+		switch err {
+		case record.ErrRecordNotFound, location.ErrLocationNotFound:
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return badRequest(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":     true,
+		"record": rec,
+	})
+}
+
+func (srv Server) GetRecordLocationById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	where, err := srv.collectionService.WhereIsRecord(id)
+	if err != nil {
+		switch err {
+		case record.ErrRecordNotFound, services.ErrRecordNotShelved:
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":       true,
+		"location": where,
+	})
+}
+
+func (srv Server) GetReshelvePlanById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	plan, err := srv.collectionService.PlanReshelve(id)
+	if err != nil {
+		switch err {
+		case location.ErrLocationNotFound:
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		case location.ErrNotAShelf:
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":   true,
+		"plan": plan,
+	})
+}
@@ -0,0 +1,167 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/domain/location"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Locations(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithLocationMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/CreateLocation", srv.CreateLocation)
+	app.Get("/GetLocations", srv.GetLocations)
+	app.Post("/AssignRecordLocationById/:id", srv.AssignRecordLocationById)
+	app.Get("/GetRecordLocationById/:id", srv.GetRecordLocationById)
+	app.Get("/GetReshelvePlanById/:id", srv.GetReshelvePlanById)
+
+	room, _ := collectionService.AddLocation("Living room", location.KindRoom, uuid.Nil, 0)
+	shelf, _ := collectionService.AddLocation("Shelf A", location.KindShelf, room.ID, 1)
+	rec, _ := collectionService.AddRecord(uuid.New(), "r1", "vinyl")
+	other, _ := collectionService.AddRecord(uuid.New(), "a2", "vinyl")
+	collectionService.AssignRecordLocation(other.ID, shelf.ID, 2)
+
+	tests := []struct {
+		description  string
+		route        string
+		method       string
+		data         []byte
+		expectedCode int
+		expectedOk   bool
+	}{
+		{
+			description:  "create slot",
+			route:        "/CreateLocation",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "name": "Slot 1", "kind": "Slot", "parentId": "%s", "position": 1 }`, shelf.ID)),
+			expectedCode: 201,
+			expectedOk:   true,
+		},
+		{
+			description:  "create location with unknown kind",
+			route:        "/CreateLocation",
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "name": "Drawer", "kind": "drawer" }`),
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:  "create location under unknown parent",
+			route:        "/CreateLocation",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "name": "Shelf B", "kind": "shelf", "parentId": "%s" }`, uuid.New())),
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+		{
+			description:  "list locations",
+			route:        "/GetLocations",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "record not shelved yet",
+			route:        fmt.Sprintf("/GetRecordLocationById/%s", rec.ID),
+			method:       fiber.MethodGet,
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+		{
+			description:  "assign record to room",
+			route:        fmt.Sprintf("/AssignRecordLocationById/%s", rec.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "locationId": "%s" }`, room.ID)),
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:  "assign record to shelf",
+			route:        fmt.Sprintf("/AssignRecordLocationById/%s", rec.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "locationId": "%s", "position": 1 }`, shelf.ID)),
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "where is the record",
+			route:        fmt.Sprintf("/GetRecordLocationById/%s", rec.ID),
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "reshelve plan",
+			route:        fmt.Sprintf("/GetReshelvePlanById/%s", shelf.ID),
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "reshelve plan of a room",
+			route:        fmt.Sprintf("/GetReshelvePlanById/%s", room.ID),
+			method:       fiber.MethodGet,
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+	}
+
+	type response struct {
+		Ok       bool                    `json:"ok,omitempty"`
+		Location services.RecordLocation `json:"location,omitempty"`
+		Plan     services.ReshelvePlan   `json:"plan,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			json.Unmarshal(body, &r)
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+
+			switch test.description {
+			case "where is the record":
+				assert.Equal(t, "Living room > Shelf A", r.Location.Label)
+			case "reshelve plan":
+				assert.Equal(t, 2, r.Plan.Records)
+				assert.Equal(t, 2, len(r.Plan.Moves))
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/pkg/musicbrainz"
+	"github.com/rodrwan/collection/services"
+)
+
+// EnrichFromMusicBrainz matches the collection against the MusicBrainz
+// release dump sent as the request body, gzip compressed or not:
+// ?dryRun=true
+func (srv Server) EnrichFromMusicBrainz(c *fiber.Ctx) error {
+	dryRun := false
+	if value := c.Query("dryRun"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+	}
+
+	body := requestBody(c)
+
+	enrichment, err := srv.collectionService.EnrichFromMusicBrainz(body, dryRun)
+	if err != nil {
+		var lineErr *musicbrainz.LineError
+		if errors.As(err, &lineErr) {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if err == services.ErrNoMatchReviewRepository {
+			return fiber.NewError(fiber.StatusNotImplemented, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":         true,
+		"enrichment": enrichment,
+	})
+}
+
+func (srv Server) GetMusicBrainzReviews(c *fiber.Ctx) error {
+	reviews, err := srv.collectionService.FindMusicBrainzReviews()
+	if err != nil {
+		if err == services.ErrNoMatchReviewRepository {
+			return fiber.NewError(fiber.StatusNotImplemented, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":      true,
+		"reviews": reviews,
+	})
+}
+
+// ResolveMusicBrainzReviewById links the record to the release picked among
+// its candidates, or dismisses them when no release is given.
+func (srv Server) ResolveMusicBrainzReviewById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		ReleaseID string
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	rec, err := srv.collectionService.ResolveMusicBrainzReview(id, params.ReleaseID)
+	if err != nil {
+		switch err {
+		case services.ErrMatchReviewNotFound, record.ErrRecordNotFound:
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		case services.ErrUnknownCandidate:
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		case services.ErrNoMatchReviewRepository:
+			return fiber.NewError(fiber.StatusNotImplemented, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":     true,
+		"record": rec,
+	})
+}
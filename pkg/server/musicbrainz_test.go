@@ -0,0 +1,97 @@
+package server_test
+
+import (
+	"encoding/json"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_MusicBrainz(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithMatchReviewMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/EnrichFromMusicBrainz", srv.EnrichFromMusicBrainz)
+	app.Get("/GetMusicBrainzReviews", srv.GetMusicBrainzReviews)
+	app.Post("/ResolveMusicBrainzReviewById/:id", srv.ResolveMusicBrainzReviewById)
+
+	rec, _ := collectionService.AddRecord(uuid.New(), "Greatest Hits", "mp3")
+	dump := `{"id":"queen-hits","title":"Greatest Hits","artist-credit":[{"name":"Queen"}],"release-group":{"id":"queen"}}
+{"id":"abba-hits","title":"Greatest Hits","artist-credit":[{"name":"ABBA"}],"release-group":{"id":"abba"}}
+`
+
+	t.Run("Enrich", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodPost, "/EnrichFromMusicBrainz", strings.NewReader(dump))
+		resp, _ := app.Test(req, 1000)
+		defer resp.Body.Close()
+
+		var body struct {
+			Enrichment services.Enrichment
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, 2, body.Enrichment.Releases)
+		assert.Len(t, body.Enrichment.Review, 1)
+	})
+
+	t.Run("Broken dump", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodPost, "/EnrichFromMusicBrainz?dryRun=true", strings.NewReader("not json"))
+		resp, _ := app.Test(req, 1000)
+		assert.Equal(t, 400, resp.StatusCode)
+	})
+
+	t.Run("Reviews", func(t *testing.T) {
+		req := httptest.NewRequest(fiber.MethodGet, "/GetMusicBrainzReviews", nil)
+		resp, _ := app.Test(req, 1000)
+		defer resp.Body.Close()
+
+		var body struct {
+			Reviews []services.MatchReview
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		assert.Len(t, body.Reviews, 1)
+		assert.Len(t, body.Reviews[0].Candidates, 2)
+	})
+
+	t.Run("Resolve", func(t *testing.T) {
+		tests := []struct {
+			id     string
+			body   string
+			status int
+		}{
+			{id: rec.ID.String(), body: `{"releaseId":"unknown"}`, status: 400},
+			{id: uuid.New().String(), body: `{}`, status: 404},
+			{id: rec.ID.String(), body: `{"releaseId":"abba-hits"}`, status: 200},
+			{id: rec.ID.String(), body: `{}`, status: 404},
+		}
+
+		for _, tt := range tests {
+			req := httptest.NewRequest(fiber.MethodPost, "/ResolveMusicBrainzReviewById/"+tt.id, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, _ := app.Test(req, 1000)
+			assert.Equal(t, tt.status, resp.StatusCode, tt.body)
+		}
+
+		found, _ := collectionService.FindRecord(rec.ID.String())
+		assert.Equal(t, "ABBA", found.Artist)
+	})
+}
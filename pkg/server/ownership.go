@@ -0,0 +1,36 @@
+package server
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+)
+
+func (srv Server) ChangeRecordStatusById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		Status      string
+		Acquisition *record.Acquisition
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	rec, err := srv.collectionService.ChangeRecordStatus(id, params.Status, params.Acquisition)
+	if err != nil {
+		if err == record.ErrRecordNotFound {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return badRequest(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":     true,
+		"record": rec,
+	})
+}
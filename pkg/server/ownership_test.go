@@ -0,0 +1,137 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Ownership(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/CreateRecord", srv.CreateRecord)
+	app.Post("/ChangeRecordStatusById/:id", srv.ChangeRecordStatusById)
+	app.Get("/GetRecords", srv.GetRecords)
+
+	owned, _ := collectionService.AddRecord(uuid.New(), "r1", "vinyl")
+
+	tests := []struct {
+		description  string
+		route        string
+		method       string
+		data         []byte
+		expectedCode int
+		expectedOk   bool
+		expectedLen  int
+	}{
+		{
+			description:  "create a wishlisted record",
+			route:        "/CreateRecord",
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "name": "r2", "kind": "vinyl", "status": "wishlist" }`),
+			expectedCode: 201,
+			expectedOk:   true,
+		},
+		{
+			description:  "create a record with an invalid acquisition",
+			route:        "/CreateRecord",
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "name": "r3", "kind": "vinyl", "acquisition": { "price": 100 } }`),
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:  "sell an owned record",
+			route:        fmt.Sprintf("/ChangeRecordStatusById/%s", owned.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "status": "sold" }`),
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "transition not allowed",
+			route:        fmt.Sprintf("/ChangeRecordStatusById/%s", owned.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "status": "ordered" }`),
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:  "unknown record",
+			route:        fmt.Sprintf("/ChangeRecordStatusById/%s", uuid.New()),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "status": "sold" }`),
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+		{
+			description:  "filter by status",
+			route:        "/GetRecords?status=wishlist",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  1,
+		},
+		{
+			description:  "filter by invalid acquisition date",
+			route:        "/GetRecords?acquiredFrom=yesterday",
+			method:       fiber.MethodGet,
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+	}
+
+	type response struct {
+		Ok      bool          `json:"ok,omitempty"`
+		Records []interface{} `json:"records,omitempty"`
+		Error   string        `json:"error,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+			if test.method == fiber.MethodGet && r.Ok {
+				assert.Equalf(t, test.expectedLen, len(r.Records), test.description)
+			}
+		})
+	}
+}
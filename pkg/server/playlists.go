@@ -0,0 +1,230 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/playlist"
+	"github.com/rodrwan/collection/domain/song"
+)
+
+func (srv Server) CreatePlaylist(c *fiber.Ctx) error {
+	params := new(struct {
+		Name       string
+		Duplicates string
+		SongIDs    []uuid.UUID
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	p, err := srv.collectionService.CreatePlaylist(params.Name, duplicatesParam(params.Duplicates), params.SongIDs)
+	if err != nil {
+		return playlistError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"ok":       true,
+		"playlist": p,
+	})
+}
+
+func (srv Server) GetPlaylists(c *fiber.Ctx) error {
+	playlists, err := srv.collectionService.FindAllPlaylists()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":        true,
+		"playlists": playlists,
+	})
+}
+
+func (srv Server) GetPlaylistById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	p, err := srv.collectionService.FindPlaylist(id)
+	if err != nil {
+		return playlistError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":       true,
+		"playlist": p,
+	})
+}
+
+func (srv Server) UpdatePlaylistById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		Name       string
+		Duplicates string
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	p, err := srv.collectionService.EditPlaylist(id, params.Name, duplicatesParam(params.Duplicates))
+	if err != nil {
+		return playlistError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":       true,
+		"playlist": p,
+	})
+}
+
+func (srv Server) DeletePlaylistById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if err := srv.collectionService.DeletePlaylist(id); err != nil {
+		return playlistError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok": true,
+	})
+}
+
+func (srv Server) AddSongsToPlaylistById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		SongIDs []uuid.UUID
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	p, err := srv.collectionService.AddSongsToPlaylist(id, params.SongIDs)
+	if err != nil {
+		return playlistError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":       true,
+		"playlist": p,
+	})
+}
+
+func (srv Server) RemoveSongFromPlaylistById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		Position int
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	p, err := srv.collectionService.RemoveSongFromPlaylist(id, params.Position)
+	if err != nil {
+		return playlistError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":       true,
+		"playlist": p,
+	})
+}
+
+func (srv Server) MovePlaylistSongById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		From int
+		To   int
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	p, err := srv.collectionService.MovePlaylistSong(id, params.From, params.To)
+	if err != nil {
+		return playlistError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":       true,
+		"playlist": p,
+	})
+}
+
+func (srv Server) ReorderPlaylistById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		SongIDs []uuid.UUID
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	p, err := srv.collectionService.ReorderPlaylist(id, params.SongIDs)
+	if err != nil {
+		return playlistError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":       true,
+		"playlist": p,
+	})
+}
+
+// duplicatesParam parses a duplicate policy, passing unknown values through
+// so they are reported as field errors. An empty value stays empty, so new
+// playlists get the default policy and edited ones keep theirs.
+func duplicatesParam(value string) playlist.Duplicates {
+	if strings.TrimSpace(value) == "" {
+		return ""
+	}
+
+	duplicates, err := playlist.ParseDuplicates(value)
+	if err != nil {
+		return playlist.Duplicates(value)
+	}
+
+	return duplicates
+}
+
+func playlistError(c *fiber.Ctx, err error) error {
+	switch err {
+	case playlist.ErrPlaylistNotFound, song.ErrSongNotFound:
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case playlist.ErrDuplicateSong:
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+
+	return badRequest(c, err)
+}
@@ -0,0 +1,223 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/domain/playlist"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Playlists(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithPlaylistMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Get("/GetSongsByRecordId/:id", srv.GetSongsByRecordId)
+	app.Post("/CreatePlaylist", srv.CreatePlaylist)
+	app.Get("/GetPlaylists", srv.GetPlaylists)
+	app.Get("/GetPlaylistById/:id", srv.GetPlaylistById)
+	app.Post("/UpdatePlaylistById/:id", srv.UpdatePlaylistById)
+	app.Delete("/DeletePlaylistById/:id", srv.DeletePlaylistById)
+	app.Post("/AddSongsToPlaylistById/:id", srv.AddSongsToPlaylistById)
+	app.Post("/RemoveSongFromPlaylistById/:id", srv.RemoveSongFromPlaylistById)
+	app.Post("/MovePlaylistSongById/:id", srv.MovePlaylistSongById)
+	app.Post("/ReorderPlaylistById/:id", srv.ReorderPlaylistById)
+
+	rec, _ := collectionService.AddRecord(uuid.New(), "r1", "vinyl")
+	collectionService.AddSongToRecord(rec.ToRecord(), "s1", 200)
+	collectionService.AddSongToRecord(rec.ToRecord(), "s2", 100)
+	songs, _ := collectionService.FindSongsByRecord(rec.ID)
+	s1, s2 := songs[0].ID, songs[1].ID
+
+	existing, _ := collectionService.CreatePlaylist("Existing", playlist.DuplicatesSkip, []uuid.UUID{s1, s2})
+
+	tests := []struct {
+		description  string
+		route        string
+		method       string
+		data         []byte
+		expectedCode int
+		expectedOk   bool
+		expectedLen  int64
+		// expectedDuplicates is checked when set
+		expectedDuplicates playlist.Duplicates
+	}{
+		{
+			description:  "list songs of a record",
+			route:        fmt.Sprintf("/GetSongsByRecordId/%s", rec.ID),
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "create playlist",
+			route:        "/CreatePlaylist",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "name": "Sunday", "duplicates": "reject", "songIds": ["%s", "%s"] }`, s1, s2)),
+			expectedCode: 201,
+			expectedOk:   true,
+			expectedLen:  300,
+		},
+		{
+			description:  "create playlist with invalid duplicate policy",
+			route:        "/CreatePlaylist",
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "name": "Sunday", "duplicates": "merge" }`),
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:  "create playlist with unknown song",
+			route:        "/CreatePlaylist",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "name": "Sunday", "songIds": ["%s"] }`, uuid.New())),
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+		{
+			description:  "get playlist",
+			route:        fmt.Sprintf("/GetPlaylistById/%s", existing.ID),
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  300,
+		},
+		{
+			description:  "add duplicate song is skipped",
+			route:        fmt.Sprintf("/AddSongsToPlaylistById/%s", existing.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "songIds": ["%s"] }`, s1)),
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  300,
+		},
+		{
+			description:        "rename playlist keeping its policy",
+			route:              fmt.Sprintf("/UpdatePlaylistById/%s", existing.ID),
+			method:             fiber.MethodPost,
+			data:               []byte(`{ "name": "Renamed" }`),
+			expectedCode:       200,
+			expectedOk:         true,
+			expectedLen:        300,
+			expectedDuplicates: playlist.DuplicatesSkip,
+		},
+		{
+			description:  "rename playlist",
+			route:        fmt.Sprintf("/UpdatePlaylistById/%s", existing.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "name": "Renamed", "duplicates": "allow" }`),
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  300,
+		},
+		{
+			description:  "move song",
+			route:        fmt.Sprintf("/MovePlaylistSongById/%s", existing.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "from": 2, "to": 1 }`),
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  300,
+		},
+		{
+			description:  "reorder with missing songs",
+			route:        fmt.Sprintf("/ReorderPlaylistById/%s", existing.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "songIds": ["%s"] }`, s1)),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "reorder",
+			route:        fmt.Sprintf("/ReorderPlaylistById/%s", existing.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "songIds": ["%s", "%s"] }`, s1, s2)),
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  300,
+		},
+		{
+			description:  "remove song",
+			route:        fmt.Sprintf("/RemoveSongFromPlaylistById/%s", existing.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "position": 1 }`),
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  100,
+		},
+		{
+			description:  "list playlists",
+			route:        "/GetPlaylists",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "delete playlist",
+			route:        fmt.Sprintf("/DeletePlaylistById/%s", existing.ID),
+			method:       fiber.MethodDelete,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "get deleted playlist",
+			route:        fmt.Sprintf("/GetPlaylistById/%s", existing.ID),
+			method:       fiber.MethodGet,
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+	}
+
+	type response struct {
+		Ok       bool                    `json:"ok,omitempty"`
+		Playlist playlist.PublicPlaylist `json:"playlist,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+			assert.Equalf(t, test.expectedLen, r.Playlist.TotalLength, test.description)
+			if test.expectedDuplicates != "" {
+				assert.Equalf(t, test.expectedDuplicates, r.Playlist.Duplicates, test.description)
+			}
+		})
+	}
+}
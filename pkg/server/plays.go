@@ -0,0 +1,161 @@
+package server
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/services"
+)
+
+// defaultStatsLimit is the number of records listed by the play stats when
+// no limit is given.
+const defaultStatsLimit = 10
+
+type playParams struct {
+	RecordID uuid.UUID
+	SongID   uuid.UUID
+	PlayedAt time.Time
+}
+
+func (pp playParams) toInput() services.PlayInput {
+	return services.PlayInput{
+		RecordID: pp.RecordID,
+		SongID:   pp.SongID,
+		PlayedAt: pp.PlayedAt,
+	}
+}
+
+func (srv Server) LogPlay(c *fiber.Ctx) error {
+	params := new(playParams)
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	p, err := srv.collectionService.LogPlay(params.toInput())
+	if err != nil {
+		switch err {
+		case record.ErrRecordNotFound, song.ErrSongNotFound:
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return badRequest(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"ok":   true,
+		"play": p,
+	})
+}
+
+func (srv Server) LogPlays(c *fiber.Ctx) error {
+	params := new(struct {
+		Plays []playParams
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	ins := make([]services.PlayInput, 0, len(params.Plays))
+	for _, p := range params.Plays {
+		ins = append(ins, p.toInput())
+	}
+
+	plays, err := srv.collectionService.LogPlays(ins)
+	if err != nil {
+		return badRequest(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"ok":    true,
+		"plays": plays,
+	})
+}
+
+func (srv Server) GetMostPlayedRecords(c *fiber.Ctx) error {
+	from, to, limit, err := playStatsQuery(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	records, err := srv.collectionService.MostPlayedRecords(from, to, limit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":      true,
+		"records": records,
+	})
+}
+
+func (srv Server) GetRecentlyPlayedRecords(c *fiber.Ctx) error {
+	from, to, limit, err := playStatsQuery(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	records, err := srv.collectionService.RecentlyPlayedRecords(from, to, limit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":      true,
+		"records": records,
+	})
+}
+
+func (srv Server) GetNeverPlayedRecords(c *fiber.Ctx) error {
+	from, to, _, err := playStatsQuery(c)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	records, err := srv.collectionService.NeverPlayedRecords(from, to)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":      true,
+		"records": records,
+	})
+}
+
+// playStatsQuery reads ?from=2021-01-01&to=2021-12-31&limit=10. Both dates
+// are optional and the end date includes the whole day.
+func playStatsQuery(c *fiber.Ctx) (time.Time, time.Time, int, error) {
+	var from, to time.Time
+
+	if value := c.Query("from"); value != "" {
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return from, to, 0, err
+		}
+		from = date
+	}
+
+	if value := c.Query("to"); value != "" {
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return from, to, 0, err
+		}
+		to = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	limit := defaultStatsLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return from, to, 0, err
+		}
+		limit = n
+	}
+
+	return from, to, limit, nil
+}
@@ -0,0 +1,156 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Plays(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithPlayMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/LogPlay", srv.LogPlay)
+	app.Post("/LogPlays", srv.LogPlays)
+	app.Get("/GetMostPlayedRecords", srv.GetMostPlayedRecords)
+	app.Get("/GetRecentlyPlayedRecords", srv.GetRecentlyPlayedRecords)
+	app.Get("/GetNeverPlayedRecords", srv.GetNeverPlayedRecords)
+
+	played, _ := collectionService.AddRecord(uuid.New(), "played", "vinyl")
+	collectionService.AddRecord(uuid.New(), "never", "vinyl")
+
+	tests := []struct {
+		description  string
+		route        string
+		method       string
+		data         []byte
+		expectedCode int
+		expectedOk   bool
+		expectedLen  int
+	}{
+		{
+			description:  "log play",
+			route:        "/LogPlay",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "recordId": "%s", "playedAt": "2021-05-01T20:00:00Z" }`, played.ID)),
+			expectedCode: 201,
+			expectedOk:   true,
+		},
+		{
+			description:  "log play of unknown record",
+			route:        "/LogPlay",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "recordId": "%s" }`, uuid.New())),
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+		{
+			description:  "log batch",
+			route:        "/LogPlays",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "plays": [{ "recordId": "%s", "playedAt": "2021-06-01T20:00:00Z" }, { "recordId": "%s" }] }`, played.ID, played.ID)),
+			expectedCode: 201,
+			expectedOk:   true,
+			expectedLen:  2,
+		},
+		{
+			description:  "log invalid batch",
+			route:        "/LogPlays",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "plays": [{ "recordId": "%s" }, {}] }`, played.ID)),
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:  "most played",
+			route:        "/GetMostPlayedRecords",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  1,
+		},
+		{
+			description:  "recently played in range",
+			route:        "/GetRecentlyPlayedRecords?from=2021-05-01&to=2021-05-01",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  1,
+		},
+		{
+			description:  "never played",
+			route:        "/GetNeverPlayedRecords",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  1,
+		},
+		{
+			description:  "never played in range",
+			route:        "/GetNeverPlayedRecords?from=2020-01-01&to=2020-12-31",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  2,
+		},
+		{
+			description:  "invalid range",
+			route:        "/GetMostPlayedRecords?from=yesterday",
+			method:       fiber.MethodGet,
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+	}
+
+	type response struct {
+		Ok      bool          `json:"ok,omitempty"`
+		Plays   []interface{} `json:"plays,omitempty"`
+		Records []interface{} `json:"records,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+			assert.Equalf(t, test.expectedLen, len(r.Plays)+len(r.Records), test.description)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+)
+
+type reviewParams struct {
+	User  string
+	Stars float64
+	Text  string
+}
+
+func (srv Server) RateRecordById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(reviewParams)
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	review, err := srv.collectionService.RateRecord(id, params.User, params.Stars, params.Text)
+	if err != nil {
+		if err == record.ErrRecordNotFound {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return badRequest(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":     true,
+		"review": review,
+	})
+}
+
+func (srv Server) RateSongById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(reviewParams)
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	review, err := srv.collectionService.RateSong(id, params.User, params.Stars, params.Text)
+	if err != nil {
+		if err == song.ErrSongNotFound {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return badRequest(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":     true,
+		"review": review,
+	})
+}
+
+func (srv Server) GetRecordReviewsById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	reviews, err := srv.collectionService.FindRecordReviews(id)
+	if err != nil {
+		if err == record.ErrRecordNotFound {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":      true,
+		"reviews": reviews,
+	})
+}
+
+func (srv Server) GetSongReviewsById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	reviews, err := srv.collectionService.FindSongReviews(id)
+	if err != nil {
+		if err == song.ErrSongNotFound {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":      true,
+		"reviews": reviews,
+	})
+}
@@ -0,0 +1,173 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Ratings(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithGenreMemoryRepository(),
+		services.WithReviewMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Get("/GetRecords", srv.GetRecords)
+	app.Post("/RateRecordById/:id", srv.RateRecordById)
+	app.Post("/RateSongById/:id", srv.RateSongById)
+	app.Get("/GetRecordReviewsById/:id", srv.GetRecordReviewsById)
+	app.Get("/GetSongReviewsById/:id", srv.GetSongReviewsById)
+
+	rec, _ := collectionService.AddRecord(uuid.New(), "r1", "vinyl")
+	low, _ := collectionService.AddRecord(uuid.New(), "r2", "vinyl")
+	collectionService.AddRecord(uuid.New(), "r3", "vinyl")
+	collectionService.RateRecord(low.ID, "percy", 2, "")
+	collectionService.AddSongToRecord(rec.ToRecord(), "s1", 200)
+	songs, _ := collectionService.FindSongsByRecord(rec.ID)
+
+	tests := []struct {
+		description  string
+		route        string
+		method       string
+		data         []byte
+		expectedCode int
+		expectedOk   bool
+		expectedLen  int
+	}{
+		{
+			description:  "rate record",
+			route:        fmt.Sprintf("/RateRecordById/%s", rec.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "user": "percy", "stars": 4.5, "text": "A classic" }`),
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "rate record with invalid stars",
+			route:        fmt.Sprintf("/RateRecordById/%s", rec.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "user": "percy", "stars": 4.3 }`),
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:  "rate unknown record",
+			route:        fmt.Sprintf("/RateRecordById/%s", uuid.New()),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "user": "percy", "stars": 4 }`),
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+		{
+			description:  "rate song",
+			route:        fmt.Sprintf("/RateSongById/%s", songs[0].ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "user": "percy", "stars": 5 }`),
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "record reviews",
+			route:        fmt.Sprintf("/GetRecordReviewsById/%s", rec.ID),
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "song reviews",
+			route:        fmt.Sprintf("/GetSongReviewsById/%s", songs[0].ID),
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "records by rating",
+			route:        "/GetRecords?minRating=1&sort=-rating",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+			expectedLen:  2,
+		},
+		{
+			description:  "records with a minimum rating out of range",
+			route:        "/GetRecords?minRating=6",
+			method:       fiber.MethodGet,
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:  "records with an invalid minimum rating",
+			route:        "/GetRecords?minRating=high",
+			method:       fiber.MethodGet,
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:  "records with invalid sort",
+			route:        "/GetRecords?sort=price",
+			method:       fiber.MethodGet,
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+	}
+
+	type response struct {
+		Ok      bool                  `json:"ok,omitempty"`
+		Records []record.PublicRecord `json:"records,omitempty"`
+		Reviews services.Reviews      `json:"reviews,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+			assert.Equalf(t, test.expectedLen, len(r.Records), test.description)
+
+			switch test.description {
+			case "record reviews":
+				assert.Equal(t, 4.5, r.Reviews.Average)
+				assert.Equal(t, 1, len(r.Reviews.Reviews))
+			case "records by rating":
+				assert.Equal(t, rec.ID, r.Records[0].ID)
+			}
+		})
+	}
+}
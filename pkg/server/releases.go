@@ -0,0 +1,144 @@
+package server
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/release"
+)
+
+func (srv Server) CreateRelease(c *fiber.Ctx) error {
+	params := new(struct {
+		Name       string
+		OriginalID uuid.UUID
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	r, err := srv.collectionService.CreateRelease(params.Name, params.OriginalID)
+	if err != nil {
+		return releaseError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"ok":      true,
+		"release": r,
+	})
+}
+
+func (srv Server) GetReleases(c *fiber.Ctx) error {
+	releases, err := srv.collectionService.FindAllReleases()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":       true,
+		"releases": releases,
+	})
+}
+
+func (srv Server) GetReleaseById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	r, err := srv.collectionService.FindRelease(id)
+	if err != nil {
+		return releaseError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":      true,
+		"release": r,
+	})
+}
+
+func (srv Server) AddVariantToReleaseById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		RecordID uuid.UUID
+		Relation string
+		Of       uuid.UUID
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	r, err := srv.collectionService.AddReleaseVariant(id, release.Variant{
+		RecordID: params.RecordID,
+		Relation: release.Relation(params.Relation),
+		Of:       params.Of,
+	})
+	if err != nil {
+		return releaseError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":      true,
+		"release": r,
+	})
+}
+
+func (srv Server) RemoveVariantFromReleaseById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		RecordID uuid.UUID
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	r, err := srv.collectionService.RemoveReleaseVariant(id, params.RecordID)
+	if err != nil {
+		return releaseError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":      true,
+		"release": r,
+	})
+}
+
+// GetRecordVariantsById lists every variant of the release a record belongs
+// to, the record included.
+func (srv Server) GetRecordVariantsById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	r, err := srv.collectionService.FindRecordVariants(id)
+	if err != nil {
+		return releaseError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":      true,
+		"release": r,
+	})
+}
+
+func releaseError(c *fiber.Ctx, err error) error {
+	switch err {
+	case release.ErrReleaseNotFound, release.ErrVariantNotFound, release.ErrRecordNoRelease, record.ErrRecordNotFound:
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case release.ErrRecordInRelease, release.ErrVariantInUse:
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+
+	return badRequest(c, err)
+}
@@ -0,0 +1,165 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/domain/release"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Releases(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithReleaseMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/CreateRelease", srv.CreateRelease)
+	app.Get("/GetReleases", srv.GetReleases)
+	app.Get("/GetReleaseById/:id", srv.GetReleaseById)
+	app.Post("/AddVariantToReleaseById/:id", srv.AddVariantToReleaseById)
+	app.Post("/RemoveVariantFromReleaseById/:id", srv.RemoveVariantFromReleaseById)
+	app.Get("/GetRecordVariantsById/:id", srv.GetRecordVariantsById)
+
+	vinyl, _ := collectionService.AddRecord(uuid.New(), "Blue", "vinyl")
+	mp3, _ := collectionService.AddRecord(uuid.New(), "Blue", "mp3")
+	loose, _ := collectionService.AddRecord(uuid.New(), "Court and Spark", "vinyl")
+	existing, _ := collectionService.CreateRelease("Blue", vinyl.ID)
+
+	tests := []struct {
+		description          string
+		route                string
+		method               string
+		data                 []byte
+		expectedCode         int
+		expectedOk           bool
+		expectedOwnedFormats int
+	}{
+		{
+			description:          "create release",
+			route:                "/CreateRelease",
+			method:               fiber.MethodPost,
+			data:                 []byte(fmt.Sprintf(`{ "name": "Court and Spark", "originalId": "%s" }`, loose.ID)),
+			expectedCode:         201,
+			expectedOk:           true,
+			expectedOwnedFormats: 1,
+		},
+		{
+			description:  "create release without name",
+			route:        "/CreateRelease",
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "name": "" }`),
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:          "add digital copy",
+			route:                fmt.Sprintf("/AddVariantToReleaseById/%s", existing.ID),
+			method:               fiber.MethodPost,
+			data:                 []byte(fmt.Sprintf(`{ "recordId": "%s", "relation": "digital-copy-of", "of": "%s" }`, mp3.ID, vinyl.ID)),
+			expectedCode:         200,
+			expectedOk:           true,
+			expectedOwnedFormats: 2,
+		},
+		{
+			description:  "add record of another release",
+			route:        fmt.Sprintf("/AddVariantToReleaseById/%s", existing.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "recordId": "%s", "relation": "reissue-of", "of": "%s" }`, loose.ID, vinyl.ID)),
+			expectedCode: 409,
+			expectedOk:   false,
+		},
+		{
+			description:  "add to unknown release",
+			route:        fmt.Sprintf("/AddVariantToReleaseById/%s", uuid.New()),
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "recordId": "%s", "relation": "reissue-of" }`, mp3.ID)),
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+		{
+			description:          "record variants",
+			route:                fmt.Sprintf("/GetRecordVariantsById/%s", mp3.ID),
+			method:               fiber.MethodGet,
+			expectedCode:         200,
+			expectedOk:           true,
+			expectedOwnedFormats: 2,
+		},
+		{
+			description:  "remove original in use",
+			route:        fmt.Sprintf("/RemoveVariantFromReleaseById/%s", existing.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "recordId": "%s" }`, vinyl.ID)),
+			expectedCode: 409,
+			expectedOk:   false,
+		},
+		{
+			description:          "get release",
+			route:                fmt.Sprintf("/GetReleaseById/%s", existing.ID),
+			method:               fiber.MethodGet,
+			expectedCode:         200,
+			expectedOk:           true,
+			expectedOwnedFormats: 2,
+		},
+		{
+			description:  "get releases",
+			route:        "/GetReleases",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+	}
+
+	type response struct {
+		Ok       bool                    `json:"ok,omitempty"`
+		Release  release.PublicRelease   `json:"release,omitempty"`
+		Releases []release.PublicRelease `json:"releases,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+			assert.Equalf(t, test.expectedOwnedFormats, r.Release.OwnedFormats, test.description)
+
+			if test.description == "get releases" {
+				assert.Len(t, r.Releases, 2)
+			}
+		})
+	}
+}
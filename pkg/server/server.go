@@ -1,10 +1,19 @@
 package server
 
 import (
+	"bytes"
 	"errors"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/validation"
 	"github.com/rodrwan/collection/services"
 )
 
@@ -12,6 +21,9 @@ var (
 	ErrServiceCannotBeNil = errors.New("service cannot be nil")
 )
 
+// dateLayout is the layout of dates given in query strings.
+const dateLayout = "2006-01-02"
+
 type Server struct {
 	collectionService *services.CollectionService
 }
@@ -26,10 +38,108 @@ func NewServer(collectionService *services.CollectionService) (Server, error) {
 	}, nil
 }
 
+// MaxStreamedBody caps the request bodies import handlers read as a
+// stream, when the app streams request bodies. They are not held to the
+// app body limit then.
+var MaxStreamedBody int64 = 256 << 20
+
+// LimitBody holds the handlers after it to the body limit of an app that
+// streams request bodies: streamed bodies over limit are rejected with 413
+// instead of being read whole by c.Body(). Handlers that read the body as a
+// stream, with requestBody, are registered before it.
+func LimitBody(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		length := c.Request().Header.ContentLength()
+		if length > limit {
+			return tooLarge(c)
+		}
+
+		stream := c.Context().RequestBodyStream()
+		if length >= 0 || stream == nil {
+			return c.Next()
+		}
+
+		// Chunked bodies have no length up front.
+		body, err := ioutil.ReadAll(io.LimitReader(stream, int64(limit)+1))
+		if err != nil {
+			return err
+		}
+		if len(body) > limit {
+			return tooLarge(c)
+		}
+		c.Request().SetBody(body)
+
+		return c.Next()
+	}
+}
+
+// tooLarge fails with 413 and closes the connection, whose unread body
+// cannot be told from the next request.
+func tooLarge(c *fiber.Ctx) error {
+	c.Context().SetConnectionClose()
+	return fiber.ErrRequestEntityTooLarge
+}
+
+// requestBody reads the request body as it comes when the app streams
+// request bodies, failing with 413 past MaxStreamedBody, and from memory
+// otherwise.
+func requestBody(c *fiber.Ctx) io.Reader {
+	stream := c.Context().RequestBodyStream()
+	if stream == nil {
+		return bytes.NewReader(c.Body())
+	}
+
+	return &cappedReader{r: stream, left: MaxStreamedBody}
+}
+
+// cappedReader reads up to left bytes from r and fails with 413 when r
+// has more.
+type cappedReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (cr *cappedReader) Read(p []byte) (int, error) {
+	if cr.left <= 0 {
+		var probe [1]byte
+		if n, _ := cr.r.Read(probe[:]); n > 0 {
+			return 0, fiber.ErrRequestEntityTooLarge
+		}
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > cr.left {
+		p = p[:cr.left]
+	}
+	n, err := cr.r.Read(p)
+	cr.left -= int64(n)
+	return n, err
+}
+
+// badRequest answers validation errors with 422 and the list of failing
+// fields, and any other error with 400.
+func badRequest(c *fiber.Ctx, err error) error {
+	var errs validation.Errors
+	if errors.As(err, &errs) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"ok":     false,
+			"error":  err.Error(),
+			"errors": errs,
+		})
+	}
+
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+		"ok":    false,
+		"error": err.Error(),
+	})
+}
+
 func (srv Server) CreateRecord(c *fiber.Ctx) error {
 	params := new(struct {
-		Name string
-		Kind string
+		Name        string
+		Kind        string
+		Status      string
+		Acquisition record.Acquisition
 	})
 
 	if err := c.BodyParser(&params); err != nil {
@@ -40,12 +150,9 @@ func (srv Server) CreateRecord(c *fiber.Ctx) error {
 	}
 
 	id := uuid.New()
-	record, err := srv.collectionService.AddRecord(id, params.Name, params.Kind)
+	record, err := srv.collectionService.AddRecordWithOwnership(id, params.Name, params.Kind, params.Status, params.Acquisition)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"ok":    false,
-			"error": err.Error(),
-		})
+		return badRequest(c, err)
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -55,7 +162,12 @@ func (srv Server) CreateRecord(c *fiber.Ctx) error {
 }
 
 func (srv Server) GetRecords(c *fiber.Ctx) error {
-	records, err := srv.collectionService.FindAllRecord()
+	filter, err := recordFilterFromQuery(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+
+	records, err := srv.collectionService.FindRecords(filter)
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
@@ -98,7 +210,7 @@ func (srv Server) AddSongToRecordById(c *fiber.Ctx) error {
 	}
 
 	if err := srv.collectionService.AddSongToRecord(record.ToRecord(), params.Name, params.Length); err != nil {
-		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return badRequest(c, err)
 	}
 
 	return c.JSON(fiber.Map{
@@ -106,3 +218,131 @@ func (srv Server) AddSongToRecordById(c *fiber.Ctx) error {
 		"record": record,
 	})
 }
+
+func (srv Server) GetSongsByRecordId(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	songs, err := srv.collectionService.FindSongsByRecord(id)
+	if err != nil {
+		if err == record.ErrRecordNotFound {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":    true,
+		"songs": songs,
+	})
+}
+
+// recordFilterFromQuery builds a record filter from the listing query string:
+// ?kind=vinyl&genre=<id>&tags=a,b&minGrade=VG+&status=owned&seller=x
+// &acquiredFrom=2021-01-01&acquiredTo=2021-12-31&minRating=3.5&sort=-rating
+func recordFilterFromQuery(c *fiber.Ctx) (record.Filter, error) {
+	filter := record.Filter{
+		Kind:   c.Query("kind"),
+		Tags:   splitList(c.Query("tags")),
+		Seller: c.Query("seller"),
+	}
+
+	if status := c.Query("status"); status != "" {
+		st, err := record.ParseStatus(status)
+		if err != nil {
+			return record.Filter{}, err
+		}
+		filter.Status = st
+	}
+
+	if from := c.Query("acquiredFrom"); from != "" {
+		date, err := time.Parse(dateLayout, from)
+		if err != nil {
+			return record.Filter{}, err
+		}
+		filter.AcquiredFrom = date
+	}
+
+	if to := c.Query("acquiredTo"); to != "" {
+		date, err := time.Parse(dateLayout, to)
+		if err != nil {
+			return record.Filter{}, err
+		}
+		// include the whole day
+		filter.AcquiredTo = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	if minGrade := queryKeepingPlus(c, "minGrade"); minGrade != "" {
+		grade, err := record.ParseGrade(minGrade)
+		if err != nil {
+			return record.Filter{}, err
+		}
+		filter.MinGrade = grade
+	}
+
+	if minRating := c.Query("minRating"); minRating != "" {
+		var errs validation.Errors
+		value, err := strconv.ParseFloat(minRating, 64)
+		switch {
+		case err != nil:
+			return record.Filter{}, errs.Add("minRating", validation.CodeInvalid, record.ErrInvalidMinRating).Err()
+		case value < record.MinRatingLow || value > record.MinRatingHigh:
+			return record.Filter{}, errs.Add("minRating", validation.CodeOutOfRange, record.ErrInvalidMinRating).Err()
+		}
+		filter.MinRating = value
+	}
+
+	if order := c.Query("sort"); order != "" {
+		sort, err := record.ParseSort(order)
+		if err != nil {
+			return record.Filter{}, err
+		}
+		filter.Sort = sort
+	}
+
+	if genre := c.Query("genre"); genre != "" {
+		id, err := uuid.Parse(genre)
+		if err != nil {
+			return record.Filter{}, err
+		}
+		filter.GenreIDs = []uuid.UUID{id}
+	}
+
+	return filter, nil
+}
+
+// queryKeepingPlus returns a query string parameter decoded without reading
+// "+" as a space, so grades such as "VG+" can be given unescaped.
+func queryKeepingPlus(c *fiber.Ctx, key string) string {
+	for _, pair := range strings.Split(string(c.Context().URI().QueryString()), "&") {
+		name, value := pair, ""
+		if i := strings.IndexByte(pair, '='); i >= 0 {
+			name, value = pair[:i], pair[i+1:]
+		}
+		if name != key {
+			continue
+		}
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			return unescaped
+		}
+		return value
+	}
+
+	return ""
+}
+
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
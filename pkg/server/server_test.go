@@ -56,13 +56,14 @@ func TestServer_NewServer(t *testing.T) {
 
 func TestServer_CreateRecord(t *testing.T) {
 	tests := []struct {
-		description   string // description of the test case
-		route         string // route path to test
-		data          []byte // request data
-		method        string // request method
-		expectedCode  int    // expected HTTP status code
-		expectedOk    bool   // expected ok messaje
-		expectedError string // expected error message messaje
+		description    string   // description of the test case
+		route          string   // route path to test
+		data           []byte   // request data
+		method         string   // request method
+		expectedCode   int      // expected HTTP status code
+		expectedOk     bool     // expected ok messaje
+		expectedError  string   // expected error message messaje
+		expectedFields []string // expected failing fields as field:code
 	}{
 		{
 			description:   "get HTTP status 201 on record creation",
@@ -74,22 +75,34 @@ func TestServer_CreateRecord(t *testing.T) {
 			expectedError: "",
 		},
 		{
-			description:   "get HTTP status 400 when create a record with empty body",
-			route:         "/CreateRecord",
-			data:          []byte(`{}`),
-			method:        fiber.MethodPost,
-			expectedCode:  400,
-			expectedOk:    false,
-			expectedError: "missing value",
+			description:    "get HTTP status 422 when create a record with empty body",
+			route:          "/CreateRecord",
+			data:           []byte(`{}`),
+			method:         fiber.MethodPost,
+			expectedCode:   422,
+			expectedOk:     false,
+			expectedError:  "name: missing value; kind: missing value",
+			expectedFields: []string{"name:required", "kind:required"},
 		},
 		{
-			description:   "get HTTP status 400 when create a record with missing record type",
-			route:         "/CreateRecord",
-			data:          []byte(`{ "name": "lala" }`),
-			method:        fiber.MethodPost,
-			expectedCode:  400,
-			expectedOk:    false,
-			expectedError: "Invalid record type",
+			description:    "get HTTP status 422 when create a record with missing record type",
+			route:          "/CreateRecord",
+			data:           []byte(`{ "name": "lala" }`),
+			method:         fiber.MethodPost,
+			expectedCode:   422,
+			expectedOk:     false,
+			expectedError:  "kind: missing value",
+			expectedFields: []string{"kind:required"},
+		},
+		{
+			description:    "get HTTP status 422 when create a record with unknown record type",
+			route:          "/CreateRecord",
+			data:           []byte(`{ "name": "lala", "kind": "aiff" }`),
+			method:         fiber.MethodPost,
+			expectedCode:   422,
+			expectedOk:     false,
+			expectedError:  "kind: invalid record kind",
+			expectedFields: []string{"kind:invalid"},
 		},
 		{
 			description:   "get HTTP status 422 when create a record with missing record type",
@@ -122,6 +135,10 @@ func TestServer_CreateRecord(t *testing.T) {
 		Ok     bool        `json:"ok,omitempty"`
 		Record interface{} `json:"record,omitempty"`
 		Error  string      `json:"error,omitempty"`
+		Errors []struct {
+			Field string `json:"field"`
+			Code  string `json:"code"`
+		} `json:"errors,omitempty"`
 	}
 	// Iterate through test single test cases
 	for _, test := range tests {
@@ -145,6 +162,12 @@ func TestServer_CreateRecord(t *testing.T) {
 			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
 			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
 			assert.Equalf(t, test.expectedError, r.Error, test.description)
+
+			var fields []string
+			for _, fe := range r.Errors {
+				fields = append(fields, fe.Field+":"+fe.Code)
+			}
+			assert.Equalf(t, test.expectedFields, fields, test.description)
 		})
 	}
 }
@@ -431,6 +454,25 @@ func TestServer_AddSongToExistingRecord(t *testing.T) {
 				},
 			},
 		},
+		{
+			description:  "get HTTP status 422 with invalid song fields",
+			route:        fmt.Sprintf("/AddSongToRecordById/%s", id.String()),
+			method:       fiber.MethodPost,
+			expectedCode: 422,
+			expectedOk:   false,
+			data:         []byte(`{ "name": "", "length": -1 }`),
+			services: []services.CollectionConfiguration{
+				services.WithRecordMemoryRepository(),
+				services.WithSongMemoryRepository(),
+			},
+			args: []args{
+				{
+					id:   id,
+					name: "lala",
+					kind: "vinyl",
+				},
+			},
+		},
 		{
 			description:   "get HTTP status 422",
 			route:         fmt.Sprintf("/AddSongToRecordById/%s", id.String()),
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/domain/taxonomy"
+	"github.com/rodrwan/collection/domain/validation"
+	"github.com/rodrwan/collection/services"
+)
+
+func (srv Server) CreateGenre(c *fiber.Ctx) error {
+	params := new(struct {
+		Name     string
+		ParentID uuid.UUID
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	genre, err := srv.collectionService.AddGenre(params.Name, params.ParentID)
+	if err != nil {
+		return badRequest(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"ok":    true,
+		"genre": genre,
+	})
+}
+
+func (srv Server) GetGenres(c *fiber.Ctx) error {
+	genres, err := srv.collectionService.FindAllGenres()
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":     true,
+		"genres": genres,
+	})
+}
+
+func (srv Server) TagRecordById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		GenreID    uuid.UUID
+		ClearGenre bool
+		Tags       []string
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	record, err := srv.collectionService.TagRecord(id, params.GenreID, params.ClearGenre, params.Tags)
+	if err != nil {
+		return tagError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":     true,
+		"record": record,
+	})
+}
+
+func (srv Server) TagSongById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		Tags []string
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	if err := srv.collectionService.TagSong(id, params.Tags); err != nil {
+		return tagError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok": true,
+	})
+}
+
+func (srv Server) GetTagCounts(c *fiber.Ctx) error {
+	filter, err := recordFilterFromQuery(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+
+	counts, err := srv.collectionService.TagCounts(filter)
+	if err != nil {
+		if err == services.ErrNoSongRepository {
+			return fiber.NewError(fiber.StatusNotImplemented, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":   true,
+		"tags": counts,
+	})
+}
+
+// tagError maps the errors of tagging a record or a song to a response.
+func tagError(c *fiber.Ctx, err error) error {
+	var errs validation.Errors
+	switch {
+	case errors.As(err, &errs), err == services.ErrGenreConflict, err == taxonomy.ErrGenreNotFound:
+		return badRequest(c, err)
+	case err == record.ErrRecordNotFound, err == song.ErrSongNotFound:
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case err == services.ErrNoGenreRepository, err == services.ErrNoSongRepository:
+		return fiber.NewError(fiber.StatusNotImplemented, err.Error())
+	}
+
+	return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,176 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Taxonomy(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithGenreMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/CreateGenre", srv.CreateGenre)
+	app.Get("/GetGenres", srv.GetGenres)
+	app.Get("/GetRecords", srv.GetRecords)
+	app.Post("/TagRecordById/:id", srv.TagRecordById)
+	app.Post("/TagSongById/:id", srv.TagSongById)
+	app.Get("/GetTagCounts", srv.GetTagCounts)
+
+	electronic, _ := collectionService.AddGenre("Electronic", uuid.Nil)
+	rec, _ := collectionService.AddRecord(uuid.New(), "r1", "vinyl")
+	collectionService.AddRecord(uuid.New(), "r2", "vinyl")
+
+	tests := []struct {
+		description  string
+		route        string
+		method       string
+		data         []byte
+		expectedCode int
+		expectedOk   bool
+	}{
+		{
+			description:  "create sub-genre",
+			route:        "/CreateGenre",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "name": "House", "parentId": "%s" }`, electronic.ID)),
+			expectedCode: 201,
+			expectedOk:   true,
+		},
+		{
+			description:  "create genre with unknown parent",
+			route:        "/CreateGenre",
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "name": "House", "parentId": "%s" }`, uuid.New())),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "list genres",
+			route:        "/GetGenres",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "tag record",
+			route:        fmt.Sprintf("/TagRecordById/%s", rec.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "genreId": "%s", "tags": ["Summer"] }`, electronic.ID)),
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "tag record with invalid id",
+			route:        "/TagRecordById/lala",
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "tags": ["summer"] }`),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "tag unknown record",
+			route:        fmt.Sprintf("/TagRecordById/%s", uuid.New()),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "tags": ["summer"] }`),
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+		{
+			description:  "tag record with unknown genre",
+			route:        fmt.Sprintf("/TagRecordById/%s", rec.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(fmt.Sprintf(`{ "genreId": "%s" }`, uuid.New())),
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "tag unknown song",
+			route:        fmt.Sprintf("/TagSongById/%s", uuid.New()),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "tags": ["summer"] }`),
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+		{
+			description:  "filter records with invalid genre",
+			route:        "/GetRecords?genre=lala",
+			method:       fiber.MethodGet,
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+		{
+			description:  "tag counts",
+			route:        "/GetTagCounts",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+	}
+
+	type response struct {
+		Ok    bool   `json:"ok,omitempty"`
+		Error string `json:"error,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+		})
+	}
+
+	req := httptest.NewRequest(fiber.MethodGet, fmt.Sprintf("/GetRecords?genre=%s&tags=summer", electronic.ID), nil)
+	resp, _ := app.Test(req, 1000)
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	var r struct {
+		Records []struct {
+			ID   string   `json:"id"`
+			Tags []string `json:"tags"`
+		} `json:"records"`
+	}
+	if err := json.Unmarshal(body, &r); err != nil {
+		log.Fatal(err)
+	}
+	assert.Equal(t, 1, len(r.Records))
+	assert.Equal(t, []string{"summer"}, r.Records[0].Tags)
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/playlist"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/pkg/tracklist"
+	"github.com/rodrwan/collection/services"
+)
+
+// ExportRecordPlaylistById downloads the songs of a digital record as a
+// playlist file: ?format=m3u|pls|xspf&base=/music
+func (srv Server) ExportRecordPlaylistById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return srv.sendTracklist(c, func(base string) (tracklist.Tracklist, error) {
+		return srv.collectionService.RecordTracklist(id, base)
+	})
+}
+
+// ExportPlaylistById downloads a playlist as a playlist file:
+// ?format=m3u|pls|xspf&base=/music
+func (srv Server) ExportPlaylistById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return srv.sendTracklist(c, func(base string) (tracklist.Tracklist, error) {
+		return srv.collectionService.PlaylistTracklist(id, base)
+	})
+}
+
+func (srv Server) sendTracklist(c *fiber.Ctx, find func(base string) (tracklist.Tracklist, error)) error {
+	format, err := tracklist.ParseFormat(c.Query("format"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	list, err := find(c.Query("base"))
+	if err != nil {
+		switch err {
+		case record.ErrRecordNotFound, playlist.ErrPlaylistNotFound:
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		case services.ErrRecordNotDigital:
+			return fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	var buf bytes.Buffer
+	if err := tracklist.Write(&buf, format, list); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+attachmentName(list.Title)+format.Extension()+`"`)
+
+	return c.Send(buf.Bytes())
+}
+
+// attachmentName keeps a title safe to quote in a Content-Disposition
+// header.
+func attachmentName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < ' ' || r > '~' || strings.ContainsRune(`"\/`, r) {
+			return '_'
+		}
+		return r
+	}, title)
+	if name == "" {
+		return "playlist"
+	}
+	return name
+}
@@ -0,0 +1,95 @@
+package server_test
+
+import (
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/domain/playlist"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Tracklists(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithPlaylistMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Get("/ExportRecordPlaylistById/:id", srv.ExportRecordPlaylistById)
+	app.Get("/ExportPlaylistById/:id", srv.ExportPlaylistById)
+
+	var mp3 services.BulkResult
+	collectionService.ImportNDJSON(strings.NewReader(`{"name":"Demos","kind":"mp3","songs":[{"name":"Hey You","length":280,"path":"Someone/Demos/01 Hey You.mp3"}]}`), 10, func(results []services.BulkResult) error {
+		mp3 = results[0]
+		return nil
+	})
+	vinyl, _ := collectionService.AddRecord(uuid.New(), "The Wall", "vinyl")
+	p, _ := collectionService.CreatePlaylist("Sunday", playlist.DuplicatesAllow, mp3.Songs)
+
+	tests := []struct {
+		name        string
+		url         string
+		status      int
+		contentType string
+		body        string
+	}{
+		{
+			name:        "M3U",
+			url:         "/ExportRecordPlaylistById/" + (*mp3.ID).String(),
+			status:      200,
+			contentType: "audio/x-mpegurl",
+			body:        "#EXTM3U\n#PLAYLIST:Demos\n#EXTINF:280,Hey You\nSomeone/Demos/01 Hey You.mp3\n",
+		},
+		{
+			name:        "PLS",
+			url:         "/ExportPlaylistById/" + p.ID.String() + "?format=pls&base=http://nas/music",
+			status:      200,
+			contentType: "audio/x-scpls",
+			body:        "File1=http://nas/music/Someone/Demos/01 Hey You.mp3\n",
+		},
+		{
+			name:        "XSPF",
+			url:         "/ExportPlaylistById/" + p.ID.String() + "?format=xspf",
+			status:      200,
+			contentType: "application/xspf+xml",
+			body:        "<location>Someone/Demos/01%20Hey%20You.mp3</location>",
+		},
+		{name: "Unknown format", url: "/ExportRecordPlaylistById/" + (*mp3.ID).String() + "?format=wpl", status: 400},
+		{name: "Vinyl record", url: "/ExportRecordPlaylistById/" + vinyl.ID.String(), status: 409},
+		{name: "Unknown record", url: "/ExportRecordPlaylistById/" + uuid.New().String(), status: 404},
+		{name: "Unknown playlist", url: "/ExportPlaylistById/" + uuid.New().String(), status: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodGet, tt.url, nil)
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, _ := ioutil.ReadAll(resp.Body)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			if tt.status != 200 {
+				return
+			}
+			assert.True(t, strings.HasPrefix(resp.Header.Get("Content-Type"), tt.contentType))
+			assert.Contains(t, resp.Header.Get("Content-Disposition"), "attachment")
+			assert.Contains(t, string(body), tt.body)
+		})
+	}
+}
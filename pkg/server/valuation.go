@@ -0,0 +1,52 @@
+package server
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/money"
+	"github.com/rodrwan/collection/domain/record"
+)
+
+func (srv Server) AddRecordValuationById(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	params := new(struct {
+		Value  money.Money
+		Date   time.Time
+		Source string
+	})
+
+	if err := c.BodyParser(&params); err != nil {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	}
+
+	rec, err := srv.collectionService.AddRecordValuation(id, params.Value, params.Date, params.Source)
+	if err != nil {
+		if err == record.ErrRecordNotFound {
+			return fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return badRequest(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":     true,
+		"record": rec,
+	})
+}
+
+func (srv Server) GetValuation(c *fiber.Ctx) error {
+	report, err := srv.collectionService.Valuation(c.Query("currency"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return c.JSON(fiber.Map{
+		"ok":        true,
+		"valuation": report,
+	})
+}
@@ -0,0 +1,125 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/domain/money"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_Valuation(t *testing.T) {
+	rates, _ := money.NewRates("EUR", time.Time{}, map[string]float64{"USD": 1.25})
+
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithExchangeRates(rates),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Post("/AddRecordValuationById/:id", srv.AddRecordValuationById)
+	app.Get("/GetValuation", srv.GetValuation)
+
+	rec, _ := collectionService.AddRecord(uuid.New(), "r1", "vinyl")
+
+	tests := []struct {
+		description  string
+		route        string
+		method       string
+		data         []byte
+		expectedCode int
+		expectedOk   bool
+	}{
+		{
+			description:  "add valuation",
+			route:        fmt.Sprintf("/AddRecordValuationById/%s", rec.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "value": { "amount": 2000, "currency": "EUR" }, "source": "discogs" }`),
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "add invalid valuation",
+			route:        fmt.Sprintf("/AddRecordValuationById/%s", rec.ID),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "value": { "amount": -1, "currency": "EURO" } }`),
+			expectedCode: 422,
+			expectedOk:   false,
+		},
+		{
+			description:  "add valuation to unknown record",
+			route:        fmt.Sprintf("/AddRecordValuationById/%s", uuid.New()),
+			method:       fiber.MethodPost,
+			data:         []byte(`{ "value": { "amount": 2000, "currency": "EUR" } }`),
+			expectedCode: 404,
+			expectedOk:   false,
+		},
+		{
+			description:  "valuation in dollars",
+			route:        "/GetValuation?currency=USD",
+			method:       fiber.MethodGet,
+			expectedCode: 200,
+			expectedOk:   true,
+		},
+		{
+			description:  "valuation in an invalid currency",
+			route:        "/GetValuation?currency=dollar",
+			method:       fiber.MethodGet,
+			expectedCode: 400,
+			expectedOk:   false,
+		},
+	}
+
+	type response struct {
+		Ok        bool `json:"ok,omitempty"`
+		Valuation *struct {
+			Total money.Money `json:"total"`
+		} `json:"valuation,omitempty"`
+		Error string `json:"error,omitempty"`
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.route, bytes.NewBuffer(test.data))
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, _ := app.Test(req, 1000)
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			var r response
+			if err := json.Unmarshal(body, &r); err != nil {
+				log.Fatal(err)
+			}
+
+			assert.Equalf(t, test.expectedCode, resp.StatusCode, test.description)
+			assert.Equalf(t, test.expectedOk, r.Ok, test.description)
+			if r.Valuation != nil {
+				assert.Equalf(t, money.Money{Amount: 2500, Currency: "USD"}, r.Valuation.Total, test.description)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package server
+
+import (
+	"bytes"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportRecordsXlsx downloads the collection as a spreadsheet with Records,
+// Songs and Summary sheets.
+func (srv Server) ExportRecordsXlsx(c *fiber.Ctx) error {
+	var buf bytes.Buffer
+	if err := srv.collectionService.ExportXLSX(&buf); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="collection.xlsx"`)
+
+	return c.Send(buf.Bytes())
+}
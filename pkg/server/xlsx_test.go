@@ -0,0 +1,56 @@
+package server_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/config"
+	"github.com/rodrwan/collection/pkg/server"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer_ExportRecordsXlsx(t *testing.T) {
+	app := fiber.New(config.NewFiberConfig)
+	collectionService, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv, err := server.NewServer(collectionService)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app.Get("/ExportRecordsXlsx", srv.ExportRecordsXlsx)
+
+	rec, _ := collectionService.AddRecord(uuid.New(), "The Wall", "vinyl")
+	collectionService.AddSongToRecord(rec.ToRecord(), "Hey You", 280)
+
+	req := httptest.NewRequest(fiber.MethodGet, "/ExportRecordsXlsx", nil)
+	resp, _ := app.Test(req, 1000)
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", resp.Header.Get("Content-Type"))
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), "collection.xlsx")
+
+	z, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	assert.Nil(t, err)
+
+	var names []string
+	for _, f := range z.File {
+		names = append(names, f.Name)
+	}
+	assert.Contains(t, names, "xl/worksheets/sheet1.xml")
+	assert.Contains(t, names, "xl/worksheets/sheet3.xml")
+}
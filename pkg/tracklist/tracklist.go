@@ -0,0 +1,204 @@
+// Package tracklist writes lists of songs in the playlist formats audio
+// players read: extended M3U, PLS and XSPF.
+package tracklist
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrInvalidFormat = errors.New("invalid playlist format, use m3u, pls or xspf")
+)
+
+// Format is a playlist file format.
+type Format string
+
+const (
+	FormatM3U  Format = "m3u"
+	FormatPLS  Format = "pls"
+	FormatXSPF Format = "xspf"
+)
+
+// ParseFormat reads a format ignoring case and surrounding spaces. An empty
+// value means FormatM3U, "m3u8" is taken as FormatM3U too since the files
+// are always written in UTF-8.
+func ParseFormat(value string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(value))); f {
+	case "", "m3u8":
+		return FormatM3U, nil
+	case FormatM3U, FormatPLS, FormatXSPF:
+		return f, nil
+	}
+
+	return "", ErrInvalidFormat
+}
+
+// ContentType is the media type files of the format are served with.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatPLS:
+		return "audio/x-scpls"
+	case FormatXSPF:
+		return "application/xspf+xml"
+	}
+	return "audio/x-mpegurl; charset=utf-8"
+}
+
+// Extension is the file name extension of the format, dot included.
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Tracklist is a titled list of songs.
+type Tracklist struct {
+	Title   string
+	Entries []Entry
+}
+
+// Entry is a song of a tracklist. Length is in seconds, 0 when unknown, and
+// Location is a file path or a URL.
+type Entry struct {
+	Title    string
+	Artist   string
+	Album    string
+	TrackNum int
+	Length   int64
+	Location string
+}
+
+// display is how M3U and PLS show an entry, "artist - title".
+func (e Entry) display() string {
+	if e.Artist == "" {
+		return e.Title
+	}
+	return e.Artist + " - " + e.Title
+}
+
+// Write writes the tracklist in the given format.
+func Write(w io.Writer, format Format, list Tracklist) error {
+	switch format {
+	case FormatM3U:
+		return writeM3U(w, list)
+	case FormatPLS:
+		return writePLS(w, list)
+	case FormatXSPF:
+		return writeXSPF(w, list)
+	}
+
+	return ErrInvalidFormat
+}
+
+// writeM3U writes an extended M3U playlist. Lengths unknown are written as
+// -1, as players expect.
+func writeM3U(w io.Writer, list Tracklist) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "#EXTM3U")
+	if list.Title != "" {
+		fmt.Fprintf(bw, "#PLAYLIST:%s\n", oneLine(list.Title))
+	}
+	for _, e := range list.Entries {
+		length := e.Length
+		if length <= 0 {
+			length = -1
+		}
+		fmt.Fprintf(bw, "#EXTINF:%d,%s\n", length, oneLine(e.display()))
+		fmt.Fprintln(bw, oneLine(e.Location))
+	}
+
+	return bw.Flush()
+}
+
+// writePLS writes a PLS version 2 playlist.
+func writePLS(w io.Writer, list Tracklist) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "[playlist]")
+	for i, e := range list.Entries {
+		length := e.Length
+		if length <= 0 {
+			length = -1
+		}
+		fmt.Fprintf(bw, "File%d=%s\n", i+1, oneLine(e.Location))
+		fmt.Fprintf(bw, "Title%d=%s\n", i+1, oneLine(e.display()))
+		fmt.Fprintf(bw, "Length%d=%d\n", i+1, length)
+	}
+	fmt.Fprintf(bw, "NumberOfEntries=%d\n", len(list.Entries))
+	fmt.Fprintln(bw, "Version=2")
+
+	return bw.Flush()
+}
+
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"http://xspf.org/ns/0/ playlist"`
+	Version int         `xml:"version,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location string `xml:"location,omitempty"`
+	Title    string `xml:"title,omitempty"`
+	Creator  string `xml:"creator,omitempty"`
+	Album    string `xml:"album,omitempty"`
+	TrackNum int    `xml:"trackNum,omitempty"`
+	// Duration is in milliseconds.
+	Duration int64 `xml:"duration,omitempty"`
+}
+
+// writeXSPF writes an XSPF playlist. Locations that are file paths are
+// written as relative URIs.
+func writeXSPF(w io.Writer, list Tracklist) error {
+	playlist := xspfPlaylist{
+		Version: 1,
+		Title:   list.Title,
+		Tracks:  make([]xspfTrack, 0, len(list.Entries)),
+	}
+	for _, e := range list.Entries {
+		playlist.Tracks = append(playlist.Tracks, xspfTrack{
+			Location: uri(e.Location),
+			Title:    e.Title,
+			Creator:  e.Artist,
+			Album:    e.Album,
+			TrackNum: e.TrackNum,
+			Duration: e.Length * 1000,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(playlist); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// uri returns URLs as they are and escapes each segment of file paths.
+func uri(location string) string {
+	if u, err := url.Parse(location); err == nil && u.Scheme != "" && u.Host != "" {
+		return location
+	}
+
+	segments := strings.Split(location, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// oneLine keeps a value from breaking the line based formats.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
@@ -0,0 +1,107 @@
+package tracklist_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rodrwan/collection/pkg/tracklist"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    tracklist.Format
+		wantErr error
+	}{
+		{value: "", want: tracklist.FormatM3U},
+		{value: "M3U8", want: tracklist.FormatM3U},
+		{value: " pls ", want: tracklist.FormatPLS},
+		{value: "xspf", want: tracklist.FormatXSPF},
+		{value: "wpl", wantErr: tracklist.ErrInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := tracklist.ParseFormat(tt.value)
+			if err != tt.wantErr || got != tt.want {
+				t.Errorf("ParseFormat() = %v, %v, want %v, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	list := tracklist.Tracklist{
+		Title: "Pink Floyd - The Wall",
+		Entries: []tracklist.Entry{
+			{Title: "In the Flesh?", Artist: "Pink Floyd", Album: "The Wall", TrackNum: 1, Length: 199, Location: "Pink Floyd/The Wall/01 - In the Flesh_.mp3"},
+			{Title: "Demo\nTake", TrackNum: 2, Location: "http://music.local/demo.mp3"},
+		},
+	}
+
+	tests := []struct {
+		format tracklist.Format
+		want   string
+	}{
+		{
+			format: tracklist.FormatM3U,
+			want: "#EXTM3U\n" +
+				"#PLAYLIST:Pink Floyd - The Wall\n" +
+				"#EXTINF:199,Pink Floyd - In the Flesh?\n" +
+				"Pink Floyd/The Wall/01 - In the Flesh_.mp3\n" +
+				"#EXTINF:-1,Demo Take\n" +
+				"http://music.local/demo.mp3\n",
+		},
+		{
+			format: tracklist.FormatPLS,
+			want: "[playlist]\n" +
+				"File1=Pink Floyd/The Wall/01 - In the Flesh_.mp3\n" +
+				"Title1=Pink Floyd - In the Flesh?\n" +
+				"Length1=199\n" +
+				"File2=http://music.local/demo.mp3\n" +
+				"Title2=Demo Take\n" +
+				"Length2=-1\n" +
+				"NumberOfEntries=2\n" +
+				"Version=2\n",
+		},
+		{
+			format: tracklist.FormatXSPF,
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<playlist xmlns="http://xspf.org/ns/0/" version="1">
+  <title>Pink Floyd - The Wall</title>
+  <trackList>
+    <track>
+      <location>Pink%20Floyd/The%20Wall/01%20-%20In%20the%20Flesh_.mp3</location>
+      <title>In the Flesh?</title>
+      <creator>Pink Floyd</creator>
+      <album>The Wall</album>
+      <trackNum>1</trackNum>
+      <duration>199000</duration>
+    </track>
+    <track>
+      <location>http://music.local/demo.mp3</location>
+      <title>Demo&#xA;Take</title>
+      <trackNum>2</trackNum>
+    </track>
+  </trackList>
+</playlist>
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tracklist.Write(&buf, tt.format, list); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Write() =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+
+	if err := tracklist.Write(&bytes.Buffer{}, "wpl", list); err != tracklist.ErrInvalidFormat {
+		t.Errorf("Write() error = %v, want %v", err, tracklist.ErrInvalidFormat)
+	}
+}
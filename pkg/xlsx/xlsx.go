@@ -0,0 +1,395 @@
+// Package xlsx writes Office Open XML workbooks, the .xlsx files of Excel,
+// LibreOffice and Numbers. Only what exports need is supported: sheets of
+// typed cells, a bold frozen header row and column widths fitted to the
+// content. Strings are written inline, so there is no shared string table.
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrNoSheets         = errors.New("workbook has no sheets")
+	ErrInvalidSheetName = errors.New("sheet names must have 1 to 31 characters and none of : \\ / ? * [ ]")
+	ErrDuplicateSheet   = errors.New("sheet name already used")
+)
+
+// maxSheetName is the longest sheet name spreadsheet applications accept.
+const maxSheetName = 31
+
+// epoch is day zero of spreadsheet dates. It is not 1900-01-01 because of
+// the 1900 leap year bug kept from Lotus 1-2-3, which only shifts dates
+// before March 1900.
+var epoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+type cellType int
+
+const (
+	typeEmpty cellType = iota
+	typeString
+	typeNumber
+	typeDate
+	typeDateTime
+	typeDuration
+)
+
+// Styles of styles.xml, by position in cellXfs.
+const (
+	styleDefault = iota
+	styleHeader
+	styleDate
+	styleDateTime
+	styleDuration
+)
+
+// Cell is a typed value of a sheet. The zero Cell is empty.
+type Cell struct {
+	typ    cellType
+	text   string
+	number float64
+	bold   bool
+}
+
+// String is a text cell. The empty string gives an empty cell.
+func String(s string) Cell {
+	return Cell{typ: typeString, text: s}
+}
+
+// Number is a numeric cell.
+func Number(n float64) Cell {
+	return Cell{typ: typeNumber, number: n}
+}
+
+// Int is a numeric cell holding an integer.
+func Int(n int64) Cell {
+	return Number(float64(n))
+}
+
+// Date is a date cell, shown as yyyy-mm-dd. The time of day is dropped and
+// the zero time gives an empty cell.
+func Date(t time.Time) Cell {
+	if t.IsZero() {
+		return Cell{}
+	}
+	y, m, d := t.Date()
+	return Cell{typ: typeDate, number: serial(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))}
+}
+
+// DateTime is a date and time cell, shown as yyyy-mm-dd hh:mm in the
+// location of t. The zero time gives an empty cell.
+func DateTime(t time.Time) Cell {
+	if t.IsZero() {
+		return Cell{}
+	}
+	y, m, d := t.Date()
+	return Cell{typ: typeDateTime, number: serial(time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC))}
+}
+
+// Duration is an elapsed time cell, shown as [h]:mm:ss so that hours do
+// not wrap around at a day.
+func Duration(d time.Duration) Cell {
+	return Cell{typ: typeDuration, number: d.Seconds() / (24 * 60 * 60)}
+}
+
+// serial is the spreadsheet value of a time: days since epoch, with the
+// time of day as the fraction.
+func serial(t time.Time) float64 {
+	return float64(t.Sub(epoch)) / float64(24*time.Hour)
+}
+
+// Workbook is a list of sheets.
+type Workbook struct {
+	sheets []*Sheet
+}
+
+// New returns an empty workbook.
+func New() *Workbook {
+	return &Workbook{}
+}
+
+// Sheet is a named grid of cells filled row by row.
+type Sheet struct {
+	name   string
+	header bool
+	rows   [][]Cell
+}
+
+// AddSheet appends a sheet. Names are unique ignoring case.
+func (wb *Workbook) AddSheet(name string) (*Sheet, error) {
+	if name == "" || utf8.RuneCountInString(name) > maxSheetName || strings.ContainsAny(name, `:\/?*[]`) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSheetName, name)
+	}
+	for _, s := range wb.sheets {
+		if strings.EqualFold(s.name, name) {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateSheet, name)
+		}
+	}
+
+	s := &Sheet{name: name}
+	wb.sheets = append(wb.sheets, s)
+	return s, nil
+}
+
+// Name returns the name of the sheet.
+func (s *Sheet) Name() string {
+	return s.name
+}
+
+// AddHeader appends a row of bold column names that stays in view when
+// scrolling. It must be the first row of the sheet.
+func (s *Sheet) AddHeader(names ...string) {
+	row := make([]Cell, len(names))
+	for i, n := range names {
+		row[i] = Cell{typ: typeString, text: n, bold: true}
+	}
+	s.header = len(s.rows) == 0
+	s.rows = append(s.rows, row)
+}
+
+// AddRow appends a row of cells.
+func (s *Sheet) AddRow(cells ...Cell) {
+	s.rows = append(s.rows, cells)
+}
+
+// Write writes the workbook as an .xlsx file.
+func (wb *Workbook) Write(w io.Writer) error {
+	if len(wb.sheets) == 0 {
+		return ErrNoSheets
+	}
+
+	z := zip.NewWriter(w)
+	parts := []part{
+		{"[Content_Types].xml", wb.contentTypes()},
+		{"_rels/.rels", []byte(rootRels)},
+		{"xl/workbook.xml", wb.workbook()},
+		{"xl/_rels/workbook.xml.rels", wb.workbookRels()},
+		{"xl/styles.xml", []byte(styles)},
+	}
+	for i, s := range wb.sheets {
+		parts = append(parts, part{sheetPath(i), s.xml(i == 0)})
+	}
+
+	now := time.Now()
+	for _, p := range parts {
+		fw, err := z.CreateHeader(&zip.FileHeader{Name: p.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(p.data); err != nil {
+			return err
+		}
+	}
+
+	return z.Close()
+}
+
+// part is a file of the workbook package.
+type part struct {
+	name string
+	data []byte
+}
+
+func sheetPath(i int) string {
+	return fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1)
+}
+
+func (wb *Workbook) contentTypes() []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range wb.sheets {
+		fmt.Fprintf(&b, `<Override PartName="/%s" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, sheetPath(i))
+	}
+	b.WriteString(`</Types>`)
+	return b.Bytes()
+}
+
+func (wb *Workbook) workbook() []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, s := range wb.sheets {
+		b.WriteString(`<sheet name="`)
+		escape(&b, s.name)
+		fmt.Fprintf(&b, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.Bytes()
+}
+
+// workbookRels links the sheets as rId1 to rIdN and the styles after them.
+func (wb *Workbook) workbookRels() []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range wb.sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(wb.sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.Bytes()
+}
+
+func (s *Sheet) xml(selected bool) []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"`)
+	if selected {
+		b.WriteString(` tabSelected="1"`)
+	}
+	if s.header {
+		b.WriteString(`><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView>`)
+	} else {
+		b.WriteString(`/>`)
+	}
+	b.WriteString(`</sheetViews>`)
+
+	if widths := s.widths(); len(widths) > 0 {
+		b.WriteString(`<cols>`)
+		for i, w := range widths {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, w)
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	b.WriteString(`<sheetData>`)
+	for r, row := range s.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			cell.write(&b, column(c)+strconv.Itoa(r+1))
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.Bytes()
+}
+
+// widths fits every column to its longest value, within limits.
+func (s *Sheet) widths() []int {
+	var widths []int
+	for _, row := range s.rows {
+		for c, cell := range row {
+			for len(widths) <= c {
+				widths = append(widths, 8)
+			}
+			if w := cell.width(); w > widths[c] {
+				widths[c] = w
+			}
+		}
+	}
+	return widths
+}
+
+func (c Cell) width() int {
+	switch c.typ {
+	case typeString:
+		n := 0
+		for _, line := range strings.Split(c.text, "\n") {
+			if l := utf8.RuneCountInString(line); l > n {
+				n = l
+			}
+		}
+		if n > 60 {
+			n = 60
+		}
+		return n + 2
+	case typeNumber:
+		return len(formatNumber(c.number)) + 2
+	case typeDate:
+		return 12
+	case typeDateTime:
+		return 18
+	case typeDuration:
+		return 10
+	}
+	return 0
+}
+
+func (c Cell) write(b *bytes.Buffer, ref string) {
+	switch c.typ {
+	case typeString:
+		if c.text == "" {
+			return
+		}
+		fmt.Fprintf(b, `<c r="%s" t="inlineStr"`, ref)
+		if c.bold {
+			fmt.Fprintf(b, ` s="%d"`, styleHeader)
+		}
+		b.WriteString(`><is><t xml:space="preserve">`)
+		escape(b, c.text)
+		b.WriteString(`</t></is></c>`)
+	case typeNumber:
+		fmt.Fprintf(b, `<c r="%s"><v>%s</v></c>`, ref, formatNumber(c.number))
+	case typeDate:
+		fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, styleDate, formatNumber(c.number))
+	case typeDateTime:
+		fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, styleDateTime, formatNumber(c.number))
+	case typeDuration:
+		fmt.Fprintf(b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, styleDuration, formatNumber(c.number))
+	}
+}
+
+// formatNumber writes n as short as it reads back. NaN and infinities have
+// no spreadsheet value and are written as 0.
+func formatNumber(n float64) string {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return "0"
+	}
+	return strconv.FormatFloat(n, 'g', -1, 64)
+}
+
+// column is the letter name of a zero based column: A to Z, then AA.
+func column(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// escape writes s as XML text. Characters XML cannot hold, such as most
+// control characters, are replaced.
+func escape(b *bytes.Buffer, s string) {
+	xml.EscapeText(b, []byte(s))
+}
+
+const rootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// styles holds the cell styles in the order of the style constants.
+const styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="3">` +
+	`<numFmt numFmtId="164" formatCode="yyyy-mm-dd"/>` +
+	`<numFmt numFmtId="165" formatCode="yyyy-mm-dd hh:mm"/>` +
+	`<numFmt numFmtId="166" formatCode="[h]:mm:ss"/>` +
+	`</numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="5">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="166" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
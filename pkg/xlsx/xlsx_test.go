@@ -0,0 +1,168 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/pkg/xlsx"
+)
+
+// unzip returns the files of a workbook, checking that each is well formed
+// XML.
+func unzip(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		d := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			_, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s: %v", f.Name, err)
+			}
+		}
+
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestWorkbook_Write(t *testing.T) {
+	wb := xlsx.New()
+
+	records, err := wb.AddSheet("Records")
+	if err != nil {
+		t.Fatal(err)
+	}
+	records.AddHeader("Name", "Songs", "Length", "Acquired", "Added")
+	records.AddRow(
+		xlsx.String("Tom & Jerry <Live>"),
+		xlsx.Int(2),
+		xlsx.Duration(90*time.Minute),
+		xlsx.Date(time.Date(2021, 3, 1, 18, 30, 0, 0, time.UTC)),
+		xlsx.DateTime(time.Date(1900, 3, 1, 12, 0, 0, 0, time.UTC)),
+	)
+	records.AddRow(xlsx.String("Blue"), xlsx.Cell{}, xlsx.Number(1.5), xlsx.Date(time.Time{}))
+
+	summary, err := wb.AddSheet("Summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary.AddRow(xlsx.String("Total"), xlsx.Duration(25*time.Hour))
+
+	var buf bytes.Buffer
+	if err := wb.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	files := unzip(t, buf.Bytes())
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("workbook has no %s", name)
+		}
+	}
+	if !strings.Contains(files["xl/workbook.xml"], `<sheet name="Summary" sheetId="2" r:id="rId2"/>`) {
+		t.Errorf("xl/workbook.xml = %s", files["xl/workbook.xml"])
+	}
+	if !strings.Contains(files["[Content_Types].xml"], `PartName="/xl/worksheets/sheet2.xml"`) {
+		t.Errorf("[Content_Types].xml = %s", files["[Content_Types].xml"])
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`state="frozen"`,
+		`<c r="A1" t="inlineStr" s="1"><is><t xml:space="preserve">Name</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">Tom &amp; Jerry &lt;Live&gt;</t></is></c>`,
+		`<c r="B2"><v>2</v></c>`,
+		`<c r="C2" s="4"><v>0.0625</v></c>`,
+		`<c r="D2" s="2"><v>44256</v></c>`,
+		`<c r="E2" s="3"><v>61.5</v></c>`,
+		`<row r="3"><c r="A3" t="inlineStr"><is><t xml:space="preserve">Blue</t></is></c><c r="C3"><v>1.5</v></c></row>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet1.xml does not contain %s", want)
+		}
+	}
+
+	second := files["xl/worksheets/sheet2.xml"]
+	if strings.Contains(second, "frozen") || strings.Contains(second, "tabSelected") {
+		t.Errorf("sheet2.xml = %s", second)
+	}
+	if !strings.Contains(second, `<c r="B1" s="4"><v>1.0416666666666667</v></c>`) {
+		t.Errorf("sheet2.xml = %s", second)
+	}
+}
+
+func TestWorkbook_Columns(t *testing.T) {
+	wb := xlsx.New()
+	s, _ := wb.AddSheet("Wide")
+
+	row := make([]xlsx.Cell, 28)
+	for i := range row {
+		row[i] = xlsx.Int(int64(i))
+	}
+	s.AddRow(row...)
+
+	var buf bytes.Buffer
+	if err := wb.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	sheet := unzip(t, buf.Bytes())["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{`<c r="Z1"><v>25</v></c>`, `<c r="AA1"><v>26</v></c>`, `<c r="AB1"><v>27</v></c>`} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet1.xml does not contain %s", want)
+		}
+	}
+}
+
+func TestWorkbook_AddSheet(t *testing.T) {
+	wb := xlsx.New()
+	if _, err := wb.AddSheet("Records"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"", xlsx.ErrInvalidSheetName},
+		{"Songs/Tracks", xlsx.ErrInvalidSheetName},
+		{"[Summary]", xlsx.ErrInvalidSheetName},
+		{strings.Repeat("a", 32), xlsx.ErrInvalidSheetName},
+		{"RECORDS", xlsx.ErrDuplicateSheet},
+	}
+	for _, tt := range tests {
+		if _, err := wb.AddSheet(tt.name); !errors.Is(err, tt.err) {
+			t.Errorf("AddSheet(%q) error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+
+	if err := xlsx.New().Write(ioutil.Discard); err != xlsx.ErrNoSheets {
+		t.Errorf("Write() error = %v, want %v", err, xlsx.ErrNoSheets)
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/artwork"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/pkg/blob"
+	"github.com/rodrwan/collection/pkg/blob/local"
+)
+
+var (
+	ErrNoBlobStore = errors.New("no blob store configured")
+)
+
+// WithBlobStore keeps cover art in the given store. baseURL is prepended to
+// blob keys to build the image URLs of PublicRecord.
+func WithBlobStore(store blob.Store, baseURL string) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		os.blobs = store
+		os.blobURL = strings.TrimSuffix(baseURL, "/")
+		return nil
+	}
+}
+
+// WithLocalBlobStore keeps cover art as files below dir.
+func WithLocalBlobStore(dir, baseURL string) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		store, err := local.New(dir)
+		if err != nil {
+			return err
+		}
+
+		return WithBlobStore(store, baseURL)(os)
+	}
+}
+
+// UploadRecordCover validates an uploaded image, stores it with its
+// thumbnails and makes it the record cover. The previous cover, if any, is
+// removed once the new one is in place, and the new renditions are removed
+// when the record cannot be updated.
+func (cs *CollectionService) UploadRecordCover(id uuid.UUID, data []byte) (record.PublicRecord, error) {
+	if cs.blobs == nil {
+		return record.PublicRecord{}, ErrNoBlobStore
+	}
+
+	rec, err := cs.records.Get(id)
+	if err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	renditions, err := artwork.Process(data)
+	if err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	now := time.Now().UTC()
+	// a new key per upload so cached images are never served stale
+	cover := record.Cover{
+		Key:         "covers/" + id.String() + "/" + strconv.FormatInt(now.UnixNano(), 36),
+		ContentType: renditions[0].ContentType,
+		UpdatedAt:   now,
+	}
+
+	var stored []string
+	for _, r := range renditions {
+		key := cover.RenditionKey(r.Name)
+		if err := cs.blobs.Put(key, r.ContentType, bytes.NewReader(r.Data)); err != nil {
+			cs.deleteBlobs(stored)
+			return record.PublicRecord{}, err
+		}
+		stored = append(stored, key)
+	}
+
+	previous := rec.GetCover()
+	rec.SetCover(cover)
+	if err := cs.records.Update(&rec); err != nil {
+		cs.deleteBlobs(stored)
+		return record.PublicRecord{}, err
+	}
+
+	if !previous.IsZero() {
+		for _, key := range previous.RenditionKeys() {
+			// leftovers only waste space, the record already points elsewhere
+			cs.blobs.Delete(key)
+		}
+	}
+
+	records, err := cs.withRecordDetails([]record.PublicRecord{rec.ToPublic()})
+	if err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	return records[0], nil
+}
+
+// deleteBlobs removes the blobs of an upload that did not go through. The
+// record never pointed at them, so failures only leave unused files.
+func (cs *CollectionService) deleteBlobs(keys []string) {
+	for _, key := range keys {
+		cs.blobs.Delete(key)
+	}
+}
+
+// OpenBlob opens a stored file, such as a cover art rendition.
+func (cs *CollectionService) OpenBlob(key string) (blob.Object, error) {
+	if cs.blobs == nil {
+		return blob.Object{}, ErrNoBlobStore
+	}
+
+	return cs.blobs.Get(key)
+}
+
+func (cs *CollectionService) withRecordImages(records []record.PublicRecord) ([]record.PublicRecord, error) {
+	if cs.blobs == nil {
+		return records, nil
+	}
+
+	for i := range records {
+		cover := records[i].Cover
+		if cover == nil {
+			continue
+		}
+
+		images := make(map[string]string)
+		for name, key := range cover.RenditionKeys() {
+			images[name] = cs.blobURL + "/" + key
+		}
+		records[i].Images = images
+	}
+
+	return records, nil
+}
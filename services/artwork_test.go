@@ -0,0 +1,118 @@
+package services_test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/artwork"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/pkg/blob"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCover(w, h int) []byte {
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h)))
+	return buf.Bytes()
+}
+
+// failingStore accepts a number of blobs, then fails every other upload.
+type failingStore struct {
+	accept int
+	blobs  map[string]bool
+}
+
+func (fs *failingStore) Put(key, contentType string, body io.Reader) error {
+	if len(fs.blobs) >= fs.accept {
+		return errors.New("disk full")
+	}
+	fs.blobs[key] = true
+	return nil
+}
+
+func (fs *failingStore) Get(key string) (blob.Object, error) {
+	return blob.Object{}, blob.ErrNotFound
+}
+
+func (fs *failingStore) Delete(key string) error {
+	delete(fs.blobs, key)
+	return nil
+}
+
+func TestCollectionService_UploadRecordCoverFailure(t *testing.T) {
+	store := &failingStore{accept: 2, blobs: make(map[string]bool)}
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithBlobStore(store, "/api/blobs"),
+	)
+
+	rec, _ := cs.AddRecord(uuid.New(), "r1", "vinyl")
+	_, err := cs.UploadRecordCover(rec.ID, testCover(800, 800))
+	assert.NotNil(t, err)
+	assert.Empty(t, store.blobs)
+
+	found, _ := cs.FindRecord(rec.ID.String())
+	assert.Nil(t, found.Cover)
+}
+
+func TestCollectionService_UploadRecordCover(t *testing.T) {
+	dir, err := ioutil.TempDir("", "covers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cs, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithLocalBlobStore(dir, "/api/blobs/"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec, _ := cs.AddRecord(uuid.New(), "r1", "vinyl")
+
+	first, err := cs.UploadRecordCover(rec.ID, testCover(800, 800))
+	assert.Nil(t, err)
+	assert.NotNil(t, first.Cover)
+	assert.Equal(t, artwork.TypePNG, first.Cover.ContentType)
+	assert.Len(t, first.Images, len(artwork.Sizes)+1)
+	for _, name := range []string{artwork.Original, "small", "medium"} {
+		key := first.Cover.RenditionKey(name)
+		assert.Equal(t, "/api/blobs/"+key, first.Images[name])
+
+		obj, err := cs.OpenBlob(key)
+		assert.Nil(t, err)
+		obj.Body.Close()
+	}
+
+	found, _ := cs.FindRecord(rec.ID.String())
+	assert.Equal(t, first.Images, found.Images)
+
+	second, err := cs.UploadRecordCover(rec.ID, testCover(300, 200))
+	assert.Nil(t, err)
+	assert.NotEqual(t, first.Cover.Key, second.Cover.Key)
+
+	// the previous renditions are gone
+	_, err = cs.OpenBlob(first.Cover.RenditionKey(artwork.Original))
+	assert.Equal(t, blob.ErrNotFound, err)
+
+	_, err = cs.UploadRecordCover(rec.ID, []byte("not an image"))
+	assert.Equal(t, artwork.ErrUnsupportedType, err)
+
+	_, err = cs.UploadRecordCover(uuid.New(), testCover(10, 10))
+	assert.Equal(t, record.ErrRecordNotFound, err)
+
+	others, _ := services.NewCollectionService(services.WithRecordMemoryRepository())
+	_, err = others.UploadRecordCover(rec.ID, testCover(10, 10))
+	assert.Equal(t, services.ErrNoBlobStore, err)
+}
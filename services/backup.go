@@ -0,0 +1,604 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/loan"
+	"github.com/rodrwan/collection/domain/location"
+	"github.com/rodrwan/collection/domain/match"
+	"github.com/rodrwan/collection/domain/play"
+	"github.com/rodrwan/collection/domain/playlist"
+	"github.com/rodrwan/collection/domain/rating"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/release"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/domain/taxonomy"
+	"github.com/rodrwan/collection/pkg/backup"
+)
+
+var (
+	ErrCollectionNotEmpty = errors.New("the collection is not empty, restore needs an empty collection")
+	ErrBackupUnsupported  = errors.New("the collection cannot store a section of the backup")
+	ErrBackupInvalid      = errors.New("invalid backup")
+)
+
+// Sections of a collection backup.
+const (
+	BackupGenres    = "genres"
+	BackupLocations = "locations"
+	BackupRecords   = "records"
+	BackupSongs     = "songs"
+	BackupPlaylists = "playlists"
+	BackupReleases  = "releases"
+	BackupLoans     = "loans"
+	BackupPlays     = "plays"
+	BackupReviews   = "reviews"
+	BackupMatches   = "matches"
+)
+
+// collectionBackup is the content of a backup, the public form of every
+// aggregate. Cover images are not part of it, records keep the key of
+// their cover in the blob store.
+type collectionBackup struct {
+	genres    []taxonomy.PublicGenre
+	locations []location.PublicLocation
+	records   []record.PublicRecord
+	songs     []song.PublicSong
+	playlists []playlist.PublicPlaylist
+	releases  []release.PublicRelease
+	loans     []loan.PublicLoan
+	plays     []play.PublicPlay
+	reviews   []rating.PublicReview
+	matches   []match.Review
+}
+
+// Backup writes the whole collection to w as a backup archive and returns
+// its manifest. Sections whose repository is not configured are left out.
+// On postgres the collection is read in one snapshot, so the archive is
+// consistent even while it is being written to.
+func (cs *CollectionService) Backup(w io.Writer) (backup.Manifest, error) {
+	var b collectionBackup
+	err := cs.inSnapshot(func(tx *CollectionService) error {
+		var err error
+		b, err = tx.readBackup()
+		return err
+	})
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+
+	out := backup.NewWriter(w, time.Now())
+	add := func(name string, items interface{}) {
+		if err == nil {
+			err = out.Add(name, items)
+		}
+	}
+
+	if cs.genres != nil {
+		add(BackupGenres, b.genres)
+	}
+	if cs.locations != nil {
+		add(BackupLocations, b.locations)
+	}
+	add(BackupRecords, b.records)
+	add(BackupSongs, b.songs)
+	if cs.playlists != nil {
+		add(BackupPlaylists, b.playlists)
+	}
+	if cs.releases != nil {
+		add(BackupReleases, b.releases)
+	}
+	if cs.loans != nil {
+		add(BackupLoans, b.loans)
+	}
+	if cs.plays != nil {
+		add(BackupPlays, b.plays)
+	}
+	if cs.reviews != nil {
+		add(BackupReviews, b.reviews)
+	}
+	if cs.matches != nil {
+		add(BackupMatches, b.matches)
+	}
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+
+	return out.Close()
+}
+
+// readBackup reads every section of the collection whose repository is
+// configured.
+func (cs *CollectionService) readBackup() (collectionBackup, error) {
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return collectionBackup{}, err
+	}
+
+	b := collectionBackup{
+		records: make([]record.PublicRecord, 0, len(records)),
+		songs:   make([]song.PublicSong, 0),
+	}
+	for _, r := range records {
+		b.records = append(b.records, r.ToPublic())
+
+		songs, err := cs.songs.FindSongsByRecord(r.GetID())
+		if err != nil {
+			return collectionBackup{}, err
+		}
+		for _, s := range songs {
+			b.songs = append(b.songs, s.ToPublic())
+		}
+	}
+
+	if cs.genres != nil {
+		genres, err := cs.genres.FindGenres()
+		if err != nil {
+			return collectionBackup{}, err
+		}
+		b.genres = taxonomy.ToPublicArray(genres)
+	}
+	if cs.locations != nil {
+		locations, err := cs.locations.FindLocations()
+		if err != nil {
+			return collectionBackup{}, err
+		}
+		b.locations = location.ToPublicArray(locations)
+	}
+	if cs.playlists != nil {
+		playlists, err := cs.playlists.FindPlaylists()
+		if err != nil {
+			return collectionBackup{}, err
+		}
+		b.playlists = playlist.ToPublicArray(playlists)
+	}
+	if cs.releases != nil {
+		releases, err := cs.releases.FindReleases()
+		if err != nil {
+			return collectionBackup{}, err
+		}
+		b.releases = make([]release.PublicRelease, 0, len(releases))
+		for _, r := range releases {
+			b.releases = append(b.releases, r.ToPublic())
+		}
+	}
+	if cs.loans != nil {
+		loans, err := cs.loans.FindLoans()
+		if err != nil {
+			return collectionBackup{}, err
+		}
+		b.loans = loan.ToPublicArray(loans)
+	}
+	if cs.plays != nil {
+		plays, err := cs.plays.FindPlays(time.Time{}, time.Time{})
+		if err != nil {
+			return collectionBackup{}, err
+		}
+		b.plays = play.ToPublicArray(plays)
+	}
+	if cs.reviews != nil {
+		var reviews []rating.Review
+		for _, subject := range []rating.Subject{rating.SubjectRecord, rating.SubjectSong} {
+			rr, err := cs.reviews.FindReviews(subject)
+			if err != nil {
+				return collectionBackup{}, err
+			}
+			reviews = append(reviews, rr...)
+		}
+		b.reviews = rating.ToPublicArray(reviews)
+	}
+	if cs.matches != nil {
+		matches, err := cs.matches.FindReviews()
+		if err != nil {
+			return collectionBackup{}, err
+		}
+		b.matches = append(make([]match.Review, 0, len(matches)), matches...)
+	}
+
+	return b, nil
+}
+
+// Restore reads a backup archive into the collection, which must be empty
+// in every section, and returns its manifest. The archive is verified and
+// every item validated before anything is stored, all in one transaction
+// on postgres.
+func (cs *CollectionService) Restore(r io.Reader) (backup.Manifest, error) {
+	archive, err := backup.Read(r)
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+
+	var b collectionBackup
+	for name, v := range map[string]interface{}{
+		BackupGenres:    &b.genres,
+		BackupLocations: &b.locations,
+		BackupRecords:   &b.records,
+		BackupSongs:     &b.songs,
+		BackupPlaylists: &b.playlists,
+		BackupReleases:  &b.releases,
+		BackupLoans:     &b.loans,
+		BackupPlays:     &b.plays,
+		BackupReviews:   &b.reviews,
+		BackupMatches:   &b.matches,
+	} {
+		if err := archive.Decode(name, v); err != nil {
+			return backup.Manifest{}, fmt.Errorf("%w: %v", ErrBackupInvalid, err)
+		}
+	}
+
+	for name, missing := range map[string]bool{
+		BackupGenres:    len(b.genres) > 0 && cs.genres == nil,
+		BackupLocations: len(b.locations) > 0 && cs.locations == nil,
+		BackupPlaylists: len(b.playlists) > 0 && cs.playlists == nil,
+		BackupReleases:  len(b.releases) > 0 && cs.releases == nil,
+		BackupLoans:     len(b.loans) > 0 && cs.loans == nil,
+		BackupPlays:     len(b.plays) > 0 && cs.plays == nil,
+		BackupReviews:   len(b.reviews) > 0 && cs.reviews == nil,
+		BackupMatches:   len(b.matches) > 0 && cs.matches == nil,
+	} {
+		if missing {
+			return backup.Manifest{}, fmt.Errorf("%w: %s", ErrBackupUnsupported, name)
+		}
+	}
+
+	restored, err := b.restore()
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+
+	empty, err := cs.isEmpty()
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+	if !empty {
+		return backup.Manifest{}, ErrCollectionNotEmpty
+	}
+
+	err = cs.inTransaction(func(tx *CollectionService) error {
+		return tx.storeBackup(restored)
+	})
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+
+	return archive.Manifest, nil
+}
+
+// restoredBackup is a backup turned back into aggregates, genres and
+// locations ordered parents first.
+type restoredBackup struct {
+	genres    []taxonomy.Genre
+	locations []location.Location
+	records   []record.Record
+	songs     []song.Song
+	playlists []playlist.Playlist
+	releases  []release.Release
+	loans     []loan.Loan
+	plays     []play.Play
+	reviews   []rating.Review
+	matches   []match.Review
+}
+
+// restore validates the items of a backup and turns them into aggregates.
+// Songs must belong to a record of the backup.
+func (b collectionBackup) restore() (restoredBackup, error) {
+	var out restoredBackup
+	invalid := func(section string, id uuid.UUID, err error) error {
+		return fmt.Errorf("%w: %s %s: %v", ErrBackupInvalid, section, id, err)
+	}
+
+	for _, pg := range b.genres {
+		g, err := taxonomy.NewGenreWithID(pg.ID, pg.Name, uuidOrNil(pg.ParentID))
+		if err != nil {
+			return restoredBackup{}, invalid(BackupGenres, pg.ID, err)
+		}
+		out.genres = append(out.genres, g)
+	}
+	out.genres = genresParentsFirst(out.genres)
+
+	for _, pl := range b.locations {
+		l, err := location.NewLocationWithID(pl.ID, pl.Name, pl.Kind, uuidOrNil(pl.ParentID), pl.Position)
+		if err != nil {
+			return restoredBackup{}, invalid(BackupLocations, pl.ID, err)
+		}
+		out.locations = append(out.locations, l)
+	}
+	out.locations = locationsParentsFirst(out.locations)
+
+	records := make(map[uuid.UUID]bool, len(b.records))
+	for i := range b.records {
+		pr := &b.records[i]
+		if _, err := record.NewRecordWithID(pr.ID, pr.Name, pr.Kind); err != nil {
+			return restoredBackup{}, invalid(BackupRecords, pr.ID, err)
+		}
+		if records[pr.ID] {
+			return restoredBackup{}, invalid(BackupRecords, pr.ID, errors.New("duplicate record"))
+		}
+		records[pr.ID] = true
+		out.records = append(out.records, *pr.ToRecord())
+	}
+
+	for _, ps := range b.songs {
+		s, err := song.NewSongWithID(ps.ID, ps.Name, ps.Length, ps.RecordID)
+		if err != nil {
+			return restoredBackup{}, invalid(BackupSongs, ps.ID, err)
+		}
+		if !records[ps.RecordID] {
+			return restoredBackup{}, invalid(BackupSongs, ps.ID, record.ErrRecordNotFound)
+		}
+		s.SetTags(ps.Tags)
+		s.SetTrack(ps.Track)
+		s.SetMusicBrainzID(ps.MusicBrainzID)
+		s.SetPath(ps.Path)
+		s.SetArtist(ps.Artist)
+		s.SetITunesID(ps.ITunesID)
+		s.SetITunesPlays(ps.ITunesPlays)
+		out.songs = append(out.songs, s)
+	}
+
+	for _, pp := range b.playlists {
+		p, err := playlist.NewPlaylistWithID(pp.ID, pp.Name, pp.Duplicates)
+		if err != nil {
+			return restoredBackup{}, invalid(BackupPlaylists, pp.ID, err)
+		}
+		p.SetSongIDs(pp.SongIDs)
+		out.playlists = append(out.playlists, p)
+	}
+
+	for _, pr := range b.releases {
+		r, err := release.NewReleaseWithID(pr.ID, pr.Name)
+		if err != nil {
+			return restoredBackup{}, invalid(BackupReleases, pr.ID, err)
+		}
+		variants := make([]release.Variant, 0, len(pr.Variants))
+		for _, v := range pr.Variants {
+			variants = append(variants, v.Variant)
+		}
+		r.SetVariants(variants)
+		out.releases = append(out.releases, r)
+	}
+
+	for _, pl := range b.loans {
+		l, err := loan.NewLoanWithID(pl.ID, pl.RecordID, pl.Borrower, pl.LentAt, timeOrZero(pl.DueAt))
+		if err != nil {
+			return restoredBackup{}, invalid(BackupLoans, pl.ID, err)
+		}
+		l.SetReturnedAt(timeOrZero(pl.ReturnedAt))
+		out.loans = append(out.loans, l)
+	}
+
+	for _, pp := range b.plays {
+		p, err := play.NewPlayWithID(pp.ID, pp.RecordID, uuidOrNil(pp.SongID), pp.PlayedAt)
+		if err != nil {
+			return restoredBackup{}, invalid(BackupPlays, pp.ID, err)
+		}
+		out.plays = append(out.plays, p)
+	}
+
+	for _, pr := range b.reviews {
+		r, err := rating.NewReviewWithID(pr.ID, pr.User, pr.Subject, pr.SubjectID, pr.Stars, pr.Text, pr.UpdatedAt)
+		if err != nil {
+			return restoredBackup{}, invalid(BackupReviews, pr.ID, err)
+		}
+		out.reviews = append(out.reviews, r)
+	}
+
+	for _, m := range b.matches {
+		if !records[m.RecordID] {
+			return restoredBackup{}, invalid(BackupMatches, m.RecordID, record.ErrRecordNotFound)
+		}
+		out.matches = append(out.matches, m)
+	}
+
+	return out, nil
+}
+
+// isEmpty reports whether every section a backup restores is empty.
+func (cs *CollectionService) isEmpty() (bool, error) {
+	counts := []func() (int, error){
+		func() (int, error) {
+			rr, err := cs.records.FindRecords()
+			return len(rr), err
+		},
+		func() (int, error) {
+			ss, err := cs.songs.FindSongs()
+			return len(ss), err
+		},
+	}
+	if cs.genres != nil {
+		counts = append(counts, func() (int, error) {
+			gg, err := cs.genres.FindGenres()
+			return len(gg), err
+		})
+	}
+	if cs.locations != nil {
+		counts = append(counts, func() (int, error) {
+			ll, err := cs.locations.FindLocations()
+			return len(ll), err
+		})
+	}
+	if cs.playlists != nil {
+		counts = append(counts, func() (int, error) {
+			pp, err := cs.playlists.FindPlaylists()
+			return len(pp), err
+		})
+	}
+	if cs.releases != nil {
+		counts = append(counts, func() (int, error) {
+			rr, err := cs.releases.FindReleases()
+			return len(rr), err
+		})
+	}
+	if cs.loans != nil {
+		counts = append(counts, func() (int, error) {
+			ll, err := cs.loans.FindLoans()
+			return len(ll), err
+		})
+	}
+	if cs.plays != nil {
+		counts = append(counts, func() (int, error) {
+			pp, err := cs.plays.FindPlays(time.Time{}, time.Time{})
+			return len(pp), err
+		})
+	}
+	if cs.reviews != nil {
+		for _, subject := range []rating.Subject{rating.SubjectRecord, rating.SubjectSong} {
+			subject := subject
+			counts = append(counts, func() (int, error) {
+				rr, err := cs.reviews.FindReviews(subject)
+				return len(rr), err
+			})
+		}
+	}
+	if cs.matches != nil {
+		counts = append(counts, func() (int, error) {
+			mm, err := cs.matches.FindReviews()
+			return len(mm), err
+		})
+	}
+
+	for _, count := range counts {
+		n, err := count()
+		if err != nil {
+			return false, err
+		}
+		if n > 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// storeBackup stores a restored backup, records, songs and plays in
+// batches and the aggregates referring to them after. Restore runs it in a
+// transaction so a failure stores nothing.
+func (cs *CollectionService) storeBackup(b restoredBackup) error {
+	for _, g := range b.genres {
+		if err := cs.genres.Add(g); err != nil {
+			return err
+		}
+	}
+	for _, l := range b.locations {
+		if err := cs.locations.Add(l); err != nil {
+			return err
+		}
+	}
+
+	if err := cs.records.AddBatch(b.records); err != nil {
+		return err
+	}
+	if err := cs.songs.AddBatch(b.songs); err != nil {
+		return err
+	}
+
+	for _, p := range b.playlists {
+		if err := cs.playlists.Add(p); err != nil {
+			return err
+		}
+	}
+	for _, r := range b.releases {
+		if err := cs.releases.Add(r); err != nil {
+			return err
+		}
+	}
+	for _, l := range b.loans {
+		if err := cs.loans.Add(l); err != nil {
+			return err
+		}
+	}
+	if len(b.plays) > 0 {
+		if err := cs.plays.AddBatch(b.plays); err != nil {
+			return err
+		}
+	}
+	for _, r := range b.reviews {
+		if err := cs.reviews.Save(r); err != nil {
+			return err
+		}
+	}
+	for _, m := range b.matches {
+		if err := cs.matches.Save(m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// genresParentsFirst orders genres so that every parent comes before its
+// children, keeping the order otherwise.
+func genresParentsFirst(genres []taxonomy.Genre) []taxonomy.Genre {
+	order := parentsFirst(len(genres), func(i int) (uuid.UUID, uuid.UUID) {
+		return genres[i].GetID(), genres[i].GetParentID()
+	})
+
+	out := make([]taxonomy.Genre, 0, len(genres))
+	for _, i := range order {
+		out = append(out, genres[i])
+	}
+	return out
+}
+
+// locationsParentsFirst orders locations so that every parent comes before
+// its children, keeping the order otherwise.
+func locationsParentsFirst(locations []location.Location) []location.Location {
+	order := parentsFirst(len(locations), func(i int) (uuid.UUID, uuid.UUID) {
+		return locations[i].GetID(), locations[i].GetParentID()
+	})
+
+	out := make([]location.Location, 0, len(locations))
+	for _, i := range order {
+		out = append(out, locations[i])
+	}
+	return out
+}
+
+// parentsFirst returns the indexes of n items of a tree ordered parents
+// first. Items whose parent is not among them are roots.
+func parentsFirst(n int, node func(i int) (id, parentID uuid.UUID)) []int {
+	index := make(map[uuid.UUID]int, n)
+	for i := 0; i < n; i++ {
+		id, _ := node(i)
+		index[id] = i
+	}
+
+	order := make([]int, 0, n)
+	done := make([]bool, n)
+	var visit func(i int)
+	visit = func(i int) {
+		if done[i] {
+			return
+		}
+		done[i] = true
+		if _, parentID := node(i); parentID != uuid.Nil {
+			if p, ok := index[parentID]; ok {
+				visit(p)
+			}
+		}
+		order = append(order, i)
+	}
+	for i := 0; i < n; i++ {
+		visit(i)
+	}
+
+	return order
+}
+
+func uuidOrNil(id *uuid.UUID) uuid.UUID {
+	if id == nil {
+		return uuid.Nil
+	}
+	return *id
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
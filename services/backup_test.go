@@ -0,0 +1,172 @@
+package services_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/location"
+	"github.com/rodrwan/collection/domain/playlist"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/pkg/backup"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFullCollectionService(t *testing.T) *services.CollectionService {
+	t.Helper()
+
+	cs, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithGenreMemoryRepository(),
+		services.WithLoanMemoryRepository(),
+		services.WithLocationMemoryRepository(),
+		services.WithPlaylistMemoryRepository(),
+		services.WithPlayMemoryRepository(),
+		services.WithReviewMemoryRepository(),
+		services.WithReleaseMemoryRepository(),
+		services.WithMatchReviewMemoryRepository(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cs
+}
+
+func TestCollectionService_BackupRestore(t *testing.T) {
+	src := newFullCollectionService(t)
+
+	rock, err := src.AddGenre("Rock", uuid.Nil)
+	assert.Nil(t, err)
+	_, err = src.AddGenre("Soft Rock", rock.ID)
+	assert.Nil(t, err)
+
+	room, _ := src.AddLocation("Living room", location.KindRoom, uuid.Nil, 0)
+	shelf, err := src.AddLocation("Shelf", location.KindShelf, room.ID, 1)
+	assert.Nil(t, err)
+
+	rumours, _ := src.AddRecord(uuid.New(), "Rumours", "vinyl")
+	tusk, _ := src.AddRecord(uuid.New(), "Tusk", "mp3")
+	assert.Nil(t, src.AddSongToRecord(rumours.ToRecord(), "Dreams", 257))
+	assert.Nil(t, src.AddSongToRecord(rumours.ToRecord(), "Go Your Own Way", 223))
+	assert.Nil(t, src.AddSongToRecord(tusk.ToRecord(), "Sara", 385))
+	hits, _ := src.AddRecord(uuid.New(), "Greatest Hits", "mp3")
+
+	_, err = src.TagRecord(rumours.ID, rock.ID, false, []string{"Classic"})
+	assert.Nil(t, err)
+	_, err = src.AssignRecordLocation(rumours.ID, shelf.ID, 3)
+	assert.Nil(t, err)
+
+	songs, _ := src.FindSongsByRecord(rumours.ID)
+	_, err = src.CreatePlaylist("Favourites", playlist.DuplicatesReject, []uuid.UUID{songs[1].ID, songs[0].ID})
+	assert.Nil(t, err)
+	_, err = src.CreateRelease("Rumours", rumours.ID)
+	assert.Nil(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err = src.LendRecord(tusk.ID, "Percy", now.AddDate(0, 0, -3), time.Time{})
+	assert.Nil(t, err)
+	_, err = src.LogPlay(services.PlayInput{RecordID: rumours.ID, SongID: songs[0].ID, PlayedAt: now})
+	assert.Nil(t, err)
+	_, err = src.RateRecord(rumours.ID, "rodrigo", 4.5, "A classic")
+	assert.Nil(t, err)
+	enrichment, err := src.EnrichFromMusicBrainz(strings.NewReader(musicBrainzDump), false)
+	assert.Nil(t, err)
+	assert.Len(t, enrichment.Review, 1)
+	assert.Equal(t, hits.ID, enrichment.Review[0].RecordID)
+
+	var archive bytes.Buffer
+	manifest, err := src.Backup(&archive)
+	assert.Nil(t, err)
+	assert.Equal(t, backup.Version, manifest.Version)
+	assert.Equal(t, 3, manifest.Count(services.BackupRecords))
+	assert.Equal(t, 3, manifest.Count(services.BackupSongs))
+	assert.Equal(t, 2, manifest.Count(services.BackupGenres))
+	assert.Equal(t, 1, manifest.Count(services.BackupMatches))
+
+	dst := newFullCollectionService(t)
+	restored, err := dst.Restore(bytes.NewReader(archive.Bytes()))
+	assert.Nil(t, err)
+	assert.Equal(t, manifest, restored)
+
+	want, _ := src.FindRecord(rumours.ID.String())
+	got, err := dst.FindRecord(rumours.ID.String())
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+
+	wantSongs, _ := src.FindSongsByRecord(rumours.ID)
+	gotSongs, _ := dst.FindSongsByRecord(rumours.ID)
+	assert.Equal(t, wantSongs, gotSongs)
+
+	for _, check := range []func(cs *services.CollectionService) (interface{}, error){
+		func(cs *services.CollectionService) (interface{}, error) { return cs.FindAllGenres() },
+		func(cs *services.CollectionService) (interface{}, error) { return cs.FindAllLocations() },
+		func(cs *services.CollectionService) (interface{}, error) { return cs.FindAllPlaylists() },
+		func(cs *services.CollectionService) (interface{}, error) { return cs.FindAllReleases() },
+		func(cs *services.CollectionService) (interface{}, error) { return cs.FindCurrentLoans() },
+		func(cs *services.CollectionService) (interface{}, error) { return cs.FindRecordReviews(rumours.ID) },
+		func(cs *services.CollectionService) (interface{}, error) { return cs.FindMusicBrainzReviews() },
+	} {
+		want, err := check(src)
+		assert.Nil(t, err)
+		got, err := check(dst)
+		assert.Nil(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err = dst.Restore(bytes.NewReader(archive.Bytes()))
+	assert.Equal(t, services.ErrCollectionNotEmpty, err)
+
+	// a collection with no records but other items is not empty either
+	genres := newFullCollectionService(t)
+	_, err = genres.AddGenre("Jazz", uuid.Nil)
+	assert.Nil(t, err)
+	_, err = genres.Restore(bytes.NewReader(archive.Bytes()))
+	assert.Equal(t, services.ErrCollectionNotEmpty, err)
+}
+
+func TestCollectionService_Restore_Invalid(t *testing.T) {
+	src, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithLoanMemoryRepository(),
+	)
+	rec, _ := src.AddRecord(uuid.New(), "Rumours", "vinyl")
+	_, err := src.LendRecord(rec.ID, "Percy", time.Time{}, time.Time{})
+	assert.Nil(t, err)
+
+	var archive bytes.Buffer
+	_, err = src.Backup(&archive)
+	assert.Nil(t, err)
+
+	// A collection without loans cannot take the backup.
+	dst, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+	_, err = dst.Restore(bytes.NewReader(archive.Bytes()))
+	assert.True(t, errors.Is(err, services.ErrBackupUnsupported), err)
+
+	// Songs must belong to a record of the backup.
+	var orphan bytes.Buffer
+	w := backup.NewWriter(&orphan, time.Now())
+	assert.Nil(t, w.Add(services.BackupRecords, []record.PublicRecord{}))
+	assert.Nil(t, w.Add(services.BackupSongs, []map[string]interface{}{
+		{"id": uuid.New(), "name": "Dreams", "length": 257, "recordId": uuid.New()},
+	}))
+	_, err = w.Close()
+	assert.Nil(t, err)
+
+	_, err = dst.Restore(&orphan)
+	assert.True(t, errors.Is(err, services.ErrBackupInvalid), err)
+
+	records, _ := dst.FindAllRecord()
+	assert.Equal(t, 0, len(records))
+
+	_, err = dst.Restore(bytes.NewReader([]byte("not a backup")))
+	assert.Equal(t, backup.ErrNotABackup, err)
+}
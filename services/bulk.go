@@ -0,0 +1,227 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+// DefaultBulkBatchSize is how many records a bulk import writes at once.
+const DefaultBulkBatchSize = 100
+
+// MaxBulkLineSize bounds a line of a bulk import, a record with its songs.
+const MaxBulkLineSize = 1 << 20
+
+var (
+	ErrDuplicateRecordID = errors.New("record id already used on another line")
+)
+
+// BulkSong is a song of a bulk import line. Path is where its file is,
+// relative to the music library, when known.
+type BulkSong struct {
+	Name   string   `json:"name"`
+	Length int64    `json:"length"`
+	Tags   []string `json:"tags"`
+	Path   string   `json:"path"`
+}
+
+// BulkRecord is a line of a bulk import. The id is optional and generated
+// when missing.
+type BulkRecord struct {
+	ID          uuid.UUID           `json:"id"`
+	Name        string              `json:"name"`
+	Artist      string              `json:"artist"`
+	Kind        string              `json:"kind"`
+	Status      string              `json:"status"`
+	Tags        []string            `json:"tags"`
+	Acquisition *record.Acquisition `json:"acquisition"`
+	Songs       []BulkSong          `json:"songs"`
+}
+
+// BulkResult tells what became of a line of a bulk import: the ids of its
+// record and songs, or why it was not imported.
+type BulkResult struct {
+	Line   int               `json:"line"`
+	OK     bool              `json:"ok"`
+	ID     *uuid.UUID        `json:"id,omitempty"`
+	Songs  []uuid.UUID       `json:"songs,omitempty"`
+	Error  string            `json:"error,omitempty"`
+	Errors validation.Errors `json:"errors,omitempty"`
+}
+
+func (br *BulkResult) fail(err error) {
+	br.OK = false
+	br.ID = nil
+	br.Songs = nil
+	br.Error = err.Error()
+	var errs validation.Errors
+	if errors.As(err, &errs) {
+		br.Errors = errs
+	}
+}
+
+// bulkBatch holds the lines read since the last write, in order.
+type bulkBatch struct {
+	results []BulkResult
+	records []record.Record
+	songs   []song.Song
+}
+
+// ImportNDJSON creates the records and songs of a stream of newline
+// delimited JSON, one BulkRecord per line. Lines are read one at a time and
+// written batchSize records at once. After every write the results of the
+// lines read so far are handed to flush, in order, so they can be streamed
+// back. Invalid lines are reported and skipped; a failed write stops the
+// import after reporting the lines of its batch.
+func (cs *CollectionService) ImportNDJSON(r io.Reader, batchSize int, flush func([]BulkResult) error) error {
+	if batchSize <= 0 {
+		batchSize = DefaultBulkBatchSize
+	}
+
+	in := bufio.NewScanner(r)
+	in.Buffer(make([]byte, 0, 64*1024), MaxBulkLineSize)
+
+	seen := make(map[uuid.UUID]bool)
+	batch := &bulkBatch{}
+
+	for line := 1; in.Scan(); line++ {
+		data := in.Bytes()
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		result := BulkResult{Line: line}
+		rec, songs, err := cs.newBulkRecord(data, seen)
+		if err != nil {
+			result.fail(err)
+		} else {
+			seen[rec.GetID()] = true
+
+			id := rec.GetID()
+			result.OK = true
+			result.ID = &id
+			result.Songs = make([]uuid.UUID, 0, len(songs))
+			for _, s := range songs {
+				result.Songs = append(result.Songs, s.GetID())
+			}
+
+			batch.records = append(batch.records, rec)
+			batch.songs = append(batch.songs, songs...)
+		}
+		batch.results = append(batch.results, result)
+
+		if len(batch.records) >= batchSize || len(batch.results) >= batchSize {
+			if err := cs.writeBulkBatch(batch, flush); err != nil {
+				return err
+			}
+			batch = &bulkBatch{}
+		}
+	}
+
+	if err := in.Err(); err != nil {
+		return err
+	}
+
+	return cs.writeBulkBatch(batch, flush)
+}
+
+// writeBulkBatch stores the records and songs of a batch in one transaction
+// and flushes its results. When storing fails, the lines of the batch are
+// flushed as failed and the error is returned.
+func (cs *CollectionService) writeBulkBatch(batch *bulkBatch, flush func([]BulkResult) error) error {
+	if len(batch.results) == 0 {
+		return nil
+	}
+
+	err := cs.inTransaction(func(tx *CollectionService) error {
+		if err := tx.records.AddBatch(batch.records); err != nil {
+			return err
+		}
+		if len(batch.songs) == 0 {
+			return nil
+		}
+		if tx.songs == nil {
+			return ErrNoSongRepository
+		}
+		return tx.songs.AddBatch(batch.songs)
+	})
+
+	if err != nil {
+		for i := range batch.results {
+			if batch.results[i].OK {
+				batch.results[i].fail(err)
+			}
+		}
+		if flushErr := flush(batch.results); flushErr != nil {
+			return flushErr
+		}
+		return err
+	}
+
+	return flush(batch.results)
+}
+
+// newBulkRecord decodes and validates a line. Every invalid field is
+// reported, those of songs prefixed with their position.
+func (cs *CollectionService) newBulkRecord(data []byte, seen map[uuid.UUID]bool) (record.Record, []song.Song, error) {
+	var br BulkRecord
+	if err := json.Unmarshal(data, &br); err != nil {
+		return record.Record{}, nil, fmt.Errorf("invalid json: %w", err)
+	}
+
+	var errs validation.Errors
+
+	id := br.ID
+	switch {
+	case id == uuid.Nil:
+		id = uuid.New()
+	case seen[id]:
+		errs = errs.Add("id", validation.CodeNotAllowed, ErrDuplicateRecordID)
+	case cs.recordExists(id):
+		errs = errs.Add("id", validation.CodeNotAllowed, ErrRecordExists)
+	}
+
+	rec, err := record.NewRecordWithID(id, br.Name, br.Kind)
+	errs = errs.Merge("", err)
+
+	rec.SetArtist(br.Artist)
+	rec.SetTags(br.Tags)
+
+	if br.Status != "" {
+		status, err := record.ParseStatus(br.Status)
+		if err != nil {
+			errs = errs.Add("status", validation.CodeInvalid, err)
+		}
+		rec.SetStatus(status)
+	}
+
+	if br.Acquisition != nil {
+		errs = errs.Merge("", rec.SetAcquisition(*br.Acquisition))
+	}
+
+	songs := make([]song.Song, 0, len(br.Songs))
+	for i, bs := range br.Songs {
+		s, err := song.NewSong(bs.Name, bs.Length, id)
+		if err != nil {
+			errs = errs.Merge(fmt.Sprintf("songs[%d]", i), err)
+			continue
+		}
+		s.SetTags(bs.Tags)
+		s.SetPath(bs.Path)
+		songs = append(songs, s)
+	}
+
+	if err := errs.Err(); err != nil {
+		return record.Record{}, nil, err
+	}
+
+	return rec, songs, nil
+}
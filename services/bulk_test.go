@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_ImportNDJSON(t *testing.T) {
+	t.Run("Batches", func(t *testing.T) {
+		cs, _ := services.NewCollectionService(
+			services.WithRecordMemoryRepository(),
+			services.WithSongMemoryRepository(),
+		)
+		existing, _ := cs.AddRecord(uuid.New(), "Blue", "vinyl")
+
+		data := `{"name":"The Wall","artist":"Pink Floyd","kind":"vinyl","songs":[{"name":"Hey You","length":280},{"name":"Mother","length":332}]}` + "\n" +
+			"\n" +
+			`{"name":"","kind":"cd","songs":[{"name":"","length":-1}]}` + "\n" +
+			`{"id":"` + existing.ID.String() + `","name":"Blue","kind":"vinyl"}` + "\n" +
+			`{"name":"Nevermind",` + "\n" +
+			`{"id":"00000000-0000-0000-0000-000000000001","name":"Court and Spark","kind":"mp3","status":"wishlist"}` + "\n" +
+			`{"id":"00000000-0000-0000-0000-000000000001","name":"Hejira","kind":"mp3"}`
+
+		var batches [][]services.BulkResult
+		err := cs.ImportNDJSON(strings.NewReader(data), 2, func(results []services.BulkResult) error {
+			batches = append(batches, results)
+			return nil
+		})
+		assert.NoError(t, err)
+
+		var results []services.BulkResult
+		for _, b := range batches {
+			results = append(results, b...)
+		}
+
+		assert.Len(t, batches, 3)
+		assert.Len(t, results, 6)
+
+		assert.Equal(t, 1, results[0].Line)
+		assert.True(t, results[0].OK)
+		assert.Len(t, results[0].Songs, 2)
+
+		assert.Equal(t, 3, results[1].Line)
+		assert.False(t, results[1].OK)
+		assert.True(t, results[1].Errors.Has("name"))
+		assert.True(t, results[1].Errors.Has("kind"))
+		assert.True(t, results[1].Errors.Has("songs[0].length"))
+
+		assert.False(t, results[2].OK)
+		assert.True(t, results[2].Errors.Has("id"))
+
+		assert.Equal(t, 5, results[3].Line)
+		assert.False(t, results[3].OK)
+		assert.Contains(t, results[3].Error, "invalid json")
+
+		assert.True(t, results[4].OK)
+		assert.Equal(t, "00000000-0000-0000-0000-000000000001", results[4].ID.String())
+
+		assert.False(t, results[5].OK)
+		assert.True(t, results[5].Errors.Has("id"))
+
+		records, _ := cs.FindAllRecord()
+		assert.Len(t, records, 3)
+
+		wall, err := cs.FindRecord(results[0].ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, "Pink Floyd", wall.Artist)
+
+		songs, _ := cs.FindSongsByRecord(*results[0].ID)
+		assert.Len(t, songs, 2)
+	})
+
+	t.Run("Failed write", func(t *testing.T) {
+		cs, _ := services.NewCollectionService(
+			services.WithFakeRecordService(true, uuid.New()),
+			services.WithSongMemoryRepository(),
+		)
+
+		data := `{"name":"The Wall","kind":"vinyl"}` + "\n" +
+			`{"name":"","kind":"vinyl"}` + "\n" +
+			`{"name":"Blue","kind":"vinyl"}` + "\n"
+
+		var results []services.BulkResult
+		err := cs.ImportNDJSON(strings.NewReader(data), 2, func(batch []services.BulkResult) error {
+			results = append(results, batch...)
+			return nil
+		})
+		assert.Error(t, err)
+
+		assert.Len(t, results, 2)
+		assert.False(t, results[0].OK)
+		assert.Equal(t, err.Error(), results[0].Error)
+		assert.Nil(t, results[0].ID)
+	})
+	t.Run("No song repository", func(t *testing.T) {
+		cs, _ := services.NewCollectionService(
+			services.WithRecordMemoryRepository(),
+		)
+
+		data := `{"name":"The Wall","kind":"vinyl","songs":[{"name":"Hey You","length":280}]}` + "\n"
+
+		var results []services.BulkResult
+		err := cs.ImportNDJSON(strings.NewReader(data), 2, func(batch []services.BulkResult) error {
+			results = append(results, batch...)
+			return nil
+		})
+		assert.Equal(t, services.ErrNoSongRepository, err)
+		assert.Len(t, results, 1)
+		assert.False(t, results[0].OK)
+	})
+}
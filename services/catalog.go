@@ -0,0 +1,109 @@
+package services
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/artwork"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/domain/taxonomy"
+	"github.com/rodrwan/collection/pkg/catalog"
+)
+
+// Catalog gathers the records, songs and cover art of the collection for
+// the static catalog. Genres are shown with their parents, e.g.
+// "Rock / Progressive Rock", and cover art is only included when a blob
+// store is configured.
+func (cs *CollectionService) Catalog(title string) (catalog.Site, error) {
+	site := catalog.Site{
+		Title:       title,
+		GeneratedAt: time.Now(),
+		Records:     make([]catalog.Record, 0),
+	}
+
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return site, err
+	}
+
+	genres, err := cs.genreLabels()
+	if err != nil {
+		return site, err
+	}
+
+	for _, rec := range records {
+		cr := catalog.Record{
+			ID:     rec.GetID().String(),
+			Name:   rec.GetName(),
+			Artist: rec.GetArtist(),
+			Kind:   rec.GetKind(),
+			Genre:  genres[rec.GetGenreID()],
+			Tags:   rec.GetTags(),
+			Status: string(rec.GetStatus()),
+		}
+
+		songs, err := cs.songs.FindSongsByRecord(rec.GetID())
+		if err != nil {
+			return site, err
+		}
+		song.SortByTrack(songs)
+		for _, s := range songs {
+			cr.Songs = append(cr.Songs, catalog.Song{Track: s.GetTrack(), Name: s.GetName(), Length: s.GetLength()})
+		}
+
+		if cover := rec.GetCover(); cs.blobs != nil && !cover.IsZero() {
+			cr.Cover = cs.catalogImage(cover, "medium")
+			cr.Thumbnail = cs.catalogImage(cover, "small")
+		}
+
+		site.Records = append(site.Records, cr)
+	}
+
+	return site, nil
+}
+
+// genreLabels names every genre with its parents, e.g.
+// "Rock / Progressive Rock".
+func (cs *CollectionService) genreLabels() (map[uuid.UUID]string, error) {
+	labels := make(map[uuid.UUID]string)
+	if cs.genres == nil {
+		return labels, nil
+	}
+
+	all, err := cs.genres.FindGenres()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range all {
+		path, err := taxonomy.Path(all, g.GetID())
+		if err != nil {
+			continue
+		}
+		names := make([]string, 0, len(path))
+		for _, p := range path {
+			names = append(names, p.GetName())
+		}
+		labels[g.GetID()] = strings.Join(names, " / ")
+	}
+
+	return labels, nil
+}
+
+// catalogImage is a rendition of a cover, read from the blob store when the
+// catalog is written.
+func (cs *CollectionService) catalogImage(cover record.Cover, rendition string) catalog.Image {
+	key := cover.RenditionKey(rendition)
+	return catalog.Image{
+		Ext: artwork.Extension(cover.ContentType),
+		Open: func() (io.ReadCloser, error) {
+			obj, err := cs.blobs.Get(key)
+			if err != nil {
+				return nil, err
+			}
+			return obj.Body, nil
+		},
+	}
+}
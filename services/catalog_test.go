@@ -0,0 +1,71 @@
+package services_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/pkg/catalog"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_Catalog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "catalog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithGenreMemoryRepository(),
+		services.WithLocalBlobStore(filepath.Join(dir, "blobs"), "/blobs"),
+	)
+
+	rock, _ := cs.AddGenre("Rock", uuid.Nil)
+	prog, _ := cs.AddGenre("Progressive Rock", rock.ID)
+
+	wall, _ := cs.AddRecord(uuid.New(), "The Wall", "vinyl")
+	assert.Nil(t, cs.AddSongToRecord(wall.ToRecord(), "Hey You", 280))
+	assert.Nil(t, cs.AddSongToRecord(wall.ToRecord(), "Comfortably Numb", 382))
+	_, err = cs.TagRecord(wall.ID, prog.ID, false, []string{"classic"})
+	assert.Nil(t, err)
+	_, err = cs.UploadRecordCover(wall.ID, testCover(800, 800))
+	assert.Nil(t, err)
+
+	blue, _ := cs.AddRecord(uuid.New(), "Blue", "mp3")
+
+	site, err := cs.Catalog("Our Records")
+	assert.Nil(t, err)
+	assert.Equal(t, "Our Records", site.Title)
+	assert.Equal(t, 2, len(site.Records))
+
+	byID := make(map[string]catalog.Record)
+	for _, r := range site.Records {
+		byID[r.ID] = r
+	}
+
+	w := byID[wall.ID.String()]
+	assert.Equal(t, "Rock / Progressive Rock", w.Genre)
+	assert.Equal(t, []string{"classic"}, w.Tags)
+	assert.Equal(t, "owned", w.Status)
+	assert.Equal(t, []catalog.Song{{Name: "Comfortably Numb", Length: 382}, {Name: "Hey You", Length: 280}}, w.Songs)
+	assert.False(t, w.Cover.IsZero())
+	assert.Equal(t, ".png", w.Thumbnail.Ext)
+
+	b := byID[blue.ID.String()]
+	assert.Equal(t, "", b.Genre)
+	assert.True(t, b.Cover.IsZero())
+
+	out := filepath.Join(dir, "site")
+	summary, err := catalog.Write(out, site)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, summary.Images)
+
+	_, err = os.Stat(filepath.Join(out, "covers", wall.ID.String()+"-small.png"))
+	assert.Nil(t, err)
+}
@@ -0,0 +1,475 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrInvalidCSVLayout = errors.New("invalid csv layout")
+	ErrInvalidCSVColumn = errors.New("unknown csv column")
+	ErrMissingCSVColumn = errors.New("csv has no name column")
+	ErrRecordExists     = errors.New("record already exists")
+)
+
+// CSVLayout tells how records and their songs are laid out in rows.
+type CSVLayout string
+
+const (
+	// CSVLayoutSongs writes a row per song, repeating the record columns.
+	// Records without songs get a single row with empty song columns.
+	CSVLayoutSongs CSVLayout = "songs"
+	// CSVLayoutRecords writes a row per record with all of its songs in one
+	// column, as "name:length|name:length". A | or \ in a song name is
+	// escaped with a \.
+	CSVLayoutRecords CSVLayout = "records"
+)
+
+// CSV columns, which are also the fields an import maps columns to.
+const (
+	CSVRecordID   = "record_id"
+	CSVName       = "name"
+	CSVKind       = "kind"
+	CSVStatus     = "status"
+	CSVTags       = "tags"
+	CSVSongName   = "song_name"
+	CSVSongLength = "song_length"
+	CSVSongs      = "songs"
+)
+
+var csvColumns = map[CSVLayout][]string{
+	CSVLayoutSongs:   {CSVRecordID, CSVName, CSVKind, CSVStatus, CSVTags, CSVSongName, CSVSongLength},
+	CSVLayoutRecords: {CSVRecordID, CSVName, CSVKind, CSVStatus, CSVTags, CSVSongs},
+}
+
+// ParseCSVLayout reads a layout ignoring case and surrounding spaces. An
+// empty value means CSVLayoutSongs.
+func ParseCSVLayout(value string) (CSVLayout, error) {
+	switch l := CSVLayout(strings.ToLower(strings.TrimSpace(value))); l {
+	case "":
+		return CSVLayoutSongs, nil
+	case CSVLayoutSongs, CSVLayoutRecords:
+		return l, nil
+	}
+
+	return "", ErrInvalidCSVLayout
+}
+
+// CSVColumns lists the columns of a layout in their default order.
+func CSVColumns(layout CSVLayout) []string {
+	return append([]string(nil), csvColumns[layout]...)
+}
+
+// ParseCSVColumnMap reads a column mapping written as
+// "Header:field,Other header:field".
+func ParseCSVColumnMap(value string) (map[string]string, error) {
+	columns := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		i := strings.LastIndex(pair, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid column mapping %q, expected header:field", pair)
+		}
+		columns[strings.TrimSpace(pair[:i])] = strings.TrimSpace(pair[i+1:])
+	}
+
+	return columns, nil
+}
+
+// ExportCSV writes every record with its songs. columns picks and orders the
+// columns of the layout, all of them when empty.
+func (cs *CollectionService) ExportCSV(w io.Writer, layout CSVLayout, columns []string) error {
+	if _, ok := csvColumns[layout]; !ok {
+		return ErrInvalidCSVLayout
+	}
+
+	if len(columns) == 0 {
+		columns = CSVColumns(layout)
+	}
+	for _, c := range columns {
+		if !hasColumn(csvColumns[layout], c) {
+			return fmt.Errorf("%w: %s", ErrInvalidCSVColumn, c)
+		}
+	}
+
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return err
+	}
+
+	out := csv.NewWriter(w)
+	if err := out.Write(columns); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		songs, err := cs.songs.FindSongsByRecord(r.GetID())
+		if err != nil {
+			return err
+		}
+
+		values := map[string]string{
+			CSVRecordID: r.GetID().String(),
+			CSVName:     r.GetName(),
+			CSVKind:     r.GetKind(),
+			CSVStatus:   string(r.GetStatus()),
+			CSVTags:     strings.Join(r.GetTags(), ","),
+		}
+
+		if layout == CSVLayoutRecords {
+			values[CSVSongs] = formatCSVSongs(songs)
+			if err := out.Write(csvRow(columns, values)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if len(songs) == 0 {
+			if err := out.Write(csvRow(columns, values)); err != nil {
+				return err
+			}
+		}
+		for _, s := range songs {
+			values[CSVSongName] = s.GetName()
+			values[CSVSongLength] = strconv.FormatInt(s.GetLength(), 10)
+			if err := out.Write(csvRow(columns, values)); err != nil {
+				return err
+			}
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func csvRow(columns []string, values map[string]string) []string {
+	row := make([]string, len(columns))
+	for i, c := range columns {
+		row[i] = values[c]
+	}
+	return row
+}
+
+// csvSongEscaper escapes the separator of the songs column, and the escape
+// character itself, in song names.
+var csvSongEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`)
+
+func formatCSVSongs(songs []song.Song) string {
+	items := make([]string, 0, len(songs))
+	for _, s := range songs {
+		items = append(items, csvSongEscaper.Replace(s.GetName())+":"+strconv.FormatInt(s.GetLength(), 10))
+	}
+	return strings.Join(items, "|")
+}
+
+// splitCSVSongs splits the songs column on the separators that are not
+// escaped and unescapes the items. A backslash before any other character
+// is kept as it is.
+func splitCSVSongs(value string) []string {
+	var items []string
+	var item strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value) && (value[i+1] == '|' || value[i+1] == '\\'):
+			i++
+			item.WriteByte(value[i])
+		case value[i] == '|':
+			items = append(items, item.String())
+			item.Reset()
+		default:
+			item.WriteByte(value[i])
+		}
+	}
+	return append(items, item.String())
+}
+
+func hasColumn(columns []string, column string) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// CSVImportOptions configures ImportCSV.
+type CSVImportOptions struct {
+	// Columns maps headers of the file to fields, e.g. "Album": "name".
+	// Headers already named after a field need no mapping and any other
+	// header is ignored.
+	Columns map[string]string
+	// DryRun validates every line without storing anything.
+	DryRun bool
+}
+
+// LineError is a line of an import that could not be imported.
+type LineError struct {
+	Line   int               `json:"line"`
+	Error  string            `json:"error"`
+	Errors validation.Errors `json:"errors,omitempty"`
+}
+
+// ImportReport tells what an import created, or would create on a dry run,
+// and which lines failed.
+type ImportReport struct {
+	DryRun  bool        `json:"dryRun"`
+	Records int         `json:"records"`
+	Songs   int         `json:"songs"`
+	Errors  []LineError `json:"errors"`
+}
+
+func (ir *ImportReport) fail(line int, err error) {
+	le := LineError{Line: line, Error: err.Error()}
+	var errs validation.Errors
+	if errors.As(err, &errs) {
+		le.Errors = errs
+	}
+	ir.Errors = append(ir.Errors, le)
+}
+
+// csvRecord is a record being imported with the songs of every line that
+// belongs to it.
+type csvRecord struct {
+	line  int
+	rec   record.Record
+	valid bool
+	songs []song.Song
+}
+
+// ImportCSV creates the records and songs of a CSV file in either layout.
+// Lines sharing a record_id, or a name and kind when there is none, belong
+// to the same record. Invalid lines are reported and skipped, the others are
+// imported all together or not at all.
+func (cs *CollectionService) ImportCSV(r io.Reader, opts CSVImportOptions) (ImportReport, error) {
+	report := ImportReport{DryRun: opts.DryRun, Errors: make([]LineError, 0)}
+
+	in := csv.NewReader(r)
+	in.FieldsPerRecord = -1
+	in.TrimLeadingSpace = true
+
+	header, err := in.Read()
+	if err == io.EOF {
+		return report, nil
+	}
+	if err != nil {
+		return report, err
+	}
+
+	index, err := csvIndex(header, opts.Columns)
+	if err != nil {
+		return report, err
+	}
+
+	var records []*csvRecord
+	byKey := make(map[string]*csvRecord)
+
+	// the header is line 1
+	for line := 2; ; line++ {
+		row, err := in.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return report, err
+			}
+			report.fail(line, err)
+			continue
+		}
+
+		get := func(field string) string {
+			i, ok := index[field]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+
+		key := "name:" + strings.ToLower(get(CSVName)) + "|" + strings.ToLower(get(CSVKind))
+		if id := get(CSVRecordID); id != "" {
+			key = "id:" + strings.ToLower(id)
+		}
+
+		var errs validation.Errors
+		cr, ok := byKey[key]
+		if !ok {
+			cr = &csvRecord{line: line}
+			cr.rec, errs = cs.newCSVRecord(get)
+			cr.valid = len(errs) == 0
+			byKey[key] = cr
+			records = append(records, cr)
+		} else if !cr.valid {
+			report.fail(line, fmt.Errorf("record on line %d is invalid", cr.line))
+			continue
+		}
+
+		songs, songErrs := csvSongs(get, cr.rec.GetID())
+		errs = append(errs, songErrs...)
+		if err := errs.Err(); err != nil {
+			report.fail(line, err)
+		}
+
+		if cr.valid {
+			cr.songs = append(cr.songs, songs...)
+		}
+	}
+
+	var recs []record.Record
+	var songs []song.Song
+	for _, cr := range records {
+		if cr.valid {
+			recs = append(recs, cr.rec)
+			songs = append(songs, cr.songs...)
+		}
+	}
+
+	if !opts.DryRun && len(recs) > 0 {
+		err := cs.inTransaction(func(tx *CollectionService) error {
+			if err := tx.records.AddBatch(recs); err != nil {
+				return err
+			}
+			if len(songs) == 0 {
+				return nil
+			}
+			if tx.songs == nil {
+				return ErrNoSongRepository
+			}
+			return tx.songs.AddBatch(songs)
+		})
+		if err != nil {
+			return report, err
+		}
+	}
+	report.Records = len(recs)
+	report.Songs = len(songs)
+
+	return report, nil
+}
+
+// csvIndex finds the column of each known field.
+func csvIndex(header []string, columns map[string]string) (map[string]int, error) {
+	known := append(CSVColumns(CSVLayoutSongs), CSVSongs)
+	for _, field := range columns {
+		if !hasColumn(known, field) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCSVColumn, field)
+		}
+	}
+
+	index := make(map[string]int)
+	for i, h := range header {
+		h = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+
+		field, ok := columns[h]
+		if !ok {
+			field = strings.ToLower(h)
+		}
+		if hasColumn(known, field) {
+			index[field] = i
+		}
+	}
+
+	if _, ok := index[CSVName]; !ok {
+		return nil, ErrMissingCSVColumn
+	}
+
+	return index, nil
+}
+
+// newCSVRecord builds the record of the first line that mentions it.
+func (cs *CollectionService) newCSVRecord(get func(string) string) (record.Record, validation.Errors) {
+	var errs validation.Errors
+
+	id := uuid.New()
+	if value := get(CSVRecordID); value != "" {
+		parsed, err := uuid.Parse(value)
+		switch {
+		case err != nil:
+			errs = errs.Add(CSVRecordID, validation.CodeInvalid, err)
+		case cs.recordExists(parsed):
+			errs = errs.Add(CSVRecordID, validation.CodeNotAllowed, ErrRecordExists)
+		default:
+			id = parsed
+		}
+	}
+
+	rec, err := record.NewRecordWithID(id, get(CSVName), strings.ToLower(get(CSVKind)))
+	errs = errs.Merge("", err)
+
+	if value := get(CSVStatus); value != "" {
+		status, err := record.ParseStatus(value)
+		if err != nil {
+			errs = errs.Add(CSVStatus, validation.CodeInvalid, err)
+		}
+		rec.SetStatus(status)
+	}
+
+	if value := get(CSVTags); value != "" {
+		rec.SetTags(strings.Split(value, ","))
+	}
+
+	return rec, errs
+}
+
+func (cs *CollectionService) recordExists(id uuid.UUID) bool {
+	_, err := cs.records.Get(id)
+	return err == nil
+}
+
+// csvSongs reads the songs of a line, from the song columns or from the
+// songs column.
+func csvSongs(get func(string) string, recordID uuid.UUID) ([]song.Song, validation.Errors) {
+	var errs validation.Errors
+	var songs []song.Song
+
+	if name, length := get(CSVSongName), get(CSVSongLength); name != "" || length != "" {
+		s, err := csvSong(name, length, recordID)
+		errs = errs.Merge("song", err)
+		if err == nil {
+			songs = append(songs, s)
+		}
+	}
+
+	if value := get(CSVSongs); value != "" {
+		for i, item := range splitCSVSongs(value) {
+			name, length := item, ""
+			if j := strings.LastIndex(item, ":"); j >= 0 {
+				name, length = item[:j], item[j+1:]
+			}
+
+			s, err := csvSong(strings.TrimSpace(name), strings.TrimSpace(length), recordID)
+			errs = errs.Merge(fmt.Sprintf("songs[%d]", i), err)
+			if err == nil {
+				songs = append(songs, s)
+			}
+		}
+	}
+
+	return songs, errs
+}
+
+func csvSong(name, length string, recordID uuid.UUID) (song.Song, error) {
+	var seconds int64
+	if length != "" {
+		n, err := strconv.ParseInt(length, 10, 64)
+		if err != nil {
+			var errs validation.Errors
+			return song.Song{}, errs.Add("length", validation.CodeInvalid, errors.New("length must be a number of seconds"))
+		}
+		seconds = n
+	}
+
+	return song.NewSong(name, seconds, recordID)
+}
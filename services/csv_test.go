@@ -0,0 +1,172 @@
+package services_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_ExportCSV(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+
+	wall, _ := cs.AddRecord(uuid.MustParse("00000000-0000-0000-0000-000000000001"), "The Wall", "vinyl")
+	cs.AddRecord(uuid.MustParse("00000000-0000-0000-0000-000000000002"), "Blue, Live", "mp3")
+	cs.AddSongToRecord(wall.ToRecord(), "Hey You", 280)
+	cs.AddSongToRecord(wall.ToRecord(), "Mother", 332)
+
+	tests := []struct {
+		name        string
+		layout      services.CSVLayout
+		columns     []string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:   "Row per song",
+			layout: services.CSVLayoutSongs,
+			expected: "record_id,name,kind,status,tags,song_name,song_length\n" +
+				"00000000-0000-0000-0000-000000000001,The Wall,vinyl,owned,,Hey You,280\n" +
+				"00000000-0000-0000-0000-000000000001,The Wall,vinyl,owned,,Mother,332\n" +
+				"00000000-0000-0000-0000-000000000002,\"Blue, Live\",mp3,owned,,,\n",
+		},
+		{
+			name:    "Row per record with some columns",
+			layout:  services.CSVLayoutRecords,
+			columns: []string{"name", "songs"},
+			expected: "name,songs\n" +
+				"The Wall,Hey You:280|Mother:332\n" +
+				"\"Blue, Live\",\n",
+		},
+		{
+			name:        "Column of another layout",
+			layout:      services.CSVLayoutRecords,
+			columns:     []string{"song_name"},
+			expectedErr: services.ErrInvalidCSVColumn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := cs.ExportCSV(&buf, tt.layout, tt.columns)
+			assert.True(t, errors.Is(err, tt.expectedErr), err)
+			if err == nil {
+				assert.Equal(t, tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestCollectionService_ImportCSV(t *testing.T) {
+	newService := func() *services.CollectionService {
+		cs, _ := services.NewCollectionService(
+			services.WithRecordMemoryRepository(),
+			services.WithSongMemoryRepository(),
+		)
+		return cs
+	}
+
+	t.Run("Row per song with mapped columns", func(t *testing.T) {
+		cs := newService()
+		data := "Album,Format,Track,Seconds,Notes\n" +
+			"The Wall,vinyl,Hey You,280,x\n" +
+			"The Wall,vinyl,Mother,nope,\n" +
+			"Blue,tape,All I Want,200,\n" +
+			"Blue,tape,River,240,\n" +
+			",vinyl,,,\n"
+
+		report, err := cs.ImportCSV(strings.NewReader(data), services.CSVImportOptions{
+			Columns: map[string]string{"Album": "name", "Format": "kind", "Track": "song_name", "Seconds": "song_length"},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, report.Records)
+		assert.Equal(t, 1, report.Songs)
+		assert.Len(t, report.Errors, 4)
+		assert.Equal(t, 3, report.Errors[0].Line)
+		assert.True(t, report.Errors[0].Errors.Has("song.length"))
+		assert.Equal(t, 4, report.Errors[1].Line)
+		assert.True(t, report.Errors[1].Errors.Has("kind"))
+		assert.Equal(t, "record on line 4 is invalid", report.Errors[2].Error)
+		assert.True(t, report.Errors[3].Errors.Has("name"))
+
+		records, _ := cs.FindAllRecord()
+		assert.Len(t, records, 1)
+		songs, _ := cs.FindSongsByRecord(records[0].ID)
+		assert.Len(t, songs, 1)
+	})
+
+	t.Run("Round trip of the record layout", func(t *testing.T) {
+		source := newService()
+		wall, _ := source.AddRecordWithOwnership(uuid.New(), "The Wall", "vinyl", "wishlist", record.Acquisition{})
+		source.AddSongToRecord(wall.ToRecord(), "Hey You", 280)
+		source.AddSongToRecord(wall.ToRecord(), "Mother", 332)
+		source.AddSongToRecord(wall.ToRecord(), `Is There Anybody Out There? | Part\2`, 160)
+
+		var buf bytes.Buffer
+		assert.Nil(t, source.ExportCSV(&buf, services.CSVLayoutRecords, nil))
+		assert.Contains(t, buf.String(), `Mother:332|Is There Anybody Out There? \| Part\\2:160`)
+
+		// importing back into the same collection clashes on ids
+		report, err := source.ImportCSV(bytes.NewReader(buf.Bytes()), services.CSVImportOptions{DryRun: true})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, report.Records)
+		assert.True(t, report.Errors[0].Errors.Is(services.ErrRecordExists))
+
+		cs := newService()
+		report, err = cs.ImportCSV(bytes.NewReader(buf.Bytes()), services.CSVImportOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, report.Records)
+		assert.Equal(t, 3, report.Songs)
+		assert.Empty(t, report.Errors)
+
+		got, err := cs.FindRecord(wall.ID.String())
+		assert.Nil(t, err)
+		assert.Equal(t, record.StatusWishlist, got.Status)
+
+		songs, _ := cs.FindSongsByRecord(wall.ID)
+		assert.Len(t, songs, 3)
+		assert.Equal(t, `Is There Anybody Out There? | Part\2`, songs[2].Name)
+	})
+
+	t.Run("Failed write", func(t *testing.T) {
+		cs, _ := services.NewCollectionService(
+			services.WithFakeRecordService(true, uuid.New()),
+			services.WithSongMemoryRepository(),
+		)
+		_, err := cs.ImportCSV(strings.NewReader("name,kind,song_name\nBlue,vinyl,River\n"), services.CSVImportOptions{})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		cs := newService()
+		report, err := cs.ImportCSV(strings.NewReader("name,kind,song_name\nBlue,vinyl,River\n"), services.CSVImportOptions{DryRun: true})
+		assert.Nil(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, 1, report.Records)
+		assert.Equal(t, 1, report.Songs)
+
+		records, _ := cs.FindAllRecord()
+		assert.Empty(t, records)
+	})
+
+	t.Run("Missing name column", func(t *testing.T) {
+		_, err := newService().ImportCSV(strings.NewReader("title,kind\nBlue,vinyl\n"), services.CSVImportOptions{})
+		assert.Equal(t, services.ErrMissingCSVColumn, err)
+	})
+
+	t.Run("Mapping to an unknown field", func(t *testing.T) {
+		_, err := newService().ImportCSV(strings.NewReader("title\nBlue\n"), services.CSVImportOptions{
+			Columns: map[string]string{"title": "album"},
+		})
+		assert.True(t, errors.Is(err, services.ErrInvalidCSVColumn))
+	})
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/pkg/cue"
+)
+
+var (
+	ErrUnknownCUEKind = errors.New("cannot tell the record kind from the files of the cue sheet")
+	ErrOutsideLibrary = errors.New("path is outside of the music library")
+)
+
+// cueKinds maps the extensions of the audio files of a cue sheet to the
+// kind of the record they make.
+var cueKinds = map[string]string{
+	".flac": record.KindLossless,
+	".wav":  record.KindLossless,
+	".ape":  record.KindLossless,
+	".wv":   record.KindLossless,
+	".aiff": record.KindLossless,
+	".aif":  record.KindLossless,
+	".mp3":  record.KindMP3,
+	".ogg":  record.KindOgg,
+	".m4a":  record.KindAAC,
+}
+
+// CUEImportOptions tune a cue sheet import. Kind is the kind of the
+// record, told from the audio files of the sheet when empty. Dir is the
+// directory of those files: when set, the length of the last track of each
+// file is read from the file itself.
+type CUEImportOptions struct {
+	Kind   string
+	Dir    string
+	DryRun bool
+}
+
+// CUETrack is a track of an imported cue sheet. Added is false for tracks
+// the record already had a song for.
+type CUETrack struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Performer string     `json:"performer,omitempty"`
+	Length    int64      `json:"length"`
+	File      string     `json:"file"`
+	Added     bool       `json:"added"`
+	SongID    *uuid.UUID `json:"songId,omitempty"`
+}
+
+// CUEImport tells what a cue sheet import created, or would create on a
+// dry run. Warnings list the files whose last track length is unknown.
+type CUEImport struct {
+	DryRun   bool                `json:"dryRun"`
+	Created  bool                `json:"created"`
+	Record   record.PublicRecord `json:"record"`
+	Tracks   []CUETrack          `json:"tracks"`
+	Warnings []string            `json:"warnings"`
+}
+
+// ImportCUE creates the record described by a cue sheet with a song per
+// track, in track order. A record with the same name, artist and kind is
+// completed with the tracks it has no song for instead. Songs keep the
+// performer of their track when it is not the artist of the record. Track
+// lengths run from one INDEX 01 to the next; the last track of each file
+// runs to the end of the file when it can be read from opts.Dir.
+func (cs *CollectionService) ImportCUE(r io.Reader, opts CUEImportOptions) (CUEImport, error) {
+	result := CUEImport{
+		DryRun:   opts.DryRun,
+		Tracks:   make([]CUETrack, 0),
+		Warnings: make([]string, 0),
+	}
+
+	sheet, err := cue.Parse(r)
+	if err != nil {
+		return result, err
+	}
+
+	kind := opts.Kind
+	if kind == "" {
+		kind = cueKinds[strings.ToLower(filepath.Ext(cueFileName(sheet.Files[0].Name)))]
+		if kind == "" {
+			return result, ErrUnknownCUEKind
+		}
+	}
+
+	name := sheet.Title
+	if strings.TrimSpace(name) == "" {
+		base := filepath.Base(cueFileName(sheet.Files[0].Name))
+		name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	for _, f := range sheet.Files {
+		var duration time.Duration
+		if opts.Dir != "" {
+			d, err := cueFileDuration(opts.Dir, f.Name)
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: length of the last track unknown: %v", f.Name, err))
+			}
+			duration = d
+		}
+
+		for i, length := range f.Lengths(duration) {
+			t := f.Tracks[i]
+
+			title := strings.TrimSpace(t.Title)
+			if title == "" {
+				title = fmt.Sprintf("Track %02d", t.Number)
+			}
+			performer := t.Performer
+			if performer == "" {
+				performer = sheet.Performer
+			}
+
+			result.Tracks = append(result.Tracks, CUETrack{
+				Number:    t.Number,
+				Title:     title,
+				Performer: performer,
+				Length:    int64(length.Round(time.Second) / time.Second),
+				File:      f.Name,
+			})
+		}
+	}
+
+	artist := sheet.Performer
+	if artist == "" {
+		artist = cueArtist(result.Tracks)
+	}
+
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return result, err
+	}
+	existing := make(importedRecords, len(records))
+	for _, rec := range records {
+		existing.add(rec)
+	}
+
+	rec, found := existing.find(name, artist, kind)
+	if !found {
+		rec, err = record.NewRecord(name, kind)
+		if err != nil {
+			return result, err
+		}
+		rec.SetArtist(artist)
+	}
+	result.Created = !found
+
+	have := make(map[string]bool)
+	if found {
+		songs, err := cs.songs.FindSongsByRecord(rec.GetID())
+		if err != nil {
+			return result, err
+		}
+		for _, s := range songs {
+			have[record.NormalizeName(s.GetName())] = true
+		}
+	}
+
+	songs := make([]song.Song, 0, len(result.Tracks))
+	for i := range result.Tracks {
+		t := &result.Tracks[i]
+		if have[record.NormalizeName(t.Title)] {
+			continue
+		}
+
+		s, err := song.NewSong(t.Title, t.Length, rec.GetID())
+		if err != nil {
+			return result, fmt.Errorf("track %d: %w", t.Number, err)
+		}
+		s.SetTrack(t.Number)
+		if record.NormalizeName(t.Performer) != record.NormalizeName(rec.GetArtist()) {
+			s.SetArtist(t.Performer)
+		}
+		id := s.GetID()
+		t.Added = true
+		t.SongID = &id
+		songs = append(songs, s)
+	}
+	result.Record = rec.ToPublic()
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if !found {
+		if err := cs.records.Add(rec); err != nil {
+			return result, err
+		}
+	}
+	if err := cs.songs.AddBatch(songs); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// cueArtist is the performer of every track, or various artists when they
+// differ.
+func cueArtist(tracks []CUETrack) string {
+	artist := ""
+	for _, t := range tracks {
+		switch {
+		case t.Performer == "":
+		case artist == "":
+			artist = t.Performer
+		case record.NormalizeName(artist) != record.NormalizeName(t.Performer):
+			return variousArtists
+		}
+	}
+	return artist
+}
+
+// cueFileName turns the name of a FILE of a cue sheet, often written on
+// Windows, into a slash separated path.
+func cueFileName(name string) string {
+	return strings.Replace(name, `\`, "/", -1)
+}
+
+// cueFileDuration reads how long a file of a cue sheet plays, the sheet
+// being in dir. Files outside of dir are not read.
+func cueFileDuration(dir, name string) (time.Duration, error) {
+	path, err := pathWithin(dir, cueFileName(name))
+	if err != nil {
+		return 0, err
+	}
+
+	read, ok := libraryFormats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return 0, ErrUnsupportedFormat
+	}
+
+	track, _, err := read(path)
+	if err != nil {
+		return 0, err
+	}
+	return track.Duration, nil
+}
+
+// LibraryPath resolves a slash separated path relative to the music
+// library, refusing those leaving it.
+func (cs *CollectionService) LibraryPath(rel string) (string, error) {
+	if cs.library == "" {
+		return "", ErrNoLibrary
+	}
+	return pathWithin(cs.library, rel)
+}
+
+// pathWithin joins dir and a slash separated path relative to it, failing
+// with ErrOutsideLibrary when the result is not under dir.
+func pathWithin(dir, rel string) (string, error) {
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+
+	r, err := filepath.Rel(dir, path)
+	if err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return "", ErrOutsideLibrary
+	}
+	return path, nil
+}
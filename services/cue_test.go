@@ -0,0 +1,137 @@
+package services_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/pkg/cue"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+const cueSheet = `REM GENRE Rock
+PERFORMER "Fleetwood Mac"
+TITLE "Rumours"
+FILE "Rumours.flac" WAVE
+  TRACK 01 AUDIO
+    TITLE "Second Hand News"
+    INDEX 01 00:00:00
+  TRACK 02 AUDIO
+    TITLE "Dreams"
+    INDEX 00 00:03:60
+    INDEX 01 00:04:00
+  TRACK 03 AUDIO
+    PERFORMER "Stevie Nicks"
+    INDEX 01 00:07:37
+`
+
+func TestCollectionService_ImportCUE(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFLAC(t, filepath.Join(dir, "Rumours.flac"), nil)
+
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+
+	dry, err := cs.ImportCUE(strings.NewReader(cueSheet), services.CUEImportOptions{Dir: dir, DryRun: true})
+	assert.Nil(t, err)
+	assert.True(t, dry.Created)
+	records, _ := cs.FindAllRecord()
+	assert.Equal(t, 0, len(records))
+
+	imported, err := cs.ImportCUE(strings.NewReader(cueSheet), services.CUEImportOptions{Dir: dir})
+	assert.Nil(t, err)
+	assert.True(t, imported.Created)
+	assert.Equal(t, "Rumours", imported.Record.Name)
+	assert.Equal(t, "Fleetwood Mac", imported.Record.Artist)
+	assert.Equal(t, record.KindLossless, imported.Record.Kind)
+	assert.Equal(t, 0, len(imported.Warnings))
+
+	assert.Equal(t, 3, len(imported.Tracks))
+	assert.Equal(t, 3, imported.Tracks[2].Number)
+	assert.Equal(t, "Track 03", imported.Tracks[2].Title)
+	assert.Equal(t, "Stevie Nicks", imported.Tracks[2].Performer)
+	assert.Equal(t, "Fleetwood Mac", imported.Tracks[1].Performer)
+
+	songs, err := cs.FindSongsByRecord(imported.Record.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(songs))
+	for i, want := range []struct {
+		name   string
+		length int64
+		artist string
+	}{{"Second Hand News", 4, ""}, {"Dreams", 3, ""}, {"Track 03", 3, "Stevie Nicks"}} {
+		assert.Equal(t, want.name, songs[i].Name)
+		assert.Equal(t, want.length, songs[i].Length)
+		assert.Equal(t, want.artist, songs[i].Artist)
+		assert.Equal(t, i+1, songs[i].Track)
+		assert.Equal(t, songs[i].ID, *imported.Tracks[i].SongID)
+	}
+
+	// Importing again completes the same record with the missing tracks.
+	more := strings.Replace(cueSheet, "TITLE \"Dreams\"", "TITLE \"Songbird\"", 1)
+	again, err := cs.ImportCUE(strings.NewReader(more), services.CUEImportOptions{Kind: "lossless"})
+	assert.Nil(t, err)
+	assert.False(t, again.Created)
+	assert.Equal(t, imported.Record.ID, again.Record.ID)
+	assert.Equal(t, []bool{false, true, false}, []bool{again.Tracks[0].Added, again.Tracks[1].Added, again.Tracks[2].Added})
+
+	songs, _ = cs.FindSongsByRecord(imported.Record.ID)
+	assert.Equal(t, 4, len(songs))
+}
+
+func TestCollectionService_ImportCUE_Invalid(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+
+	// The length of the last track cannot be read from a missing file.
+	dir, err := ioutil.TempDir("", "cue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	imported, err := cs.ImportCUE(strings.NewReader(cueSheet), services.CUEImportOptions{Dir: dir, DryRun: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(imported.Warnings))
+	assert.Equal(t, int64(0), imported.Tracks[2].Length)
+
+	escaping := strings.Replace(cueSheet, `"Rumours.flac"`, `"..\..\Rumours.flac"`, 1)
+	imported, err = cs.ImportCUE(strings.NewReader(escaping), services.CUEImportOptions{Dir: dir, DryRun: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(imported.Warnings))
+	assert.Contains(t, imported.Warnings[0], services.ErrOutsideLibrary.Error())
+
+	ogg := strings.Replace(cueSheet, "Rumours.flac", "Rumours.ogg", 1)
+	imported, err = cs.ImportCUE(strings.NewReader(ogg), services.CUEImportOptions{DryRun: true})
+	assert.Nil(t, err)
+	assert.Equal(t, record.KindOgg, imported.Record.Kind)
+
+	unknown := strings.Replace(cueSheet, "Rumours.flac", "Rumours.bin", 1)
+	_, err = cs.ImportCUE(strings.NewReader(unknown), services.CUEImportOptions{})
+	assert.Equal(t, services.ErrUnknownCUEKind, err)
+
+	_, err = cs.ImportCUE(strings.NewReader("TITLE Rumours\n"), services.CUEImportOptions{})
+	assert.Equal(t, cue.ErrNoTracks, err)
+
+	_, err = cs.LibraryPath("The Wall")
+	assert.Equal(t, services.ErrNoLibrary, err)
+
+	library, _ := services.NewCollectionService(services.WithMusicLibrary(dir))
+	path, err := library.LibraryPath("Pink Floyd/The Wall")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(dir, "Pink Floyd", "The Wall"), path)
+	_, err = library.LibraryPath("../elsewhere")
+	assert.Equal(t, services.ErrOutsideLibrary, err)
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/validation"
+	"github.com/rodrwan/collection/pkg/discogs"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported format")
+)
+
+// discogsKinds maps the Discogs format descriptions to record kinds. The
+// first description of a row with a kind decides it.
+var discogsKinds = map[string]string{
+	"vinyl":      record.KindVinyl,
+	"lp":         record.KindVinyl,
+	"ep":         record.KindVinyl,
+	`12"`:        record.KindVinyl,
+	`10"`:        record.KindVinyl,
+	`7"`:         record.KindVinyl,
+	"lathe cut":  record.KindVinyl,
+	"flexi-disc": record.KindVinyl,
+	"acetate":    record.KindVinyl,
+	"shellac":    record.KindVinyl,
+	"mp3":        record.KindMP3,
+	"aac":        record.KindAAC,
+	"flac":       record.KindLossless,
+	"alac":       record.KindLossless,
+	"wav":        record.KindLossless,
+	"aiff":       record.KindLossless,
+}
+
+// discogsGrades maps the Discogs condition codes to our grades. Discogs
+// grades in between ours are rounded down.
+var discogsGrades = map[string]record.Grade{
+	"M":   record.GradeMint,
+	"NM":  record.GradeNearMint,
+	"M-":  record.GradeNearMint,
+	"VG+": record.GradeVeryGoodPlus,
+	"VG":  record.GradeVeryGood,
+	"G+":  record.GradeGood,
+	"G":   record.GradeGood,
+	"F":   record.GradePoor,
+	"P":   record.GradePoor,
+}
+
+// discogsKind finds the kind of a Discogs format such as "LP, Album, RE".
+func discogsKind(format string) (string, error) {
+	for _, f := range discogs.Formats(format) {
+		if kind, ok := discogsKinds[strings.ToLower(f)]; ok {
+			return kind, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+}
+
+// ImportRow is a row of an import and what became of it. Skipped rows point
+// at the record they duplicate and failed rows tell why.
+type ImportRow struct {
+	Line     int               `json:"line"`
+	Name     string            `json:"name"`
+	Artist   string            `json:"artist,omitempty"`
+	RecordID uuid.UUID         `json:"recordId,omitempty"`
+	Reason   string            `json:"reason,omitempty"`
+	Errors   validation.Errors `json:"errors,omitempty"`
+}
+
+// ImportSummary tells which rows of an import created a record, or would on
+// a dry run, which were already in the collection and which failed.
+type ImportSummary struct {
+	DryRun  bool        `json:"dryRun"`
+	Created []ImportRow `json:"created"`
+	Skipped []ImportRow `json:"skipped"`
+	Failed  []ImportRow `json:"failed"`
+}
+
+func (is *ImportSummary) fail(row ImportRow, err error) {
+	row.Reason = err.Error()
+	var errs validation.Errors
+	if errors.As(err, &errs) {
+		row.Errors = errs
+	}
+	is.Failed = append(is.Failed, row)
+}
+
+// importedRecords finds the records an import would duplicate, by
+// normalized name and kind, and by artist when both sides have one.
+type importedRecords map[string][]record.Record
+
+func importKey(name, kind string) string {
+	return record.NormalizeName(name) + "|" + kind
+}
+
+func (ir importedRecords) add(r record.Record) {
+	key := importKey(r.GetName(), r.GetKind())
+	ir[key] = append(ir[key], r)
+}
+
+func (ir importedRecords) find(name, artist, kind string) (record.Record, bool) {
+	for _, r := range ir[importKey(name, kind)] {
+		if artist == "" || r.GetArtist() == "" || record.NormalizeName(artist) == record.NormalizeName(r.GetArtist()) {
+			return r, true
+		}
+	}
+
+	return record.Record{}, false
+}
+
+// ImportDiscogs creates a record for every item of a Discogs collection
+// export that is not in the collection yet. Rows whose format is not a kind
+// we support, or that are otherwise invalid, are reported and skipped.
+func (cs *CollectionService) ImportDiscogs(r io.Reader, dryRun bool) (ImportSummary, error) {
+	summary := ImportSummary{
+		DryRun:  dryRun,
+		Created: make([]ImportRow, 0),
+		Skipped: make([]ImportRow, 0),
+		Failed:  make([]ImportRow, 0),
+	}
+
+	in, err := discogs.NewReader(r)
+	if err != nil {
+		return summary, err
+	}
+
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return summary, err
+	}
+
+	existing := make(importedRecords, len(records))
+	for _, rec := range records {
+		existing.add(rec)
+	}
+
+	for {
+		item, err := in.Read()
+		if err == io.EOF {
+			break
+		}
+
+		row := ImportRow{Line: item.Line, Name: item.Title, Artist: item.Artist}
+		if err != nil {
+			var rowErr *discogs.RowError
+			if !errors.As(err, &rowErr) {
+				return summary, err
+			}
+			summary.fail(row, rowErr.Err)
+			continue
+		}
+
+		rec, err := newDiscogsRecord(item)
+		if err != nil {
+			summary.fail(row, err)
+			continue
+		}
+
+		if dup, ok := existing.find(rec.GetName(), rec.GetArtist(), rec.GetKind()); ok {
+			row.RecordID = dup.GetID()
+			row.Reason = ErrRecordExists.Error()
+			summary.Skipped = append(summary.Skipped, row)
+			continue
+		}
+
+		if !dryRun {
+			if err := cs.records.Add(rec); err != nil {
+				return summary, err
+			}
+		}
+
+		existing.add(rec)
+		row.RecordID = rec.GetID()
+		summary.Created = append(summary.Created, row)
+	}
+
+	return summary, nil
+}
+
+// newDiscogsRecord builds the record of an export item, with the date it
+// was added as its acquisition and its conditions as its first grading.
+func newDiscogsRecord(item discogs.Item) (record.Record, error) {
+	var errs validation.Errors
+
+	kind, err := discogsKind(item.Format)
+	if err != nil {
+		errs = errs.Add("kind", validation.CodeInvalid, err)
+	}
+
+	rec, err := record.NewRecord(item.Title, kind)
+	if kind != "" {
+		errs = errs.Merge("", err)
+	} else if strings.TrimSpace(item.Title) == "" {
+		errs = errs.Add("name", validation.CodeRequired, record.ErrMissingValues)
+	}
+	if err := errs.Err(); err != nil {
+		return record.Record{}, err
+	}
+
+	rec.SetArtist(item.Artist)
+
+	if !item.DateAdded.IsZero() || item.Notes != "" {
+		if err := rec.SetAcquisition(record.Acquisition{Date: item.DateAdded, Notes: item.Notes}); err != nil {
+			return record.Record{}, err
+		}
+	}
+
+	media, ok := discogsGrades[discogs.ConditionCode(item.MediaCondition)]
+	if ok && kind == record.KindVinyl {
+		sleeve := discogsGrades[discogs.ConditionCode(item.SleeveCondition)]
+		if err := rec.Grade(media, sleeve, "", item.DateAdded); err != nil {
+			return record.Record{}, err
+		}
+	}
+
+	return rec, nil
+}
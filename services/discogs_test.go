@@ -0,0 +1,90 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+const discogsExport = "Catalog#,Artist,Title,Label,Format,Rating,Released,release_id,CollectionFolder,Date Added,Collection Media Condition,Collection Sleeve Condition,Collection Notes\n" +
+	"SHVL 804,Pink Floyd,The Dark Side Of The Moon,Harvest,\"LP, Album\",5,1973,1,Uncategorized,2021-03-04 10:20:30,Very Good Plus (VG+),Generic,Seam split\n" +
+	"none,Nirvana (2),Nevermind,DGC,\"File, MP3, Album\",,1991,2,Uncategorized,2021-03-05 10:00:00,,,\n" +
+	"SHVL 822,Pink Floyd,The Wall,Harvest,\"2xLP, Album\",,1979,3,Uncategorized,,Good Plus (G+),Fair (F),\n" +
+	"none,Joni Mitchell,Blue,Reprise,\"CD, Album\",,1971,4,Uncategorized,,,,\n" +
+	"none,Joni Mitchell,,Reprise,\"LP, Album\",,1971,5,Uncategorized,,,,\n" +
+	"none,Pink Floyd*,Dark Side of the Moon (Remastered),Harvest,\"LP, Album, RE\",,2016,6,Uncategorized,,,,\n"
+
+func TestCollectionService_ImportDiscogs(t *testing.T) {
+	newService := func() *services.CollectionService {
+		cs, _ := services.NewCollectionService(
+			services.WithRecordMemoryRepository(),
+			services.WithSongMemoryRepository(),
+		)
+		return cs
+	}
+
+	t.Run("Import", func(t *testing.T) {
+		cs := newService()
+		wall, _ := cs.AddRecord(uuid.New(), "Wall, The", "vinyl")
+
+		summary, err := cs.ImportDiscogs(strings.NewReader(discogsExport), false)
+		assert.NoError(t, err)
+
+		assert.Len(t, summary.Created, 2)
+		assert.Equal(t, 2, summary.Created[0].Line)
+		assert.Equal(t, 3, summary.Created[1].Line)
+
+		assert.Len(t, summary.Skipped, 2)
+		assert.Equal(t, wall.ID, summary.Skipped[0].RecordID)
+		assert.Equal(t, summary.Created[0].RecordID, summary.Skipped[1].RecordID)
+
+		assert.Len(t, summary.Failed, 2)
+		assert.Equal(t, 5, summary.Failed[0].Line)
+		assert.True(t, summary.Failed[0].Errors.Has("kind"))
+		assert.Equal(t, 6, summary.Failed[1].Line)
+		assert.True(t, summary.Failed[1].Errors.Has("name"))
+
+		records, _ := cs.FindAllRecord()
+		assert.Len(t, records, 3)
+
+		dsotm, err := cs.FindRecord(summary.Created[0].RecordID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, "Pink Floyd", dsotm.Artist)
+		assert.Equal(t, record.KindVinyl, dsotm.Kind)
+		assert.Equal(t, record.GradeVeryGoodPlus, dsotm.MediaGrade)
+		assert.Equal(t, record.Grade(""), dsotm.SleeveGrade)
+		assert.Equal(t, "Seam split", dsotm.Acquisition.Notes)
+
+		nevermind, _ := cs.FindRecord(summary.Created[1].RecordID.String())
+		assert.Equal(t, "Nirvana", nevermind.Artist)
+		assert.Equal(t, record.KindMP3, nevermind.Kind)
+		assert.Equal(t, record.Grade(""), nevermind.MediaGrade)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		cs := newService()
+
+		summary, err := cs.ImportDiscogs(strings.NewReader(discogsExport), true)
+		assert.NoError(t, err)
+		assert.True(t, summary.DryRun)
+		assert.Len(t, summary.Created, 3)
+		assert.Len(t, summary.Skipped, 1)
+
+		records, _ := cs.FindAllRecord()
+		assert.Len(t, records, 0)
+	})
+
+	t.Run("Import twice", func(t *testing.T) {
+		cs := newService()
+		cs.ImportDiscogs(strings.NewReader(discogsExport), false)
+
+		summary, err := cs.ImportDiscogs(strings.NewReader(discogsExport), false)
+		assert.NoError(t, err)
+		assert.Len(t, summary.Created, 0)
+		assert.Len(t, summary.Skipped, 4)
+	})
+}
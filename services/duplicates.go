@@ -0,0 +1,354 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/loan"
+	"github.com/rodrwan/collection/domain/rating"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/release"
+)
+
+var (
+	ErrMergeSameRecord    = errors.New("a record cannot be merged into itself")
+	ErrDuplicateInRelease = errors.New("remove the duplicate from its release before merging")
+	ErrMergeBothLent      = errors.New("both records are lent, return one of them before merging")
+)
+
+// Merge is the outcome of folding a duplicate into another record, with
+// how much was moved over. Playlists counts the playlist entries pointed at
+// the songs of the kept record.
+type Merge struct {
+	Record    record.PublicRecord `json:"record"`
+	Songs     int                 `json:"songs"`
+	Plays     int                 `json:"plays"`
+	Reviews   int                 `json:"reviews"`
+	Loans     int                 `json:"loans"`
+	Playlists int                 `json:"playlists"`
+}
+
+// FindDuplicateRecords reports the pairs of records that look like the same
+// release entered twice, scoring at least minScore.
+func (cs *CollectionService) FindDuplicateRecords(minScore float64) ([]record.Duplicate, error) {
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return []record.Duplicate{}, err
+	}
+
+	tracks := make(map[uuid.UUID][]string, len(records))
+	for _, r := range records {
+		songs, err := cs.songs.FindSongsByRecord(r.GetID())
+		if err != nil {
+			return []record.Duplicate{}, err
+		}
+		for _, s := range songs {
+			tracks[r.GetID()] = append(tracks[r.GetID()], s.GetName())
+		}
+	}
+
+	return record.FindDuplicates(records, tracks, minScore), nil
+}
+
+// MergeRecords folds the duplicate into the kept record and deletes it. Its
+// songs not already on the kept record move over, and so do its plays,
+// loans and the reviews of users who did not review the kept record. Songs
+// the kept record already has are deleted once their plays, playlist
+// entries and reviews point at the kept song. Records that are both lent
+// are not merged, a record has one loan out at most. The merge is written
+// in one transaction.
+func (cs *CollectionService) MergeRecords(keepID, duplicateID uuid.UUID) (Merge, error) {
+	if keepID == duplicateID {
+		return Merge{}, ErrMergeSameRecord
+	}
+
+	keep, err := cs.records.Get(keepID)
+	if err != nil {
+		return Merge{}, err
+	}
+
+	duplicate, err := cs.records.Get(duplicateID)
+	if err != nil {
+		return Merge{}, err
+	}
+
+	if cs.releases != nil {
+		_, err := cs.releases.FindByRecord(duplicateID)
+		if err == nil {
+			return Merge{}, ErrDuplicateInRelease
+		}
+		if err != release.ErrRecordNoRelease {
+			return Merge{}, err
+		}
+	}
+
+	lent, err := cs.bothLent(keepID, duplicateID)
+	if err != nil {
+		return Merge{}, err
+	}
+	if lent {
+		return Merge{}, ErrMergeBothLent
+	}
+
+	var merge Merge
+	err = cs.inTransaction(func(tx *CollectionService) error {
+		var err error
+		merge, err = tx.mergeRecords(&keep, duplicate)
+		return err
+	})
+	if err != nil {
+		return Merge{}, err
+	}
+
+	records, err := cs.withRecordDetails([]record.PublicRecord{keep.ToPublic()})
+	if err != nil {
+		return Merge{}, err
+	}
+	merge.Record = records[0]
+
+	return merge, nil
+}
+
+// mergeRecords writes the merge of the duplicate into the kept record.
+func (cs *CollectionService) mergeRecords(keep *record.Record, duplicate record.Record) (Merge, error) {
+	keepID, duplicateID := keep.GetID(), duplicate.GetID()
+
+	var merge Merge
+
+	songIDs, moved, err := cs.mergeSongs(keepID, duplicateID)
+	if err != nil {
+		return Merge{}, err
+	}
+	merge.Songs = moved
+
+	if merge.Plays, err = cs.mergePlays(keepID, duplicateID, songIDs); err != nil {
+		return Merge{}, err
+	}
+
+	if merge.Playlists, err = cs.mergePlaylists(songIDs); err != nil {
+		return Merge{}, err
+	}
+
+	if merge.Reviews, err = cs.moveReviews(rating.SubjectRecord, duplicateID, keepID); err != nil {
+		return Merge{}, err
+	}
+	for from, to := range songIDs {
+		if from == to {
+			continue
+		}
+		moved, err := cs.moveReviews(rating.SubjectSong, from, to)
+		if err != nil {
+			return Merge{}, err
+		}
+		merge.Reviews += moved
+		if err := cs.songs.Delete(from); err != nil {
+			return Merge{}, err
+		}
+	}
+
+	if merge.Loans, err = cs.mergeLoans(keepID, duplicateID); err != nil {
+		return Merge{}, err
+	}
+
+	keep.Merge(duplicate)
+	if err := cs.records.Update(keep); err != nil {
+		return Merge{}, err
+	}
+
+	if err := cs.records.Delete(duplicateID); err != nil {
+		return Merge{}, err
+	}
+
+	return merge, nil
+}
+
+// bothLent reports whether the two records each have a loan out.
+func (cs *CollectionService) bothLent(keepID, duplicateID uuid.UUID) (bool, error) {
+	if cs.loans == nil {
+		return false, nil
+	}
+
+	for _, id := range []uuid.UUID{keepID, duplicateID} {
+		if _, err := cs.loans.FindActiveByRecord(id); err != nil {
+			if err == loan.ErrLoanNotFound {
+				return false, nil
+			}
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// mergeSongs moves the songs of the duplicate that the kept record lacks.
+// It returns where each song of the duplicate ends up, for the plays,
+// playlists and reviews that point at it.
+func (cs *CollectionService) mergeSongs(keepID, duplicateID uuid.UUID) (map[uuid.UUID]uuid.UUID, int, error) {
+	kept, err := cs.songs.FindSongsByRecord(keepID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	byName := make(map[string]uuid.UUID, len(kept))
+	for _, s := range kept {
+		byName[record.NormalizeName(s.GetName())] = s.GetID()
+	}
+
+	songs, err := cs.songs.FindSongsByRecord(duplicateID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	songIDs := make(map[uuid.UUID]uuid.UUID, len(songs))
+	moved := 0
+	for _, s := range songs {
+		if id, ok := byName[record.NormalizeName(s.GetName())]; ok {
+			songIDs[s.GetID()] = id
+			continue
+		}
+
+		s := s
+		s.SetRecordID(keepID)
+		if err := cs.songs.Update(&s); err != nil {
+			return nil, 0, err
+		}
+		songIDs[s.GetID()] = s.GetID()
+		moved++
+	}
+
+	return songIDs, moved, nil
+}
+
+func (cs *CollectionService) mergePlays(keepID, duplicateID uuid.UUID, songIDs map[uuid.UUID]uuid.UUID) (int, error) {
+	if cs.plays == nil {
+		return 0, nil
+	}
+
+	plays, err := cs.plays.FindPlays(time.Time{}, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, p := range plays {
+		if p.GetRecordID() != duplicateID {
+			continue
+		}
+
+		p := p
+		p.SetRecordID(keepID)
+		if id, ok := songIDs[p.GetSongID()]; ok {
+			p.SetSongID(id)
+		}
+		if err := cs.plays.Update(&p); err != nil {
+			return 0, err
+		}
+		moved++
+	}
+
+	return moved, nil
+}
+
+// mergePlaylists points the playlist entries of the folded songs at the
+// songs of the kept record.
+func (cs *CollectionService) mergePlaylists(songIDs map[uuid.UUID]uuid.UUID) (int, error) {
+	if cs.playlists == nil {
+		return 0, nil
+	}
+
+	playlists, err := cs.playlists.FindPlaylists()
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, p := range playlists {
+		changed := 0
+		for from, to := range songIDs {
+			if from != to {
+				changed += p.ReplaceSong(from, to)
+			}
+		}
+		if changed == 0 {
+			continue
+		}
+
+		p := p
+		if err := cs.playlists.Update(&p); err != nil {
+			return 0, err
+		}
+		moved += changed
+	}
+
+	return moved, nil
+}
+
+// moveReviews moves the reviews of a subject to another one. The reviews of
+// users who already reviewed the other subject are deleted, theirs stands.
+func (cs *CollectionService) moveReviews(subject rating.Subject, fromID, toID uuid.UUID) (int, error) {
+	if cs.reviews == nil {
+		return 0, nil
+	}
+
+	kept, err := cs.reviews.FindBySubject(subject, toID)
+	if err != nil {
+		return 0, err
+	}
+
+	reviewed := make(map[string]bool, len(kept))
+	for _, r := range kept {
+		reviewed[r.GetUser()] = true
+	}
+
+	reviews, err := cs.reviews.FindBySubject(subject, fromID)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, r := range reviews {
+		if reviewed[r.GetUser()] {
+			if err := cs.reviews.Delete(r.GetID()); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		r := r
+		r.SetSubjectID(toID)
+		if err := cs.reviews.Update(&r); err != nil {
+			return 0, err
+		}
+		moved++
+	}
+
+	return moved, nil
+}
+
+func (cs *CollectionService) mergeLoans(keepID, duplicateID uuid.UUID) (int, error) {
+	if cs.loans == nil {
+		return 0, nil
+	}
+
+	loans, err := cs.loans.FindLoans()
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	for _, l := range loans {
+		if l.GetRecordID() != duplicateID {
+			continue
+		}
+
+		l := l
+		l.SetRecordID(keepID)
+		if err := cs.loans.Update(&l); err != nil {
+			return 0, err
+		}
+		moved++
+	}
+
+	return moved, nil
+}
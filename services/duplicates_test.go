@@ -0,0 +1,101 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/playlist"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_Duplicates(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithPlayMemoryRepository(),
+		services.WithReviewMemoryRepository(),
+		services.WithLoanMemoryRepository(),
+		services.WithReleaseMemoryRepository(),
+		services.WithPlaylistMemoryRepository(),
+	)
+
+	wall, _ := cs.AddRecord(uuid.New(), "The Wall", "vinyl")
+	dup, _ := cs.AddRecord(uuid.New(), "Wall, The (Remastered)", "vinyl")
+	cs.AddRecord(uuid.New(), "Blue", "vinyl")
+
+	cs.AddSongToRecord(wall.ToRecord(), "Hey You", 280)
+	cs.AddSongToRecord(dup.ToRecord(), "Hey You", 281)
+	cs.AddSongToRecord(dup.ToRecord(), "Comfortably Numb", 382)
+	dupSongs, _ := cs.FindSongsByRecord(dup.ID)
+
+	duplicates, err := cs.FindDuplicateRecords(record.DefaultDuplicateScore)
+	assert.Nil(t, err)
+	assert.Len(t, duplicates, 1)
+	assert.Equal(t, wall.ID, duplicates[0].Records[0].ID)
+	assert.Equal(t, dup.ID, duplicates[0].Records[1].ID)
+
+	cs.LogPlay(services.PlayInput{RecordID: dup.ID, SongID: dupSongs[0].ID})
+	cs.LogPlay(services.PlayInput{RecordID: dup.ID})
+	cs.RateRecord(dup.ID, "percy", 4, "")
+	cs.RateRecord(wall.ID, "ana", 3, "")
+	cs.RateRecord(dup.ID, "ana", 2, "")
+	cs.RateSong(dupSongs[0].ID, "percy", 5, "")
+	mix, _ := cs.CreatePlaylist("mix", playlist.DuplicatesAllow, []uuid.UUID{dupSongs[0].ID, dupSongs[1].ID})
+	cs.LendRecord(dup.ID, "ana", time.Now(), time.Now().AddDate(0, 0, 7))
+
+	_, err = cs.MergeRecords(wall.ID, wall.ID)
+	assert.Equal(t, services.ErrMergeSameRecord, err)
+
+	_, err = cs.MergeRecords(wall.ID, uuid.New())
+	assert.Equal(t, record.ErrRecordNotFound, err)
+
+	cs.LendRecord(wall.ID, "percy", time.Now(), time.Time{})
+	_, err = cs.MergeRecords(wall.ID, dup.ID)
+	assert.Equal(t, services.ErrMergeBothLent, err)
+	cs.ReturnRecord(wall.ID, time.Time{})
+
+	r, _ := cs.CreateRelease("The Wall", dup.ID)
+	_, err = cs.MergeRecords(wall.ID, dup.ID)
+	assert.Equal(t, services.ErrDuplicateInRelease, err)
+	cs.RemoveReleaseVariant(r.ID, dup.ID)
+
+	merge, err := cs.MergeRecords(wall.ID, dup.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, merge.Songs)
+	assert.Equal(t, 2, merge.Plays)
+	// the record review of percy and the review of the shared song
+	assert.Equal(t, 2, merge.Reviews)
+	assert.Equal(t, 1, merge.Loans)
+	assert.Equal(t, 1, merge.Playlists)
+	assert.Equal(t, 2, merge.Record.PlayCount)
+	assert.Equal(t, 2, merge.Record.RatingCount)
+
+	_, err = cs.FindRecord(dup.ID.String())
+	assert.Equal(t, record.ErrRecordNotFound, err)
+
+	songs, _ := cs.FindSongsByRecord(wall.ID)
+	assert.Len(t, songs, 2)
+	// the play and review of the shared song now count for the kept one
+	heyYou := songs[0]
+	assert.Equal(t, "Hey You", heyYou.Name)
+	assert.Equal(t, 1, heyYou.PlayCount)
+	reviews, _ := cs.FindSongReviews(heyYou.ID)
+	assert.Len(t, reviews.Reviews, 1)
+
+	_, err = cs.FindSongReviews(dupSongs[0].ID)
+	assert.Equal(t, song.ErrSongNotFound, err)
+
+	mix, _ = cs.FindPlaylist(mix.ID)
+	assert.Equal(t, []uuid.UUID{heyYou.ID, dupSongs[1].ID}, mix.SongIDs)
+
+	loans, _ := cs.FindCurrentLoans()
+	assert.Len(t, loans, 1)
+	assert.Equal(t, wall.ID, loans[0].RecordID)
+
+	_, err = cs.FindRecordVariants(dup.ID)
+	assert.Equal(t, record.ErrRecordNotFound, err)
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+)
+
+// GradeRecord adds an entry to the grading history of a vinyl record. A zero
+// date means the record is being graded now.
+func (cs *CollectionService) GradeRecord(id uuid.UUID, media, sleeve string, notes string, date time.Time) (record.PublicRecord, error) {
+	rec, err := cs.records.Get(id)
+	if err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	// grades that do not parse are handed over as they are, so the record
+	// reports them together with any other invalid field
+	mediaGrade, err := record.ParseGrade(media)
+	if err != nil {
+		mediaGrade = record.Grade(media)
+	}
+
+	sleeveGrade, err := record.ParseGrade(sleeve)
+	if err != nil {
+		sleeveGrade = record.Grade(sleeve)
+	}
+
+	if date.IsZero() {
+		date = time.Now().UTC()
+	}
+
+	if err := rec.Grade(mediaGrade, sleeveGrade, notes, date); err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	if err := cs.records.Update(&rec); err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	return rec.ToPublic(), nil
+}
@@ -0,0 +1,75 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_GradeRecord(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+
+	vinyl, _ := cs.AddRecord(uuid.New(), "v1", "vinyl")
+	mp3, _ := cs.AddRecord(uuid.New(), "m1", "mp3")
+
+	tests := []struct {
+		name        string
+		id          uuid.UUID
+		media       string
+		sleeve      string
+		expectedErr error
+	}{
+		{
+			name:   "Grade vinyl",
+			id:     vinyl.ID,
+			media:  "vg+",
+			sleeve: "VG",
+		},
+		{
+			name:        "Invalid sleeve grade",
+			id:          vinyl.ID,
+			media:       "NM",
+			sleeve:      "lala",
+			expectedErr: record.ErrInvalidGrade,
+		},
+		{
+			name:        "Grade mp3",
+			id:          mp3.ID,
+			media:       "NM",
+			expectedErr: record.ErrGradingNotAllowed,
+		},
+		{
+			name:        "Unknown record",
+			id:          uuid.New(),
+			media:       "NM",
+			expectedErr: record.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := cs.GradeRecord(test.id, test.media, test.sleeve, "", time.Time{})
+			if err != nil {
+				assert.True(t, errors.Is(err, test.expectedErr))
+				return
+			}
+
+			assert.Equal(t, record.GradeVeryGoodPlus, got.MediaGrade)
+			assert.Equal(t, record.GradeVeryGood, got.SleeveGrade)
+			assert.Equal(t, 1, len(got.GradingHistory))
+		})
+	}
+
+	graded, err := cs.FindRecords(record.Filter{MinGrade: record.GradeVeryGood})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(graded))
+	assert.Equal(t, vinyl.ID, graded[0].ID)
+}
@@ -0,0 +1,436 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/play"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/domain/validation"
+	"github.com/rodrwan/collection/pkg/itunes"
+)
+
+var (
+	ErrSongExists = errors.New("song already exists")
+	ErrNotMusic   = errors.New("podcasts and videos are not imported")
+)
+
+// itunesKinds maps the extensions of the files of an iTunes library to
+// record kinds.
+var itunesKinds = map[string]string{
+	".mp3":  record.KindMP3,
+	".m4a":  record.KindAAC,
+	".m4p":  record.KindAAC,
+	".aac":  record.KindAAC,
+	".flac": record.KindLossless,
+	".wav":  record.KindLossless,
+	".aif":  record.KindLossless,
+	".aiff": record.KindLossless,
+}
+
+// itunesKind finds the kind of a track by its file, or by its description
+// for tracks without one, such as those of the Apple Music catalog. Apple
+// Lossless files share the .m4a extension with AAC ones and are only told
+// apart by their description.
+func itunesKind(t itunes.Track) (string, error) {
+	description := strings.ToLower(t.Kind)
+	if strings.Contains(description, "lossless") {
+		return record.KindLossless, nil
+	}
+	if kind, ok := itunesKinds[t.Ext()]; ok {
+		return kind, nil
+	}
+
+	switch {
+	case strings.Contains(description, "aac"):
+		return record.KindAAC, nil
+	case strings.Contains(description, "mpeg"):
+		return record.KindMP3, nil
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, t.Kind)
+}
+
+// ITunesTrack is a track of an iTunes library that was not imported, with
+// the reason why. Tracks already in the collection point at their song.
+type ITunesTrack struct {
+	TrackID  int               `json:"trackId"`
+	Name     string            `json:"name"`
+	Artist   string            `json:"artist,omitempty"`
+	Album    string            `json:"album,omitempty"`
+	RecordID uuid.UUID         `json:"recordId,omitempty"`
+	SongID   uuid.UUID         `json:"songId,omitempty"`
+	Reason   string            `json:"reason"`
+	Errors   validation.Errors `json:"errors,omitempty"`
+}
+
+// ITunesImport tells which records an iTunes library import created or
+// added songs to, or would on a dry run, how many plays it logged, and
+// which tracks it skipped or could not import.
+type ITunesImport struct {
+	DryRun  bool            `json:"dryRun"`
+	Tracks  int             `json:"tracks"`
+	Created []ScannedRecord `json:"created"`
+	Updated []ScannedRecord `json:"updated"`
+	Plays   int             `json:"plays"`
+	Skipped []ITunesTrack   `json:"skipped"`
+	Failed  []ITunesTrack   `json:"failed"`
+}
+
+func (ii *ITunesImport) skip(t itunes.Track, err error) {
+	ii.Skipped = append(ii.Skipped, newITunesTrack(t, err))
+}
+
+func (ii *ITunesImport) fail(t itunes.Track, err error) {
+	ii.Failed = append(ii.Failed, newITunesTrack(t, err))
+}
+
+func newITunesTrack(t itunes.Track, err error) ITunesTrack {
+	it := ITunesTrack{
+		TrackID: t.ID,
+		Name:    t.Name,
+		Artist:  t.Artist,
+		Album:   t.Album,
+		Reason:  err.Error(),
+	}
+	var errs validation.Errors
+	if errors.As(err, &errs) {
+		it.Errors = errs
+	}
+	return it
+}
+
+// itunesAlbum is the tracks of a record, grouped by album.
+type itunesAlbum struct {
+	name   string
+	artist string
+	kind   string
+	tracks []itunes.Track
+}
+
+// ImportITunes creates a record for every album of an iTunes library, with
+// a song for each of its tracks carrying the track length and number. The
+// play count of a track is logged as that many plays of its song at the
+// date it was last played, when a play repository is configured.
+//
+// Songs keep the persistent ID of their track, so importing a newer copy of
+// the library skips the tracks already imported and only logs the plays
+// they gained since, or all of them when their count went down as the
+// library counts were reset. Tracks new to the collection are matched by name with
+// the songs of their record that were not imported from iTunes, each song
+// at most once, so same-named tracks of an album all get a song. Podcasts,
+// videos and files of kinds we do not support are reported and skipped.
+func (cs *CollectionService) ImportITunes(r io.Reader, dryRun bool) (ITunesImport, error) {
+	result := ITunesImport{
+		DryRun:  dryRun,
+		Created: make([]ScannedRecord, 0),
+		Updated: make([]ScannedRecord, 0),
+		Skipped: make([]ITunesTrack, 0),
+		Failed:  make([]ITunesTrack, 0),
+	}
+
+	lib, err := itunes.Read(r)
+	if err != nil {
+		return result, err
+	}
+	result.Tracks = len(lib.Tracks)
+
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return result, err
+	}
+
+	existing := make(importedRecords, len(records))
+	for _, rec := range records {
+		existing.add(rec)
+	}
+
+	all, err := cs.songs.FindSongs()
+	if err != nil {
+		return result, err
+	}
+	imported := make(map[string]song.Song)
+	for _, s := range all {
+		if s.GetITunesID() != "" {
+			imported[s.GetITunesID()] = s
+		}
+	}
+
+	for _, album := range groupITunesAlbums(lib.Tracks, &result) {
+		rec, found := existing.find(album.name, album.artist, album.kind)
+		if !found {
+			rec, err = newITunesRecord(album)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		// byName holds the songs of the record that were not imported
+		// from iTunes, the ones a new track may stand for.
+		byName := make(map[string][]song.Song)
+		if found {
+			songs, err := cs.songs.FindSongsByRecord(rec.GetID())
+			if err != nil {
+				return result, err
+			}
+			for _, s := range songs {
+				if s.GetITunesID() == "" {
+					key := record.NormalizeName(s.GetName())
+					byName[key] = append(byName[key], s)
+				}
+			}
+		}
+
+		var songs, updated []song.Song
+		var plays []play.Play
+		for _, t := range album.tracks {
+			s, ok := imported[t.PersistentID]
+			if !ok {
+				key := record.NormalizeName(t.Name)
+				if matches := byName[key]; len(matches) > 0 {
+					s, ok = matches[0], true
+					byName[key] = matches[1:]
+				}
+			}
+
+			if ok {
+				skipped := newITunesTrack(t, ErrSongExists)
+				skipped.RecordID = s.GetRecordID()
+				skipped.SongID = s.GetID()
+				result.Skipped = append(result.Skipped, skipped)
+
+				changed := s.GetITunesID() == "" && t.PersistentID != ""
+				s.SetITunesID(t.PersistentID)
+				if cs.plays != nil && t.PlayCount != s.GetITunesPlays() {
+					// a count below the last one means the library counts
+					// were reset, every play it holds is new
+					n := t.PlayCount - s.GetITunesPlays()
+					if n < 0 {
+						n = t.PlayCount
+					}
+					newPlays, err := itunesPlays(t, s, n)
+					if err != nil {
+						return result, err
+					}
+					plays = append(plays, newPlays...)
+					s.SetITunesPlays(t.PlayCount)
+					changed = true
+				}
+				if changed {
+					updated = append(updated, s)
+				}
+				continue
+			}
+
+			s, err := song.NewSong(t.Name, t.Seconds(), rec.GetID())
+			if err != nil {
+				result.fail(t, err)
+				continue
+			}
+			s.SetTrack(t.TrackNumber)
+			s.SetITunesID(t.PersistentID)
+			if cs.plays != nil {
+				newPlays, err := itunesPlays(t, s, t.PlayCount)
+				if err != nil {
+					return result, err
+				}
+				plays = append(plays, newPlays...)
+				s.SetITunesPlays(t.PlayCount)
+			}
+			songs = append(songs, s)
+		}
+		result.Plays += len(plays)
+
+		if len(songs) > 0 {
+			scanned := ScannedRecord{
+				RecordID: rec.GetID(),
+				Name:     rec.GetName(),
+				Artist:   rec.GetArtist(),
+				Songs:    len(songs),
+			}
+			if found {
+				result.Updated = append(result.Updated, scanned)
+			} else {
+				result.Created = append(result.Created, scanned)
+				existing.add(rec)
+			}
+		}
+
+		if dryRun || len(songs) == 0 && len(updated) == 0 {
+			continue
+		}
+		err := cs.inTransaction(func(tx *CollectionService) error {
+			if !found && len(songs) > 0 {
+				if err := tx.records.Add(rec); err != nil {
+					return err
+				}
+			}
+			if len(songs) > 0 {
+				if err := tx.songs.AddBatch(songs); err != nil {
+					return err
+				}
+			}
+			for i := range updated {
+				if err := tx.songs.Update(&updated[i]); err != nil {
+					return err
+				}
+			}
+			if len(plays) > 0 {
+				return tx.plays.AddBatch(plays)
+			}
+			return nil
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// itunesPlays logs n plays of the song of a track, at the date the track
+// was last played.
+func itunesPlays(t itunes.Track, s song.Song, n int) ([]play.Play, error) {
+	var plays []play.Play
+	for i := 0; i < n; i++ {
+		p, err := play.NewPlay(s.GetRecordID(), s.GetID(), itunesPlayedAt(t))
+		if err != nil {
+			return nil, err
+		}
+		plays = append(plays, p)
+	}
+	return plays, nil
+}
+
+// groupITunesAlbums groups the music tracks of a library into the records
+// they belong to, in the order their first track was added. Tracks without
+// an album make a record of their own, named after them. Tracks that are
+// not music or not of a supported kind are reported in the import.
+func groupITunesAlbums(tracks []itunes.Track, result *ITunesImport) []*itunesAlbum {
+	var albums []*itunesAlbum
+	byKey := make(map[string]*itunesAlbum)
+
+	for _, t := range tracks {
+		if t.Podcast || t.Video {
+			result.skip(t, ErrNotMusic)
+			continue
+		}
+
+		kind, err := itunesKind(t)
+		if err != nil {
+			var errs validation.Errors
+			result.fail(t, errs.Add("kind", validation.CodeInvalid, err))
+			continue
+		}
+
+		name := t.Album
+		if name == "" {
+			name = t.Name
+		}
+		if strings.TrimSpace(name) == "" {
+			var errs validation.Errors
+			result.fail(t, errs.Add("name", validation.CodeRequired, record.ErrMissingValues))
+			continue
+		}
+
+		key := record.NormalizeName(name) + "|" + kind
+		switch {
+		case t.AlbumArtist != "":
+			key += "|artist:" + record.NormalizeName(t.AlbumArtist)
+		case t.Compilation:
+			key += "|compilation"
+		case t.Album == "":
+			key += "|single:" + record.NormalizeName(t.Artist)
+		default:
+			key += "|artist:" + record.NormalizeName(t.Artist)
+		}
+
+		album, ok := byKey[key]
+		if !ok {
+			album = &itunesAlbum{name: name, kind: kind}
+			byKey[key] = album
+			albums = append(albums, album)
+		}
+		album.tracks = append(album.tracks, t)
+	}
+
+	for _, album := range albums {
+		album.artist = itunesAlbumArtist(album.tracks)
+		sort.SliceStable(album.tracks, func(i, j int) bool {
+			a, b := album.tracks[i], album.tracks[j]
+			if a.DiscNumber != b.DiscNumber {
+				return a.DiscNumber < b.DiscNumber
+			}
+			if a.TrackNumber != b.TrackNumber {
+				return a.TrackNumber < b.TrackNumber
+			}
+			return a.ID < b.ID
+		})
+	}
+
+	return albums
+}
+
+// itunesAlbumArtist is the album artist of the tracks, else various artists
+// for compilations and albums whose tracks have different artists.
+func itunesAlbumArtist(tracks []itunes.Track) string {
+	artist := ""
+	for _, t := range tracks {
+		if t.AlbumArtist != "" {
+			return t.AlbumArtist
+		}
+		switch {
+		case t.Compilation:
+			return variousArtists
+		case t.Artist == "":
+		case artist == "":
+			artist = t.Artist
+		case record.NormalizeName(artist) != record.NormalizeName(t.Artist):
+			return variousArtists
+		}
+	}
+	return artist
+}
+
+// newITunesRecord builds the record of an album, acquired when its first
+// track was added to the library.
+func newITunesRecord(album *itunesAlbum) (record.Record, error) {
+	rec, err := record.NewRecord(album.name, album.kind)
+	if err != nil {
+		return record.Record{}, err
+	}
+	rec.SetArtist(album.artist)
+
+	var added time.Time
+	for _, t := range album.tracks {
+		if !t.DateAdded.IsZero() && (added.IsZero() || t.DateAdded.Before(added)) {
+			added = t.DateAdded
+		}
+	}
+	if !added.IsZero() {
+		if err := rec.SetAcquisition(record.Acquisition{Date: added}); err != nil {
+			return record.Record{}, err
+		}
+	}
+
+	return rec, nil
+}
+
+// itunesPlayedAt is when the plays of a track are logged. The library only
+// keeps the last play, else the date the track was added is the best we
+// know.
+func itunesPlayedAt(t itunes.Track) time.Time {
+	switch {
+	case !t.PlayDate.IsZero():
+		return t.PlayDate
+	case !t.DateAdded.IsZero():
+		return t.DateAdded
+	}
+	return time.Now().UTC()
+}
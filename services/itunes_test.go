@@ -0,0 +1,210 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/pkg/itunes"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// itunesTrack writes a track of an iTunes library, with its key/value
+// pairs already formatted.
+func itunesTrack(id string, pairs ...string) string {
+	var b strings.Builder
+	b.WriteString("<key>" + id + "</key><dict><key>Track ID</key><integer>" + id + "</integer>")
+	for i := 0; i+1 < len(pairs); i += 2 {
+		b.WriteString("<key>" + pairs[i] + "</key>" + pairs[i+1])
+	}
+	b.WriteString("</dict>\n")
+	return b.String()
+}
+
+func itunesLibrary(tracks ...string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple Computer//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0"><dict><key>Major Version</key><integer>1</integer><key>Tracks</key><dict>
+` + strings.Join(tracks, "") + `</dict></dict></plist>`
+}
+
+func plistString(s string) string {
+	return "<string>" + s + "</string>"
+}
+
+func TestCollectionService_ImportITunes(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithPlayMemoryRepository(),
+	)
+
+	blue, _ := cs.AddRecord(uuid.New(), "Blue", "aac")
+	assert.Nil(t, cs.AddSongToRecord(blue.ToRecord(), "River", 240))
+
+	lib := itunesLibrary(
+		itunesTrack("101", "Name", plistString("Hey You"), "Artist", plistString("Pink Floyd"), "Album", plistString("The Wall"),
+			"Kind", plistString("AAC audio file"), "Total Time", "<integer>280493</integer>",
+			"Disc Number", "<integer>2</integer>", "Track Number", "<integer>1</integer>",
+			"Date Added", "<date>2009-05-01T10:00:00Z</date>",
+			"Play Count", "<integer>3</integer>", "Play Date UTC", "<date>2020-01-02T03:04:05Z</date>",
+			"Location", plistString("file:///Music/Pink%20Floyd/The%20Wall/2-01%20Hey%20You.m4a")),
+		itunesTrack("102", "Name", plistString("In the Flesh?"), "Artist", plistString("Pink Floyd"), "Album", plistString("The Wall"),
+			"Kind", plistString("AAC audio file"), "Total Time", "<integer>199000</integer>",
+			"Disc Number", "<integer>1</integer>", "Track Number", "<integer>1</integer>",
+			"Date Added", "<date>2008-05-01T10:00:00Z</date>",
+			"Location", plistString("file:///Music/Pink%20Floyd/The%20Wall/1-01%20In%20the%20Flesh_.m4a")),
+		itunesTrack("103", "Name", plistString("So What"), "Artist", plistString("Miles Davis"), "Album", plistString("Kind of Blue"),
+			"Kind", plistString("Apple Lossless audio file"), "Total Time", "<integer>562000</integer>",
+			"Location", plistString("file:///Music/Miles%20Davis/Kind%20of%20Blue/01%20So%20What.m4a")),
+		itunesTrack("104", "Name", plistString("Song A"), "Artist", plistString("One"), "Album", plistString("Hits"),
+			"Compilation", "<true/>", "Location", plistString("file:///Music/Compilations/Hits/01%20Song%20A.mp3")),
+		itunesTrack("105", "Name", plistString("Song B"), "Artist", plistString("Two"), "Album", plistString("Hits"),
+			"Compilation", "<true/>", "Location", plistString("file:///Music/Compilations/Hits/02%20Song%20B.mp3")),
+		itunesTrack("106", "Name", plistString("River"), "Artist", plistString("Joni Mitchell"), "Album", plistString("Blue"),
+			"Kind", plistString("Purchased AAC audio file"), "Track Type", plistString("Remote")),
+		itunesTrack("107", "Name", plistString("Carey"), "Artist", plistString("Joni Mitchell"), "Album", plistString("Blue"),
+			"Kind", plistString("Apple Music AAC audio file"), "Track Type", plistString("Remote"), "Track Number", "<integer>3</integer>"),
+		itunesTrack("108", "Name", plistString("Episode 1"), "Podcast", "<true/>", "Kind", plistString("MPEG audio file")),
+		itunesTrack("109", "Name", plistString("Trailer"), "Has Video", "<true/>", "Location", plistString("file:///Movies/trailer.m4v")),
+		itunesTrack("110", "Name", plistString("Chapter 1"), "Kind", plistString("Audible file"), "Location", plistString("file:///Books/book.aa")),
+	)
+
+	dry, err := cs.ImportITunes(strings.NewReader(lib), true)
+	assert.Nil(t, err)
+	assert.True(t, dry.DryRun)
+	assert.Equal(t, 3, len(dry.Created))
+	assert.Equal(t, 3, dry.Plays)
+	all, _ := cs.FindAllRecord()
+	assert.Equal(t, 1, len(all))
+
+	imported, err := cs.ImportITunes(strings.NewReader(lib), false)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, imported.Tracks)
+	assert.Equal(t, 3, imported.Plays)
+
+	created := make(map[string]services.ScannedRecord)
+	for _, c := range imported.Created {
+		created[c.Name] = c
+	}
+	assert.Equal(t, 3, len(created))
+	assert.Equal(t, "Pink Floyd", created["The Wall"].Artist)
+	assert.Equal(t, "Various Artists", created["Hits"].Artist)
+	assert.Equal(t, 2, created["Hits"].Songs)
+
+	assert.Equal(t, 1, len(imported.Updated))
+	assert.Equal(t, blue.ID, imported.Updated[0].RecordID)
+	assert.Equal(t, 1, imported.Updated[0].Songs)
+
+	skipped := make(map[int]services.ITunesTrack)
+	for _, s := range imported.Skipped {
+		skipped[s.TrackID] = s
+	}
+	assert.Equal(t, 3, len(skipped))
+	assert.Equal(t, services.ErrSongExists.Error(), skipped[106].Reason)
+	assert.Equal(t, blue.ID, skipped[106].RecordID)
+	assert.Equal(t, services.ErrNotMusic.Error(), skipped[108].Reason)
+	assert.Equal(t, services.ErrNotMusic.Error(), skipped[109].Reason)
+
+	assert.Equal(t, 1, len(imported.Failed))
+	assert.Equal(t, 110, imported.Failed[0].TrackID)
+	assert.Equal(t, "kind", imported.Failed[0].Errors[0].Field)
+
+	wall, _ := cs.FindRecord(created["The Wall"].RecordID.String())
+	assert.Equal(t, record.KindAAC, wall.Kind)
+	assert.Equal(t, "2008-05-01", wall.Acquisition.Date.Format("2006-01-02"))
+
+	songs, _ := cs.FindSongsByRecord(wall.ID)
+	assert.Equal(t, 2, len(songs))
+	assert.Equal(t, "In the Flesh?", songs[0].Name)
+	assert.Equal(t, "Hey You", songs[1].Name)
+	assert.Equal(t, int64(280), songs[1].Length)
+	assert.Equal(t, 1, songs[1].Track)
+	assert.Equal(t, 3, songs[1].PlayCount)
+
+	kob, _ := cs.FindRecord(created["Kind of Blue"].RecordID.String())
+	assert.Equal(t, record.KindLossless, kob.Kind)
+
+	again, err := cs.ImportITunes(strings.NewReader(lib), false)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(again.Created))
+	assert.Equal(t, 0, len(again.Updated))
+	assert.Equal(t, 0, again.Plays)
+	// the 7 music tracks, the podcast and the video
+	assert.Equal(t, 9, len(again.Skipped))
+
+	songs, _ = cs.FindSongsByRecord(wall.ID)
+	assert.Equal(t, 3, songs[1].PlayCount)
+}
+
+func TestCollectionService_ImportITunes_Reimport(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithPlayMemoryRepository(),
+	)
+
+	// An Intro entered by hand stands for the first Intro of the library.
+	bowie, _ := cs.AddRecord(uuid.New(), "Live", "mp3")
+	assert.Nil(t, cs.AddSongToRecord(bowie.ToRecord(), "Intro", 60))
+
+	intro := func(id, persistentID, disc, plays string) string {
+		return itunesTrack(id, "Persistent ID", plistString(persistentID), "Name", plistString("Intro"),
+			"Artist", plistString("Bowie"), "Album", plistString("Live"), "Kind", plistString("MPEG audio file"),
+			"Disc Number", "<integer>"+disc+"</integer>", "Track Number", "<integer>1</integer>",
+			"Play Count", "<integer>"+plays+"</integer>", "Play Date UTC", "<date>2020-01-02T03:04:05Z</date>")
+	}
+
+	imported, err := cs.ImportITunes(strings.NewReader(itunesLibrary(intro("1", "AAAA", "1", "2"), intro("2", "BBBB", "2", "1"))), false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(imported.Updated))
+	assert.Equal(t, 1, imported.Updated[0].Songs)
+	assert.Equal(t, 1, len(imported.Skipped))
+	assert.Equal(t, 3, imported.Plays)
+
+	songs, _ := cs.FindSongsByRecord(bowie.ID)
+	assert.Equal(t, 2, len(songs))
+	assert.Equal(t, "AAAA", songs[0].ITunesID)
+	assert.Equal(t, 2, songs[0].PlayCount)
+	assert.Equal(t, "BBBB", songs[1].ITunesID)
+	assert.Equal(t, 1, songs[1].PlayCount)
+
+	// Only the plays gained since the last import are logged.
+	again, err := cs.ImportITunes(strings.NewReader(itunesLibrary(intro("1", "AAAA", "1", "5"), intro("2", "BBBB", "2", "1"))), false)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(again.Updated))
+	assert.Equal(t, 2, len(again.Skipped))
+	assert.Equal(t, 3, again.Plays)
+
+	songs, _ = cs.FindSongsByRecord(bowie.ID)
+	assert.Equal(t, 2, len(songs))
+	assert.Equal(t, 5, songs[0].PlayCount)
+	assert.Equal(t, 5, songs[0].ITunesPlays)
+	assert.Equal(t, 1, songs[1].PlayCount)
+
+	// A lower count is a library whose counts were reset, its plays are new.
+	reset, err := cs.ImportITunes(strings.NewReader(itunesLibrary(intro("1", "AAAA", "1", "2"), intro("2", "BBBB", "2", "1"))), false)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, reset.Plays)
+
+	songs, _ = cs.FindSongsByRecord(bowie.ID)
+	assert.Equal(t, 7, songs[0].PlayCount)
+	assert.Equal(t, 2, songs[0].ITunesPlays)
+
+	// and the next import counts from the reset
+	again, err = cs.ImportITunes(strings.NewReader(itunesLibrary(intro("1", "AAAA", "1", "3"), intro("2", "BBBB", "2", "1"))), false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, again.Plays)
+}
+
+func TestCollectionService_ImportITunes_NotALibrary(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+
+	_, err := cs.ImportITunes(strings.NewReader(`<plist><dict><key>Playlists</key><array/></dict></plist>`), false)
+	assert.Equal(t, itunes.ErrNotALibrary, err)
+}
@@ -0,0 +1,495 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/artwork"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/pkg/audio"
+	"github.com/rodrwan/collection/pkg/audio/flac"
+	"github.com/rodrwan/collection/pkg/audio/mp3"
+	"github.com/rodrwan/collection/pkg/audio/ogg"
+)
+
+var (
+	ErrNoLibrary    = errors.New("no music library configured")
+	ErrScanRunning  = errors.New("a library scan is already running")
+	ErrScanNotFound = errors.New("library scan not found")
+)
+
+// variousArtists is the artist of records whose songs have different
+// artists and no album artist.
+const variousArtists = "Various Artists"
+
+// libraryReader reads an audio file and tells the kind of the records made
+// of it.
+type libraryReader func(path string) (audio.Track, string, error)
+
+// libraryFormats lists the formats the library scan reads, by extension.
+var libraryFormats = map[string]libraryReader{
+	".mp3":  readMP3,
+	".flac": readFLAC,
+	".ogg":  readOgg,
+	".oga":  readOgg,
+}
+
+func readMP3(path string) (audio.Track, string, error) {
+	track, err := mp3.ReadFile(path)
+	return track, record.KindMP3, err
+}
+
+func readFLAC(path string) (audio.Track, string, error) {
+	track, err := flac.ReadFile(path)
+	return track, record.KindLossless, err
+}
+
+// readOgg tells FLAC streams, which are lossless, from Vorbis ones.
+func readOgg(path string) (audio.Track, string, error) {
+	track, codec, err := ogg.ReadFile(path)
+	if codec == ogg.CodecFLAC {
+		return track, record.KindLossless, err
+	}
+	return track, record.KindOgg, err
+}
+
+// WithMusicLibrary sets the directory the library scan job walks.
+func WithMusicLibrary(dir string) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		os.library = dir
+		return nil
+	}
+}
+
+// ScanError is a file or directory the library scan could not read.
+type ScanError struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// ScannedRecord is a record the library scan created or added songs to.
+// Cover tells whether a picture embedded in its files became its cover.
+type ScannedRecord struct {
+	RecordID uuid.UUID `json:"recordId"`
+	Name     string    `json:"name"`
+	Artist   string    `json:"artist,omitempty"`
+	Songs    int       `json:"songs"`
+	Cover    bool      `json:"cover,omitempty"`
+}
+
+// LibraryScan tells what a library scan found and what it created, or
+// would create on a dry run. Records already holding every song found, and
+// a cover when one is embedded, are only counted as unchanged.
+type LibraryScan struct {
+	DryRun    bool            `json:"dryRun"`
+	Files     int             `json:"files"`
+	Created   []ScannedRecord `json:"created"`
+	Updated   []ScannedRecord `json:"updated"`
+	Unchanged int             `json:"unchanged"`
+	Failed    []ScanError     `json:"failed"`
+}
+
+// libraryFile is an audio file found by the library scan, rel being its
+// path relative to the library as kept on songs. Its track does not keep
+// the embedded pictures, cover tells whether one is a usable cover, to be
+// read again from the file when uploaded.
+type libraryFile struct {
+	path  string
+	rel   string
+	kind  string
+	track audio.Track
+	cover bool
+}
+
+// libraryAlbum is the files of a record, grouped by album tag.
+type libraryAlbum struct {
+	name   string
+	artist string
+	kind   string
+	files  []libraryFile
+}
+
+// coverFile is the first file of the album with a usable cover embedded.
+func (a *libraryAlbum) coverFile() (string, bool) {
+	for _, f := range a.files {
+		if f.cover {
+			return f.path, true
+		}
+	}
+	return "", false
+}
+
+// readCover reads again the cover embedded in an audio file.
+func readCover(path string) ([]byte, error) {
+	track, _, err := libraryFormats[strings.ToLower(filepath.Ext(path))](path)
+	if err != nil {
+		return nil, err
+	}
+
+	picture, _ := track.Cover()
+	return picture.Data, nil
+}
+
+// ScanLibrary walks dir for audio files, groups them into records by album
+// and adds the records and songs that are not in the collection yet, so
+// scanning again only picks up what changed. Files whose album artist is
+// unknown are grouped by album and directory; those without an album tag
+// are named after their directory. When a blob store is configured, records
+// without a cover get the first JPEG or PNG picture embedded in their files.
+// A cover that fails to upload is reported and the record is only counted
+// as updated if songs were added; the next scan tries again.
+func (cs *CollectionService) ScanLibrary(dir string, dryRun bool) (LibraryScan, error) {
+	scan := LibraryScan{
+		DryRun:  dryRun,
+		Created: make([]ScannedRecord, 0),
+		Updated: make([]ScannedRecord, 0),
+		Failed:  make([]ScanError, 0),
+	}
+
+	files, err := walkLibrary(dir, &scan)
+	if err != nil {
+		return scan, err
+	}
+	scan.Files = len(files)
+
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return scan, err
+	}
+
+	existing := make(importedRecords, len(records))
+	for _, rec := range records {
+		existing.add(rec)
+	}
+
+	for _, album := range groupAlbums(files) {
+		rec, found := existing.find(album.name, album.artist, album.kind)
+		if !found {
+			rec, err = record.NewRecord(album.name, album.kind)
+			if err != nil {
+				return scan, err
+			}
+			rec.SetArtist(album.artist)
+		}
+
+		songs, located, err := cs.missingSongs(rec.GetID(), found, album.files)
+		if err != nil {
+			return scan, err
+		}
+
+		var coverPath string
+		if cs.blobs != nil && rec.GetCover().IsZero() {
+			coverPath, _ = album.coverFile()
+		}
+
+		scanned := ScannedRecord{
+			RecordID: rec.GetID(),
+			Name:     rec.GetName(),
+			Artist:   rec.GetArtist(),
+			Songs:    len(songs),
+			Cover:    coverPath != "",
+		}
+
+		if !dryRun {
+			if !found {
+				if err := cs.records.Add(rec); err != nil {
+					return scan, err
+				}
+			}
+			if err := cs.songs.AddBatch(songs); err != nil {
+				return scan, err
+			}
+			for i := range located {
+				if err := cs.songs.Update(&located[i]); err != nil {
+					return scan, err
+				}
+			}
+			if scanned.Cover {
+				if err := cs.uploadScannedCover(rec.GetID(), coverPath); err != nil {
+					scan.Failed = append(scan.Failed, ScanError{Path: coverPath, Error: err.Error()})
+					scanned.Cover = false
+				}
+			}
+		}
+
+		switch {
+		case !found:
+			scan.Created = append(scan.Created, scanned)
+			existing.add(rec)
+		case len(songs) > 0 || scanned.Cover:
+			scan.Updated = append(scan.Updated, scanned)
+		default:
+			scan.Unchanged++
+		}
+	}
+
+	return scan, nil
+}
+
+// uploadScannedCover reads the cover embedded in the file again and makes
+// it the cover of the record.
+func (cs *CollectionService) uploadScannedCover(recordID uuid.UUID, path string) error {
+	data, err := readCover(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = cs.UploadRecordCover(recordID, data)
+	return err
+}
+
+// walkLibrary reads every audio file below dir. Files and directories that
+// cannot be read are reported in the scan and skipped.
+func walkLibrary(dir string, scan *LibraryScan) ([]libraryFile, error) {
+	var files []libraryFile
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			scan.Failed = append(scan.Failed, ScanError{Path: path, Error: err.Error()})
+			if info != nil && info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() {
+			if path != dir && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		read, ok := libraryFormats[strings.ToLower(filepath.Ext(path))]
+		if !ok {
+			return nil
+		}
+
+		track, kind, err := read(path)
+		if err != nil {
+			scan.Failed = append(scan.Failed, ScanError{Path: path, Error: err.Error()})
+			return nil
+		}
+
+		// pictures are dropped so a large library is not held in memory,
+		// a usable cover is read again when uploaded
+		picture, cover := track.Cover()
+		if cover {
+			_, err := artwork.DetectType(picture.Data)
+			cover = err == nil
+		}
+		track.Pictures = nil
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, libraryFile{path: path, rel: filepath.ToSlash(rel), kind: kind, track: track, cover: cover})
+		return nil
+	})
+
+	return files, err
+}
+
+// groupAlbums groups files into the records they belong to, in the order
+// they were found.
+func groupAlbums(files []libraryFile) []*libraryAlbum {
+	var albums []*libraryAlbum
+	byKey := make(map[string]*libraryAlbum)
+
+	for _, f := range files {
+		name := f.track.Album
+		if name == "" {
+			name = filepath.Base(filepath.Dir(f.path))
+		}
+
+		key := record.NormalizeName(name) + "|" + f.kind
+		if f.track.AlbumArtist != "" {
+			key += "|artist:" + record.NormalizeName(f.track.AlbumArtist)
+		} else {
+			key += "|dir:" + filepath.Dir(f.path)
+		}
+
+		album, ok := byKey[key]
+		if !ok {
+			album = &libraryAlbum{name: name, kind: f.kind}
+			byKey[key] = album
+			albums = append(albums, album)
+		}
+		album.files = append(album.files, f)
+	}
+
+	for _, album := range albums {
+		album.artist = albumArtist(album.files)
+		sort.SliceStable(album.files, func(i, j int) bool {
+			a, b := album.files[i].track, album.files[j].track
+			if a.Disc != b.Disc {
+				return a.Disc < b.Disc
+			}
+			if a.Track != b.Track {
+				return a.Track < b.Track
+			}
+			return album.files[i].path < album.files[j].path
+		})
+	}
+
+	return albums
+}
+
+// albumArtist is the album artist of the files, else the artist they all
+// share, else various artists.
+func albumArtist(files []libraryFile) string {
+	artist := ""
+	for _, f := range files {
+		if f.track.AlbumArtist != "" {
+			return f.track.AlbumArtist
+		}
+		switch {
+		case f.track.Artist == "":
+		case artist == "":
+			artist = f.track.Artist
+		case record.NormalizeName(artist) != record.NormalizeName(f.track.Artist):
+			return variousArtists
+		}
+	}
+	return artist
+}
+
+// missingSongs builds the songs of the files that the record does not have
+// yet, by normalized name. Songs without a title are named after their file.
+// Songs the record has without a path are returned apart, located at their
+// file.
+func (cs *CollectionService) missingSongs(recordID uuid.UUID, found bool, files []libraryFile) ([]song.Song, []song.Song, error) {
+	have := make(map[string]*song.Song)
+	if found {
+		songs, err := cs.songs.FindSongsByRecord(recordID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range songs {
+			have[record.NormalizeName(songs[i].GetName())] = &songs[i]
+		}
+	}
+
+	songs := make([]song.Song, 0, len(files))
+	var located []song.Song
+	for _, f := range files {
+		name := f.track.Title
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(f.path), filepath.Ext(f.path))
+		}
+		if s, ok := have[record.NormalizeName(name)]; ok {
+			if s.GetPath() == "" {
+				s.SetPath(f.rel)
+				located = append(located, *s)
+			}
+			continue
+		}
+
+		s, err := song.NewSong(name, f.track.Seconds(), recordID)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.SetTrack(f.track.Track)
+		s.SetPath(f.rel)
+		songs = append(songs, s)
+	}
+
+	return songs, located, nil
+}
+
+// ScanStatus is where a library scan job stands.
+type ScanStatus string
+
+const (
+	ScanRunning ScanStatus = "running"
+	ScanDone    ScanStatus = "done"
+	ScanFailed  ScanStatus = "failed"
+)
+
+// ScanJob is a library scan run in the background.
+type ScanJob struct {
+	ID         uuid.UUID    `json:"id"`
+	Status     ScanStatus   `json:"status"`
+	StartedAt  time.Time    `json:"startedAt"`
+	FinishedAt *time.Time   `json:"finishedAt,omitempty"`
+	Result     *LibraryScan `json:"result,omitempty"`
+	Error      string       `json:"error,omitempty"`
+}
+
+// scanJobs keeps the library scan jobs. Only one runs at a time so two
+// scans never add the same songs.
+type scanJobs struct {
+	jobs    map[uuid.UUID]*ScanJob
+	running bool
+
+	sync.Mutex
+}
+
+// StartLibraryScan scans the configured library in the background. The job
+// returned can be followed with FindLibraryScan.
+func (cs *CollectionService) StartLibraryScan(dryRun bool) (ScanJob, error) {
+	if cs.library == "" {
+		return ScanJob{}, ErrNoLibrary
+	}
+
+	cs.scans.Lock()
+	defer cs.scans.Unlock()
+
+	if cs.scans.running {
+		return ScanJob{}, ErrScanRunning
+	}
+	if cs.scans.jobs == nil {
+		cs.scans.jobs = make(map[uuid.UUID]*ScanJob)
+	}
+
+	job := &ScanJob{
+		ID:        uuid.New(),
+		Status:    ScanRunning,
+		StartedAt: time.Now().UTC(),
+	}
+	cs.scans.jobs[job.ID] = job
+	cs.scans.running = true
+
+	go func() {
+		result, err := cs.ScanLibrary(cs.library, dryRun)
+
+		cs.scans.Lock()
+		defer cs.scans.Unlock()
+
+		finished := time.Now().UTC()
+		job.FinishedAt = &finished
+		job.Result = &result
+		job.Status = ScanDone
+		if err != nil {
+			job.Status = ScanFailed
+			job.Error = err.Error()
+		}
+		cs.scans.running = false
+	}()
+
+	return *job, nil
+}
+
+// FindLibraryScan returns a library scan job as it stands.
+func (cs *CollectionService) FindLibraryScan(id uuid.UUID) (ScanJob, error) {
+	cs.scans.Lock()
+	defer cs.scans.Unlock()
+
+	job, ok := cs.scans.jobs[id]
+	if !ok {
+		return ScanJob{}, ErrScanNotFound
+	}
+
+	return *job, nil
+}
@@ -0,0 +1,287 @@
+package services_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeMP3 writes an MP3 file of 383 frames, about 10 seconds, tagged with
+// the given ID3v2.3 text frames, id and value pairs.
+func writeMP3(t *testing.T, path string, pairs ...string) {
+	var body bytes.Buffer
+	for i := 0; i+1 < len(pairs); i += 2 {
+		data := append([]byte{3}, pairs[i+1]...)
+		body.WriteString(pairs[i])
+		binary.Write(&body, binary.BigEndian, uint32(len(data)))
+		body.Write([]byte{0, 0})
+		body.Write(data)
+	}
+
+	n := body.Len()
+	var file bytes.Buffer
+	file.Write([]byte{'I', 'D', '3', 3, 0, 0, byte(n >> 21 & 0x7f), byte(n >> 14 & 0x7f), byte(n >> 7 & 0x7f), byte(n & 0x7f)})
+	file.Write(body.Bytes())
+	for i := 0; i < 383; i++ {
+		frame := make([]byte, 417)
+		copy(frame, []byte{0xff, 0xfb, 0x90, 0x00})
+		file.Write(frame)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, file.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeFLAC writes a FLAC file of 10 seconds with the given Vorbis comment
+// fields and, when not nil, an embedded front cover.
+func writeFLAC(t *testing.T, path string, cover []byte, fields ...string) {
+	var file bytes.Buffer
+	file.WriteString("fLaC")
+
+	info := make([]byte, 34)
+	info[10], info[11], info[12], info[13] = 0x0a, 0xc4, 0x42, 0xf0
+	binary.BigEndian.PutUint32(info[14:], 441000)
+	file.Write([]byte{0, 0, 0, 34})
+	file.Write(info)
+
+	var comment bytes.Buffer
+	binary.Write(&comment, binary.LittleEndian, uint32(0))
+	binary.Write(&comment, binary.LittleEndian, uint32(len(fields)))
+	for _, f := range fields {
+		binary.Write(&comment, binary.LittleEndian, uint32(len(f)))
+		comment.WriteString(f)
+	}
+	last := byte(0x80)
+	if cover != nil {
+		last = 0
+	}
+	n := comment.Len()
+	file.Write([]byte{4 | last, byte(n >> 16), byte(n >> 8), byte(n)})
+	file.Write(comment.Bytes())
+
+	if cover != nil {
+		var picture bytes.Buffer
+		binary.Write(&picture, binary.BigEndian, uint32(3))
+		binary.Write(&picture, binary.BigEndian, uint32(len("image/png")))
+		picture.WriteString("image/png")
+		picture.Write(make([]byte, 4+16))
+		binary.Write(&picture, binary.BigEndian, uint32(len(cover)))
+		picture.Write(cover)
+
+		n := picture.Len()
+		file.Write([]byte{6 | 0x80, byte(n >> 16), byte(n >> 8), byte(n)})
+		file.Write(picture.Bytes())
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, file.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newLibrary writes a library with an album of two songs, a song without
+// album in its own directory, a file that is not MP3 and one that is not
+// audio.
+func newLibrary(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "library")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeMP3(t, filepath.Join(dir, "Pink Floyd", "The Wall", "a.mp3"), "TIT2", "Mother", "TPE1", "Pink Floyd", "TALB", "The Wall", "TRCK", "5/13")
+	writeMP3(t, filepath.Join(dir, "Pink Floyd", "The Wall", "b.mp3"), "TIT2", "Hey You", "TPE1", "Pink Floyd", "TALB", "The Wall", "TRCK", "1/13")
+	writeMP3(t, filepath.Join(dir, "Loose", "Untitled Demo.mp3"), "TPE1", "Someone")
+	ioutil.WriteFile(filepath.Join(dir, "Loose", "notes.txt"), []byte("notes"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "Loose", "broken.mp3"), bytes.Repeat([]byte("no audio"), 100), 0644)
+
+	return dir
+}
+
+func TestCollectionService_ScanLibrary(t *testing.T) {
+	dir := newLibrary(t)
+	defer os.RemoveAll(dir)
+
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+
+	t.Run("Dry run", func(t *testing.T) {
+		scan, err := cs.ScanLibrary(dir, true)
+		assert.NoError(t, err)
+		assert.Len(t, scan.Created, 2)
+
+		records, _ := cs.FindAllRecord()
+		assert.Len(t, records, 0)
+	})
+
+	t.Run("First scan", func(t *testing.T) {
+		scan, err := cs.ScanLibrary(dir, false)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 3, scan.Files)
+		assert.Len(t, scan.Failed, 1)
+		assert.Equal(t, "broken.mp3", filepath.Base(scan.Failed[0].Path))
+
+		assert.Len(t, scan.Created, 2)
+		loose, wall := scan.Created[0], scan.Created[1]
+		assert.Equal(t, "Loose", loose.Name)
+		assert.Equal(t, "Someone", loose.Artist)
+		assert.Equal(t, "The Wall", wall.Name)
+		assert.Equal(t, "Pink Floyd", wall.Artist)
+		assert.Equal(t, 2, wall.Songs)
+
+		songs, _ := cs.FindSongsByRecord(wall.RecordID)
+		assert.Len(t, songs, 2)
+		assert.Equal(t, "Hey You", songs[0].Name)
+		assert.Equal(t, int64(10), songs[0].Length)
+		assert.Equal(t, "Pink Floyd/The Wall/b.mp3", songs[0].Path)
+		assert.Equal(t, "Mother", songs[1].Name)
+
+		songs, _ = cs.FindSongsByRecord(loose.RecordID)
+		assert.Len(t, songs, 1)
+		assert.Equal(t, "Untitled Demo", songs[0].Name)
+	})
+
+	t.Run("Scan again", func(t *testing.T) {
+		scan, err := cs.ScanLibrary(dir, false)
+		assert.NoError(t, err)
+		assert.Len(t, scan.Created, 0)
+		assert.Len(t, scan.Updated, 0)
+		assert.Equal(t, 2, scan.Unchanged)
+	})
+
+	t.Run("Scan a new song", func(t *testing.T) {
+		writeMP3(t, filepath.Join(dir, "Pink Floyd", "The Wall", "c.mp3"), "TIT2", "Comfortably Numb", "TPE1", "Pink Floyd", "TALB", "The Wall", "TRCK", "6/13")
+
+		scan, err := cs.ScanLibrary(dir, false)
+		assert.NoError(t, err)
+		assert.Len(t, scan.Updated, 1)
+		assert.Equal(t, 1, scan.Updated[0].Songs)
+		assert.Equal(t, 1, scan.Unchanged)
+
+		records, _ := cs.FindAllRecord()
+		assert.Len(t, records, 2)
+		songs, _ := cs.FindSongsByRecord(scan.Updated[0].RecordID)
+		assert.Len(t, songs, 3)
+	})
+}
+
+func TestCollectionService_ScanLibrary_Lossless(t *testing.T) {
+	dir := newLibrary(t)
+	defer os.RemoveAll(dir)
+	blobs, err := ioutil.TempDir("", "covers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(blobs)
+
+	writeFLAC(t, filepath.Join(dir, "Pink Floyd", "The Wall (FLAC)", "a.flac"), testCover(800, 800),
+		"TITLE=Mother", "ARTIST=Pink Floyd", "ALBUM=The Wall", "TRACKNUMBER=5")
+	writeFLAC(t, filepath.Join(dir, "Pink Floyd", "The Wall (FLAC)", "b.flac"), nil,
+		"TITLE=Hey You", "ARTIST=Pink Floyd", "ALBUM=The Wall", "TRACKNUMBER=1")
+	writeFLAC(t, filepath.Join(dir, "Pink Floyd", "Animals", "a.flac"), []byte("not an image"),
+		"TITLE=Dogs", "ARTIST=Pink Floyd", "ALBUM=Animals")
+
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithLocalBlobStore(blobs, "/api/blobs/"),
+	)
+
+	scan, err := cs.ScanLibrary(dir, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, scan.Files)
+	assert.Len(t, scan.Created, 4)
+	// the picture that is not an image is ignored, its record is kept
+	assert.Len(t, scan.Failed, 1)
+
+	records, _ := cs.FindRecords(record.Filter{Kind: record.KindLossless})
+	assert.Len(t, records, 2)
+	for _, r := range records {
+		assert.Equal(t, "Pink Floyd", r.Artist)
+		if r.Name == "The Wall" {
+			assert.NotNil(t, r.Cover)
+		} else {
+			assert.Nil(t, r.Cover)
+		}
+	}
+
+	songs, _ := cs.FindSongsByRecord(records[0].ID)
+	assert.NotEmpty(t, songs)
+	assert.Equal(t, int64(10), songs[0].Length)
+
+	t.Run("Scan again", func(t *testing.T) {
+		scan, err := cs.ScanLibrary(dir, false)
+		assert.NoError(t, err)
+		assert.Len(t, scan.Created, 0)
+		assert.Len(t, scan.Updated, 0)
+		assert.Equal(t, 4, scan.Unchanged)
+	})
+
+	t.Run("Cover upload fails", func(t *testing.T) {
+		store := &failingStore{blobs: make(map[string]bool)}
+		cs, _ := services.NewCollectionService(
+			services.WithRecordMemoryRepository(),
+			services.WithSongMemoryRepository(),
+			services.WithBlobStore(store, "/api/blobs"),
+		)
+
+		for i := 0; i < 2; i++ {
+			scan, err := cs.ScanLibrary(dir, false)
+			assert.NoError(t, err)
+			assert.Len(t, scan.Failed, 2)
+			assert.Equal(t, "a.flac", filepath.Base(scan.Failed[1].Path))
+			assert.Len(t, scan.Updated, 0)
+			for _, created := range scan.Created {
+				assert.False(t, created.Cover)
+			}
+		}
+	})
+}
+
+func TestCollectionService_StartLibraryScan(t *testing.T) {
+	dir := newLibrary(t)
+	defer os.RemoveAll(dir)
+
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+	_, err := cs.StartLibraryScan(false)
+	assert.Equal(t, services.ErrNoLibrary, err)
+
+	cs, _ = services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithMusicLibrary(dir),
+	)
+
+	job, err := cs.StartLibraryScan(false)
+	assert.NoError(t, err)
+	assert.Equal(t, services.ScanRunning, job.Status)
+
+	for i := 0; i < 100 && job.Status == services.ScanRunning; i++ {
+		time.Sleep(10 * time.Millisecond)
+		job, err = cs.FindLibraryScan(job.ID)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, services.ScanDone, job.Status)
+	assert.NotNil(t, job.FinishedAt)
+	assert.Len(t, job.Result.Created, 2)
+}
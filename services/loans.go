@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/loan"
+	lmemory "github.com/rodrwan/collection/domain/loan/memory"
+	lpostgres "github.com/rodrwan/collection/domain/loan/postgres"
+)
+
+var (
+	ErrRecordNotOwned = errors.New("only owned records can be lent")
+	ErrRecordNotLent  = errors.New("record is not lent")
+)
+
+// WithLoanMemoryRepository ...
+func WithLoanMemoryRepository() CollectionConfiguration {
+	return func(os *CollectionService) error {
+		mem, err := lmemory.New(context.Background())
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.loans = mem
+		return nil
+	}
+}
+
+// WithLoanPostgresRepository ...
+func WithLoanPostgresRepository(connectionString string, connect lpostgres.SqlOpener) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		pg, err := lpostgres.New(context.Background(), connectionString, connect)
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.loans = pg
+		return nil
+	}
+}
+
+// LendRecord lends an owned record to a borrower. A record can only be lent
+// to one borrower at a time. A zero lentAt means it is lent now.
+func (cs *CollectionService) LendRecord(recordID uuid.UUID, borrower string, lentAt, dueAt time.Time) (loan.PublicLoan, error) {
+	rec, err := cs.records.Get(recordID)
+	if err != nil {
+		return loan.PublicLoan{}, err
+	}
+
+	if !rec.IsOwned() {
+		return loan.PublicLoan{}, ErrRecordNotOwned
+	}
+
+	if _, err := cs.loans.FindActiveByRecord(recordID); err != loan.ErrLoanNotFound {
+		if err == nil {
+			return loan.PublicLoan{}, loan.ErrAlreadyLent
+		}
+		return loan.PublicLoan{}, err
+	}
+
+	if lentAt.IsZero() {
+		lentAt = time.Now().UTC()
+	}
+
+	l, err := loan.NewLoan(recordID, borrower, lentAt, dueAt)
+	if err != nil {
+		return loan.PublicLoan{}, err
+	}
+
+	if err := cs.loans.Add(l); err != nil {
+		return loan.PublicLoan{}, err
+	}
+
+	return l.ToPublic(), nil
+}
+
+// ReturnRecord closes the active loan of a record. A zero date means it was
+// returned now.
+func (cs *CollectionService) ReturnRecord(recordID uuid.UUID, returnedAt time.Time) (loan.PublicLoan, error) {
+	l, err := cs.loans.FindActiveByRecord(recordID)
+	if err != nil {
+		if err == loan.ErrLoanNotFound {
+			return loan.PublicLoan{}, ErrRecordNotLent
+		}
+		return loan.PublicLoan{}, err
+	}
+
+	if returnedAt.IsZero() {
+		returnedAt = time.Now().UTC()
+	}
+
+	if err := l.Return(returnedAt); err != nil {
+		return loan.PublicLoan{}, err
+	}
+
+	if err := cs.loans.Update(&l); err != nil {
+		return loan.PublicLoan{}, err
+	}
+
+	return l.ToPublic(), nil
+}
+
+// FindCurrentLoans lists the records that are still out.
+func (cs *CollectionService) FindCurrentLoans() ([]loan.PublicLoan, error) {
+	return cs.findLoans(func(l loan.Loan) bool {
+		return l.IsActive()
+	})
+}
+
+// FindOverdueLoans lists the records still out after their due date.
+func (cs *CollectionService) FindOverdueLoans(now time.Time) ([]loan.PublicLoan, error) {
+	return cs.findLoans(func(l loan.Loan) bool {
+		return l.IsOverdue(now)
+	})
+}
+
+func (cs *CollectionService) findLoans(match func(loan.Loan) bool) ([]loan.PublicLoan, error) {
+	loans, err := cs.loans.FindLoans()
+	if err != nil {
+		return []loan.PublicLoan{}, err
+	}
+
+	var ll []loan.Loan
+	for _, l := range loans {
+		if match(l) {
+			ll = append(ll, l)
+		}
+	}
+
+	return loan.ToPublicArray(ll), nil
+}
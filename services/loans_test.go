@@ -0,0 +1,59 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/loan"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_LendRecord(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithLoanMemoryRepository(),
+	)
+
+	owned, _ := cs.AddRecord(uuid.New(), "r1", "vinyl")
+	wish, _ := cs.AddRecordWithOwnership(uuid.New(), "r2", "vinyl", "wishlist", record.Acquisition{})
+
+	now := time.Now().UTC()
+
+	l, err := cs.LendRecord(owned.ID, "Percy", now.AddDate(0, 0, -30), now.AddDate(0, 0, -16))
+	assert.Nil(t, err)
+	assert.Equal(t, owned.ID, l.RecordID)
+
+	_, err = cs.LendRecord(owned.ID, "Rodrigo", time.Time{}, time.Time{})
+	assert.Equal(t, loan.ErrAlreadyLent, err)
+
+	_, err = cs.LendRecord(wish.ID, "Rodrigo", time.Time{}, time.Time{})
+	assert.Equal(t, services.ErrRecordNotOwned, err)
+
+	_, err = cs.LendRecord(uuid.New(), "Rodrigo", time.Time{}, time.Time{})
+	assert.Equal(t, record.ErrRecordNotFound, err)
+
+	current, err := cs.FindCurrentLoans()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(current))
+
+	overdue, err := cs.FindOverdueLoans(now)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(overdue))
+
+	returned, err := cs.ReturnRecord(owned.ID, time.Time{})
+	assert.Nil(t, err)
+	assert.NotNil(t, returned.ReturnedAt)
+
+	_, err = cs.ReturnRecord(owned.ID, time.Time{})
+	assert.Equal(t, services.ErrRecordNotLent, err)
+
+	current, _ = cs.FindCurrentLoans()
+	assert.Equal(t, 0, len(current))
+
+	_, err = cs.LendRecord(owned.ID, "Rodrigo", time.Time{}, time.Time{})
+	assert.Nil(t, err)
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/location"
+	lomemory "github.com/rodrwan/collection/domain/location/memory"
+	lopostgres "github.com/rodrwan/collection/domain/location/postgres"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrRecordNotShelved = errors.New("record has no location")
+)
+
+// RecordLocation tells where a record physically lives.
+type RecordLocation struct {
+	RecordID uuid.UUID                 `json:"recordId"`
+	Label    string                    `json:"label"`
+	Position int                       `json:"position,omitempty"`
+	Path     []location.PublicLocation `json:"path"`
+}
+
+// ReshelvePlan lists the records that would move if a shelf was re-sorted
+// alphabetically.
+type ReshelvePlan struct {
+	ShelfID uuid.UUID       `json:"shelfId"`
+	Label   string          `json:"label"`
+	Records int             `json:"records"`
+	Moves   []location.Move `json:"moves"`
+}
+
+// WithLocationMemoryRepository ...
+func WithLocationMemoryRepository() CollectionConfiguration {
+	return func(os *CollectionService) error {
+		mem, err := lomemory.New(context.Background())
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.locations = mem
+		return nil
+	}
+}
+
+// WithLocationPostgresRepository ...
+func WithLocationPostgresRepository(connectionString string, connect lopostgres.SqlOpener) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		pg, err := lopostgres.New(context.Background(), connectionString, connect)
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.locations = pg
+		return nil
+	}
+}
+
+// AddLocation creates a room, a shelf inside a room or a slot inside a
+// shelf.
+func (cs *CollectionService) AddLocation(name string, kind location.Kind, parentID uuid.UUID, position int) (location.PublicLocation, error) {
+	l, err := location.NewLocation(name, kind, parentID, position)
+	if err != nil {
+		return location.PublicLocation{}, err
+	}
+
+	if parentID != uuid.Nil {
+		parent, err := cs.locations.Get(parentID)
+		if err != nil {
+			return location.PublicLocation{}, err
+		}
+		if parent.GetKind() != location.ParentKind(kind) {
+			var errs validation.Errors
+			return location.PublicLocation{}, errs.Add("parentId", validation.CodeNotAllowed, location.ErrInvalidParent)
+		}
+	}
+
+	if err := cs.locations.Add(l); err != nil {
+		return location.PublicLocation{}, err
+	}
+
+	return l.ToPublic(), nil
+}
+
+// FindAllLocations ...
+func (cs *CollectionService) FindAllLocations() ([]location.PublicLocation, error) {
+	locations, err := cs.locations.FindLocations()
+	if err != nil {
+		return []location.PublicLocation{}, err
+	}
+
+	return location.ToPublicArray(locations), nil
+}
+
+// AssignRecordLocation places a record on a shelf or slot. A nil location
+// takes the record off the shelves.
+func (cs *CollectionService) AssignRecordLocation(recordID, locationID uuid.UUID, position int) (record.PublicRecord, error) {
+	rec, err := cs.records.Get(recordID)
+	if err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	if locationID != uuid.Nil {
+		l, err := cs.locations.Get(locationID)
+		if err != nil {
+			return record.PublicRecord{}, err
+		}
+		if !l.CanHoldRecords() {
+			var errs validation.Errors
+			return record.PublicRecord{}, errs.Add("locationId", validation.CodeNotAllowed, location.ErrNotAssignable)
+		}
+	}
+
+	if err := rec.PlaceAt(locationID, position); err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	if err := cs.records.Update(&rec); err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	return rec.ToPublic(), nil
+}
+
+// WhereIsRecord returns the full path to the location of a record.
+func (cs *CollectionService) WhereIsRecord(recordID uuid.UUID) (RecordLocation, error) {
+	rec, err := cs.records.Get(recordID)
+	if err != nil {
+		return RecordLocation{}, err
+	}
+
+	placement := rec.GetPlacement()
+	if placement.IsZero() {
+		return RecordLocation{}, ErrRecordNotShelved
+	}
+
+	locations, err := cs.locations.FindLocations()
+	if err != nil {
+		return RecordLocation{}, err
+	}
+
+	path, err := location.Path(locations, placement.LocationID)
+	if err != nil {
+		return RecordLocation{}, err
+	}
+
+	return RecordLocation{
+		RecordID: recordID,
+		Label:    location.Label(path),
+		Position: placement.Position,
+		Path:     location.ToPublicArray(path),
+	}, nil
+}
+
+// PlanReshelve reports which records on a shelf, including those in its
+// slots, would move if the shelf was sorted alphabetically. Nothing is
+// moved.
+func (cs *CollectionService) PlanReshelve(shelfID uuid.UUID) (ReshelvePlan, error) {
+	shelf, err := cs.locations.Get(shelfID)
+	if err != nil {
+		return ReshelvePlan{}, err
+	}
+	if shelf.GetKind() != location.KindShelf {
+		return ReshelvePlan{}, location.ErrNotAShelf
+	}
+
+	locations, err := cs.locations.FindLocations()
+	if err != nil {
+		return ReshelvePlan{}, err
+	}
+
+	// records sitting on the shelf itself go before the first slot
+	slots := map[uuid.UUID]int{shelfID: 0}
+	for i, slot := range location.Children(locations, shelfID) {
+		slots[slot.GetID()] = i + 1
+	}
+
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return ReshelvePlan{}, err
+	}
+
+	var items []location.ShelfItem
+	for _, r := range records {
+		placement := r.GetPlacement()
+		slot, ok := slots[placement.LocationID]
+		if placement.IsZero() || !ok {
+			continue
+		}
+		items = append(items, location.ShelfItem{
+			RecordID: r.GetID(),
+			Name:     r.GetName(),
+			Slot:     slot,
+			Position: placement.Position,
+		})
+	}
+
+	path, err := location.Path(locations, shelfID)
+	if err != nil {
+		return ReshelvePlan{}, err
+	}
+
+	return ReshelvePlan{
+		ShelfID: shelfID,
+		Label:   location.Label(path),
+		Records: len(items),
+		Moves:   location.PlanReshelve(items),
+	}, nil
+}
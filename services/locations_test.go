@@ -0,0 +1,66 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/location"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_Locations(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithLocationMemoryRepository(),
+	)
+
+	room, err := cs.AddLocation("Living room", location.KindRoom, uuid.Nil, 0)
+	assert.Nil(t, err)
+	shelf, err := cs.AddLocation("Shelf A", location.KindShelf, room.ID, 1)
+	assert.Nil(t, err)
+	slot1, _ := cs.AddLocation("Slot 1", location.KindSlot, shelf.ID, 1)
+	slot2, _ := cs.AddLocation("Slot 2", location.KindSlot, shelf.ID, 2)
+
+	_, err = cs.AddLocation("Slot 9", location.KindSlot, room.ID, 9)
+	assert.True(t, errors.Is(err, location.ErrInvalidParent))
+
+	_, err = cs.AddLocation("Shelf B", location.KindShelf, uuid.New(), 2)
+	assert.Equal(t, location.ErrLocationNotFound, err)
+
+	zappa, _ := cs.AddRecord(uuid.New(), "Zappa", "vinyl")
+	abba, _ := cs.AddRecord(uuid.New(), "Abba Gold", "vinyl")
+	miles, _ := cs.AddRecord(uuid.New(), "Miles Smiles", "vinyl")
+
+	_, err = cs.AssignRecordLocation(zappa.ID, room.ID, 0)
+	assert.True(t, errors.Is(err, location.ErrNotAssignable))
+
+	placed, err := cs.AssignRecordLocation(zappa.ID, slot1.ID, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, slot1.ID, placed.Placement.LocationID)
+	cs.AssignRecordLocation(miles.ID, slot1.ID, 2)
+	cs.AssignRecordLocation(abba.ID, slot2.ID, 1)
+
+	where, err := cs.WhereIsRecord(zappa.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Living room > Shelf A > Slot 1", where.Label)
+	assert.Equal(t, 3, len(where.Path))
+
+	other, _ := cs.AddRecord(uuid.New(), "Unshelved", "vinyl")
+	_, err = cs.WhereIsRecord(other.ID)
+	assert.Equal(t, services.ErrRecordNotShelved, err)
+
+	plan, err := cs.PlanReshelve(shelf.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, plan.Records)
+	assert.Equal(t, "Living room > Shelf A", plan.Label)
+	assert.Equal(t, []location.Move{
+		{RecordID: abba.ID, Name: "Abba Gold", From: 3, To: 1},
+		{RecordID: zappa.ID, Name: "Zappa", From: 1, To: 3},
+	}, plan.Moves)
+
+	_, err = cs.PlanReshelve(slot1.ID)
+	assert.Equal(t, location.ErrNotAShelf, err)
+}
@@ -0,0 +1,471 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/match"
+	mmemory "github.com/rodrwan/collection/domain/match/memory"
+	mpostgres "github.com/rodrwan/collection/domain/match/postgres"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/pkg/musicbrainz"
+)
+
+var (
+	ErrMatchReviewNotFound     = match.ErrReviewNotFound
+	ErrUnknownCandidate        = errors.New("release is not a candidate of the review")
+	ErrNoMatchReviewRepository = errors.New("no musicbrainz match review repository configured")
+)
+
+const (
+	// AutoMatchScore is the confidence from which a match is applied without
+	// review.
+	AutoMatchScore = 0.85
+	// MinMatchScore is the confidence below which a release is not a
+	// candidate at all.
+	MinMatchScore = 0.65
+	// matchMargin is how far ahead of the next album an automatic match has
+	// to be.
+	matchMargin = 0.1
+	// maxReviewCandidates bounds the candidates kept for review.
+	maxReviewCandidates = 5
+)
+
+// Weights of each signal in the match score. Releases are only candidates
+// when their title normalizes to the record name, which gives the title
+// weight; a signal missing on our side counts half.
+const (
+	titleMatchWeight  = 0.4
+	artistMatchWeight = 0.3
+	trackMatchWeight  = 0.3
+)
+
+// MatchCandidate is a MusicBrainz release a record may be.
+type MatchCandidate struct {
+	ReleaseID    string  `json:"releaseId"`
+	Title        string  `json:"title"`
+	ArtistCredit string  `json:"artistCredit,omitempty"`
+	ReleaseDate  string  `json:"releaseDate,omitempty"`
+	Tracks       int     `json:"tracks"`
+	Score        float64 `json:"score"`
+}
+
+// MatchReview is a record whose best candidates are too close to tell, or
+// not close enough to be trusted, waiting for someone to pick one.
+type MatchReview struct {
+	RecordID   uuid.UUID        `json:"recordId"`
+	Name       string           `json:"name"`
+	Artist     string           `json:"artist,omitempty"`
+	Candidates []MatchCandidate `json:"candidates"`
+	CreatedAt  time.Time        `json:"createdAt"`
+}
+
+// EnrichedRecord is a record linked to a MusicBrainz release, with the
+// number of its songs linked to recordings.
+type EnrichedRecord struct {
+	RecordID  uuid.UUID `json:"recordId"`
+	Name      string    `json:"name"`
+	ReleaseID string    `json:"releaseId"`
+	Score     float64   `json:"score"`
+	Songs     int       `json:"songs"`
+}
+
+// Enrichment tells what a MusicBrainz dump import matched, or would match
+// on a dry run. Records already linked are left alone.
+type Enrichment struct {
+	DryRun    bool             `json:"dryRun"`
+	Releases  int              `json:"releases"`
+	Skipped   int              `json:"skipped"`
+	Matched   []EnrichedRecord `json:"matched"`
+	Review    []MatchReview    `json:"review"`
+	Unmatched int              `json:"unmatched"`
+	Linked    int              `json:"linked"`
+}
+
+// WithMatchReviewMemoryRepository ...
+func WithMatchReviewMemoryRepository() CollectionConfiguration {
+	return func(os *CollectionService) error {
+		mem, err := mmemory.New(context.Background())
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.matches = mem
+		return nil
+	}
+}
+
+// WithMatchReviewPostgresRepository ...
+func WithMatchReviewPostgresRepository(connectionString string, connect mpostgres.SqlOpener) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		pg, err := mpostgres.New(context.Background(), connectionString, connect)
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.matches = pg
+		return nil
+	}
+}
+
+func toMatchReview(r match.Review) MatchReview {
+	review := MatchReview{
+		RecordID:   r.RecordID,
+		Name:       r.Name,
+		Artist:     r.Artist,
+		Candidates: make([]MatchCandidate, 0, len(r.Candidates)),
+		CreatedAt:  r.CreatedAt,
+	}
+	for _, c := range r.Candidates {
+		review.Candidates = append(review.Candidates, MatchCandidate{
+			ReleaseID:    c.ReleaseID,
+			Title:        c.Title,
+			ArtistCredit: c.ArtistCredit,
+			ReleaseDate:  c.ReleaseDate,
+			Tracks:       len(c.Tracks),
+			Score:        c.Score,
+		})
+	}
+
+	return review
+}
+
+// enrichTarget is a record being matched and its best candidate of each
+// release group, so editions of the same album do not compete.
+type enrichTarget struct {
+	rec    record.Record
+	songs  []string
+	groups map[string]match.Candidate
+}
+
+// EnrichFromMusicBrainz reads a MusicBrainz release dump and links the
+// records whose name matches a release title to the best release: its ids,
+// artist credit and release date, and the recordings and lengths of the
+// songs found in its tracklist. Matches that are not confident enough, or
+// that compete with another album, go to the review queue instead.
+//
+// The dump is read once and only releases titled like a record are kept,
+// so the whole dump is never held in memory. Lines that are not releases
+// are counted and skipped.
+func (cs *CollectionService) EnrichFromMusicBrainz(r io.Reader, dryRun bool) (Enrichment, error) {
+	enrichment := Enrichment{
+		DryRun:  dryRun,
+		Matched: make([]EnrichedRecord, 0),
+		Review:  make([]MatchReview, 0),
+	}
+	if !dryRun && cs.matches == nil {
+		return enrichment, ErrNoMatchReviewRepository
+	}
+
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return enrichment, err
+	}
+
+	var targets []*enrichTarget
+	byTitle := make(map[string][]*enrichTarget)
+	for _, rec := range records {
+		if !rec.GetMusicBrainz().IsZero() {
+			enrichment.Linked++
+			continue
+		}
+
+		songs, err := cs.songs.FindSongsByRecord(rec.GetID())
+		if err != nil {
+			return enrichment, err
+		}
+		target := &enrichTarget{rec: rec, groups: make(map[string]match.Candidate)}
+		for _, s := range songs {
+			target.songs = append(target.songs, s.GetName())
+		}
+
+		key := record.NormalizeName(rec.GetName())
+		targets = append(targets, target)
+		byTitle[key] = append(byTitle[key], target)
+	}
+
+	dump, err := musicbrainz.NewReader(r)
+	if err != nil {
+		return enrichment, err
+	}
+	var firstLineErr error
+	for {
+		release, err := dump.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var lineErr *musicbrainz.LineError
+			if !errors.As(err, &lineErr) {
+				return enrichment, err
+			}
+			if firstLineErr == nil {
+				firstLineErr = err
+			}
+			enrichment.Skipped++
+			continue
+		}
+		enrichment.Releases++
+
+		for _, target := range byTitle[record.NormalizeName(release.Title)] {
+			target.consider(release)
+		}
+	}
+	// a dump without a single release is not a dump
+	if enrichment.Releases == 0 && firstLineErr != nil {
+		return enrichment, firstLineErr
+	}
+
+	now := time.Now().UTC()
+	for _, target := range targets {
+		candidates := target.candidates()
+
+		switch {
+		case len(candidates) == 0:
+			enrichment.Unmatched++
+		case candidates[0].Score >= AutoMatchScore && (len(candidates) == 1 || candidates[0].Score-candidates[1].Score >= matchMargin):
+			matched := EnrichedRecord{
+				RecordID:  target.rec.GetID(),
+				Name:      target.rec.GetName(),
+				ReleaseID: candidates[0].ReleaseID,
+				Score:     candidates[0].Score,
+			}
+			if !dryRun {
+				if matched.Songs, err = cs.applyMatch(target.rec, candidates[0], now); err != nil {
+					return enrichment, err
+				}
+				if err := cs.matches.Delete(matched.RecordID); err != nil && err != match.ErrReviewNotFound {
+					return enrichment, err
+				}
+			}
+			enrichment.Matched = append(enrichment.Matched, matched)
+		default:
+			if len(candidates) > maxReviewCandidates {
+				candidates = candidates[:maxReviewCandidates]
+			}
+			review := match.Review{
+				RecordID:   target.rec.GetID(),
+				Name:       target.rec.GetName(),
+				Artist:     target.rec.GetArtist(),
+				Candidates: candidates,
+				CreatedAt:  now,
+			}
+			if !dryRun {
+				if err := cs.matches.Save(review); err != nil {
+					return enrichment, err
+				}
+			}
+			enrichment.Review = append(enrichment.Review, toMatchReview(review))
+		}
+	}
+
+	return enrichment, nil
+}
+
+// consider scores a release titled like the record, keeping it when it is
+// the best of its release group. Between equal scores the earliest release
+// wins, the original rather than a reissue.
+func (t *enrichTarget) consider(release musicbrainz.Release) {
+	score := titleMatchWeight
+
+	if t.rec.GetArtist() == "" {
+		score += artistMatchWeight / 2
+	} else {
+		score += artistMatchWeight * record.NameSimilarity(t.rec.GetArtist(), release.Artist())
+	}
+
+	tracks := release.Tracks()
+	if len(t.songs) == 0 {
+		score += trackMatchWeight / 2
+	} else {
+		titles := make([]string, 0, len(tracks))
+		for _, track := range tracks {
+			titles = append(titles, track.Title)
+		}
+		score += trackMatchWeight * record.TrackOverlap(t.songs, titles)
+	}
+
+	score = record.RoundScore(score)
+	if score < MinMatchScore {
+		return
+	}
+
+	group := release.ReleaseGroup.ID
+	if group == "" {
+		group = release.ID
+	}
+	if best, ok := t.groups[group]; ok && (best.Score > score || best.Score == score && !earlier(release.Date, best.ReleaseDate)) {
+		return
+	}
+
+	candidate := match.Candidate{
+		ReleaseID:      release.ID,
+		ReleaseGroupID: release.ReleaseGroup.ID,
+		Title:          release.Title,
+		ArtistCredit:   release.Artist(),
+		ArtistIDs:      release.ArtistIDs(),
+		ReleaseDate:    release.Date,
+		Tracks:         make([]match.Track, 0, len(tracks)),
+		Score:          score,
+	}
+	for _, track := range tracks {
+		candidate.Tracks = append(candidate.Tracks, match.Track{
+			Title:       track.Title,
+			RecordingID: track.Recording.ID,
+			Length:      track.Seconds(),
+		})
+	}
+	t.groups[group] = candidate
+}
+
+// candidates lists the best release of each group, best first.
+func (t *enrichTarget) candidates() []match.Candidate {
+	candidates := make([]match.Candidate, 0, len(t.groups))
+	for _, c := range t.groups {
+		candidates = append(candidates, c)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		if candidates[i].ReleaseDate != candidates[j].ReleaseDate {
+			return earlier(candidates[i].ReleaseDate, candidates[j].ReleaseDate)
+		}
+		return candidates[i].ReleaseID < candidates[j].ReleaseID
+	})
+
+	return candidates
+}
+
+// earlier compares release dates, which sort as text. Unknown dates come
+// last.
+func earlier(a, b string) bool {
+	if a == "" || b == "" {
+		return b == "" && a != ""
+	}
+	return a < b
+}
+
+// applyMatch links the record to the release and its songs to the
+// recordings of the tracks with the same name, filling in lengths we do
+// not know. It returns the number of songs linked.
+func (cs *CollectionService) applyMatch(rec record.Record, candidate match.Candidate, now time.Time) (int, error) {
+	rec.SetMusicBrainz(record.MusicBrainz{
+		ReleaseID:      candidate.ReleaseID,
+		ReleaseGroupID: candidate.ReleaseGroupID,
+		ArtistCredit:   candidate.ArtistCredit,
+		ArtistIDs:      candidate.ArtistIDs,
+		ReleaseDate:    candidate.ReleaseDate,
+		Score:          candidate.Score,
+		MatchedAt:      now,
+	})
+	if rec.GetArtist() == "" {
+		rec.SetArtist(candidate.ArtistCredit)
+	}
+	if err := cs.records.Update(&rec); err != nil {
+		return 0, err
+	}
+
+	tracks := make(map[string]match.Track)
+	for _, track := range candidate.Tracks {
+		key := record.NormalizeName(track.Title)
+		if _, ok := tracks[key]; !ok {
+			tracks[key] = track
+		}
+	}
+
+	songs, err := cs.songs.FindSongsByRecord(rec.GetID())
+	if err != nil {
+		return 0, err
+	}
+
+	linked := 0
+	for _, s := range songs {
+		track, ok := tracks[record.NormalizeName(s.GetName())]
+		if !ok {
+			continue
+		}
+
+		s.SetMusicBrainzID(track.RecordingID)
+		if s.GetLength() == 0 {
+			s.SetLength(track.Length)
+		}
+		if err := cs.songs.Update(&s); err != nil {
+			return linked, err
+		}
+		linked++
+	}
+
+	return linked, nil
+}
+
+// FindMusicBrainzReviews lists the matches waiting for review, by record
+// name.
+func (cs *CollectionService) FindMusicBrainzReviews() ([]MatchReview, error) {
+	if cs.matches == nil {
+		return []MatchReview{}, ErrNoMatchReviewRepository
+	}
+
+	rr, err := cs.matches.FindReviews()
+	if err != nil {
+		return []MatchReview{}, err
+	}
+
+	reviews := make([]MatchReview, 0, len(rr))
+	for _, r := range rr {
+		reviews = append(reviews, toMatchReview(r))
+	}
+
+	return reviews, nil
+}
+
+// ResolveMusicBrainzReview settles the review of a record by linking it to
+// one of its candidates, or by dismissing them all when releaseID is empty.
+func (cs *CollectionService) ResolveMusicBrainzReview(recordID uuid.UUID, releaseID string) (record.PublicRecord, error) {
+	if cs.matches == nil {
+		return record.PublicRecord{}, ErrNoMatchReviewRepository
+	}
+
+	review, err := cs.matches.Get(recordID)
+	if err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	rec, err := cs.records.Get(recordID)
+	if err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	if releaseID != "" {
+		candidate, ok := review.Candidate(releaseID)
+		if !ok {
+			return record.PublicRecord{}, ErrUnknownCandidate
+		}
+
+		if _, err := cs.applyMatch(rec, candidate, time.Now().UTC()); err != nil {
+			return record.PublicRecord{}, err
+		}
+		if rec, err = cs.records.Get(recordID); err != nil {
+			return record.PublicRecord{}, err
+		}
+	}
+
+	if err := cs.matches.Delete(recordID); err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	records, err := cs.withRecordDetails([]record.PublicRecord{rec.ToPublic()})
+	if err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	return records[0], nil
+}
@@ -0,0 +1,133 @@
+package services_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/pkg/musicbrainz"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+const musicBrainzDump = `{"id":"wall-2011","title":"The Wall","date":"2011-09-26","artist-credit":[{"name":"Pink Floyd","artist":{"id":"pf"}}],"release-group":{"id":"wall"},"media":[{"tracks":[{"title":"In the Flesh?","length":199000,"recording":{"id":"rec-flesh"}},{"title":"The Thin Ice","length":147000,"recording":{"id":"rec-ice"}},{"title":"Mother","length":335000,"recording":{"id":"rec-mother"}}]}]}
+{"id":"wall-1979","title":"The Wall","date":"1979-11-30","artist-credit":[{"name":"Pink Floyd","artist":{"id":"pf"}}],"release-group":{"id":"wall"},"media":[{"tracks":[{"title":"In the Flesh?","length":199000,"recording":{"id":"rec-flesh"}},{"title":"The Thin Ice","length":147000,"recording":{"id":"rec-ice"}},{"title":"Mother","length":335000,"recording":{"id":"rec-mother"}}]}]}
+{"id":"queen-hits","title":"Greatest Hits","date":"1981","artist-credit":[{"name":"Queen","artist":{"id":"queen"}}],"release-group":{"id":"queen"}}
+{"id":"abba-hits","title":"Greatest Hits","date":"1975","artist-credit":[{"name":"ABBA","artist":{"id":"abba"}}],"release-group":{"id":"abba"}}
+{"id":"other-rumours","title":"Rumours","artist-credit":[{"name":"Kid Koala","artist":{"id":"kk"}}],"release-group":{"id":"kk"}}
+{"id":"unrelated","title":"Kind of Blue","artist-credit":[{"name":"Miles Davis","artist":{"id":"md"}}]}
+`
+
+func TestCollectionService_EnrichFromMusicBrainz(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithMatchReviewMemoryRepository(),
+	)
+
+	var results []services.BulkResult
+	err := cs.ImportNDJSON(strings.NewReader(`{"name":"The Wall","artist":"Pink Floyd","kind":"vinyl","songs":[{"name":"In The Flesh"},{"name":"Mother","length":330}]}
+{"name":"Greatest Hits","kind":"mp3"}
+{"name":"Rumours","artist":"Fleetwood Mac","kind":"vinyl"}
+`), 10, func(batch []services.BulkResult) error {
+		results = append(results, batch...)
+		return nil
+	})
+	assert.NoError(t, err)
+	wall, hits := *results[0].ID, *results[1].ID
+
+	t.Run("Dry run", func(t *testing.T) {
+		enrichment, err := cs.EnrichFromMusicBrainz(strings.NewReader(musicBrainzDump), true)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, enrichment.Releases)
+		assert.Len(t, enrichment.Matched, 1)
+		assert.Len(t, enrichment.Review, 1)
+
+		rec, _ := cs.FindRecord(wall.String())
+		assert.Nil(t, rec.MusicBrainz)
+		reviews, err := cs.FindMusicBrainzReviews()
+		assert.NoError(t, err)
+		assert.Len(t, reviews, 0)
+	})
+
+	t.Run("Enrich", func(t *testing.T) {
+		enrichment, err := cs.EnrichFromMusicBrainz(strings.NewReader(musicBrainzDump), false)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, enrichment.Unmatched)
+
+		// editions of the same album do not compete, the original wins
+		assert.Len(t, enrichment.Matched, 1)
+		matched := enrichment.Matched[0]
+		assert.Equal(t, wall, matched.RecordID)
+		assert.Equal(t, "wall-1979", matched.ReleaseID)
+		assert.Equal(t, 0.9, matched.Score)
+		assert.Equal(t, 2, matched.Songs)
+
+		rec, _ := cs.FindRecord(wall.String())
+		assert.NotNil(t, rec.MusicBrainz)
+		assert.Equal(t, "1979-11-30", rec.MusicBrainz.ReleaseDate)
+		assert.Equal(t, "wall", rec.MusicBrainz.ReleaseGroupID)
+		assert.Equal(t, []string{"pf"}, rec.MusicBrainz.ArtistIDs)
+
+		songs, _ := cs.FindSongsByRecord(wall)
+		assert.Equal(t, "rec-flesh", songs[0].MusicBrainzID)
+		assert.Equal(t, int64(199), songs[0].Length)
+		// lengths we know are kept
+		assert.Equal(t, "rec-mother", songs[1].MusicBrainzID)
+		assert.Equal(t, int64(330), songs[1].Length)
+
+		reviews, err := cs.FindMusicBrainzReviews()
+		assert.NoError(t, err)
+		assert.Len(t, reviews, 1)
+		assert.Equal(t, hits, reviews[0].RecordID)
+		assert.Len(t, reviews[0].Candidates, 2)
+		assert.Equal(t, "abba-hits", reviews[0].Candidates[0].ReleaseID)
+	})
+
+	t.Run("Resolve review", func(t *testing.T) {
+		_, err := cs.ResolveMusicBrainzReview(hits, "wall-1979")
+		assert.Equal(t, services.ErrUnknownCandidate, err)
+
+		_, err = cs.ResolveMusicBrainzReview(uuid.New(), "")
+		assert.Equal(t, services.ErrMatchReviewNotFound, err)
+
+		rec, err := cs.ResolveMusicBrainzReview(hits, "queen-hits")
+		assert.NoError(t, err)
+		assert.Equal(t, "Queen", rec.Artist)
+		assert.Equal(t, "queen-hits", rec.MusicBrainz.ReleaseID)
+		reviews, _ := cs.FindMusicBrainzReviews()
+		assert.Len(t, reviews, 0)
+	})
+
+	t.Run("Enrich again", func(t *testing.T) {
+		enrichment, err := cs.EnrichFromMusicBrainz(strings.NewReader(musicBrainzDump), false)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, enrichment.Linked)
+		assert.Len(t, enrichment.Matched, 0)
+		assert.Equal(t, 1, enrichment.Unmatched)
+	})
+
+	t.Run("Broken lines", func(t *testing.T) {
+		broken := "{\"id\":\"r1\",\"title\":\"x\"}\n{\"name\":\"Pink Floyd\"}\nnot json\n" + musicBrainzDump
+		enrichment, err := cs.EnrichFromMusicBrainz(strings.NewReader(broken), true)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, enrichment.Releases)
+		assert.Equal(t, 2, enrichment.Skipped)
+
+		_, err = cs.EnrichFromMusicBrainz(strings.NewReader("not json\n{\"name\":\"Pink Floyd\"}\n"), true)
+		var lineErr *musicbrainz.LineError
+		assert.True(t, errors.As(err, &lineErr))
+	})
+
+	t.Run("No review repository", func(t *testing.T) {
+		others, _ := services.NewCollectionService(
+			services.WithRecordMemoryRepository(),
+			services.WithSongMemoryRepository(),
+		)
+		_, err := others.EnrichFromMusicBrainz(strings.NewReader(musicBrainzDump), false)
+		assert.Equal(t, services.ErrNoMatchReviewRepository, err)
+		_, err = others.FindMusicBrainzReviews()
+		assert.Equal(t, services.ErrNoMatchReviewRepository, err)
+	})
+}
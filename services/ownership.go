@@ -0,0 +1,39 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+// ChangeRecordStatus moves a record along the ownership lifecycle. When
+// acquisition is given it replaces the stored acquisition details.
+func (cs *CollectionService) ChangeRecordStatus(id uuid.UUID, status string, acquisition *record.Acquisition) (record.PublicRecord, error) {
+	rec, err := cs.records.Get(id)
+	if err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	var errs validation.Errors
+
+	st, err := record.ParseStatus(status)
+	if err != nil {
+		errs = errs.Add("status", validation.CodeInvalid, err)
+	} else {
+		errs = errs.Merge("", rec.TransitionTo(st))
+	}
+
+	if acquisition != nil {
+		errs = errs.Merge("", rec.SetAcquisition(*acquisition))
+	}
+
+	if err := errs.Err(); err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	if err := cs.records.Update(&rec); err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	return rec.ToPublic(), nil
+}
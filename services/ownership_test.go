@@ -0,0 +1,96 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_AddRecordWithOwnership(t *testing.T) {
+	tests := []struct {
+		name        string
+		record      string
+		status      string
+		acquisition record.Acquisition
+		want        record.Status
+		expectedErr string
+	}{
+		{
+			name:   "Default status is owned",
+			record: "r1",
+			want:   record.StatusOwned,
+		},
+		{
+			name:   "Wishlisted record",
+			record: "r1",
+			status: "wishlist",
+			want:   record.StatusWishlist,
+		},
+		{
+			name:        "Invalid status",
+			record:      "r1",
+			status:      "lost",
+			expectedErr: "status: invalid ownership status",
+		},
+		{
+			name:        "Every invalid field is reported",
+			record:      "",
+			acquisition: record.Acquisition{Price: -1},
+			expectedErr: "name: missing value; acquisition.price: price cannot be negative",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cs, _ := services.NewCollectionService(
+				services.WithRecordMemoryRepository(),
+				services.WithSongMemoryRepository(),
+			)
+
+			got, err := cs.AddRecordWithOwnership(uuid.New(), test.record, "vinyl", test.status, test.acquisition)
+			if err != nil {
+				assert.Equal(t, test.expectedErr, err.Error())
+				return
+			}
+
+			assert.Equal(t, test.want, got.Status)
+		})
+	}
+}
+
+func TestCollectionService_ChangeRecordStatus(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+
+	wish, _ := cs.AddRecordWithOwnership(uuid.New(), "r1", "vinyl", "wishlist", record.Acquisition{})
+	owned, _ := cs.AddRecord(uuid.New(), "r2", "vinyl")
+
+	date := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	got, err := cs.ChangeRecordStatus(wish.ID, "owned", &record.Acquisition{
+		Date:     date,
+		Price:    2500,
+		Currency: "EUR",
+		Seller:   "Discogs",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, record.StatusOwned, got.Status)
+	assert.Equal(t, int64(2500), got.Acquisition.Price)
+
+	_, err = cs.ChangeRecordStatus(owned.ID, "wishlist", nil)
+	assert.True(t, errors.Is(err, record.ErrInvalidTransition))
+
+	_, err = cs.ChangeRecordStatus(uuid.New(), "sold", nil)
+	assert.Equal(t, record.ErrRecordNotFound, err)
+
+	bought, err := cs.FindRecords(record.Filter{Seller: "discogs", AcquiredFrom: date})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(bought))
+	assert.Equal(t, wish.ID, bought[0].ID)
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/playlist"
+	pmemory "github.com/rodrwan/collection/domain/playlist/memory"
+	ppostgres "github.com/rodrwan/collection/domain/playlist/postgres"
+	"github.com/rodrwan/collection/domain/song"
+)
+
+// WithPlaylistMemoryRepository ...
+func WithPlaylistMemoryRepository() CollectionConfiguration {
+	return func(os *CollectionService) error {
+		mem, err := pmemory.New(context.Background())
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.playlists = mem
+		return nil
+	}
+}
+
+// WithPlaylistPostgresRepository ...
+func WithPlaylistPostgresRepository(connectionString string, connect ppostgres.SqlOpener) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		pg, err := ppostgres.New(context.Background(), connectionString, connect)
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.playlists = pg
+		return nil
+	}
+}
+
+// CreatePlaylist creates a playlist, optionally seeded with songs that are
+// added following its duplicate policy.
+func (cs *CollectionService) CreatePlaylist(name string, duplicates playlist.Duplicates, songIDs []uuid.UUID) (playlist.PublicPlaylist, error) {
+	p, err := playlist.NewPlaylist(name, duplicates)
+	if err != nil {
+		return playlist.PublicPlaylist{}, err
+	}
+
+	if err := cs.checkSongs(songIDs); err != nil {
+		return playlist.PublicPlaylist{}, err
+	}
+
+	if _, err := p.AddSongs(songIDs...); err != nil {
+		return playlist.PublicPlaylist{}, err
+	}
+
+	if err := cs.playlists.Add(p); err != nil {
+		return playlist.PublicPlaylist{}, err
+	}
+
+	return cs.publicPlaylist(p)
+}
+
+// FindPlaylist returns a playlist with the details of its songs and its
+// total length.
+func (cs *CollectionService) FindPlaylist(id uuid.UUID) (playlist.PublicPlaylist, error) {
+	p, err := cs.playlists.Get(id)
+	if err != nil {
+		return playlist.PublicPlaylist{}, err
+	}
+
+	return cs.publicPlaylist(p)
+}
+
+// FindAllPlaylists lists every playlist with its total length.
+func (cs *CollectionService) FindAllPlaylists() ([]playlist.PublicPlaylist, error) {
+	playlists, err := cs.playlists.FindPlaylists()
+	if err != nil {
+		return []playlist.PublicPlaylist{}, err
+	}
+
+	var pp []playlist.PublicPlaylist
+	for _, p := range playlists {
+		public, err := cs.publicPlaylist(p)
+		if err != nil {
+			return []playlist.PublicPlaylist{}, err
+		}
+		pp = append(pp, public)
+	}
+
+	return pp, nil
+}
+
+// EditPlaylist renames a playlist and changes its duplicate policy.
+func (cs *CollectionService) EditPlaylist(id uuid.UUID, name string, duplicates playlist.Duplicates) (playlist.PublicPlaylist, error) {
+	return cs.updatePlaylist(id, func(p *playlist.Playlist) error {
+		return p.Edit(name, duplicates)
+	})
+}
+
+// DeletePlaylist removes a playlist. Its songs are left untouched.
+func (cs *CollectionService) DeletePlaylist(id uuid.UUID) error {
+	return cs.playlists.Delete(id)
+}
+
+// AddSongsToPlaylist appends songs to a playlist following its duplicate
+// policy.
+func (cs *CollectionService) AddSongsToPlaylist(id uuid.UUID, songIDs []uuid.UUID) (playlist.PublicPlaylist, error) {
+	if err := cs.checkSongs(songIDs); err != nil {
+		return playlist.PublicPlaylist{}, err
+	}
+
+	return cs.updatePlaylist(id, func(p *playlist.Playlist) error {
+		_, err := p.AddSongs(songIDs...)
+		return err
+	})
+}
+
+// RemoveSongFromPlaylist removes the entry at the given position.
+func (cs *CollectionService) RemoveSongFromPlaylist(id uuid.UUID, position int) (playlist.PublicPlaylist, error) {
+	return cs.updatePlaylist(id, func(p *playlist.Playlist) error {
+		return p.RemoveSong(position)
+	})
+}
+
+// MovePlaylistSong moves a single entry of a playlist.
+func (cs *CollectionService) MovePlaylistSong(id uuid.UUID, from, to int) (playlist.PublicPlaylist, error) {
+	return cs.updatePlaylist(id, func(p *playlist.Playlist) error {
+		return p.Move(from, to)
+	})
+}
+
+// ReorderPlaylist replaces the whole order of a playlist.
+func (cs *CollectionService) ReorderPlaylist(id uuid.UUID, songIDs []uuid.UUID) (playlist.PublicPlaylist, error) {
+	return cs.updatePlaylist(id, func(p *playlist.Playlist) error {
+		return p.Reorder(songIDs)
+	})
+}
+
+func (cs *CollectionService) updatePlaylist(id uuid.UUID, change func(*playlist.Playlist) error) (playlist.PublicPlaylist, error) {
+	p, err := cs.playlists.Get(id)
+	if err != nil {
+		return playlist.PublicPlaylist{}, err
+	}
+
+	if err := change(&p); err != nil {
+		return playlist.PublicPlaylist{}, err
+	}
+
+	if err := cs.playlists.Update(&p); err != nil {
+		return playlist.PublicPlaylist{}, err
+	}
+
+	return cs.publicPlaylist(p)
+}
+
+// checkSongs makes sure every song exists before it is referenced.
+func (cs *CollectionService) checkSongs(ids []uuid.UUID) error {
+	for _, id := range ids {
+		if _, err := cs.songs.Get(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cs *CollectionService) publicPlaylist(p playlist.Playlist) (playlist.PublicPlaylist, error) {
+	var songs []song.Song
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range p.GetSongIDs() {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		s, err := cs.songs.Get(id)
+		if err == song.ErrSongNotFound {
+			continue
+		}
+		if err != nil {
+			return playlist.PublicPlaylist{}, err
+		}
+		songs = append(songs, s)
+	}
+
+	return p.WithSongs(songs), nil
+}
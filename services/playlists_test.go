@@ -0,0 +1,79 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/playlist"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_Playlists(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithPlaylistMemoryRepository(),
+	)
+
+	r1, _ := cs.AddRecord(uuid.New(), "r1", "vinyl")
+	r2, _ := cs.AddRecord(uuid.New(), "r2", "mp3")
+	cs.AddSongToRecord(r1.ToRecord(), "s1", 200)
+	cs.AddSongToRecord(r2.ToRecord(), "s2", 100)
+
+	songs1, err := cs.FindSongsByRecord(r1.ID)
+	assert.Nil(t, err)
+	songs2, _ := cs.FindSongsByRecord(r2.ID)
+	s1, s2 := songs1[0].ID, songs2[0].ID
+
+	_, err = cs.CreatePlaylist("Sunday", playlist.DuplicatesReject, []uuid.UUID{s1, uuid.New()})
+	assert.Equal(t, song.ErrSongNotFound, err)
+
+	_, err = cs.CreatePlaylist("", playlist.DuplicatesReject, nil)
+	assert.True(t, errors.Is(err, playlist.ErrMissingValues))
+
+	p, err := cs.CreatePlaylist("Sunday", playlist.DuplicatesReject, []uuid.UUID{s1, s2})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(300), p.TotalLength)
+	assert.Equal(t, 2, len(p.Songs))
+
+	_, err = cs.AddSongsToPlaylist(p.ID, []uuid.UUID{s1})
+	assert.Equal(t, playlist.ErrDuplicateSong, err)
+
+	// no policy leaves it unchanged
+	p, err = cs.EditPlaylist(p.ID, "Sunday", "")
+	assert.Nil(t, err)
+	assert.Equal(t, playlist.DuplicatesReject, p.Duplicates)
+
+	p, err = cs.EditPlaylist(p.ID, "Sunday morning", playlist.DuplicatesAllow)
+	assert.Nil(t, err)
+	assert.Equal(t, "Sunday morning", p.Name)
+
+	p, err = cs.AddSongsToPlaylist(p.ID, []uuid.UUID{s1})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(500), p.TotalLength)
+
+	p, err = cs.MovePlaylistSong(p.ID, 3, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, []uuid.UUID{s1, s1, s2}, p.SongIDs)
+
+	p, err = cs.ReorderPlaylist(p.ID, []uuid.UUID{s2, s1, s1})
+	assert.Nil(t, err)
+	assert.Equal(t, []uuid.UUID{s2, s1, s1}, p.SongIDs)
+
+	_, err = cs.ReorderPlaylist(p.ID, []uuid.UUID{s2, s1})
+	assert.Equal(t, playlist.ErrInvalidOrder, err)
+
+	p, err = cs.RemoveSongFromPlaylist(p.ID, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(400), p.TotalLength)
+
+	all, _ := cs.FindAllPlaylists()
+	assert.Equal(t, 1, len(all))
+
+	assert.Nil(t, cs.DeletePlaylist(p.ID))
+	_, err = cs.FindPlaylist(p.ID)
+	assert.Equal(t, playlist.ErrPlaylistNotFound, err)
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/play"
+	plmemory "github.com/rodrwan/collection/domain/play/memory"
+	plpostgres "github.com/rodrwan/collection/domain/play/postgres"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/domain/validation"
+)
+
+var (
+	ErrSongNotOnRecord = errors.New("song does not belong to the record")
+)
+
+// PlayInput is a play to log. The record can be left out when a song is
+// given, and a zero PlayedAt means it was played now.
+type PlayInput struct {
+	RecordID uuid.UUID
+	SongID   uuid.UUID
+	PlayedAt time.Time
+}
+
+// RecordPlays is a record with how often and how recently it was played.
+type RecordPlays struct {
+	Record       record.PublicRecord `json:"record"`
+	Plays        int                 `json:"plays"`
+	LastPlayedAt time.Time           `json:"lastPlayedAt"`
+}
+
+// WithPlayMemoryRepository ...
+func WithPlayMemoryRepository() CollectionConfiguration {
+	return func(os *CollectionService) error {
+		mem, err := plmemory.New(context.Background())
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.plays = mem
+		return nil
+	}
+}
+
+// WithPlayPostgresRepository ...
+func WithPlayPostgresRepository(connectionString string, connect plpostgres.SqlOpener) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		pg, err := plpostgres.New(context.Background(), connectionString, connect)
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.plays = pg
+		return nil
+	}
+}
+
+// LogPlay records a play of a whole record or of one of its songs.
+func (cs *CollectionService) LogPlay(in PlayInput) (play.PublicPlay, error) {
+	p, err := cs.newPlay(in)
+	if err != nil {
+		return play.PublicPlay{}, err
+	}
+
+	if err := cs.plays.Add(p); err != nil {
+		return play.PublicPlay{}, err
+	}
+
+	return p.ToPublic(), nil
+}
+
+// LogPlays records a batch of plays. Nothing is stored unless every play is
+// valid; the failing ones are reported as plays[i] fields.
+func (cs *CollectionService) LogPlays(ins []PlayInput) ([]play.PublicPlay, error) {
+	var errs validation.Errors
+
+	plays := make([]play.Play, 0, len(ins))
+	for i, in := range ins {
+		p, err := cs.newPlay(in)
+		if err != nil {
+			errs = errs.Merge(fmt.Sprintf("plays[%d]", i), err)
+			continue
+		}
+		plays = append(plays, p)
+	}
+	if err := errs.Err(); err != nil {
+		return []play.PublicPlay{}, err
+	}
+
+	if err := cs.plays.AddBatch(plays); err != nil {
+		return []play.PublicPlay{}, err
+	}
+
+	return play.ToPublicArray(plays), nil
+}
+
+func (cs *CollectionService) newPlay(in PlayInput) (play.Play, error) {
+	if in.SongID != uuid.Nil {
+		s, err := cs.songs.Get(in.SongID)
+		if err != nil {
+			return play.Play{}, err
+		}
+
+		if in.RecordID == uuid.Nil {
+			in.RecordID = s.GetRecordID()
+		} else if in.RecordID != s.GetRecordID() {
+			var errs validation.Errors
+			return play.Play{}, errs.Add("songId", validation.CodeNotAllowed, ErrSongNotOnRecord)
+		}
+	}
+
+	if in.RecordID != uuid.Nil {
+		if _, err := cs.records.Get(in.RecordID); err != nil {
+			return play.Play{}, err
+		}
+	}
+
+	if in.PlayedAt.IsZero() {
+		in.PlayedAt = time.Now().UTC()
+	}
+
+	return play.NewPlay(in.RecordID, in.SongID, in.PlayedAt)
+}
+
+// MostPlayedRecords ranks the records played in the range by play count. A
+// limit of 0 returns them all.
+func (cs *CollectionService) MostPlayedRecords(from, to time.Time, limit int) ([]RecordPlays, error) {
+	return cs.rankRecords(from, to, limit, play.MostPlayed)
+}
+
+// RecentlyPlayedRecords ranks the records played in the range by their last
+// play.
+func (cs *CollectionService) RecentlyPlayedRecords(from, to time.Time, limit int) ([]RecordPlays, error) {
+	return cs.rankRecords(from, to, limit, play.RecentlyPlayed)
+}
+
+// NeverPlayedRecords lists the owned records without any play in the range.
+func (cs *CollectionService) NeverPlayedRecords(from, to time.Time) ([]record.PublicRecord, error) {
+	plays, err := cs.plays.FindPlays(from, to)
+	if err != nil {
+		return []record.PublicRecord{}, err
+	}
+	stats := play.ByRecord(plays)
+
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return []record.PublicRecord{}, err
+	}
+
+	var never []record.Record
+	for _, r := range records {
+		if !r.IsOwned() {
+			continue
+		}
+		if _, ok := stats[r.GetID()]; !ok {
+			never = append(never, r)
+		}
+	}
+
+	return cs.withRecordDetails(record.ToPublicArray(never))
+}
+
+func (cs *CollectionService) rankRecords(from, to time.Time, limit int, order func(map[uuid.UUID]play.Stats) []play.Ranked) ([]RecordPlays, error) {
+	plays, err := cs.plays.FindPlays(from, to)
+	if err != nil {
+		return []RecordPlays{}, err
+	}
+
+	var ranked []RecordPlays
+	for _, r := range order(play.ByRecord(plays)) {
+		if limit > 0 && len(ranked) == limit {
+			break
+		}
+
+		rec, err := cs.records.Get(r.ID)
+		if err == record.ErrRecordNotFound {
+			continue
+		}
+		if err != nil {
+			return []RecordPlays{}, err
+		}
+
+		pr := rec.ToPublic()
+		pr.PlayCount = r.Count
+		ranked = append(ranked, RecordPlays{
+			Record:       pr,
+			Plays:        r.Count,
+			LastPlayedAt: r.LastPlayedAt,
+		})
+	}
+
+	return ranked, nil
+}
+
+// withRecordPlayCounts fills in the all time play count of each record. It
+// is a no-op when no play repository is configured.
+func (cs *CollectionService) withRecordPlayCounts(records []record.PublicRecord) ([]record.PublicRecord, error) {
+	if cs.plays == nil || len(records) == 0 {
+		return records, nil
+	}
+
+	plays, err := cs.plays.FindPlays(time.Time{}, time.Time{})
+	if err != nil {
+		return records, err
+	}
+
+	stats := play.ByRecord(plays)
+	for i := range records {
+		records[i].PlayCount = stats[records[i].ID].Count
+	}
+
+	return records, nil
+}
+
+// withSongPlayCounts fills in the all time play count of each song.
+func (cs *CollectionService) withSongPlayCounts(songs []song.PublicSong) ([]song.PublicSong, error) {
+	if cs.plays == nil || len(songs) == 0 {
+		return songs, nil
+	}
+
+	plays, err := cs.plays.FindPlays(time.Time{}, time.Time{})
+	if err != nil {
+		return songs, err
+	}
+
+	stats := play.BySong(plays)
+	for i := range songs {
+		songs[i].PlayCount = stats[songs[i].ID].Count
+	}
+
+	return songs, nil
+}
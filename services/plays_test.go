@@ -0,0 +1,86 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/domain/validation"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_Plays(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithPlayMemoryRepository(),
+	)
+
+	r1, _ := cs.AddRecord(uuid.New(), "r1", "vinyl")
+	r2, _ := cs.AddRecord(uuid.New(), "r2", "vinyl")
+	r3, _ := cs.AddRecord(uuid.New(), "r3", "mp3")
+	cs.AddRecordWithOwnership(uuid.New(), "wish", "vinyl", "wishlist", record.Acquisition{})
+	cs.AddSongToRecord(r1.ToRecord(), "s1", 200)
+	songs, _ := cs.FindSongsByRecord(r1.ID)
+	s1 := songs[0].ID
+
+	day := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	p, err := cs.LogPlay(services.PlayInput{SongID: s1, PlayedAt: day})
+	assert.Nil(t, err)
+	assert.Equal(t, r1.ID, p.RecordID)
+
+	_, err = cs.LogPlay(services.PlayInput{RecordID: r2.ID, SongID: s1, PlayedAt: day})
+	assert.True(t, errors.Is(err, services.ErrSongNotOnRecord))
+
+	_, err = cs.LogPlay(services.PlayInput{SongID: uuid.New()})
+	assert.Equal(t, song.ErrSongNotFound, err)
+
+	_, err = cs.LogPlays([]services.PlayInput{
+		{RecordID: r2.ID, PlayedAt: day},
+		{RecordID: uuid.New(), PlayedAt: day},
+		{},
+	})
+	var errs validation.Errors
+	assert.True(t, errors.As(err, &errs))
+	assert.True(t, errs.Has("plays[1]"))
+	assert.True(t, errs.Has("plays[2].recordId"))
+
+	plays, err := cs.LogPlays([]services.PlayInput{
+		{RecordID: r1.ID, PlayedAt: day.AddDate(0, 0, 1)},
+		{RecordID: r2.ID, PlayedAt: day.AddDate(0, 0, 10)},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(plays))
+
+	rec, _ := cs.FindRecord(r1.ID.String())
+	assert.Equal(t, 2, rec.PlayCount)
+	songs, _ = cs.FindSongsByRecord(r1.ID)
+	assert.Equal(t, 1, songs[0].PlayCount)
+
+	most, err := cs.MostPlayedRecords(time.Time{}, time.Time{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(most))
+	assert.Equal(t, r1.ID, most[0].Record.ID)
+	assert.Equal(t, 2, most[0].Plays)
+
+	recent, _ := cs.RecentlyPlayedRecords(time.Time{}, time.Time{}, 1)
+	assert.Equal(t, 1, len(recent))
+	assert.Equal(t, r2.ID, recent[0].Record.ID)
+
+	ranged, _ := cs.MostPlayedRecords(day.AddDate(0, 0, 5), time.Time{}, 0)
+	assert.Equal(t, 1, len(ranged))
+	assert.Equal(t, r2.ID, ranged[0].Record.ID)
+
+	never, err := cs.NeverPlayedRecords(time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(never))
+	assert.Equal(t, r3.ID, never[0].ID)
+
+	never, _ = cs.NeverPlayedRecords(day.AddDate(0, 0, 5), time.Time{})
+	assert.Equal(t, 2, len(never))
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/rating"
+	rtmemory "github.com/rodrwan/collection/domain/rating/memory"
+	rtpostgres "github.com/rodrwan/collection/domain/rating/postgres"
+	"github.com/rodrwan/collection/domain/record"
+)
+
+// Reviews are the reviews of a record or song with their average.
+type Reviews struct {
+	rating.Summary
+	Reviews []rating.PublicReview `json:"reviews"`
+}
+
+// WithReviewMemoryRepository ...
+func WithReviewMemoryRepository() CollectionConfiguration {
+	return func(os *CollectionService) error {
+		mem, err := rtmemory.New(context.Background())
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.reviews = mem
+		return nil
+	}
+}
+
+// WithReviewPostgresRepository ...
+func WithReviewPostgresRepository(connectionString string, connect rtpostgres.SqlOpener) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		pg, err := rtpostgres.New(context.Background(), connectionString, connect)
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.reviews = pg
+		return nil
+	}
+}
+
+// RateRecord saves the rating and review of a user for a record, replacing
+// the one they gave before.
+func (cs *CollectionService) RateRecord(recordID uuid.UUID, user string, stars float64, text string) (rating.PublicReview, error) {
+	if _, err := cs.records.Get(recordID); err != nil {
+		return rating.PublicReview{}, err
+	}
+
+	return cs.saveReview(user, rating.SubjectRecord, recordID, stars, text)
+}
+
+// RateSong saves the rating and review of a user for a song.
+func (cs *CollectionService) RateSong(songID uuid.UUID, user string, stars float64, text string) (rating.PublicReview, error) {
+	if _, err := cs.songs.Get(songID); err != nil {
+		return rating.PublicReview{}, err
+	}
+
+	return cs.saveReview(user, rating.SubjectSong, songID, stars, text)
+}
+
+// FindRecordReviews lists the reviews of a record.
+func (cs *CollectionService) FindRecordReviews(recordID uuid.UUID) (Reviews, error) {
+	if _, err := cs.records.Get(recordID); err != nil {
+		return Reviews{}, err
+	}
+
+	return cs.findReviews(rating.SubjectRecord, recordID)
+}
+
+// FindSongReviews lists the reviews of a song.
+func (cs *CollectionService) FindSongReviews(songID uuid.UUID) (Reviews, error) {
+	if _, err := cs.songs.Get(songID); err != nil {
+		return Reviews{}, err
+	}
+
+	return cs.findReviews(rating.SubjectSong, songID)
+}
+
+func (cs *CollectionService) saveReview(user string, subject rating.Subject, id uuid.UUID, stars float64, text string) (rating.PublicReview, error) {
+	r, err := rating.NewReview(user, subject, id, stars, text, time.Now().UTC())
+	if err != nil {
+		return rating.PublicReview{}, err
+	}
+
+	if err := cs.reviews.Save(r); err != nil {
+		return rating.PublicReview{}, err
+	}
+
+	return r.ToPublic(), nil
+}
+
+func (cs *CollectionService) findReviews(subject rating.Subject, id uuid.UUID) (Reviews, error) {
+	reviews, err := cs.reviews.FindBySubject(subject, id)
+	if err != nil {
+		return Reviews{}, err
+	}
+
+	return Reviews{
+		Summary: rating.Summarize(reviews)[id],
+		Reviews: rating.ToPublicArray(reviews),
+	}, nil
+}
+
+// withRecordDetails fills in the play counts, average ratings and cover
+// art URLs of each record, skipping whatever is not configured.
+func (cs *CollectionService) withRecordDetails(records []record.PublicRecord) ([]record.PublicRecord, error) {
+	records, err := cs.withRecordPlayCounts(records)
+	if err != nil {
+		return records, err
+	}
+
+	records, err = cs.withRecordRatings(records)
+	if err != nil {
+		return records, err
+	}
+
+	return cs.withRecordImages(records)
+}
+
+func (cs *CollectionService) withRecordRatings(records []record.PublicRecord) ([]record.PublicRecord, error) {
+	if cs.reviews == nil || len(records) == 0 {
+		return records, nil
+	}
+
+	reviews, err := cs.reviews.FindReviews(rating.SubjectRecord)
+	if err != nil {
+		return records, err
+	}
+
+	summaries := rating.Summarize(reviews)
+	for i := range records {
+		summary, ok := summaries[records[i].ID]
+		if !ok {
+			continue
+		}
+		average := summary.Average
+		records[i].AverageRating = &average
+		records[i].RatingCount = summary.Count
+	}
+
+	return records, nil
+}
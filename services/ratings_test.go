@@ -0,0 +1,70 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/rating"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_Ratings(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithGenreMemoryRepository(),
+		services.WithReviewMemoryRepository(),
+	)
+
+	r1, _ := cs.AddRecord(uuid.New(), "Blue", "vinyl")
+	r2, _ := cs.AddRecord(uuid.New(), "Abbey Road", "vinyl")
+	cs.AddRecord(uuid.New(), "Unrated", "vinyl")
+	cs.AddSongToRecord(r1.ToRecord(), "s1", 200)
+	songs, _ := cs.FindSongsByRecord(r1.ID)
+
+	_, err := cs.RateRecord(r1.ID, "percy", 3, "")
+	assert.Nil(t, err)
+	_, err = cs.RateRecord(r1.ID, "Percy", 4.5, "better on a second listen")
+	assert.Nil(t, err)
+	_, err = cs.RateRecord(r1.ID, "rodrigo", 4, "")
+	assert.Nil(t, err)
+	_, err = cs.RateRecord(r2.ID, "percy", 2.5, "")
+	assert.Nil(t, err)
+
+	_, err = cs.RateRecord(r2.ID, "percy", 6, "")
+	assert.True(t, errors.Is(err, rating.ErrInvalidStars))
+
+	_, err = cs.RateRecord(uuid.New(), "percy", 3, "")
+	assert.Equal(t, record.ErrRecordNotFound, err)
+
+	_, err = cs.RateSong(songs[0].ID, "percy", 5, "")
+	assert.Nil(t, err)
+	_, err = cs.RateSong(uuid.New(), "percy", 5, "")
+	assert.Equal(t, song.ErrSongNotFound, err)
+
+	reviews, err := cs.FindRecordReviews(r1.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, reviews.Count)
+	assert.Equal(t, 4.25, reviews.Average)
+
+	songReviews, _ := cs.FindSongReviews(songs[0].ID)
+	assert.Equal(t, 1, songReviews.Count)
+
+	rec, _ := cs.FindRecord(r1.ID.String())
+	assert.Equal(t, 4.25, *rec.AverageRating)
+	assert.Equal(t, 2, rec.RatingCount)
+
+	rated, err := cs.FindRecords(record.Filter{MinRating: 2, Sort: "rating"})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(rated))
+	assert.Equal(t, r2.ID, rated[0].ID)
+
+	all, _ := cs.FindRecords(record.Filter{Sort: "-rating"})
+	assert.Equal(t, 3, len(all))
+	assert.Equal(t, r1.ID, all[0].ID)
+	assert.Nil(t, all[2].AverageRating)
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/release"
+	rmemory "github.com/rodrwan/collection/domain/release/memory"
+	rpostgres "github.com/rodrwan/collection/domain/release/postgres"
+)
+
+// WithReleaseMemoryRepository ...
+func WithReleaseMemoryRepository() CollectionConfiguration {
+	return func(os *CollectionService) error {
+		mem, err := rmemory.New(context.Background())
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.releases = mem
+		return nil
+	}
+}
+
+// WithReleasePostgresRepository ...
+func WithReleasePostgresRepository(connectionString string, connect rpostgres.SqlOpener) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		pg, err := rpostgres.New(context.Background(), connectionString, connect)
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.releases = pg
+		return nil
+	}
+}
+
+// CreateRelease creates a master release, optionally with the record of
+// its original pressing.
+func (cs *CollectionService) CreateRelease(name string, originalID uuid.UUID) (release.PublicRelease, error) {
+	r, err := release.NewRelease(name)
+	if err != nil {
+		return release.PublicRelease{}, err
+	}
+
+	if originalID != uuid.Nil {
+		if err := cs.checkVariantRecord(originalID); err != nil {
+			return release.PublicRelease{}, err
+		}
+		if err := r.AddVariant(release.Variant{RecordID: originalID, Relation: release.RelationOriginal}); err != nil {
+			return release.PublicRelease{}, err
+		}
+	}
+
+	if err := cs.releases.Add(r); err != nil {
+		return release.PublicRelease{}, err
+	}
+
+	return cs.publicRelease(r)
+}
+
+// FindRelease returns a release with the details of its variants.
+func (cs *CollectionService) FindRelease(id uuid.UUID) (release.PublicRelease, error) {
+	r, err := cs.releases.Get(id)
+	if err != nil {
+		return release.PublicRelease{}, err
+	}
+
+	return cs.publicRelease(r)
+}
+
+// FindAllReleases lists every release with the formats it is owned in.
+func (cs *CollectionService) FindAllReleases() ([]release.PublicRelease, error) {
+	releases, err := cs.releases.FindReleases()
+	if err != nil {
+		return []release.PublicRelease{}, err
+	}
+
+	var rr []release.PublicRelease
+	for _, r := range releases {
+		public, err := cs.publicRelease(r)
+		if err != nil {
+			return []release.PublicRelease{}, err
+		}
+		rr = append(rr, public)
+	}
+
+	return rr, nil
+}
+
+// AddReleaseVariant adds a record to a release. A record is a variant of at
+// most one release.
+func (cs *CollectionService) AddReleaseVariant(id uuid.UUID, variant release.Variant) (release.PublicRelease, error) {
+	r, err := cs.releases.Get(id)
+	if err != nil {
+		return release.PublicRelease{}, err
+	}
+
+	if err := cs.checkVariantRecord(variant.RecordID); err != nil {
+		return release.PublicRelease{}, err
+	}
+
+	if err := r.AddVariant(variant); err != nil {
+		return release.PublicRelease{}, err
+	}
+
+	if err := cs.releases.Update(&r); err != nil {
+		return release.PublicRelease{}, err
+	}
+
+	return cs.publicRelease(r)
+}
+
+// RemoveReleaseVariant takes a record out of a release.
+func (cs *CollectionService) RemoveReleaseVariant(id, recordID uuid.UUID) (release.PublicRelease, error) {
+	r, err := cs.releases.Get(id)
+	if err != nil {
+		return release.PublicRelease{}, err
+	}
+
+	if err := r.RemoveVariant(recordID); err != nil {
+		return release.PublicRelease{}, err
+	}
+
+	if err := cs.releases.Update(&r); err != nil {
+		return release.PublicRelease{}, err
+	}
+
+	return cs.publicRelease(r)
+}
+
+// FindRecordVariants returns the release a record belongs to, listing all
+// of its variants.
+func (cs *CollectionService) FindRecordVariants(recordID uuid.UUID) (release.PublicRelease, error) {
+	if _, err := cs.records.Get(recordID); err != nil {
+		return release.PublicRelease{}, err
+	}
+
+	r, err := cs.releases.FindByRecord(recordID)
+	if err != nil {
+		return release.PublicRelease{}, err
+	}
+
+	return cs.publicRelease(r)
+}
+
+// checkVariantRecord makes sure the record exists and is not already part
+// of a release.
+func (cs *CollectionService) checkVariantRecord(recordID uuid.UUID) error {
+	if recordID == uuid.Nil {
+		// left for the domain to report as a missing field
+		return nil
+	}
+
+	if _, err := cs.records.Get(recordID); err != nil {
+		return err
+	}
+
+	_, err := cs.releases.FindByRecord(recordID)
+	switch err {
+	case nil:
+		return release.ErrRecordInRelease
+	case release.ErrRecordNoRelease:
+		return nil
+	}
+
+	return err
+}
+
+func (cs *CollectionService) publicRelease(r release.Release) (release.PublicRelease, error) {
+	var records []record.Record
+	for _, v := range r.GetVariants() {
+		rec, err := cs.records.Get(v.RecordID)
+		if err == record.ErrRecordNotFound {
+			continue
+		}
+		if err != nil {
+			return release.PublicRelease{}, err
+		}
+		records = append(records, rec)
+	}
+
+	return r.WithRecords(records), nil
+}
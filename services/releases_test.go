@@ -0,0 +1,73 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/release"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_Releases(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithReleaseMemoryRepository(),
+	)
+
+	vinyl, _ := cs.AddRecord(uuid.New(), "Kind of Blue", "vinyl")
+	remaster, _ := cs.AddRecord(uuid.New(), "Kind of Blue (Remastered)", "vinyl")
+	mp3, _ := cs.AddRecord(uuid.New(), "Kind of Blue", "mp3")
+	wanted, _ := cs.AddRecordWithOwnership(uuid.New(), "Kind of Blue (Mono)", "vinyl", "wishlist", record.Acquisition{})
+
+	r, err := cs.CreateRelease("Kind of Blue", vinyl.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, r.OwnedFormats)
+
+	_, err = cs.CreateRelease("Duplicate", vinyl.ID)
+	assert.Equal(t, release.ErrRecordInRelease, err)
+
+	_, err = cs.AddReleaseVariant(r.ID, release.Variant{RecordID: remaster.ID, Relation: release.RelationRemaster, Of: vinyl.ID})
+	assert.Nil(t, err)
+	_, err = cs.AddReleaseVariant(r.ID, release.Variant{RecordID: wanted.ID, Relation: release.RelationReissue, Of: vinyl.ID})
+	assert.Nil(t, err)
+	r, err = cs.AddReleaseVariant(r.ID, release.Variant{RecordID: mp3.ID, Relation: release.RelationDigital, Of: remaster.ID})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, r.OwnedFormats)
+	assert.Equal(t, []string{"mp3", "vinyl"}, r.Formats)
+	assert.Len(t, r.Variants, 4)
+
+	_, err = cs.AddReleaseVariant(r.ID, release.Variant{RecordID: uuid.New(), Relation: release.RelationReissue, Of: vinyl.ID})
+	assert.Equal(t, record.ErrRecordNotFound, err)
+
+	_, err = cs.AddReleaseVariant(r.ID, release.Variant{RecordID: mp3.ID, Relation: "bootleg", Of: vinyl.ID})
+	assert.Equal(t, release.ErrRecordInRelease, err)
+
+	variants, err := cs.FindRecordVariants(mp3.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, r.ID, variants.ID)
+	assert.Equal(t, "Kind of Blue (Remastered)", variants.Variants[1].Name)
+
+	_, err = cs.RemoveReleaseVariant(r.ID, remaster.ID)
+	assert.Equal(t, release.ErrVariantInUse, err)
+
+	r, err = cs.RemoveReleaseVariant(r.ID, mp3.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, r.OwnedFormats)
+
+	_, err = cs.FindRecordVariants(mp3.ID)
+	assert.Equal(t, release.ErrRecordNoRelease, err)
+
+	other, _ := cs.AddRecord(uuid.New(), "Blue", "vinyl")
+	second, err := cs.CreateRelease("Blue", uuid.Nil)
+	assert.Nil(t, err)
+	_, err = cs.AddReleaseVariant(second.ID, release.Variant{RecordID: other.ID, Relation: release.RelationReissue})
+	assert.True(t, errors.Is(err, release.ErrMissingValues))
+
+	releases, err := cs.FindAllReleases()
+	assert.Nil(t, err)
+	assert.Len(t, releases, 2)
+}
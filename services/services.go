@@ -2,20 +2,45 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"log"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rodrwan/collection/domain/loan"
+	lpostgres "github.com/rodrwan/collection/domain/loan/postgres"
+	"github.com/rodrwan/collection/domain/location"
+	lopostgres "github.com/rodrwan/collection/domain/location/postgres"
+	"github.com/rodrwan/collection/domain/match"
+	mpostgres "github.com/rodrwan/collection/domain/match/postgres"
+	"github.com/rodrwan/collection/domain/money"
+	"github.com/rodrwan/collection/domain/play"
+	plpostgres "github.com/rodrwan/collection/domain/play/postgres"
+	"github.com/rodrwan/collection/domain/playlist"
+	ppostgres "github.com/rodrwan/collection/domain/playlist/postgres"
+	"github.com/rodrwan/collection/domain/rating"
+	rtpostgres "github.com/rodrwan/collection/domain/rating/postgres"
 	"github.com/rodrwan/collection/domain/record"
 	"github.com/rodrwan/collection/domain/record/memory"
 	rmock "github.com/rodrwan/collection/domain/record/mock"
 	"github.com/rodrwan/collection/domain/record/postgres"
+	"github.com/rodrwan/collection/domain/release"
+	rpostgres "github.com/rodrwan/collection/domain/release/postgres"
 	"github.com/rodrwan/collection/domain/song"
 	smemory "github.com/rodrwan/collection/domain/song/memory"
+	spostgres "github.com/rodrwan/collection/domain/song/postgres"
+	"github.com/rodrwan/collection/domain/taxonomy"
+	tpostgres "github.com/rodrwan/collection/domain/taxonomy/postgres"
+	"github.com/rodrwan/collection/domain/validation"
+	"github.com/rodrwan/collection/pkg/blob"
 )
 
 var (
-	ErrInvalidType = errors.New("Invalid record type")
+	// ErrInvalidType is kept for callers matching on it, records now report
+	// their kind with a validation error wrapping record.ErrInvalidKind.
+	ErrInvalidType      = record.ErrInvalidKind
+	ErrNoSongRepository = errors.New("no song repository configured")
 )
 
 // ICollectionService ...
@@ -35,8 +60,21 @@ type CollectionConfiguration func(cs *CollectionService) error
 
 // CollectionService ...
 type CollectionService struct {
-	records record.RecordRepository
-	songs   song.SongRepository
+	records   record.RecordRepository
+	songs     song.SongRepository
+	genres    taxonomy.GenreRepository
+	rates     money.Rates
+	loans     loan.LoanRepository
+	locations location.LocationRepository
+	playlists playlist.PlaylistRepository
+	plays     play.PlayRepository
+	reviews   rating.ReviewRepository
+	releases  release.ReleaseRepository
+	blobs     blob.Store
+	blobURL   string
+	library   string
+	scans     scanJobs
+	matches   match.ReviewRepository
 }
 
 // WithRecordMemoryRepository ...
@@ -109,18 +147,19 @@ func WithError() CollectionConfiguration {
 	}
 }
 
-// // WithSongPostgresRepository ...
-// func WithSongPostgresRepository(connectionString string) CollectionConfiguration {
-// 	return func(os *CollectionService) error {
-// 		pg, err := postgres.New(context.Background(), connectionString)
-// 		if err != nil {
-// 			return err
-// 		}
+// WithSongPostgresRepository ...
+func WithSongPostgresRepository(connectionString string, connect spostgres.SqlOpener) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		pg, err := spostgres.New(context.Background(), connectionString, connect)
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
 
-// 		os.records = pg
-// 		return nil
-// 	}
-// }
+		os.songs = pg
+		return nil
+	}
+}
 
 // NewCollectionService ...
 func NewCollectionService(cfgs ...CollectionConfiguration) (*CollectionService, error) {
@@ -135,29 +174,128 @@ func NewCollectionService(cfgs ...CollectionConfiguration) (*CollectionService,
 	return cs, nil
 }
 
+// inTransaction runs fn with a service whose postgres repositories write in
+// one transaction, committed when fn succeeds and rolled back otherwise.
+// Without a postgres record repository to begin it on, fn runs on cs and
+// its writes are not undone on failure.
+func (cs *CollectionService) inTransaction(fn func(tx *CollectionService) error) error {
+	return cs.transaction(nil, fn)
+}
+
+// inSnapshot runs fn with a service whose postgres repositories read in one
+// read-only repeatable read transaction, so they all see the collection as
+// it was when it began.
+func (cs *CollectionService) inSnapshot(fn func(tx *CollectionService) error) error {
+	return cs.transaction(&sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, fn)
+}
+
+func (cs *CollectionService) transaction(opts *sql.TxOptions, fn func(tx *CollectionService) error) error {
+	pg, ok := cs.records.(*postgres.PostgresRepository)
+	if !ok {
+		return fn(cs)
+	}
+
+	tx, err := pg.Begin(opts)
+	if err == postgres.ErrNoTransactions {
+		return fn(cs)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := fn(cs.withTx(tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// withTx returns a service sharing the repositories of cs, those on
+// postgres running their statements in tx. Scan jobs stay with cs.
+func (cs *CollectionService) withTx(tx *sqlx.Tx) *CollectionService {
+	txcs := &CollectionService{
+		records:   cs.records,
+		songs:     cs.songs,
+		genres:    cs.genres,
+		rates:     cs.rates,
+		loans:     cs.loans,
+		locations: cs.locations,
+		playlists: cs.playlists,
+		plays:     cs.plays,
+		reviews:   cs.reviews,
+		releases:  cs.releases,
+		blobs:     cs.blobs,
+		blobURL:   cs.blobURL,
+		library:   cs.library,
+		matches:   cs.matches,
+	}
+
+	if pg, ok := cs.records.(*postgres.PostgresRepository); ok {
+		txcs.records = pg.WithTx(tx)
+	}
+	if pg, ok := cs.songs.(*spostgres.PostgresRepository); ok {
+		txcs.songs = pg.WithTx(tx)
+	}
+	if pg, ok := cs.genres.(*tpostgres.PostgresRepository); ok {
+		txcs.genres = pg.WithTx(tx)
+	}
+	if pg, ok := cs.loans.(*lpostgres.PostgresRepository); ok {
+		txcs.loans = pg.WithTx(tx)
+	}
+	if pg, ok := cs.locations.(*lopostgres.PostgresRepository); ok {
+		txcs.locations = pg.WithTx(tx)
+	}
+	if pg, ok := cs.playlists.(*ppostgres.PostgresRepository); ok {
+		txcs.playlists = pg.WithTx(tx)
+	}
+	if pg, ok := cs.plays.(*plpostgres.PostgresRepository); ok {
+		txcs.plays = pg.WithTx(tx)
+	}
+	if pg, ok := cs.reviews.(*rtpostgres.PostgresRepository); ok {
+		txcs.reviews = pg.WithTx(tx)
+	}
+	if pg, ok := cs.releases.(*rpostgres.PostgresRepository); ok {
+		txcs.releases = pg.WithTx(tx)
+	}
+	if pg, ok := cs.matches.(*mpostgres.PostgresRepository); ok {
+		txcs.matches = pg.WithTx(tx)
+	}
+
+	return txcs
+}
+
 // AddRecord ...
 func (cs *CollectionService) AddRecord(id uuid.UUID, name string, kind string) (record.PublicRecord, error) {
+	return cs.AddRecordWithOwnership(id, name, kind, "", record.Acquisition{})
+}
+
+// AddRecordWithOwnership adds a record starting at the given ownership
+// status, owned when empty, with its acquisition details.
+func (cs *CollectionService) AddRecordWithOwnership(id uuid.UUID, name string, kind string, status string, acquisition record.Acquisition) (record.PublicRecord, error) {
+	var errs validation.Errors
+
 	rec, err := record.NewRecordWithID(id, name, kind)
-	if err != nil {
-		return (&record.Record{}).ToPublic(), err
-	}
+	errs = errs.Merge("", err)
 
-	switch kind {
-	case "vinyl":
-		if err := cs.records.Add(rec); err != nil {
-			return (&record.Record{}).ToPublic(), err
+	if status != "" {
+		st, err := record.ParseStatus(status)
+		if err != nil {
+			errs = errs.Add("status", validation.CodeInvalid, err)
 		}
+		rec.SetStatus(st)
+	}
 
-		return rec.ToPublic(), nil
-	case "mp3":
-		if err := cs.records.Add(rec); err != nil {
-			return (&record.Record{}).ToPublic(), err
-		}
+	errs = errs.Merge("", rec.SetAcquisition(acquisition))
+	if err := errs.Err(); err != nil {
+		return (&record.Record{}).ToPublic(), err
+	}
 
-		return rec.ToPublic(), nil
+	if err := cs.records.Add(rec); err != nil {
+		return (&record.Record{}).ToPublic(), err
 	}
 
-	return (&record.Record{}).ToPublic(), ErrInvalidType
+	return rec.ToPublic(), nil
 }
 
 // FindRecord ...
@@ -168,19 +306,46 @@ func (cs *CollectionService) FindRecord(id string) (record.PublicRecord, error)
 		return (&record.Record{}).ToPublic(), err
 	}
 
-	return rec.ToPublic(), nil
+	records, err := cs.withRecordDetails([]record.PublicRecord{rec.ToPublic()})
+	if err != nil {
+		return (&record.Record{}).ToPublic(), err
+	}
+
+	return records[0], nil
 }
 
 // AddSongToRecord ...
 func (cs *CollectionService) AddSongToRecord(record *record.Record, name string, length int64) error {
+	if cs.songs == nil {
+		return ErrNoSongRepository
+	}
+
 	s, err := song.NewSong(name, length, record.GetID())
 	if err != nil {
 		return err
 	}
 
+	if err := cs.songs.Add(s); err != nil {
+		return err
+	}
+
 	return cs.records.AddSong(record.GetID(), &s)
 }
 
+// FindSongsByRecord lists the songs of a record.
+func (cs *CollectionService) FindSongsByRecord(recordID uuid.UUID) ([]song.PublicSong, error) {
+	if _, err := cs.records.Get(recordID); err != nil {
+		return []song.PublicSong{}, err
+	}
+
+	songs, err := cs.songs.FindSongsByRecord(recordID)
+	if err != nil {
+		return []song.PublicSong{}, err
+	}
+
+	return cs.withSongPlayCounts(song.ToPublicArray(songs))
+}
+
 // FindAllRecord ...
 func (cs *CollectionService) FindAllRecord() ([]record.PublicRecord, error) {
 	records, err := cs.records.FindRecords()
@@ -188,5 +353,5 @@ func (cs *CollectionService) FindAllRecord() ([]record.PublicRecord, error) {
 		return []record.PublicRecord{}, err
 	}
 
-	return record.ToPublicArray(records), nil
+	return cs.withRecordDetails(record.ToPublicArray(records))
 }
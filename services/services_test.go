@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rodrwan/collection/domain/record"
+	rmock "github.com/rodrwan/collection/domain/record/mock"
 	"github.com/rodrwan/collection/domain/record/postgres"
 	"github.com/rodrwan/collection/services"
 	"github.com/stretchr/testify/assert"
@@ -91,7 +92,7 @@ func TestCollectionService_AddRecord(t *testing.T) {
 				Name: "lala",
 				Kind: "vinyl",
 			},
-			expectedErr: errors.New("something went wrong"),
+			expectedErr: rmock.ErrMock,
 			services: []services.CollectionConfiguration{
 				services.WithFakeRecordService(true, uuid.Nil),
 				services.WithSongMemoryRepository(),
@@ -105,7 +106,7 @@ func TestCollectionService_AddRecord(t *testing.T) {
 				Name: "lala",
 				Kind: "mp3",
 			},
-			expectedErr: errors.New("something went wrong"),
+			expectedErr: rmock.ErrMock,
 			services: []services.CollectionConfiguration{
 				services.WithFakeRecordService(true, uuid.Nil),
 				services.WithSongMemoryRepository(),
@@ -121,7 +122,7 @@ func TestCollectionService_AddRecord(t *testing.T) {
 
 			got, err := cs.AddRecord(test.args.id, test.args.name, test.args.kind)
 			if err != nil {
-				assert.Equalf(t, test.expectedErr.Error(), err.Error(), test.description)
+				assert.Truef(t, errors.Is(err, test.expectedErr), "%s: got %v, want %v", test.description, err, test.expectedErr)
 				return
 			}
 
@@ -265,6 +266,20 @@ func TestCollectionService_AddSongToRecord(t *testing.T) {
 				services.WithSongMemoryRepository(),
 			},
 		},
+		{
+			name:        "No songs",
+			description: "without a song repository",
+			args: args{
+				rec:    &r,
+				name:   "lalo",
+				length: 100,
+			},
+			want:        0,
+			expectedErr: services.ErrNoSongRepository,
+			services: []services.CollectionConfiguration{
+				services.WithRecordMemoryRepository(),
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -356,14 +371,40 @@ func TestCollectionService_AddRecordWithPostgres(t *testing.T) {
 
 			got, err := cs.AddRecord(test.args.id, test.args.name, test.args.kind)
 			if err != nil {
-				assert.Equalf(t, test.expectedErr.Error(), err.Error(), test.description)
+				assert.Truef(t, errors.Is(err, test.expectedErr), "%s: got %v, want %v", test.description, err, test.expectedErr)
 				return
 			}
 
 			// expectedQuery
-			expectedQuery := "INSERT INTO records (id, name, kind) VALUES (:id, :name, :kind)"
+			expectedQuery := "INSERT INTO records (id, name, kind, genre_id, tags, grading_history, status, acquired_at, acquisition_price, acquisition_currency, seller, acquisition_notes, valuations, location_id, shelf_position, cover_key, cover_type, cover_updated_at, artist, musicbrainz) VALUES (:id, :name, :kind, :genre_id, :tags, :grading_history, :status, :acquired_at, :acquisition_price, :acquisition_currency, :seller, :acquisition_notes, :valuations, :location_id, :shelf_position, :cover_key, :cover_type, :cover_updated_at, :artist, :musicbrainz)"
 			assert.Equalf(t, test.want.Kind, got.Kind, test.description)
 			assert.Equalf(t, expectedQuery, mock.CalledWith()[0], test.description)
 		})
 	}
 }
+
+func TestCollectionService_AddSongToRecordWithPostgres(t *testing.T) {
+	mock := &postgres.MockDB{}
+	cs, _ := services.NewCollectionService(
+		services.WithRecordPostgresWithMock(mock),
+		services.WithSongMemoryRepository(),
+	)
+
+	r, _ := record.NewRecord("lala", "vinyl")
+	assert.Nil(t, cs.AddSongToRecord(&r, "lalo", 100))
+	assert.Equal(t, "UPDATE songs SET record_id = :record_id WHERE id = :id", mock.CalledWith()[0])
+	assert.Equal(t, r.GetID(), mock.CalledWith()[1].(map[string]interface{})["record_id"])
+}
+
+func TestCollectionService_FindRecordWithPostgres(t *testing.T) {
+	mock := &postgres.MockDB{}
+	cs, _ := services.NewCollectionService(
+		services.WithRecordPostgresWithMock(mock),
+		services.WithSongMemoryRepository(),
+	)
+
+	id := uuid.New()
+	cs.FindRecord(id.String())
+	assert.Equal(t, "SELECT * FROM records WHERE id = $1", mock.CalledWith()[0])
+	assert.Equal(t, id, mock.CalledWith()[1])
+}
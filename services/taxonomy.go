@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/taxonomy"
+	tmemory "github.com/rodrwan/collection/domain/taxonomy/memory"
+	tpostgres "github.com/rodrwan/collection/domain/taxonomy/postgres"
+)
+
+var (
+	ErrNoGenreRepository = errors.New("no genre repository configured")
+	ErrGenreConflict     = errors.New("a genre cannot be set and cleared at once")
+)
+
+// WithGenreMemoryRepository ...
+func WithGenreMemoryRepository() CollectionConfiguration {
+	return func(os *CollectionService) error {
+		mem, err := tmemory.New(context.Background())
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.genres = mem
+		return nil
+	}
+}
+
+// WithGenrePostgresRepository ...
+func WithGenrePostgresRepository(connectionString string, connect tpostgres.SqlOpener) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		pg, err := tpostgres.New(context.Background(), connectionString, connect)
+		if err != nil {
+			log.Fatal(err)
+			return err
+		}
+
+		os.genres = pg
+		return nil
+	}
+}
+
+// AddGenre creates a genre, nested under parentID unless it is uuid.Nil.
+func (cs *CollectionService) AddGenre(name string, parentID uuid.UUID) (taxonomy.PublicGenre, error) {
+	if parentID != uuid.Nil {
+		if _, err := cs.genres.Get(parentID); err != nil {
+			return taxonomy.PublicGenre{}, err
+		}
+	}
+
+	g, err := taxonomy.NewGenre(name, parentID)
+	if err != nil {
+		return taxonomy.PublicGenre{}, err
+	}
+
+	if err := cs.genres.Add(g); err != nil {
+		return taxonomy.PublicGenre{}, err
+	}
+
+	return g.ToPublic(), nil
+}
+
+// FindAllGenres ...
+func (cs *CollectionService) FindAllGenres() ([]taxonomy.PublicGenre, error) {
+	genres, err := cs.genres.FindGenres()
+	if err != nil {
+		return []taxonomy.PublicGenre{}, err
+	}
+
+	return taxonomy.ToPublicArray(genres), nil
+}
+
+// TagRecord sets the genre and tags of a record. A nil genreID leaves the
+// current genre untouched, unless clearGenre is set to remove it.
+func (cs *CollectionService) TagRecord(id uuid.UUID, genreID uuid.UUID, clearGenre bool, tags []string) (record.PublicRecord, error) {
+	rec, err := cs.records.Get(id)
+	if err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	switch {
+	case clearGenre && genreID != uuid.Nil:
+		return record.PublicRecord{}, ErrGenreConflict
+	case clearGenre:
+		rec.SetGenreID(uuid.Nil)
+	case genreID != uuid.Nil:
+		if cs.genres == nil {
+			return record.PublicRecord{}, ErrNoGenreRepository
+		}
+		if _, err := cs.genres.Get(genreID); err != nil {
+			return record.PublicRecord{}, err
+		}
+		rec.SetGenreID(genreID)
+	}
+	rec.SetTags(tags)
+
+	if err := cs.records.Update(&rec); err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	return rec.ToPublic(), nil
+}
+
+// TagSong replaces the tags of a song.
+func (cs *CollectionService) TagSong(id uuid.UUID, tags []string) error {
+	if cs.songs == nil {
+		return ErrNoSongRepository
+	}
+
+	s, err := cs.songs.Get(id)
+	if err != nil {
+		return err
+	}
+
+	s.SetTags(tags)
+
+	return cs.songs.Update(&s)
+}
+
+// FindRecords returns the records matching the filter, in the filter sort
+// order. Genres in the filter also match every one of their sub-genres.
+func (cs *CollectionService) FindRecords(filter record.Filter) ([]record.PublicRecord, error) {
+	records, err := cs.findRecords(filter)
+	if err != nil {
+		return []record.PublicRecord{}, err
+	}
+
+	public, err := cs.withRecordDetails(record.ToPublicArray(records))
+	if err != nil {
+		return []record.PublicRecord{}, err
+	}
+
+	return filter.ApplyPublic(public), nil
+}
+
+// TagCounts counts the tags of the records matching the filter and of their
+// songs.
+func (cs *CollectionService) TagCounts(filter record.Filter) ([]taxonomy.TagCount, error) {
+	if cs.songs == nil {
+		return []taxonomy.TagCount{}, ErrNoSongRepository
+	}
+
+	records, err := cs.findRecords(filter)
+	if err != nil {
+		return []taxonomy.TagCount{}, err
+	}
+
+	var tagSets [][]string
+	for _, r := range records {
+		tagSets = append(tagSets, r.GetTags())
+
+		songs, err := cs.songs.FindSongsByRecord(r.GetID())
+		if err != nil {
+			return []taxonomy.TagCount{}, err
+		}
+		for _, s := range songs {
+			tagSets = append(tagSets, s.GetTags())
+		}
+	}
+
+	return taxonomy.CountTags(tagSets...), nil
+}
+
+func (cs *CollectionService) findRecords(filter record.Filter) ([]record.Record, error) {
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filter.GenreIDs) > 0 && cs.genres != nil {
+		genres, err := cs.genres.FindGenres()
+		if err != nil {
+			return nil, err
+		}
+
+		var expanded []uuid.UUID
+		for _, id := range filter.GenreIDs {
+			expanded = append(expanded, taxonomy.Descendants(genres, id)...)
+		}
+		filter.GenreIDs = expanded
+	}
+
+	return filter.Apply(records), nil
+}
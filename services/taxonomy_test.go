@@ -0,0 +1,171 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/taxonomy"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTaxonomyService(t *testing.T) *services.CollectionService {
+	cs, err := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithGenreMemoryRepository(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cs
+}
+
+func TestCollectionService_AddGenre(t *testing.T) {
+	cs := newTaxonomyService(t)
+
+	electronic, err := cs.AddGenre("Electronic", uuid.Nil)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name        string
+		genre       string
+		parentID    uuid.UUID
+		expectedErr error
+	}{
+		{
+			name:        "Sub-genre",
+			genre:       "House",
+			parentID:    electronic.ID,
+			expectedErr: nil,
+		},
+		{
+			name:        "Unknown parent",
+			genre:       "House",
+			parentID:    uuid.New(),
+			expectedErr: taxonomy.ErrGenreNotFound,
+		},
+		{
+			name:        "Missing name",
+			genre:       "",
+			parentID:    uuid.Nil,
+			expectedErr: taxonomy.ErrMissingValues,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := cs.AddGenre(test.genre, test.parentID)
+			assert.True(t, errors.Is(err, test.expectedErr))
+		})
+	}
+}
+
+func TestCollectionService_FindRecordsByGenre(t *testing.T) {
+	cs := newTaxonomyService(t)
+
+	electronic, _ := cs.AddGenre("Electronic", uuid.Nil)
+	house, _ := cs.AddGenre("House", electronic.ID)
+	deepHouse, _ := cs.AddGenre("Deep House", house.ID)
+	rock, _ := cs.AddGenre("Rock", uuid.Nil)
+
+	r1, _ := cs.AddRecord(uuid.New(), "r1", "vinyl")
+	r2, _ := cs.AddRecord(uuid.New(), "r2", "vinyl")
+	r3, _ := cs.AddRecord(uuid.New(), "r3", "mp3")
+	cs.TagRecord(r1.ID, deepHouse.ID, false, []string{"summer"})
+	cs.TagRecord(r2.ID, house.ID, false, []string{"Summer", "club"})
+	cs.TagRecord(r3.ID, rock.ID, false, nil)
+
+	tests := []struct {
+		name   string
+		filter record.Filter
+		want   int
+	}{
+		{
+			name:   "Top level genre includes descendants",
+			filter: record.Filter{GenreIDs: []uuid.UUID{electronic.ID}},
+			want:   2,
+		},
+		{
+			name:   "Leaf genre",
+			filter: record.Filter{GenreIDs: []uuid.UUID{deepHouse.ID}},
+			want:   1,
+		},
+		{
+			name:   "Genre and tag",
+			filter: record.Filter{GenreIDs: []uuid.UUID{electronic.ID}, Tags: []string{"club"}},
+			want:   1,
+		},
+		{
+			name:   "Kind",
+			filter: record.Filter{Kind: "mp3"},
+			want:   1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := cs.FindRecords(test.filter)
+			assert.Nil(t, err)
+			assert.Equal(t, test.want, len(got))
+		})
+	}
+}
+
+func TestCollectionService_TagCounts(t *testing.T) {
+	cs := newTaxonomyService(t)
+
+	r1, _ := cs.AddRecord(uuid.New(), "r1", "vinyl")
+	r2, _ := cs.AddRecord(uuid.New(), "r2", "vinyl")
+	cs.TagRecord(r1.ID, uuid.Nil, false, []string{"summer"})
+	cs.TagRecord(r2.ID, uuid.Nil, false, []string{"summer", "club"})
+
+	got, err := cs.TagCounts(record.Filter{})
+	assert.Nil(t, err)
+	assert.Equal(t, []taxonomy.TagCount{
+		{Tag: "summer", Count: 2},
+		{Tag: "club", Count: 1},
+	}, got)
+}
+
+func TestCollectionService_TagRecordUnknownGenre(t *testing.T) {
+	cs := newTaxonomyService(t)
+
+	r, _ := cs.AddRecord(uuid.New(), "r1", "vinyl")
+	_, err := cs.TagRecord(r.ID, uuid.New(), false, []string{"summer"})
+	assert.Equal(t, taxonomy.ErrGenreNotFound, err)
+}
+
+func TestCollectionService_TagRecordClearGenre(t *testing.T) {
+	cs := newTaxonomyService(t)
+
+	rock, _ := cs.AddGenre("Rock", uuid.Nil)
+	r, _ := cs.AddRecord(uuid.New(), "r1", "vinyl")
+	tagged, err := cs.TagRecord(r.ID, rock.ID, false, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, rock.ID, *tagged.GenreID)
+
+	_, err = cs.TagRecord(r.ID, rock.ID, true, nil)
+	assert.Equal(t, services.ErrGenreConflict, err)
+
+	cleared, err := cs.TagRecord(r.ID, uuid.Nil, true, []string{"summer"})
+	assert.Nil(t, err)
+	assert.Nil(t, cleared.GenreID)
+	assert.Equal(t, []string{"summer"}, cleared.Tags)
+}
+
+func TestCollectionService_TagWithoutSongRepository(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithGenreMemoryRepository(),
+	)
+
+	err := cs.TagSong(uuid.New(), []string{"summer"})
+	assert.Equal(t, services.ErrNoSongRepository, err)
+
+	_, err = cs.TagCounts(record.Filter{})
+	assert.Equal(t, services.ErrNoSongRepository, err)
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/pkg/tracklist"
+)
+
+var (
+	ErrRecordNotDigital = errors.New("vinyl records cannot be exported as playlists")
+)
+
+// RecordTracklist lists the songs of a digital record for a playlist file.
+//
+// Locations are the paths of the song files below base, a directory or a
+// URL where the music library is found. With an empty base they are
+// relative to the library. Songs without a known file, as those not found
+// by a library scan, are left out.
+func (cs *CollectionService) RecordTracklist(id uuid.UUID, base string) (tracklist.Tracklist, error) {
+	rec, err := cs.records.Get(id)
+	if err != nil {
+		return tracklist.Tracklist{}, err
+	}
+	if rec.GetKind() == record.KindVinyl {
+		return tracklist.Tracklist{}, ErrRecordNotDigital
+	}
+
+	songs, err := cs.songs.FindSongsByRecord(id)
+	if err != nil {
+		return tracklist.Tracklist{}, err
+	}
+
+	list := tracklist.Tracklist{Title: rec.GetName()}
+	if rec.GetArtist() != "" {
+		list.Title = rec.GetArtist() + " - " + rec.GetName()
+	}
+	for i, s := range songs {
+		if s.GetPath() == "" {
+			continue
+		}
+		list.Entries = append(list.Entries, trackEntry(rec, i+1, s, base))
+	}
+
+	return list, nil
+}
+
+// PlaylistTracklist lists the songs of a playlist for a playlist file, in
+// its order. Songs without a known file, as those of vinyl records, are left
+// out, as are songs deleted since they were added. Locations are built as in
+// RecordTracklist.
+func (cs *CollectionService) PlaylistTracklist(id uuid.UUID, base string) (tracklist.Tracklist, error) {
+	p, err := cs.playlists.Get(id)
+	if err != nil {
+		return tracklist.Tracklist{}, err
+	}
+
+	list := tracklist.Tracklist{Title: p.GetName()}
+
+	records := make(map[uuid.UUID]record.Record)
+	positions := make(map[uuid.UUID]int)
+	for _, songID := range p.GetSongIDs() {
+		s, err := cs.songs.Get(songID)
+		if err == song.ErrSongNotFound {
+			continue
+		}
+		if err != nil {
+			return tracklist.Tracklist{}, err
+		}
+
+		rec, ok := records[s.GetRecordID()]
+		if !ok {
+			if rec, err = cs.records.Get(s.GetRecordID()); err != nil {
+				return tracklist.Tracklist{}, err
+			}
+			records[rec.GetID()] = rec
+
+			songs, err := cs.songs.FindSongsByRecord(rec.GetID())
+			if err != nil {
+				return tracklist.Tracklist{}, err
+			}
+			for i, rs := range songs {
+				positions[rs.GetID()] = i + 1
+			}
+		}
+
+		if s.GetPath() == "" {
+			continue
+		}
+		list.Entries = append(list.Entries, trackEntry(rec, positions[songID], s, base))
+	}
+
+	return list, nil
+}
+
+// trackEntry is the playlist entry of the song at the given position of a
+// digital record. The track number of the song, when known, wins over its
+// position, as the record may not hold the whole album.
+func trackEntry(rec record.Record, position int, s song.Song, base string) tracklist.Entry {
+	if track := s.GetTrack(); track > 0 {
+		position = track
+	}
+
+	location := s.GetPath()
+	if base != "" {
+		location = strings.TrimSuffix(base, "/") + "/" + location
+	}
+
+	return tracklist.Entry{
+		Title:    s.GetName(),
+		Artist:   rec.GetArtist(),
+		Album:    rec.GetName(),
+		TrackNum: position,
+		Length:   s.GetLength(),
+		Location: location,
+	}
+}
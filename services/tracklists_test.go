@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/playlist"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/pkg/backup"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_Tracklists(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithPlaylistMemoryRepository(),
+	)
+
+	var results []services.BulkResult
+	err := cs.ImportNDJSON(strings.NewReader(`{"name":"The Wall","artist":"Pink Floyd","kind":"mp3","songs":[{"name":"In the Flesh?","length":199,"path":"Pink Floyd/The Wall/1-01 In the Flesh.mp3"},{"name":"The Thin Ice","length":147,"path":"Pink Floyd/The Wall/1-02 The Thin Ice.mp3"},{"name":"Another Brick in the Wall","length":191}]}
+{"name":"Kind of Blue","artist":"Miles Davis","kind":"lossless","songs":[{"name":"So What","length":562,"path":"Miles Davis/Kind of Blue/So What.flac"}]}
+{"name":"Rumours","kind":"vinyl","songs":[{"name":"Dreams","length":257}]}
+`), 10, func(batch []services.BulkResult) error {
+		results = append(results, batch...)
+		return nil
+	})
+	assert.NoError(t, err)
+	wall, rumours := *results[0].ID, *results[2].ID
+
+	t.Run("Record", func(t *testing.T) {
+		list, err := cs.RecordTracklist(wall, "/music/")
+		assert.NoError(t, err)
+		assert.Equal(t, "Pink Floyd - The Wall", list.Title)
+		// the song without a file is left out
+		assert.Len(t, list.Entries, 2)
+		assert.Equal(t, "/music/Pink Floyd/The Wall/1-01 In the Flesh.mp3", list.Entries[0].Location)
+		assert.Equal(t, int64(199), list.Entries[0].Length)
+		assert.Equal(t, 2, list.Entries[1].TrackNum)
+
+		_, err = cs.RecordTracklist(rumours, "")
+		assert.Equal(t, services.ErrRecordNotDigital, err)
+
+		_, err = cs.RecordTracklist(uuid.New(), "")
+		assert.Equal(t, record.ErrRecordNotFound, err)
+	})
+
+	t.Run("Playlist", func(t *testing.T) {
+		p, err := cs.CreatePlaylist("Mix", playlist.DuplicatesAllow, []uuid.UUID{
+			results[1].Songs[0], results[2].Songs[0], results[0].Songs[1],
+		})
+		assert.NoError(t, err)
+
+		list, err := cs.PlaylistTracklist(p.ID, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "Mix", list.Title)
+		// the vinyl song has no file
+		assert.Len(t, list.Entries, 2)
+		assert.Equal(t, "Miles Davis/Kind of Blue/So What.flac", list.Entries[0].Location)
+		assert.Equal(t, "Pink Floyd/The Wall/1-02 The Thin Ice.mp3", list.Entries[1].Location)
+
+		_, err = cs.PlaylistTracklist(uuid.New(), "")
+		assert.Equal(t, playlist.ErrPlaylistNotFound, err)
+	})
+
+	t.Run("Track numbers", func(t *testing.T) {
+		// the second disc only, its songs know their track numbers
+		disc := record.PublicRecord{ID: uuid.New(), Name: "The Wall (Disc 2)", Artist: "Pink Floyd", Kind: record.KindMP3}
+		var archive bytes.Buffer
+		out := backup.NewWriter(&archive, time.Now())
+		assert.NoError(t, out.Add(services.BackupRecords, []record.PublicRecord{disc}))
+		assert.NoError(t, out.Add(services.BackupSongs, []song.PublicSong{
+			{ID: uuid.New(), Name: "Hey You", Length: 280, Track: 14, RecordID: disc.ID, Path: "Pink Floyd/The Wall/2-01 Hey You.mp3"},
+			{ID: uuid.New(), Name: "Nobody Home", Length: 206, Track: 17, RecordID: disc.ID, Path: "Pink Floyd/The Wall/2-04 Nobody Home.mp3"},
+		}))
+		_, err := out.Close()
+		assert.NoError(t, err)
+
+		restored, _ := services.NewCollectionService(
+			services.WithRecordMemoryRepository(),
+			services.WithSongMemoryRepository(),
+			services.WithPlaylistMemoryRepository(),
+		)
+		_, err = restored.Restore(&archive)
+		assert.NoError(t, err)
+
+		list, err := restored.RecordTracklist(disc.ID, "")
+		assert.NoError(t, err)
+		assert.Len(t, list.Entries, 2)
+		assert.Equal(t, 14, list.Entries[0].TrackNum)
+		assert.Equal(t, 17, list.Entries[1].TrackNum)
+
+		songs, _ := restored.FindSongsByRecord(disc.ID)
+		p, err := restored.CreatePlaylist("Disc 2", playlist.DuplicatesAllow, []uuid.UUID{songs[1].ID})
+		assert.NoError(t, err)
+		list, err = restored.PlaylistTracklist(p.ID, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 17, list.Entries[0].TrackNum)
+	})
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/money"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/taxonomy"
+)
+
+var (
+	ErrMissingCurrency = errors.New("valuation currency is required when no exchange rates are loaded")
+)
+
+// unclassified is the genre breakdown key of records without a genre.
+const unclassified = "unclassified"
+
+// ValuationBreakdown is the value of a group of records.
+type ValuationBreakdown struct {
+	Key   string      `json:"key"`
+	Count int         `json:"count"`
+	Total money.Money `json:"total"`
+}
+
+// ValuationReport sums the latest estimated value of every owned record in
+// a single currency.
+type ValuationReport struct {
+	Currency  string      `json:"currency"`
+	RatesDate string      `json:"ratesDate,omitempty"`
+	Total     money.Money `json:"total"`
+	Valued    int         `json:"valued"`
+	// Unvalued counts owned records without any estimate.
+	Unvalued int `json:"unvalued"`
+	// Unconverted counts records valued in a currency missing from the
+	// exchange rate table. They are left out of the totals.
+	Unconverted int                  `json:"unconverted"`
+	ByKind      []ValuationBreakdown `json:"byKind"`
+	ByGenre     []ValuationBreakdown `json:"byGenre"`
+}
+
+// WithExchangeRates ...
+func WithExchangeRates(rates money.Rates) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		os.rates = rates
+		return nil
+	}
+}
+
+// WithExchangeRatesFile loads the exchange rate table from a local file.
+func WithExchangeRatesFile(path string) CollectionConfiguration {
+	return func(os *CollectionService) error {
+		rates, err := money.LoadRates(path)
+		if err != nil {
+			return err
+		}
+
+		os.rates = rates
+		return nil
+	}
+}
+
+// AddRecordValuation adds a dated estimate to the price history of a record.
+// A zero date means the estimate is from today.
+func (cs *CollectionService) AddRecordValuation(id uuid.UUID, value money.Money, date time.Time, source string) (record.PublicRecord, error) {
+	rec, err := cs.records.Get(id)
+	if err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	if date.IsZero() {
+		date = time.Now().UTC()
+	}
+
+	if err := rec.AddValuation(record.Valuation{Date: date, Value: value, Source: source}); err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	if err := cs.records.Update(&rec); err != nil {
+		return record.PublicRecord{}, err
+	}
+
+	return rec.ToPublic(), nil
+}
+
+// Valuation reports what the owned records are worth in the given currency,
+// the exchange rate base currency when empty.
+func (cs *CollectionService) Valuation(currency string) (ValuationReport, error) {
+	if currency == "" {
+		currency = cs.rates.Base()
+	}
+	if currency == "" {
+		return ValuationReport{}, ErrMissingCurrency
+	}
+	if !money.IsValidCurrency(currency) {
+		return ValuationReport{}, money.ErrInvalidCurrency
+	}
+	currency = strings.ToUpper(currency)
+
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return ValuationReport{}, err
+	}
+
+	genreNames, err := cs.genreNames()
+	if err != nil {
+		return ValuationReport{}, err
+	}
+
+	report := ValuationReport{
+		Currency: currency,
+		Total:    money.Zero(currency),
+	}
+	if !cs.rates.Date().IsZero() {
+		report.RatesDate = cs.rates.Date().Format("2006-01-02")
+	}
+
+	byKind := make(map[string]*ValuationBreakdown)
+	byGenre := make(map[string]*ValuationBreakdown)
+	for _, r := range records {
+		if !r.IsOwned() {
+			continue
+		}
+
+		value, ok := r.GetEstimatedValue()
+		if !ok {
+			report.Unvalued++
+			continue
+		}
+
+		converted, err := cs.rates.Convert(value, currency)
+		if err != nil {
+			report.Unconverted++
+			continue
+		}
+
+		report.Valued++
+		report.Total.Amount += converted.Amount
+
+		genre := unclassified
+		if name, ok := genreNames[r.GetGenreID()]; ok {
+			genre = name
+		}
+		addToBreakdown(byKind, r.GetKind(), converted)
+		addToBreakdown(byGenre, genre, converted)
+	}
+
+	report.ByKind = sortedBreakdown(byKind)
+	report.ByGenre = sortedBreakdown(byGenre)
+
+	return report, nil
+}
+
+// genreNames maps every genre to its full path, e.g. Electronic > House.
+func (cs *CollectionService) genreNames() (map[uuid.UUID]string, error) {
+	names := make(map[uuid.UUID]string)
+	if cs.genres == nil {
+		return names, nil
+	}
+
+	genres, err := cs.genres.FindGenres()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, g := range genres {
+		path, err := taxonomy.Path(genres, g.GetID())
+		if err != nil {
+			names[g.GetID()] = g.GetName()
+			continue
+		}
+
+		parts := make([]string, 0, len(path))
+		for _, p := range path {
+			parts = append(parts, p.GetName())
+		}
+		names[g.GetID()] = strings.Join(parts, " > ")
+	}
+
+	return names, nil
+}
+
+func addToBreakdown(groups map[string]*ValuationBreakdown, key string, value money.Money) {
+	group, ok := groups[key]
+	if !ok {
+		group = &ValuationBreakdown{Key: key, Total: money.Zero(value.Currency)}
+		groups[key] = group
+	}
+
+	group.Count++
+	group.Total.Amount += value.Amount
+}
+
+// sortedBreakdown lists the groups from the most to the least valuable.
+func sortedBreakdown(groups map[string]*ValuationBreakdown) []ValuationBreakdown {
+	breakdown := make([]ValuationBreakdown, 0, len(groups))
+	for _, g := range groups {
+		breakdown = append(breakdown, *g)
+	}
+
+	sort.Slice(breakdown, func(i, j int) bool {
+		if breakdown[i].Total.Amount != breakdown[j].Total.Amount {
+			return breakdown[i].Total.Amount > breakdown[j].Total.Amount
+		}
+		return breakdown[i].Key < breakdown[j].Key
+	})
+
+	return breakdown
+}
@@ -0,0 +1,87 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/money"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionService_Valuation(t *testing.T) {
+	rates, _ := money.NewRates("EUR", time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC), map[string]float64{
+		"USD": 1.25,
+	})
+
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithGenreMemoryRepository(),
+		services.WithExchangeRates(rates),
+	)
+
+	electronic, _ := cs.AddGenre("Electronic", uuid.Nil)
+	house, _ := cs.AddGenre("House", electronic.ID)
+
+	v1, _ := cs.AddRecord(uuid.New(), "v1", "vinyl")
+	v2, _ := cs.AddRecord(uuid.New(), "v2", "vinyl")
+	m1, _ := cs.AddRecord(uuid.New(), "m1", "mp3")
+	sold, _ := cs.AddRecord(uuid.New(), "sold", "vinyl")
+	gbp, _ := cs.AddRecord(uuid.New(), "gbp", "vinyl")
+	cs.AddRecord(uuid.New(), "unvalued", "vinyl")
+
+	cs.TagRecord(v1.ID, house.ID, false, nil)
+	cs.AddRecordValuation(v1.ID, money.Money{Amount: 1000, Currency: "EUR"}, time.Time{}, "")
+	cs.AddRecordValuation(v2.ID, money.Money{Amount: 1250, Currency: "USD"}, time.Time{}, "discogs")
+	cs.AddRecordValuation(m1.ID, money.Money{Amount: 500, Currency: "EUR"}, time.Time{}, "")
+	cs.AddRecordValuation(sold.ID, money.Money{Amount: 9900, Currency: "EUR"}, time.Time{}, "")
+	cs.ChangeRecordStatus(sold.ID, "sold", nil)
+	cs.AddRecordValuation(gbp.ID, money.Money{Amount: 1000, Currency: "GBP"}, time.Time{}, "")
+
+	report, err := cs.Valuation("")
+	assert.Nil(t, err)
+	assert.Equal(t, "EUR", report.Currency)
+	assert.Equal(t, "2021-10-01", report.RatesDate)
+	assert.Equal(t, money.Money{Amount: 2500, Currency: "EUR"}, report.Total)
+	assert.Equal(t, 3, report.Valued)
+	assert.Equal(t, 1, report.Unvalued)
+	assert.Equal(t, 1, report.Unconverted)
+	assert.Equal(t, []services.ValuationBreakdown{
+		{Key: "vinyl", Count: 2, Total: money.Money{Amount: 2000, Currency: "EUR"}},
+		{Key: "mp3", Count: 1, Total: money.Money{Amount: 500, Currency: "EUR"}},
+	}, report.ByKind)
+	assert.Equal(t, []services.ValuationBreakdown{
+		{Key: "unclassified", Count: 2, Total: money.Money{Amount: 1500, Currency: "EUR"}},
+		{Key: "Electronic > House", Count: 1, Total: money.Money{Amount: 1000, Currency: "EUR"}},
+	}, report.ByGenre)
+
+	usd, err := cs.Valuation("usd")
+	assert.Nil(t, err)
+	assert.Equal(t, money.Money{Amount: 3125, Currency: "USD"}, usd.Total)
+}
+
+func TestCollectionService_ValuationWithoutRates(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+	)
+
+	_, err := cs.Valuation("")
+	assert.Equal(t, services.ErrMissingCurrency, err)
+
+	_, err = cs.Valuation("euro")
+	assert.Equal(t, money.ErrInvalidCurrency, err)
+
+	r, _ := cs.AddRecord(uuid.New(), "v1", "vinyl")
+	_, err = cs.AddRecordValuation(r.ID, money.Money{Amount: -1, Currency: "EUR"}, time.Time{}, "")
+	assert.True(t, errors.Is(err, record.ErrInvalidValue))
+
+	cs.AddRecordValuation(r.ID, money.Money{Amount: 100, Currency: "EUR"}, time.Time{}, "")
+	report, err := cs.Valuation("EUR")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(100), report.Total.Amount)
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rodrwan/collection/domain/money"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/domain/song"
+	"github.com/rodrwan/collection/pkg/xlsx"
+)
+
+// kindTotals is a row of the summary sheet.
+type kindTotals struct {
+	records int
+	songs   int
+	length  int64
+}
+
+// ExportXLSX writes the collection as a spreadsheet with three sheets:
+// Records, a row per record, Songs, a row per song, and Summary, the
+// records, songs and total runtime of every kind. Lengths are duration
+// cells and acquisition dates date cells, so they can be summed and sorted.
+func (cs *CollectionService) ExportXLSX(w io.Writer) error {
+	records, err := cs.records.FindRecords()
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if !strings.EqualFold(a.GetArtist(), b.GetArtist()) {
+			return strings.ToLower(a.GetArtist()) < strings.ToLower(b.GetArtist())
+		}
+		return strings.ToLower(a.GetName()) < strings.ToLower(b.GetName())
+	})
+
+	genres, err := cs.genreLabels()
+	if err != nil {
+		return err
+	}
+
+	wb := xlsx.New()
+	recordSheet, _ := wb.AddSheet("Records")
+	songSheet, _ := wb.AddSheet("Songs")
+	summarySheet, _ := wb.AddSheet("Summary")
+
+	recordSheet.AddHeader("ID", "Name", "Artist", "Kind", "Genre", "Status", "Tags", "Songs", "Length", "Acquired", "Price", "Currency", "Seller")
+	songSheet.AddHeader("Record ID", "Record", "Artist", "Kind", "#", "Song", "Length", "Tags")
+
+	totals := make(map[string]*kindTotals)
+	for _, rec := range records {
+		songs, err := cs.songs.FindSongsByRecord(rec.GetID())
+		if err != nil {
+			return err
+		}
+
+		song.SortByTrack(songs)
+		var length int64
+		for i, s := range songs {
+			length += s.GetLength()
+			track := s.GetTrack()
+			if track == 0 {
+				track = i + 1
+			}
+			songSheet.AddRow(
+				xlsx.String(rec.GetID().String()),
+				xlsx.String(rec.GetName()),
+				xlsx.String(rec.GetArtist()),
+				xlsx.String(rec.GetKind()),
+				xlsx.Int(int64(track)),
+				xlsx.String(s.GetName()),
+				seconds(s.GetLength()),
+				xlsx.String(strings.Join(s.GetTags(), ", ")),
+			)
+		}
+
+		acquisition := rec.GetAcquisition()
+		recordSheet.AddRow(
+			xlsx.String(rec.GetID().String()),
+			xlsx.String(rec.GetName()),
+			xlsx.String(rec.GetArtist()),
+			xlsx.String(rec.GetKind()),
+			xlsx.String(genres[rec.GetGenreID()]),
+			xlsx.String(string(rec.GetStatus())),
+			xlsx.String(strings.Join(rec.GetTags(), ", ")),
+			xlsx.Int(int64(len(songs))),
+			seconds(length),
+			xlsx.Date(acquisition.Date),
+			price(acquisition),
+			xlsx.String(acquisition.Currency),
+			xlsx.String(acquisition.Seller),
+		)
+
+		t, ok := totals[rec.GetKind()]
+		if !ok {
+			t = &kindTotals{}
+			totals[rec.GetKind()] = t
+		}
+		t.records++
+		t.songs += len(songs)
+		t.length += length
+	}
+
+	summarySheet.AddHeader("Kind", "Records", "Songs", "Runtime")
+	var all kindTotals
+	for _, kind := range summaryKinds(totals) {
+		t := totals[kind]
+		summarySheet.AddRow(xlsx.String(kind), xlsx.Int(int64(t.records)), xlsx.Int(int64(t.songs)), seconds(t.length))
+		all.records += t.records
+		all.songs += t.songs
+		all.length += t.length
+	}
+	summarySheet.AddRow(xlsx.String("Total"), xlsx.Int(int64(all.records)), xlsx.Int(int64(all.songs)), seconds(all.length))
+	summarySheet.AddRow()
+	summarySheet.AddRow(xlsx.String("Generated"), xlsx.DateTime(time.Now()))
+
+	return wb.Write(w)
+}
+
+// summaryKinds lists the supported kinds first, even without records, then
+// any other kind found in the collection.
+func summaryKinds(totals map[string]*kindTotals) []string {
+	kinds := record.Kinds()
+	for _, k := range kinds {
+		if _, ok := totals[k]; !ok {
+			totals[k] = &kindTotals{}
+		}
+	}
+
+	var others []string
+	for k := range totals {
+		if !record.IsValidKind(k) {
+			others = append(others, k)
+		}
+	}
+	sort.Strings(others)
+
+	return append(kinds, others...)
+}
+
+func seconds(n int64) xlsx.Cell {
+	return xlsx.Duration(time.Duration(n) * time.Second)
+}
+
+// price is the acquisition price in units of its currency, e.g. 12.5 for
+// 1250 cents. Records without a price get an empty cell.
+func price(a record.Acquisition) xlsx.Cell {
+	if a.Price == 0 && a.Currency == "" {
+		return xlsx.Cell{}
+	}
+	return xlsx.Number(float64(a.Price) / math.Pow10(money.Exponent(a.Currency)))
+}
@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rodrwan/collection/domain/record"
+	"github.com/rodrwan/collection/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// readXLSX returns the files of a workbook by name.
+func readXLSX(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string]string)
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := ioutil.ReadAll(r)
+		r.Close()
+		files[f.Name] = string(content)
+	}
+	return files
+}
+
+func TestCollectionService_ExportXLSX(t *testing.T) {
+	cs, _ := services.NewCollectionService(
+		services.WithRecordMemoryRepository(),
+		services.WithSongMemoryRepository(),
+		services.WithGenreMemoryRepository(),
+	)
+
+	rock, _ := cs.AddGenre("Rock", uuid.Nil)
+	wall, err := cs.AddRecordWithOwnership(uuid.New(), "The Wall", "vinyl", "owned", record.Acquisition{
+		Date:     time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+		Price:    2550,
+		Currency: "EUR",
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, cs.AddSongToRecord(wall.ToRecord(), "In the Flesh?", 199))
+	assert.Nil(t, cs.AddSongToRecord(wall.ToRecord(), "Hey You", 280))
+	_, err = cs.TagRecord(wall.ID, rock.ID, false, []string{"classic"})
+	assert.Nil(t, err)
+
+	blue, _ := cs.AddRecord(uuid.New(), "Blue", "mp3")
+	assert.Nil(t, cs.AddSongToRecord(blue.ToRecord(), "River", 240))
+
+	// Songs are listed in track order, numbered by their track.
+	_, err = cs.ImportCUE(strings.NewReader(strings.Replace(cueSheet, "TRACK 02", "TRACK 05", 1)), services.CUEImportOptions{})
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	assert.Nil(t, cs.ExportXLSX(&buf))
+	files := readXLSX(t, buf.Bytes())
+
+	assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Records" sheetId="1" r:id="rId1"/><sheet name="Songs" sheetId="2" r:id="rId2"/><sheet name="Summary" sheetId="3" r:id="rId3"/>`)
+
+	records := files["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, records, `<t xml:space="preserve">The Wall</t>`)
+	assert.Contains(t, records, `<t xml:space="preserve">Rock</t>`)
+	// 479 seconds, the acquisition date and 25.50 EUR
+	assert.Contains(t, records, `<c r="I3" s="4"><v>0.005543981481481481</v></c><c r="J3" s="2"><v>44256</v></c><c r="K3"><v>25.5</v></c>`)
+	assert.NotContains(t, records, `<c r="K2">`)
+	assert.NotContains(t, records, `<c r="C2"`)
+
+	songs := files["xl/worksheets/sheet2.xml"]
+	assert.Contains(t, songs, `<c r="E3"><v>1</v></c><c r="F3" t="inlineStr"><is><t xml:space="preserve">Hey You</t></is></c>`)
+	assert.Contains(t, songs, `<c r="E4"><v>2</v></c><c r="F4" t="inlineStr"><is><t xml:space="preserve">In the Flesh?</t></is></c>`)
+	assert.Contains(t, songs, `<c r="E6"><v>3</v></c><c r="F6" t="inlineStr"><is><t xml:space="preserve">Track 03</t></is></c>`)
+	assert.Contains(t, songs, `<c r="E7"><v>5</v></c><c r="F7" t="inlineStr"><is><t xml:space="preserve">Dreams</t></is></c>`)
+
+	summary := files["xl/worksheets/sheet3.xml"]
+	for _, want := range []string{
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">vinyl</t></is></c><c r="B2"><v>1</v></c><c r="C2"><v>2</v></c>`,
+		`<c r="A5" t="inlineStr"><is><t xml:space="preserve">lossless</t></is></c><c r="B5"><v>1</v></c><c r="C5"><v>3</v></c>`,
+		`<c r="A7" t="inlineStr"><is><t xml:space="preserve">Total</t></is></c><c r="B7"><v>3</v></c><c r="C7"><v>6</v></c>`,
+		`<t xml:space="preserve">Generated</t>`,
+	} {
+		assert.Contains(t, summary, want)
+	}
+}